@@ -0,0 +1,623 @@
+package handlers
+
+import (
+	"api-shiners/api/handlers/dto"
+	"api-shiners/pkg/attempt"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/grading"
+	"api-shiners/pkg/quiz"
+	"api-shiners/pkg/utils"
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type AttemptController struct {
+	attemptService attempt.AttemptService
+	// integrityService mencatat IP request mulai, simpan dan submit ke log integritas
+	integrityService attempt.IntegrityService
+}
+
+func NewAttemptController(attemptService attempt.AttemptService, integrityService attempt.IntegrityService) *AttemptController {
+	return &AttemptController{attemptService: attemptService, integrityService: integrityService}
+}
+
+// recordRequest mencatat IP dan user agent request ke log integritas attempt. Kegagalan
+// mencatat tidak membatalkan respons karena perubahan attempt sudah tersimpan.
+func (ctrl *AttemptController) recordRequest(c *fiber.Ctx, attemptID uuid.UUID, eventType entities.AttemptEventType) {
+	_ = ctrl.integrityService.RecordRequest(context.Background(), attemptID, eventType, c.IP(), c.Get(fiber.HeaderUserAgent))
+}
+
+func attemptError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, attempt.ErrAttemptNotFound), errors.Is(err, attempt.ErrOverrideNotFound),
+		errors.Is(err, attempt.ErrStudentNotEnrolled):
+		return utils.Error(c, http.StatusNotFound, err.Error(), "NotFoundException", nil)
+	case errors.Is(err, attempt.ErrForbidden):
+		return utils.Error(c, http.StatusForbidden, err.Error(), "ForbiddenException", nil)
+	case errors.Is(err, attempt.ErrQuizNotOpen), errors.Is(err, attempt.ErrQuizClosed),
+		errors.Is(err, attempt.ErrNoAttemptsLeft), errors.Is(err, attempt.ErrAttemptInProgress),
+		errors.Is(err, attempt.ErrAlreadySubmitted), errors.Is(err, attempt.ErrAttemptExpired),
+		errors.Is(err, attempt.ErrNotSubmitted):
+		return utils.Error(c, http.StatusConflict, err.Error(), "ConflictException", nil)
+	case errors.Is(err, attempt.ErrInvalidAnswer), errors.Is(err, attempt.ErrNotManualGraded), errors.Is(err, attempt.ErrInvalidPoints),
+		errors.Is(err, attempt.ErrInvalidEvent), errors.Is(err, attempt.ErrTooManyEvents):
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "BadRequestException", nil)
+	default:
+		return quizError(c, err)
+	}
+}
+
+// toAttemptResponse: score dan hasil per soal hanya dikirim bila sudah dirilis (lihat attempt.Review)
+func toAttemptResponse(a entities.QuizAttempt, q *entities.Quiz, review attempt.Review) dto.AttemptResponse {
+	resp := dto.AttemptResponse{
+		ID:            a.ID.String(),
+		QuizID:        a.QuizID.String(),
+		StudentID:     a.StudentID.String(),
+		StartedAt:     a.StartedAt,
+		Deadline:      attempt.Deadline(q, &a),
+		SubmittedAt:   a.SubmittedAt,
+		DurationSec:   a.DurationSec,
+		IsLate:        a.IsLate,
+		PendingReview: a.PendingReview,
+		ScoreMode:     string(entities.ScoreModePercentage),
+	}
+	if q.ScoreMode == entities.ScoreModePoints {
+		resp.ScoreMode = string(entities.ScoreModePoints)
+	}
+	submitted := a.SubmittedAt != nil
+	if submitted && review.ShowScore {
+		score, max := grading.DisplayScore(q, &a)
+		percentage := a.Score
+		resp.Score = &score
+		resp.MaxScore = &max
+		resp.Percentage = &percentage
+	}
+	for _, ans := range a.Answers {
+		resp.Answers = append(resp.Answers, toAnswerResponse(ans, submitted, review))
+	}
+	return resp
+}
+
+// toAnswerResponse: benar/salah per soal mengikuti release_content quiz; essay yang
+// sudah dinilai manual ikut terlihat begitu score dirilis
+func toAnswerResponse(ans entities.Answer, submitted bool, review attempt.Review) dto.AnswerResponse {
+	item := dto.AnswerResponse{
+		ID:            ans.ID.String(),
+		QuestionID:    ans.QuestionID.String(),
+		TextAnswer:    ans.TextAnswer,
+		NumericAnswer: ans.NumericAnswer,
+	}
+	if ans.ChoiceID != nil {
+		choiceID := ans.ChoiceID.String()
+		item.ChoiceID = &choiceID
+	}
+	for _, id := range ans.ChoiceIDs {
+		item.ChoiceIDs = append(item.ChoiceIDs, id.String())
+	}
+	if submitted && review.ShowCorrectness {
+		isCorrect := ans.IsCorrect
+		item.IsCorrect = &isCorrect
+		item.Points = ans.Points
+	}
+	if ans.GradedAt != nil && review.ShowScore {
+		item.Points = ans.Points
+		item.Comment = ans.Comment
+		item.GradedAt = ans.GradedAt
+	}
+	return item
+}
+
+func toAttemptResponses(attempts []entities.QuizAttempt, q *entities.Quiz, review attempt.Review) []dto.AttemptResponse {
+	resp := make([]dto.AttemptResponse, 0, len(attempts))
+	for _, a := range attempts {
+		resp = append(resp, toAttemptResponse(a, q, review))
+	}
+	return resp
+}
+
+func parseAnswerInputs(reqs []dto.AnswerRequest) ([]attempt.AnswerInput, error) {
+	inputs := make([]attempt.AnswerInput, 0, len(reqs))
+	for _, r := range reqs {
+		questionID, err := uuid.Parse(r.QuestionID)
+		if err != nil {
+			return nil, err
+		}
+		choiceID, err := parseOptionalUUID(r.ChoiceID)
+		if err != nil {
+			return nil, err
+		}
+		input := attempt.AnswerInput{QuestionID: questionID, ChoiceID: choiceID, Text: r.Text, Number: r.Number}
+		for _, raw := range r.ChoiceIDs {
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				return nil, err
+			}
+			input.ChoiceIDs = append(input.ChoiceIDs, id)
+		}
+		inputs = append(inputs, input)
+	}
+	return inputs, nil
+}
+
+// StartAttempt godoc
+// @Summary Start quiz attempt
+// @Description Student memulai attempt baru (atau melanjutkan attempt yang masih berjalan). Jadwal, batas attempt dan batas waktu divalidasi server.
+// @Tags Quiz Attempts
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Success 201 {object} dto.AttemptDetailResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id}/attempts [post]
+func (ctrl *AttemptController) StartAttempt(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id", "quiz_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	started, q, err := ctrl.attemptService.StartAttempt(context.Background(), userID, currentCourseRole(c), ids[0], ids[1])
+	if err != nil {
+		return attemptError(c, err)
+	}
+	ctrl.recordRequest(c, started.ID, entities.AttemptEventStarted)
+
+	return utils.Success(c, http.StatusCreated, "Attempt started successfully", dto.AttemptDetailResponse{
+		Attempt: toAttemptResponse(*started, q, attempt.Review{}),
+		Quiz:    toQuizResponse(*q, false),
+	}, nil)
+}
+
+// GetMyAttempts godoc
+// @Summary Get my quiz attempts
+// @Description Menampilkan riwayat attempt student untuk quiz
+// @Tags Quiz Attempts
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Success 200 {object} utils.SuccessResponse{data=[]dto.AttemptResponse}
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id}/attempts/me [get]
+func (ctrl *AttemptController) GetMyAttempts(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id", "quiz_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	attempts, q, err := ctrl.attemptService.GetMyAttempts(context.Background(), userID, ids[0], ids[1])
+	if err != nil {
+		return attemptError(c, err)
+	}
+	review, err := ctrl.attemptService.GetReview(context.Background(), currentCourseRole(c), q)
+	if err != nil {
+		return attemptError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Get attempts successfully", toAttemptResponses(attempts, q, review), nil)
+}
+
+// GetQuizAttempts godoc
+// @Summary Get quiz attempts
+// @Description Menampilkan semua attempt quiz (teacher course atau admin)
+// @Tags Quiz Attempts
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Success 200 {object} utils.SuccessResponse{data=[]dto.AttemptResponse}
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id}/attempts [get]
+func (ctrl *AttemptController) GetQuizAttempts(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id", "quiz_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	attempts, q, overrides, err := ctrl.attemptService.GetQuizAttempts(context.Background(), currentCourseRole(c), ids[0], ids[1])
+	if err != nil {
+		return attemptError(c, err)
+	}
+
+	// deadline tiap attempt dihitung dengan override milik student-nya
+	resp := make([]dto.AttemptResponse, 0, len(attempts))
+	for _, a := range attempts {
+		resp = append(resp, toAttemptResponse(a, overrides.For(q, a.StudentID), attempt.FullReview))
+	}
+
+	return utils.Success(c, http.StatusOK, "Get attempts successfully", resp, nil)
+}
+
+// GetAttempt godoc
+// @Summary Get attempt detail
+// @Description Menampilkan attempt beserta jawaban dan soal quiz. Student hanya bisa melihat attempt miliknya.
+// @Tags Quiz Attempts
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param attempt_id path string true "Attempt ID"
+// @Success 200 {object} dto.AttemptDetailResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/attempts/{attempt_id} [get]
+func (ctrl *AttemptController) GetAttempt(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id", "attempt_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	role := currentCourseRole(c)
+	found, q, err := ctrl.attemptService.GetAttempt(context.Background(), userID, role, ids[0], ids[1])
+	if err != nil {
+		return attemptError(c, err)
+	}
+
+	review, err := ctrl.attemptService.GetReview(context.Background(), role, q)
+	if err != nil {
+		return attemptError(c, err)
+	}
+
+	// kunci jawaban tidak pernah dikirim ke student selama attempt masih berjalan
+	withAnswerKey := review.ShowAnswerKey && (quiz.CanAuthor(role) || found.SubmittedAt != nil)
+	return utils.Success(c, http.StatusOK, "Get attempt successfully", dto.AttemptDetailResponse{
+		Attempt: toAttemptResponse(*found, q, review),
+		Quiz:    toQuizResponse(*q, withAnswerKey),
+	}, nil)
+}
+
+// SaveAnswers godoc
+// @Summary Save attempt answers
+// @Description Menyimpan jawaban sementara. Ditolak bila attempt sudah disubmit atau waktunya habis.
+// @Tags Quiz Attempts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param attempt_id path string true "Attempt ID"
+// @Param request body dto.SaveAnswersRequest true "Answers"
+// @Success 200 {object} dto.AttemptResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/attempts/{attempt_id}/answers [put]
+func (ctrl *AttemptController) SaveAnswers(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id", "attempt_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	var req dto.SaveAnswersRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+
+	inputs, err := parseAnswerInputs(req.Answers)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid question or choice ID", "InvalidUUID", nil)
+	}
+
+	saved, q, err := ctrl.attemptService.SaveAnswers(context.Background(), userID, ids[0], ids[1], inputs)
+	if err != nil {
+		return attemptError(c, err)
+	}
+	ctrl.recordRequest(c, saved.ID, entities.AttemptEventSaved)
+
+	return utils.Success(c, http.StatusOK, "Answers saved successfully", toAttemptResponse(*saved, q, attempt.Review{}), nil)
+}
+
+// SubmitAttempt godoc
+// @Summary Submit attempt
+// @Description Menyimpan jawaban terakhir (opsional) lalu menilai attempt. Submit setelah deadline server ditandai is_late dan hanya jawaban yang tersimpan sebelum deadline yang dinilai.
+// @Tags Quiz Attempts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param attempt_id path string true "Attempt ID"
+// @Param request body dto.SaveAnswersRequest false "Answers"
+// @Success 200 {object} dto.AttemptResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/attempts/{attempt_id}/submit [post]
+func (ctrl *AttemptController) SubmitAttempt(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id", "attempt_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	var req dto.SaveAnswersRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+		}
+	}
+
+	inputs, err := parseAnswerInputs(req.Answers)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid question or choice ID", "InvalidUUID", nil)
+	}
+
+	submitted, q, err := ctrl.attemptService.SubmitAttempt(context.Background(), userID, ids[0], ids[1], inputs)
+	if err != nil {
+		return attemptError(c, err)
+	}
+	ctrl.recordRequest(c, submitted.ID, entities.AttemptEventSubmitted)
+	review, err := ctrl.attemptService.GetReview(context.Background(), currentCourseRole(c), q)
+	if err != nil {
+		return attemptError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Attempt submitted successfully", toAttemptResponse(*submitted, q, review), nil)
+}
+
+// ReviewAttempt godoc
+// @Summary Review submitted attempt
+// @Description Menampilkan hasil attempt milik student sesuai kebijakan rilis quiz: score, benar/salah per soal, dan kunci jawaban bila release_content FULL
+// @Tags Quiz Attempts
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param attempt_id path string true "Attempt ID"
+// @Success 200 {object} dto.AttemptReviewResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/attempts/{attempt_id}/review [get]
+func (ctrl *AttemptController) ReviewAttempt(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id", "attempt_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	found, q, review, err := ctrl.attemptService.ReviewAttempt(context.Background(), userID, ids[0], ids[1])
+	if err != nil {
+		return attemptError(c, err)
+	}
+
+	paper := *q
+	paper.Pools = nil
+	return utils.Success(c, http.StatusOK, "Get attempt review successfully", dto.AttemptReviewResponse{
+		Attempt: toAttemptResponse(*found, q, review),
+		Quiz:    toQuizResponse(paper, review.ShowAnswerKey),
+		Release: dto.ReleaseResponse{
+			Policy:    string(q.ReleasePolicy),
+			Content:   string(q.ReleaseContent),
+			Released:  review.Released,
+			ReleaseAt: review.ReleaseAt,
+		},
+	}, nil)
+}
+
+// GetGradingQueue godoc
+// @Summary Get essay grading queue
+// @Description Menampilkan jawaban essay dari attempt yang sudah disubmit dan belum dinilai (teacher course atau admin)
+// @Tags Quiz Attempts
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Success 200 {object} utils.SuccessResponse{data=[]dto.GradingQueueItem}
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id}/grading-queue [get]
+func (ctrl *AttemptController) GetGradingQueue(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id", "quiz_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	answers, _, err := ctrl.attemptService.GetGradingQueue(context.Background(), currentCourseRole(c), ids[0], ids[1])
+	if err != nil {
+		return attemptError(c, err)
+	}
+
+	resp := make([]dto.GradingQueueItem, 0, len(answers))
+	for _, ans := range answers {
+		// soal bisa berasal dari bank (undian pool), jadi diambil dari relasi answer
+		question := ans.Question
+		resp = append(resp, dto.GradingQueueItem{
+			AnswerID:     ans.ID.String(),
+			AttemptID:    ans.AttemptID.String(),
+			StudentID:    ans.Attempt.StudentID.String(),
+			StudentName:  ans.Attempt.Student.Name,
+			QuestionID:   ans.QuestionID.String(),
+			QuestionText: question.Text,
+			TextAnswer:   ans.TextAnswer,
+			MaxPoints:    grading.MaxPoints(question),
+			SubmittedAt:  ans.Attempt.SubmittedAt,
+		})
+	}
+
+	return utils.Success(c, http.StatusOK, "Get grading queue successfully", resp, nil)
+}
+
+// GradeAnswer godoc
+// @Summary Grade essay answer
+// @Description Memberi nilai dan komentar untuk jawaban essay, lalu menghitung ulang score attempt
+// @Tags Quiz Attempts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param answer_id path string true "Answer ID"
+// @Param request body dto.GradeAnswerRequest true "Points and comment"
+// @Success 200 {object} dto.AttemptResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/answers/{answer_id}/grade [put]
+func (ctrl *AttemptController) GradeAnswer(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id", "answer_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	var req dto.GradeAnswerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+
+	graded, q, err := ctrl.attemptService.GradeAnswer(context.Background(), userID, currentCourseRole(c), ids[0], ids[1], attempt.ManualGradeInput{
+		Points:  req.Points,
+		Comment: req.Comment,
+	})
+	if err != nil {
+		return attemptError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Answer graded successfully", toAttemptResponse(*graded, q, attempt.FullReview), nil)
+}
+
+func toRegradeResponse(r entities.QuizRegrade, dryRun bool) dto.RegradeResponse {
+	resp := dto.RegradeResponse{
+		QuizID:          r.QuizID.String(),
+		RegradedByID:    r.RegradedByID.String(),
+		Reason:          r.Reason,
+		DryRun:          dryRun,
+		AttemptsChecked: r.AttemptsChecked,
+		AttemptsChanged: r.AttemptsChanged,
+		Changes:         make([]dto.RegradeChangeResponse, 0, len(r.Changes)),
+	}
+	if !dryRun {
+		resp.ID = r.ID.String()
+		createdAt := r.CreatedAt
+		resp.CreatedAt = &createdAt
+	}
+	if r.QuestionID != nil {
+		questionID := r.QuestionID.String()
+		resp.QuestionID = &questionID
+	}
+	for _, change := range r.Changes {
+		resp.Changes = append(resp.Changes, dto.RegradeChangeResponse{
+			AttemptID:      change.AttemptID.String(),
+			StudentID:      change.StudentID.String(),
+			OldScore:       change.OldScore,
+			NewScore:       change.NewScore,
+			OldPoints:      change.OldPoints,
+			NewPoints:      change.NewPoints,
+			AnswersChanged: change.AnswersChanged,
+		})
+	}
+	return resp
+}
+
+// RegradeQuiz godoc
+// @Summary Regrade quiz attempts
+// @Description Menilai ulang attempt yang sudah disubmit setelah kunci jawaban diperbaiki, untuk satu soal (question_id) atau seluruh quiz. dry_run=true hanya menampilkan pratinjau perubahan nilai tanpa menyimpan.
+// @Tags Quiz Attempts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Param request body dto.RegradeRequest true "Regrade"
+// @Success 200 {object} utils.SuccessResponse{data=dto.RegradeResponse}
+// @Success 201 {object} utils.SuccessResponse{data=dto.RegradeResponse}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id}/regrade [post]
+func (ctrl *AttemptController) RegradeQuiz(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id", "quiz_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	var req dto.RegradeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+
+	input := attempt.RegradeInput{Reason: req.Reason, DryRun: req.DryRun}
+	if req.QuestionID != nil && *req.QuestionID != "" {
+		questionID, err := uuid.Parse(*req.QuestionID)
+		if err != nil {
+			return utils.Error(c, http.StatusBadRequest, "Invalid question ID format", "InvalidUUID", nil)
+		}
+		input.QuestionID = &questionID
+	}
+
+	regrade, err := ctrl.attemptService.Regrade(context.Background(), userID, currentCourseRole(c), ids[0], ids[1], input)
+	if err != nil {
+		return attemptError(c, err)
+	}
+
+	if req.DryRun {
+		return utils.Success(c, http.StatusOK, "Regrade preview generated successfully", toRegradeResponse(*regrade, true), nil)
+	}
+	return utils.Success(c, http.StatusCreated, "Quiz regraded successfully", toRegradeResponse(*regrade, false), nil)
+}
+
+// GetRegrades godoc
+// @Summary Get quiz regrade history
+// @Description Menampilkan riwayat regrade quiz beserta perubahan nilai tiap attempt (teacher course atau admin)
+// @Tags Quiz Attempts
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Success 200 {object} utils.SuccessResponse{data=[]dto.RegradeResponse}
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id}/regrades [get]
+func (ctrl *AttemptController) GetRegrades(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id", "quiz_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	regrades, err := ctrl.attemptService.GetRegrades(context.Background(), currentCourseRole(c), ids[0], ids[1])
+	if err != nil {
+		return attemptError(c, err)
+	}
+
+	resp := make([]dto.RegradeResponse, 0, len(regrades))
+	for _, r := range regrades {
+		resp = append(resp, toRegradeResponse(r, false))
+	}
+	return utils.Success(c, http.StatusOK, "Get regrades successfully", resp, nil)
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"api-shiners/pkg/entities"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// currentUserID mengambil user_id yang disimpan middleware di context
+func currentUserID(c *fiber.Ctx) (uuid.UUID, error) {
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok || userIDStr == "" {
+		return uuid.Nil, errors.New("unauthorized")
+	}
+	return uuid.Parse(userIDStr)
+}
+
+// currentUserRole mengambil role global dari claim "roles" di token
+func currentUserRole(c *fiber.Ctx) string {
+	switch roles := c.Locals("roles").(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.ToUpper(strings.TrimSpace(roles))
+	case []interface{}:
+		for _, r := range roles {
+			if rs, ok := r.(string); ok && strings.EqualFold(rs, string(entities.ADMIN)) {
+				return string(entities.ADMIN)
+			}
+		}
+		if len(roles) > 0 {
+			return strings.ToUpper(fmt.Sprint(roles[0]))
+		}
+		return ""
+	default:
+		return strings.ToUpper(strings.TrimSpace(fmt.Sprint(roles)))
+	}
+}
+
+// parseUUIDParam mem-parse path parameter berformat UUID
+func parseUUIDParam(c *fiber.Ctx, name string) (uuid.UUID, error) {
+	return uuid.Parse(c.Params(name))
+}
+
+// currentCourseRole mengambil role user di course yang diset CourseAccess middleware
+func currentCourseRole(c *fiber.Ctx) string {
+	role, _ := c.Locals("course_role").(string)
+	return role
+}
+
+// parseUUIDParams mem-parse beberapa path parameter sekaligus, error menyebut parameter yang salah
+func parseUUIDParams(c *fiber.Ctx, names ...string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(names))
+	for _, name := range names {
+		id, err := uuid.Parse(c.Params(name))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid %s format", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
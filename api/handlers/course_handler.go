@@ -0,0 +1,533 @@
+package handlers
+
+import (
+	"api-shiners/api/handlers/dto"
+	"api-shiners/pkg/course"
+	"api-shiners/pkg/utils"
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type CourseController struct {
+	courseService course.CourseService
+}
+
+func NewCourseController(courseService course.CourseService) *CourseController {
+	return &CourseController{courseService: courseService}
+}
+
+func courseError(c *fiber.Ctx, err error) error {
+	var mismatch *course.OrderMismatchError
+	if errors.As(err, &mismatch) {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "ValidationError", orderFieldErrors(mismatch))
+	}
+
+	switch {
+	case errors.Is(err, course.ErrCourseNotFound), errors.Is(err, course.ErrModuleNotFound):
+		return utils.Error(c, http.StatusNotFound, err.Error(), "NotFoundException", nil)
+	case errors.Is(err, course.ErrForbidden):
+		return utils.Error(c, http.StatusForbidden, err.Error(), "ForbiddenException", nil)
+	case errors.Is(err, course.ErrCourseCodeTaken):
+		return utils.Error(c, http.StatusConflict, err.Error(), "ConflictException", nil)
+	case errors.Is(err, course.ErrCourseRequired), errors.Is(err, course.ErrTitleRequired), errors.Is(err, course.ErrInvalidOrder):
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "BadRequestException", nil)
+	default:
+		// error database dan sejenisnya tidak diteruskan ke client
+		return utils.Error(c, http.StatusInternalServerError, "Internal server error", "InternalServerError", nil)
+	}
+}
+
+func orderFieldErrors(mismatch *course.OrderMismatchError) []utils.FieldError {
+	var fieldErrors []utils.FieldError
+	add := func(message string, ids []uuid.UUID) {
+		if len(ids) == 0 {
+			return
+		}
+		messages := make([]string, 0, len(ids))
+		for _, id := range ids {
+			messages = append(messages, id.String())
+		}
+		fieldErrors = append(fieldErrors, utils.FieldError{Field: "ids", Message: message, Messages: messages})
+	}
+
+	add("missing IDs", mismatch.Missing)
+	add("IDs that do not belong to this parent", mismatch.Foreign)
+	add("duplicated IDs", mismatch.Duplicate)
+	return fieldErrors
+}
+
+func parseUUIDList(values []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(values))
+	for _, v := range values {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func parseOptionalUUID(value *string) (*uuid.UUID, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(*value)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// CreateCourse godoc
+// @Summary Create course
+// @Description Teacher membuat course baru (menjadi owner), admin dapat menentukan owner teacher
+// @Tags Courses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateCourseRequest true "Course payload"
+// @Success 201 {object} entities.Course
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Router /api/courses [post]
+func (ctrl *CourseController) CreateCourse(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	var req dto.CreateCourseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+
+	ownerID, err := parseOptionalUUID(req.OwnerTeacherID)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid owner teacher ID", "InvalidUUID", nil)
+	}
+
+	created, err := ctrl.courseService.CreateCourse(context.Background(), userID, currentUserRole(c), course.CourseInput{
+		Code:           req.Code,
+		Title:          req.Title,
+		Description:    &req.Description,
+		OwnerTeacherID: ownerID,
+	})
+	if err != nil {
+		return courseError(c, err)
+	}
+
+	return utils.Success(c, http.StatusCreated, "Course created successfully", created, nil)
+}
+
+// maxCoursesPerPage membatasi per_page agar satu request tidak memuat seluruh tabel
+const maxCoursesPerPage = 100
+
+// GetAllCourses godoc
+// @Summary Get courses
+// @Description Admin melihat semua course, teacher melihat course miliknya, student melihat course yang sudah dipublish
+// @Tags Courses
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number"
+// @Param per_page query int false "Items per page (max 100)"
+// @Success 200 {object} utils.SuccessResponse{data=[]entities.Course}
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/courses [get]
+func (ctrl *CourseController) GetAllCourses(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	page := c.QueryInt("page", 1)
+	perPage := c.QueryInt("per_page", 10)
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = 10
+	}
+	if perPage > maxCoursesPerPage {
+		perPage = maxCoursesPerPage
+	}
+
+	courses, total, err := ctrl.courseService.GetAllCourses(context.Background(), userID, currentUserRole(c), page, perPage)
+	if err != nil {
+		return courseError(c, err)
+	}
+
+	meta := &utils.Meta{Page: page, PerPage: perPage, Total: int(total)}
+	return utils.Success(c, http.StatusOK, "Get courses successfully", courses, meta)
+}
+
+// GetCourseByID godoc
+// @Summary Get course by ID
+// @Description Menampilkan detail course beserta modulnya
+// @Tags Courses
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Success 200 {object} entities.Course
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id} [get]
+func (ctrl *CourseController) GetCourseByID(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	courseID, err := parseUUIDParam(c, "course_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+
+	found, err := ctrl.courseService.GetCourseByID(context.Background(), userID, currentUserRole(c), courseID)
+	if err != nil {
+		return courseError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Get course successfully", found, nil)
+}
+
+// UpdateCourse godoc
+// @Summary Update course
+// @Description Mengubah data course (owner teacher atau admin)
+// @Tags Courses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param request body dto.UpdateCourseRequest true "Course payload"
+// @Success 200 {object} entities.Course
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id} [put]
+func (ctrl *CourseController) UpdateCourse(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	courseID, err := parseUUIDParam(c, "course_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+
+	var req dto.UpdateCourseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+
+	ownerID, err := parseOptionalUUID(req.OwnerTeacherID)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid owner teacher ID", "InvalidUUID", nil)
+	}
+
+	updated, err := ctrl.courseService.UpdateCourse(context.Background(), userID, currentUserRole(c), courseID, course.CourseInput{
+		Code:           req.Code,
+		Title:          req.Title,
+		Description:    req.Description,
+		OwnerTeacherID: ownerID,
+	})
+	if err != nil {
+		return courseError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Course updated successfully", updated, nil)
+}
+
+// DeleteCourse godoc
+// @Summary Delete course
+// @Description Menghapus course beserta modulnya (owner teacher atau admin)
+// @Tags Courses
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Success 200 {object} utils.SuccessResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id} [delete]
+func (ctrl *CourseController) DeleteCourse(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	courseID, err := parseUUIDParam(c, "course_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+
+	if err := ctrl.courseService.DeleteCourse(context.Background(), userID, currentUserRole(c), courseID); err != nil {
+		return courseError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Course deleted successfully", nil, nil)
+}
+
+// PublishCourse godoc
+// @Summary Publish course
+// @Description Menampilkan course ke student
+// @Tags Courses
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Success 200 {object} entities.Course
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/publish [post]
+func (ctrl *CourseController) PublishCourse(c *fiber.Ctx) error {
+	return ctrl.setPublished(c, true, "Course published successfully")
+}
+
+// UnpublishCourse godoc
+// @Summary Unpublish course
+// @Description Menyembunyikan course dari student
+// @Tags Courses
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Success 200 {object} entities.Course
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/unpublish [post]
+func (ctrl *CourseController) UnpublishCourse(c *fiber.Ctx) error {
+	return ctrl.setPublished(c, false, "Course unpublished successfully")
+}
+
+func (ctrl *CourseController) setPublished(c *fiber.Ctx, published bool, message string) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	courseID, err := parseUUIDParam(c, "course_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+
+	updated, err := ctrl.courseService.SetPublished(context.Background(), userID, currentUserRole(c), courseID, published)
+	if err != nil {
+		return courseError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, message, updated, nil)
+}
+
+// GetModules godoc
+// @Summary Get course modules
+// @Description Menampilkan daftar modul course berdasarkan urutan
+// @Tags Courses
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Success 200 {object} utils.SuccessResponse{data=[]entities.CourseModule}
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/modules [get]
+func (ctrl *CourseController) GetModules(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	courseID, err := parseUUIDParam(c, "course_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+
+	modules, err := ctrl.courseService.GetModules(context.Background(), userID, currentUserRole(c), courseID)
+	if err != nil {
+		return courseError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Get modules successfully", modules, nil)
+}
+
+// CreateModule godoc
+// @Summary Create course module
+// @Description Menambahkan modul baru di urutan terakhir course
+// @Tags Courses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param request body dto.ModuleRequest true "Module payload"
+// @Success 201 {object} entities.CourseModule
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/modules [post]
+func (ctrl *CourseController) CreateModule(c *fiber.Ctx) error {
+	courseID, err := parseUUIDParam(c, "course_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+
+	var req dto.ModuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+
+	module, err := ctrl.courseService.CreateModule(context.Background(), currentCourseRole(c), courseID, course.ModuleInput{
+		Title: req.Title,
+	})
+	if err != nil {
+		return courseError(c, err)
+	}
+
+	return utils.Success(c, http.StatusCreated, "Module created successfully", module, nil)
+}
+
+// UpdateModule godoc
+// @Summary Update course module
+// @Description Mengubah judul modul; urutan diubah lewat endpoint reorder
+// @Tags Courses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param module_id path string true "Module ID"
+// @Param request body dto.ModuleRequest true "Module payload"
+// @Success 200 {object} entities.CourseModule
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/modules/{module_id} [put]
+func (ctrl *CourseController) UpdateModule(c *fiber.Ctx) error {
+	courseID, err := parseUUIDParam(c, "course_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+
+	moduleID, err := parseUUIDParam(c, "module_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid module ID format", "InvalidUUID", nil)
+	}
+
+	var req dto.ModuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+
+	module, err := ctrl.courseService.UpdateModule(context.Background(), currentCourseRole(c), courseID, moduleID, course.ModuleInput{
+		Title: req.Title,
+	})
+	if err != nil {
+		return courseError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Module updated successfully", module, nil)
+}
+
+// DeleteModule godoc
+// @Summary Delete course module
+// @Description Menghapus modul dari course
+// @Tags Courses
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param module_id path string true "Module ID"
+// @Success 200 {object} utils.SuccessResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/modules/{module_id} [delete]
+func (ctrl *CourseController) DeleteModule(c *fiber.Ctx) error {
+	courseID, err := parseUUIDParam(c, "course_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+
+	moduleID, err := parseUUIDParam(c, "module_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid module ID format", "InvalidUUID", nil)
+	}
+
+	if err := ctrl.courseService.DeleteModule(context.Background(), currentCourseRole(c), courseID, moduleID); err != nil {
+		return courseError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Module deleted successfully", nil, nil)
+}
+
+// ReorderModules godoc
+// @Summary Reorder course modules
+// @Description Menyimpan urutan modul hasil drag-and-drop. Daftar harus berisi seluruh ID modul course tepat satu kali
+// @Tags Courses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param request body dto.ReorderRequest true "Ordered module IDs"
+// @Success 200 {object} utils.SuccessResponse{data=[]entities.CourseModule}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/modules/reorder [put]
+func (ctrl *CourseController) ReorderModules(c *fiber.Ctx) error {
+	courseID, err := parseUUIDParam(c, "course_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+
+	var req dto.ReorderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+
+	ids, err := parseUUIDList(req.IDs)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid module ID format", "InvalidUUID", nil)
+	}
+
+	modules, err := ctrl.courseService.ReorderModules(context.Background(), currentCourseRole(c), courseID, ids)
+	if err != nil {
+		return courseError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Modules reordered successfully", modules, nil)
+}
+
+// ReorderMaterials godoc
+// @Summary Reorder module materials
+// @Description Menyimpan urutan material di dalam modul. Daftar harus berisi seluruh ID material modul tepat satu kali
+// @Tags Courses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param module_id path string true "Module ID"
+// @Param request body dto.ReorderRequest true "Ordered material IDs"
+// @Success 200 {object} utils.SuccessResponse{data=[]entities.Material}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/modules/{module_id}/materials/reorder [put]
+func (ctrl *CourseController) ReorderMaterials(c *fiber.Ctx) error {
+	courseID, err := parseUUIDParam(c, "course_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+
+	moduleID, err := parseUUIDParam(c, "module_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid module ID format", "InvalidUUID", nil)
+	}
+
+	var req dto.ReorderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+
+	ids, err := parseUUIDList(req.IDs)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid material ID format", "InvalidUUID", nil)
+	}
+
+	materials, err := ctrl.courseService.ReorderMaterials(context.Background(), currentCourseRole(c), courseID, moduleID, ids)
+	if err != nil {
+		return courseError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Materials reordered successfully", materials, nil)
+}
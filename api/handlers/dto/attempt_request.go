@@ -0,0 +1,120 @@
+package dto
+
+import "time"
+
+// AnswerRequest: isi sesuai tipe soal — choice_id (single/true-false),
+// choice_ids (multiple), text (short answer/essay) atau number (numeric)
+type AnswerRequest struct {
+	QuestionID string   `json:"question_id" example:"9f1c2b7e-1d2a-4c3b-8e4f-5a6b7c8d9e0f"`
+	ChoiceID   *string  `json:"choice_id,omitempty" example:"0a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"`
+	ChoiceIDs  []string `json:"choice_ids,omitempty"`
+	Text       *string  `json:"text,omitempty" example:"Jakarta"`
+	Number     *float64 `json:"number,omitempty" example:"3.14"`
+}
+
+type SaveAnswersRequest struct {
+	Answers []AnswerRequest `json:"answers"`
+}
+
+// AnswerResponse: is_correct dan points hanya diisi untuk teacher/admin course
+type AnswerResponse struct {
+	ID            string     `json:"id"`
+	QuestionID    string     `json:"question_id"`
+	ChoiceID      *string    `json:"choice_id,omitempty"`
+	ChoiceIDs     []string   `json:"choice_ids,omitempty"`
+	TextAnswer    string     `json:"text_answer,omitempty"`
+	NumericAnswer *float64   `json:"numeric_answer,omitempty"`
+	IsCorrect     *bool      `json:"is_correct,omitempty"`
+	Points        *float64   `json:"points,omitempty"`
+	Comment       string     `json:"comment,omitempty"`
+	GradedAt      *time.Time `json:"graded_at,omitempty"`
+}
+
+type AttemptResponse struct {
+	ID          string     `json:"id"`
+	QuizID      string     `json:"quiz_id"`
+	StudentID   string     `json:"student_id"`
+	StartedAt   time.Time  `json:"started_at"`
+	Deadline    *time.Time `json:"deadline"`
+	SubmittedAt *time.Time `json:"submitted_at"`
+	DurationSec *int       `json:"duration_sec"`
+	// Score dan MaxScore mengikuti score_mode quiz (PERCENTAGE atau POINTS)
+	Score      *float64 `json:"score"`
+	MaxScore   *float64 `json:"max_score"`
+	ScoreMode  string   `json:"score_mode" example:"PERCENTAGE"`
+	Percentage *float64 `json:"percentage"`
+	IsLate     bool     `json:"is_late"`
+	// PendingReview: score belum final karena ada essay yang belum dinilai
+	PendingReview bool             `json:"pending_review"`
+	Answers       []AnswerResponse `json:"answers,omitempty"`
+}
+
+type AttemptDetailResponse struct {
+	Attempt AttemptResponse `json:"attempt"`
+	Quiz    QuizResponse    `json:"quiz"`
+}
+
+// ReleaseResponse menjelaskan status rilis hasil quiz untuk student.
+// release_at hanya terisi untuk kebijakan AFTER_CLOSE.
+type ReleaseResponse struct {
+	Policy    string     `json:"policy" example:"AFTER_CLOSE"`
+	Content   string     `json:"content" example:"CORRECTNESS"`
+	Released  bool       `json:"released"`
+	ReleaseAt *time.Time `json:"release_at"`
+}
+
+type AttemptReviewResponse struct {
+	Attempt AttemptResponse `json:"attempt"`
+	Quiz    QuizResponse    `json:"quiz"`
+	Release ReleaseResponse `json:"release"`
+}
+
+type GradeAnswerRequest struct {
+	Points  float64 `json:"points" example:"0.75"`
+	Comment string  `json:"comment" example:"Argumen sudah baik, kurang contoh."`
+}
+
+// GradingQueueItem adalah jawaban essay yang menunggu penilaian teacher
+type GradingQueueItem struct {
+	AnswerID     string     `json:"answer_id"`
+	AttemptID    string     `json:"attempt_id"`
+	StudentID    string     `json:"student_id"`
+	StudentName  string     `json:"student_name"`
+	QuestionID   string     `json:"question_id"`
+	QuestionText string     `json:"question_text"`
+	TextAnswer   string     `json:"text_answer"`
+	MaxPoints    float64    `json:"max_points"`
+	SubmittedAt  *time.Time `json:"submitted_at"`
+}
+
+// RegradeRequest: question_id kosong berarti seluruh soal quiz dinilai ulang.
+// dry_run true hanya menampilkan pratinjau perubahan nilai.
+type RegradeRequest struct {
+	QuestionID *string `json:"question_id,omitempty" example:"9f1c2b7e-1d2a-4c3b-8e4f-5a6b7c8d9e0f"`
+	Reason     string  `json:"reason" example:"Kunci jawaban soal 3 salah"`
+	DryRun     bool    `json:"dry_run" example:"true"`
+}
+
+type RegradeChangeResponse struct {
+	AttemptID      string  `json:"attempt_id"`
+	StudentID      string  `json:"student_id"`
+	OldScore       float64 `json:"old_score"`
+	NewScore       float64 `json:"new_score"`
+	OldPoints      float64 `json:"old_points"`
+	NewPoints      float64 `json:"new_points"`
+	AnswersChanged int     `json:"answers_changed"`
+}
+
+// RegradeResponse: id kosong untuk dry run (belum dicatat)
+type RegradeResponse struct {
+	ID              string                  `json:"id,omitempty"`
+	QuizID          string                  `json:"quiz_id"`
+	QuestionID      *string                 `json:"question_id"`
+	RegradedByID    string                  `json:"regraded_by_id"`
+	Reason          string                  `json:"reason"`
+	DryRun          bool                    `json:"dry_run"`
+	AttemptsChecked int                     `json:"attempts_checked"`
+	AttemptsChanged int                     `json:"attempts_changed"`
+	Changes         []RegradeChangeResponse `json:"changes"`
+	CreatedAt       *time.Time              `json:"created_at,omitempty"`
+}
@@ -0,0 +1,23 @@
+package dto
+
+type CreateCourseRequest struct {
+	Code           string  `json:"code" example:"MTK-10A"`
+	Title          string  `json:"title" example:"Matematika Kelas 10A"`
+	Description    string  `json:"description" example:"Materi matematika semester ganjil"`
+	OwnerTeacherID *string `json:"owner_teacher_id,omitempty" example:"b7dfe843-4297-4d13-b666-d865df01ecbc"`
+}
+
+type UpdateCourseRequest struct {
+	Code           string  `json:"code" example:"MTK-10A"`
+	Title          string  `json:"title" example:"Matematika Kelas 10A"`
+	Description    *string `json:"description,omitempty" example:"Materi matematika semester ganjil"`
+	OwnerTeacherID *string `json:"owner_teacher_id,omitempty" example:"b7dfe843-4297-4d13-b666-d865df01ecbc"`
+}
+
+type ModuleRequest struct {
+	Title string `json:"title" example:"Bab 1 - Persamaan Linear"`
+}
+
+type ReorderRequest struct {
+	IDs []string `json:"ids" example:"9f1c2b7e-1d2a-4c3b-8e4f-5a6b7c8d9e0f,0a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"`
+}
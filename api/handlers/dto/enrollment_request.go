@@ -0,0 +1,34 @@
+package dto
+
+import "time"
+
+type EnrollRequest struct {
+	UserID       string `json:"user_id" example:"aa5bada7-1063-4817-b31d-3a62f233e20f"`
+	RoleInCourse string `json:"role_in_course" example:"STUDENT"`
+}
+
+type JoinCourseRequest struct {
+	Code string `json:"code" example:"K7PX2QMA"`
+}
+
+type JoinCodeResponse struct {
+	CourseID string `json:"course_id" example:"9f1c2b7e-1d2a-4c3b-8e4f-5a6b7c8d9e0f"`
+	JoinCode string `json:"join_code" example:"K7PX2QMA"`
+}
+
+type EnrollmentCourseResponse struct {
+	ID          string `json:"id"`
+	Code        string `json:"code"`
+	Title       string `json:"title"`
+	IsPublished bool   `json:"is_published"`
+}
+
+type EnrollmentResponse struct {
+	ID           string                    `json:"id"`
+	UserID       string                    `json:"user_id"`
+	CourseID     string                    `json:"course_id"`
+	RoleInCourse string                    `json:"role_in_course" example:"STUDENT"`
+	EnrolledAt   time.Time                 `json:"enrolled_at"`
+	User         *UserResponse             `json:"user,omitempty"`
+	Course       *EnrollmentCourseResponse `json:"course,omitempty"`
+}
@@ -0,0 +1,52 @@
+package dto
+
+import "time"
+
+// AttemptEventRequest: type salah satu dari FOCUS_LOST, FULLSCREEN_EXIT, PASTE, IP_CHANGE.
+// occurred_at kosong atau terlalu jauh dari jam server diganti waktu server.
+type AttemptEventRequest struct {
+	Type       string     `json:"type" example:"FOCUS_LOST"`
+	Detail     string     `json:"detail" example:"Pindah ke tab lain"`
+	OccurredAt *time.Time `json:"occurred_at,omitempty" example:"2025-01-06T07:15:00Z"`
+}
+
+// RecordEventsRequest menampung maksimal 50 kejadian per request
+type RecordEventsRequest struct {
+	Events []AttemptEventRequest `json:"events"`
+}
+
+type RecordEventsResponse struct {
+	Recorded int `json:"recorded"`
+}
+
+type AttemptEventResponse struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	Detail     string    `json:"detail"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	OccurredAt time.Time `json:"occurred_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// IntegrityFlagResponse: event_type, count dan threshold hanya untuk THRESHOLD_EXCEEDED
+type IntegrityFlagResponse struct {
+	Type      string    `json:"type" example:"THRESHOLD_EXCEEDED"`
+	EventType string    `json:"event_type,omitempty" example:"FOCUS_LOST"`
+	Count     int       `json:"count,omitempty" example:"5"`
+	Threshold int       `json:"threshold,omitempty" example:"3"`
+	Detail    string    `json:"detail,omitempty"`
+	At        time.Time `json:"at"`
+}
+
+// IntegrityTimelineResponse: events hanya diisi pada timeline satu attempt
+type IntegrityTimelineResponse struct {
+	AttemptID   string                  `json:"attempt_id"`
+	StudentID   string                  `json:"student_id"`
+	StartedAt   time.Time               `json:"started_at"`
+	SubmittedAt *time.Time              `json:"submitted_at"`
+	Counts      map[string]int          `json:"counts"`
+	Flagged     bool                    `json:"flagged"`
+	Flags       []IntegrityFlagResponse `json:"flags"`
+	Events      []AttemptEventResponse  `json:"events,omitempty"`
+}
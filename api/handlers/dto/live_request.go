@@ -0,0 +1,43 @@
+package dto
+
+import "time"
+
+// OpenLiveSessionRequest: duration_sec adalah countdown tiap soal (5-120, default 20)
+type OpenLiveSessionRequest struct {
+	DurationSec int `json:"duration_sec" example:"20"`
+}
+
+type JoinLiveSessionRequest struct {
+	PIN string `json:"pin" example:"482913"`
+}
+
+type LivePlayerResponse struct {
+	Rank       int    `json:"rank"`
+	UserID     string `json:"user_id"`
+	Name       string `json:"name"`
+	Score      int    `json:"score"`
+	Correct    int    `json:"correct"`
+	LastPoints int    `json:"last_points"`
+}
+
+// LiveSessionResponse: question_index -1 selama lobby
+type LiveSessionResponse struct {
+	ID             string               `json:"id"`
+	PIN            string               `json:"pin"`
+	QuizID         string               `json:"quiz_id"`
+	Status         string               `json:"status" example:"LOBBY"`
+	QuestionIndex  int                  `json:"question_index"`
+	TotalQuestions int                  `json:"total_questions"`
+	DurationSec    int                  `json:"duration_sec"`
+	QuestionEndsAt *time.Time           `json:"question_ends_at"`
+	CreatedAt      time.Time            `json:"created_at"`
+	Leaderboard    []LivePlayerResponse `json:"leaderboard,omitempty"`
+}
+
+// LiveTicketResponse: buka WebSocket ke /api/live/ws?ticket=<ticket> dalam 1 menit
+type LiveTicketResponse struct {
+	SessionID string `json:"session_id"`
+	QuizID    string `json:"quiz_id"`
+	Status    string `json:"status"`
+	Ticket    string `json:"ticket"`
+}
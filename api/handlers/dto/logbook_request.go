@@ -0,0 +1,117 @@
+package dto
+
+import "time"
+
+// CreateLogBookRequest: tanggal dalam format YYYY-MM-DD, period_end inklusif
+type CreateLogBookRequest struct {
+	PeriodStart string `json:"period_start" example:"2025-01-06"`
+	PeriodEnd   string `json:"period_end" example:"2025-01-31"`
+}
+
+// LogBookEntryRequest: entry_date harus di dalam periode logbook, satu entry per tanggal
+type LogBookEntryRequest struct {
+	EntryDate string `json:"entry_date" example:"2025-01-06"`
+	Content   string `json:"content" example:"Mempelajari konfigurasi jaringan bersama pembimbing"`
+}
+
+type LogBookAttachmentResponse struct {
+	ID          string    `json:"id"`
+	EntryID     string    `json:"entry_id"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type LogBookEntryResponse struct {
+	ID          string                      `json:"id"`
+	LogBookID   string                      `json:"logbook_id"`
+	EntryDate   string                      `json:"entry_date" example:"2025-01-06"`
+	Content     string                      `json:"content"`
+	Attachments []LogBookAttachmentResponse `json:"attachments"`
+	CreatedAt   time.Time                   `json:"created_at"`
+	UpdatedAt   time.Time                   `json:"updated_at"`
+}
+
+// LogBookResponse: entries hanya diisi pada detail logbook
+type LogBookResponse struct {
+	ID          string                 `json:"id"`
+	CourseID    string                 `json:"course_id"`
+	StudentID   string                 `json:"student_id"`
+	Student     *UserResponse          `json:"student,omitempty"`
+	PeriodStart string                 `json:"period_start" example:"2025-01-06"`
+	PeriodEnd   string                 `json:"period_end" example:"2025-01-31"`
+	Status      string                 `json:"status" example:"DRAFT"`
+	SubmittedAt *time.Time             `json:"submitted_at"`
+	LockedAt    *time.Time             `json:"locked_at"`
+	CreatedAt   time.Time              `json:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+	Entries     []LogBookEntryResponse `json:"entries,omitempty"`
+}
+
+// LogBookReviewRequest: reason wajib untuk permintaan revisi, opsional (catatan) untuk approve
+type LogBookReviewRequest struct {
+	Reason string `json:"reason" example:"Lengkapi uraian kegiatan tanggal 8 Januari"`
+}
+
+// LogBookCommentRequest: entry_id kosong berarti komentar untuk logbook secara keseluruhan
+type LogBookCommentRequest struct {
+	EntryID *string `json:"entry_id,omitempty"`
+	Body    string  `json:"body" example:"Uraian sudah baik, tambahkan hasil pengamatan"`
+}
+
+type LogBookCommentResponse struct {
+	ID        string        `json:"id"`
+	LogBookID string        `json:"logbook_id"`
+	EntryID   *string       `json:"entry_id"`
+	AuthorID  string        `json:"author_id"`
+	Author    *UserResponse `json:"author,omitempty"`
+	Body      string        `json:"body"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// LogBookReviewResponse adalah satu baris riwayat: siapa melakukan apa dan kapan.
+// actor_id null berarti dilakukan job terjadwal (AUTO_SUBMITTED/AUTO_LOCKED).
+type LogBookReviewResponse struct {
+	ID         string        `json:"id"`
+	Action     string        `json:"action" example:"REVISION_REQUESTED"`
+	FromStatus string        `json:"from_status" example:"SUBMITTED"`
+	ToStatus   string        `json:"to_status" example:"DRAFT"`
+	Reason     string        `json:"reason,omitempty"`
+	ActorID    *string       `json:"actor_id"`
+	Actor      *UserResponse `json:"actor,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+// LogBookScheduleRequest: tanggal dalam format YYYY-MM-DD. Field kosong memakai default:
+// WEEKLY, week_start 1 (Senin), AUTO_SUBMIT, grace_days 0, aktif.
+type LogBookScheduleRequest struct {
+	Frequency   *string `json:"frequency,omitempty" example:"WEEKLY"`
+	WeekStart   *int    `json:"week_start,omitempty" example:"1"`
+	StartDate   string  `json:"start_date" example:"2025-01-06"`
+	EndDate     *string `json:"end_date,omitempty" example:"2025-06-29"`
+	CloseAction *string `json:"close_action,omitempty" example:"AUTO_SUBMIT"`
+	GraceDays   *int    `json:"grace_days,omitempty" example:"2"`
+	IsActive    *bool   `json:"is_active,omitempty"`
+}
+
+type LogBookScheduleResponse struct {
+	ID          string    `json:"id"`
+	CourseID    string    `json:"course_id"`
+	Frequency   string    `json:"frequency"`
+	WeekStart   int       `json:"week_start"`
+	StartDate   string    `json:"start_date"`
+	EndDate     *string   `json:"end_date"`
+	CloseAction string    `json:"close_action"`
+	GraceDays   int       `json:"grace_days"`
+	IsActive    bool      `json:"is_active"`
+	CreatedByID string    `json:"created_by_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// SaveLogBookScheduleResponse: generated adalah jumlah logbook periode berjalan yang baru dibuat
+type SaveLogBookScheduleResponse struct {
+	Schedule  LogBookScheduleResponse `json:"schedule"`
+	Generated int                     `json:"generated"`
+}
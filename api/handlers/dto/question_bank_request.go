@@ -0,0 +1,20 @@
+package dto
+
+import "time"
+
+type QuestionBankRequest struct {
+	Title       string `json:"title" example:"Bank Soal Aljabar"`
+	Description string `json:"description" example:"Soal latihan aljabar kelas 7"`
+}
+
+// QuestionBankResponse: course_id kosong berarti bank pribadi teacher
+type QuestionBankResponse struct {
+	ID          string             `json:"id"`
+	OwnerID     string             `json:"owner_id"`
+	CourseID    *string            `json:"course_id"`
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+	Questions   []QuestionResponse `json:"questions,omitempty"`
+}
@@ -0,0 +1,41 @@
+package dto
+
+import "time"
+
+// QuizOverrideRequest: field yang tidak diisi mengikuti pengaturan quiz
+type QuizOverrideRequest struct {
+	TimeLimitSec   *int       `json:"time_limit_sec,omitempty" example:"2700"`
+	AttemptAllowed *int       `json:"attempt_allowed,omitempty" example:"2"`
+	OpenAt         *time.Time `json:"open_at,omitempty" example:"2025-01-06T07:00:00Z"`
+	CloseAt        *time.Time `json:"close_at,omitempty" example:"2025-01-07T09:00:00Z"`
+	Reason         string     `json:"reason" example:"Akomodasi waktu tambahan"`
+}
+
+type QuizOverrideResponse struct {
+	ID             string        `json:"id"`
+	QuizID         string        `json:"quiz_id"`
+	StudentID      string        `json:"student_id"`
+	Student        *UserResponse `json:"student,omitempty"`
+	TimeLimitSec   *int          `json:"time_limit_sec"`
+	AttemptAllowed *int          `json:"attempt_allowed"`
+	OpenAt         *time.Time    `json:"open_at"`
+	CloseAt        *time.Time    `json:"close_at"`
+	Reason         string        `json:"reason"`
+	CreatedByID    string        `json:"created_by_id"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+}
+
+// RosterEntryResponse: time_limit_sec, attempt_allowed, open_at dan close_at
+// adalah pengaturan yang berlaku untuk student (setelah override)
+type RosterEntryResponse struct {
+	Student         UserResponse          `json:"student"`
+	Override        *QuizOverrideResponse `json:"override"`
+	TimeLimitSec    *int                  `json:"time_limit_sec"`
+	AttemptAllowed  int                   `json:"attempt_allowed"`
+	OpenAt          *time.Time            `json:"open_at"`
+	CloseAt         *time.Time            `json:"close_at"`
+	AttemptsUsed    int                   `json:"attempts_used"`
+	InProgress      bool                  `json:"in_progress"`
+	BestScore       *float64              `json:"best_score"`
+	LastSubmittedAt *time.Time            `json:"last_submitted_at"`
+}
@@ -0,0 +1,135 @@
+package dto
+
+import "time"
+
+type QuizRequest struct {
+	Title          string     `json:"title" example:"Kuis Bab 1"`
+	Instructions   string     `json:"instructions" example:"Kerjakan dengan jujur"`
+	OpenAt         *time.Time `json:"open_at,omitempty" example:"2025-01-06T07:00:00Z"`
+	CloseAt        *time.Time `json:"close_at,omitempty" example:"2025-01-06T09:00:00Z"`
+	TimeLimitSec   *int       `json:"time_limit_sec,omitempty" example:"1800"`
+	AttemptAllowed *int       `json:"attempt_allowed,omitempty" example:"1"`
+
+	ShuffleQuestions *bool `json:"shuffle_questions,omitempty" example:"true"`
+	ShuffleChoices   *bool `json:"shuffle_choices,omitempty" example:"true"`
+
+	// Kebijakan penilaian: negative_marking 0-1 (proporsi nilai soal yang dikurangi untuk
+	// pilihan salah), multi_select_scoring PARTIAL/ALL_OR_NOTHING, score_mode PERCENTAGE/POINTS
+	NegativeMarking    *float64 `json:"negative_marking,omitempty" example:"0.25"`
+	AllowNegativeScore *bool    `json:"allow_negative_score,omitempty" example:"false"`
+	MultiSelectScoring *string  `json:"multi_select_scoring,omitempty" example:"PARTIAL"`
+	ScoreMode          *string  `json:"score_mode,omitempty" example:"PERCENTAGE"`
+
+	// Rilis hasil ke student: release_policy IMMEDIATE/AFTER_CLOSE/MANUAL,
+	// release_content SCORE/CORRECTNESS/FULL (FULL termasuk kunci jawaban)
+	ReleasePolicy  *string `json:"release_policy,omitempty" example:"AFTER_CLOSE"`
+	ReleaseContent *string `json:"release_content,omitempty" example:"CORRECTNESS"`
+
+	// Batas kejadian integritas per attempt sebelum ditandai; kosong berarti tidak ditandai
+	MaxFocusLost      *int `json:"max_focus_lost,omitempty" example:"3"`
+	MaxFullscreenExit *int `json:"max_fullscreen_exit,omitempty" example:"2"`
+	MaxPaste          *int `json:"max_paste,omitempty" example:"0"`
+}
+
+type ChoiceRequest struct {
+	Text      string `json:"text" example:"x = 2"`
+	IsCorrect bool   `json:"is_correct" example:"true"`
+	Position  *int   `json:"position,omitempty" example:"1"`
+}
+
+// QuestionRequest: type salah satu dari SINGLE_CHOICE, MULTIPLE_CHOICE, TRUE_FALSE,
+// SHORT_ANSWER, NUMERIC, ESSAY
+type QuestionRequest struct {
+	Type             string          `json:"type" example:"SINGLE_CHOICE"`
+	Text             string          `json:"text" example:"Berapakah nilai x jika 2x + 4 = 8?"`
+	Position         *int            `json:"position,omitempty" example:"1"`
+	Points           *float64        `json:"points,omitempty" example:"2"`
+	Tags             []string        `json:"tags,omitempty" example:"aljabar,persamaan"`
+	Choices          []ChoiceRequest `json:"choices,omitempty"`
+	AcceptedAnswers  []string        `json:"accepted_answers,omitempty" example:"Jakarta,DKI Jakarta"`
+	AnswerPattern    string          `json:"answer_pattern,omitempty" example:"(dki )?jakarta"`
+	NumericAnswer    *float64        `json:"numeric_answer,omitempty" example:"3.14"`
+	NumericTolerance *float64        `json:"numeric_tolerance,omitempty" example:"0.01"`
+}
+
+// ChoiceResponse: is_correct hanya diisi untuk teacher/admin course
+type ChoiceResponse struct {
+	ID         string `json:"id"`
+	QuestionID string `json:"question_id"`
+	Text       string `json:"text"`
+	Position   *int   `json:"position"`
+	IsCorrect  *bool  `json:"is_correct,omitempty"`
+}
+
+// QuestionResponse: field kunci jawaban dan tags hanya diisi untuk teacher/admin course
+type QuestionResponse struct {
+	ID               string           `json:"id"`
+	QuizID           string           `json:"quiz_id,omitempty"`
+	BankID           string           `json:"bank_id,omitempty"`
+	Type             string           `json:"type" example:"SINGLE_CHOICE"`
+	Text             string           `json:"text"`
+	Position         *int             `json:"position"`
+	Points           float64          `json:"points"`
+	Choices          []ChoiceResponse `json:"choices"`
+	AcceptedAnswers  []string         `json:"accepted_answers,omitempty"`
+	AnswerPattern    string           `json:"answer_pattern,omitempty"`
+	NumericAnswer    *float64         `json:"numeric_answer,omitempty"`
+	NumericTolerance *float64         `json:"numeric_tolerance,omitempty"`
+	Tags             []string         `json:"tags,omitempty"`
+}
+
+// QuizPoolRequest: tags kosong berarti semua soal di bank
+type QuizPoolRequest struct {
+	BankID    string   `json:"bank_id" example:"9f1c2b7e-1d2a-4c3b-8e4f-5a6b7c8d9e0f"`
+	Tags      []string `json:"tags,omitempty" example:"aljabar"`
+	DrawCount int      `json:"draw_count" example:"5"`
+	Position  *int     `json:"position,omitempty" example:"1"`
+}
+
+type QuizPoolResponse struct {
+	ID        string   `json:"id"`
+	QuizID    string   `json:"quiz_id"`
+	BankID    string   `json:"bank_id"`
+	Tags      []string `json:"tags"`
+	DrawCount int      `json:"draw_count"`
+	Position  *int     `json:"position"`
+}
+
+type QuizResponse struct {
+	ID             string             `json:"id"`
+	ModuleID       string             `json:"module_id"`
+	Title          string             `json:"title"`
+	Instructions   string             `json:"instructions"`
+	OpenAt         *time.Time         `json:"open_at"`
+	CloseAt        *time.Time         `json:"close_at"`
+	TimeLimitSec   *int               `json:"time_limit_sec"`
+	AttemptAllowed int                `json:"attempt_allowed"`
+	IsPublished    bool               `json:"is_published"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+	Questions      []QuestionResponse `json:"questions,omitempty"`
+
+	ShuffleQuestions bool               `json:"shuffle_questions"`
+	ShuffleChoices   bool               `json:"shuffle_choices"`
+	Pools            []QuizPoolResponse `json:"pools,omitempty"` // hanya untuk teacher/admin course
+
+	NegativeMarking    float64 `json:"negative_marking"`
+	AllowNegativeScore bool    `json:"allow_negative_score"`
+	MultiSelectScoring string  `json:"multi_select_scoring"`
+	ScoreMode          string  `json:"score_mode"`
+
+	ReleasePolicy     string     `json:"release_policy"`
+	ReleaseContent    string     `json:"release_content"`
+	ResultsReleasedAt *time.Time `json:"results_released_at"`
+
+	MaxFocusLost      *int `json:"max_focus_lost"`
+	MaxFullscreenExit *int `json:"max_fullscreen_exit"`
+	MaxPaste          *int `json:"max_paste"`
+}
+
+// ImportQuestionsResponse: pada dry run soal belum disimpan sehingga id kosong
+type ImportQuestionsResponse struct {
+	DryRun    bool               `json:"dry_run"`
+	Count     int                `json:"count"`
+	Questions []QuestionResponse `json:"questions"`
+}
@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"api-shiners/api/handlers/dto"
+	"api-shiners/pkg/course"
+	"api-shiners/pkg/enrollment"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/utils"
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type EnrollmentController struct {
+	enrollmentService enrollment.EnrollmentService
+}
+
+func NewEnrollmentController(enrollmentService enrollment.EnrollmentService) *EnrollmentController {
+	return &EnrollmentController{enrollmentService: enrollmentService}
+}
+
+func enrollmentError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, enrollment.ErrAlreadyEnrolled):
+		return utils.Error(c, http.StatusConflict, err.Error(), "ConflictException", nil)
+	case errors.Is(err, enrollment.ErrEnrollmentNotFound), errors.Is(err, enrollment.ErrUserNotFound), errors.Is(err, enrollment.ErrInvalidJoinCode):
+		return utils.Error(c, http.StatusNotFound, err.Error(), "NotFoundException", nil)
+	case errors.Is(err, enrollment.ErrInvalidCourseRole), errors.Is(err, enrollment.ErrTooManyRows), errors.Is(err, enrollment.ErrInvalidCSV):
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "BadRequestException", nil)
+	case errors.Is(err, course.ErrCourseNotFound), errors.Is(err, course.ErrForbidden):
+		return courseError(c, err)
+	default:
+		// error database dan sejenisnya tidak diteruskan ke client
+		return utils.Error(c, http.StatusInternalServerError, "Internal server error", "InternalServerError", nil)
+	}
+}
+
+func toEnrollmentResponse(e entities.Enrollment) dto.EnrollmentResponse {
+	resp := dto.EnrollmentResponse{
+		ID:           e.ID.String(),
+		UserID:       e.UserID.String(),
+		CourseID:     e.CourseID.String(),
+		RoleInCourse: string(e.RoleInCourse),
+		EnrolledAt:   e.EnrolledAt,
+	}
+	if e.User.ID != uuid.Nil {
+		resp.User = &dto.UserResponse{
+			ID:       e.User.ID.String(),
+			Name:     e.User.Name,
+			Email:    e.User.Email,
+			IsActive: e.User.IsActive,
+		}
+	}
+	if e.Course.ID != uuid.Nil {
+		resp.Course = &dto.EnrollmentCourseResponse{
+			ID:          e.Course.ID.String(),
+			Code:        e.Course.Code,
+			Title:       e.Course.Title,
+			IsPublished: e.Course.IsPublished,
+		}
+	}
+	return resp
+}
+
+func toEnrollmentResponses(enrollments []entities.Enrollment) []dto.EnrollmentResponse {
+	resp := make([]dto.EnrollmentResponse, 0, len(enrollments))
+	for _, e := range enrollments {
+		resp = append(resp, toEnrollmentResponse(e))
+	}
+	return resp
+}
+
+// GetEnrollments godoc
+// @Summary Get course enrollments
+// @Description Menampilkan daftar peserta course (teacher pemilik atau admin)
+// @Tags Enrollments
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Success 200 {object} utils.SuccessResponse{data=[]dto.EnrollmentResponse}
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/enrollments [get]
+func (ctrl *EnrollmentController) GetEnrollments(c *fiber.Ctx) error {
+	courseID, err := parseUUIDParam(c, "course_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+
+	enrollments, err := ctrl.enrollmentService.GetEnrollments(context.Background(), currentCourseRole(c), courseID)
+	if err != nil {
+		return enrollmentError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Get enrollments successfully", toEnrollmentResponses(enrollments), nil)
+}
+
+// GetMyEnrollments godoc
+// @Summary Get my enrollments
+// @Description Menampilkan course yang diikuti user yang sedang login
+// @Tags Enrollments
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.SuccessResponse{data=[]dto.EnrollmentResponse}
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /api/enrollments/me [get]
+func (ctrl *EnrollmentController) GetMyEnrollments(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	enrollments, err := ctrl.enrollmentService.GetMyEnrollments(context.Background(), userID)
+	if err != nil {
+		return enrollmentError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Get my enrollments successfully", toEnrollmentResponses(enrollments), nil)
+}
+
+// Enroll godoc
+// @Summary Enroll user
+// @Description Mendaftarkan user ke course sebagai STUDENT atau TEACHER
+// @Tags Enrollments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param request body dto.EnrollRequest true "Enrollment payload"
+// @Success 201 {object} dto.EnrollmentResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/enrollments [post]
+func (ctrl *EnrollmentController) Enroll(c *fiber.Ctx) error {
+	courseID, err := parseUUIDParam(c, "course_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+
+	var req dto.EnrollRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+
+	targetUserID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid user ID format", "InvalidUUID", nil)
+	}
+
+	created, err := ctrl.enrollmentService.Enroll(context.Background(), currentCourseRole(c), courseID, targetUserID, entities.CourseRole(req.RoleInCourse))
+	if err != nil {
+		return enrollmentError(c, err)
+	}
+
+	return utils.Success(c, http.StatusCreated, "User enrolled successfully", toEnrollmentResponse(*created), nil)
+}
+
+// Unenroll godoc
+// @Summary Unenroll user
+// @Description Mengeluarkan user dari course
+// @Tags Enrollments
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param user_id path string true "User ID"
+// @Success 200 {object} utils.SuccessResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/enrollments/{user_id} [delete]
+func (ctrl *EnrollmentController) Unenroll(c *fiber.Ctx) error {
+	courseID, err := parseUUIDParam(c, "course_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+
+	targetUserID, err := parseUUIDParam(c, "user_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid user ID format", "InvalidUUID", nil)
+	}
+
+	if err := ctrl.enrollmentService.Unenroll(context.Background(), currentCourseRole(c), courseID, targetUserID); err != nil {
+		return enrollmentError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "User unenrolled successfully", nil, nil)
+}
+
+// BulkEnroll godoc
+// @Summary Bulk enroll from CSV
+// @Description Upload CSV berisi kolom email (opsional role_in_course). Hasil dilaporkan per baris
+// @Tags Enrollments
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param file formData file true "CSV file"
+// @Success 200 {object} utils.SuccessResponse{data=[]enrollment.BulkEnrollResult}
+// @Failure 400 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/enrollments/bulk [post]
+func (ctrl *EnrollmentController) BulkEnroll(c *fiber.Ctx) error {
+	courseID, err := parseUUIDParam(c, "course_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "CSV file is required", "ValidationError", nil)
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Failed to read uploaded file", "BadRequestException", nil)
+	}
+	defer file.Close()
+
+	results, err := ctrl.enrollmentService.BulkEnroll(context.Background(), currentCourseRole(c), courseID, file)
+	if err != nil {
+		return enrollmentError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Bulk enrollment processed", results, nil)
+}
+
+// GetJoinCode godoc
+// @Summary Get course join code
+// @Description Menampilkan kode join course, dibuat otomatis bila belum ada
+// @Tags Enrollments
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Success 200 {object} dto.JoinCodeResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/join-code [get]
+func (ctrl *EnrollmentController) GetJoinCode(c *fiber.Ctx) error {
+	return ctrl.joinCode(c, false)
+}
+
+// RotateJoinCode godoc
+// @Summary Rotate course join code
+// @Description Mengganti kode join course, kode lama tidak berlaku lagi
+// @Tags Enrollments
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Success 200 {object} dto.JoinCodeResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/join-code/rotate [post]
+func (ctrl *EnrollmentController) RotateJoinCode(c *fiber.Ctx) error {
+	return ctrl.joinCode(c, true)
+}
+
+func (ctrl *EnrollmentController) joinCode(c *fiber.Ctx, rotate bool) error {
+	courseID, err := parseUUIDParam(c, "course_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+
+	var code string
+	if rotate {
+		code, err = ctrl.enrollmentService.RotateJoinCode(context.Background(), currentCourseRole(c), courseID)
+	} else {
+		code, err = ctrl.enrollmentService.GetJoinCode(context.Background(), currentCourseRole(c), courseID)
+	}
+	if err != nil {
+		return enrollmentError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Get join code successfully", dto.JoinCodeResponse{
+		CourseID: courseID.String(),
+		JoinCode: code,
+	}, nil)
+}
+
+// JoinByCode godoc
+// @Summary Join course by code
+// @Description Student mendaftar sendiri ke course yang sudah dipublish menggunakan kode join
+// @Tags Enrollments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.JoinCourseRequest true "Join code"
+// @Success 201 {object} dto.EnrollmentResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Router /api/enrollments/join [post]
+func (ctrl *EnrollmentController) JoinByCode(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	var req dto.JoinCourseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+
+	created, err := ctrl.enrollmentService.JoinByCode(context.Background(), userID, req.Code)
+	if err != nil {
+		return enrollmentError(c, err)
+	}
+
+	return utils.Success(c, http.StatusCreated, "Joined course successfully", toEnrollmentResponse(*created), nil)
+}
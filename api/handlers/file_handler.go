@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"api-shiners/pkg/storage"
+	"api-shiners/pkg/utils"
+	"context"
+	"errors"
+	"mime"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FileController melayani download dari storage lokal lewat URL bertanda tangan
+type FileController struct {
+	store storage.Storage
+}
+
+func NewFileController(store storage.Storage) *FileController {
+	return &FileController{store: store}
+}
+
+// Download godoc
+// @Summary Download file by signed URL
+// @Description Mengunduh file storage lokal. URL didapat dari endpoint download-url dan hanya berlaku sementara
+// @Tags Files
+// @Produce octet-stream
+// @Param key path string true "File key"
+// @Param expires query int true "Unix timestamp kedaluwarsa"
+// @Param signature query string true "Signature"
+// @Param name query string false "Nama file download (ikut ditandatangani)"
+// @Success 200 {file} file
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/files/{key} [get]
+func (ctrl *FileController) Download(c *fiber.Ctx) error {
+	local, ok := ctrl.store.(*storage.LocalStorage)
+	if !ok {
+		return utils.Error(c, http.StatusNotFound, "File not found", "NotFoundException", nil)
+	}
+
+	key, err := url.PathUnescape(c.Params("*"))
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid file key", "BadRequestException", nil)
+	}
+
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		return utils.Error(c, http.StatusForbidden, storage.ErrInvalidSignature.Error(), "ForbiddenException", nil)
+	}
+
+	name := c.Query("name")
+	if err := local.Verify(key, name, expires, c.Query("signature")); err != nil {
+		return utils.Error(c, http.StatusForbidden, storage.ErrInvalidSignature.Error(), "ForbiddenException", nil)
+	}
+
+	file, err := local.Open(context.Background(), key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return utils.Error(c, http.StatusNotFound, "File not found", "NotFoundException", nil)
+		}
+		return utils.Error(c, http.StatusInternalServerError, "Failed to read file", "InternalServerError", nil)
+	}
+
+	if name == "" {
+		name = filepath.Base(key)
+	}
+	// content type mengikuti file yang tersimpan, bukan nama download
+	if contentType := mime.TypeByExtension(filepath.Ext(key)); contentType != "" {
+		c.Set(fiber.HeaderContentType, contentType)
+	} else {
+		c.Set(fiber.HeaderContentType, fiber.MIMEOctetStream)
+	}
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+
+	// fasthttp menutup file setelah stream selesai dikirim
+	return c.Status(http.StatusOK).SendStream(file)
+}
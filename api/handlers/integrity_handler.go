@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"api-shiners/api/handlers/dto"
+	"api-shiners/pkg/attempt"
+	"api-shiners/pkg/utils"
+	"context"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type IntegrityController struct {
+	integrityService attempt.IntegrityService
+}
+
+func NewIntegrityController(integrityService attempt.IntegrityService) *IntegrityController {
+	return &IntegrityController{integrityService: integrityService}
+}
+
+func toIntegrityTimelineResponse(t attempt.Timeline) dto.IntegrityTimelineResponse {
+	resp := dto.IntegrityTimelineResponse{
+		AttemptID:   t.Attempt.ID.String(),
+		StudentID:   t.Attempt.StudentID.String(),
+		StartedAt:   t.Attempt.StartedAt,
+		SubmittedAt: t.Attempt.SubmittedAt,
+		Counts:      make(map[string]int, len(t.Counts)),
+		Flagged:     len(t.Flags) > 0,
+		Flags:       make([]dto.IntegrityFlagResponse, 0, len(t.Flags)),
+	}
+	for eventType, count := range t.Counts {
+		resp.Counts[string(eventType)] = count
+	}
+	for _, f := range t.Flags {
+		resp.Flags = append(resp.Flags, dto.IntegrityFlagResponse{
+			Type:      string(f.Type),
+			EventType: string(f.EventType),
+			Count:     f.Count,
+			Threshold: f.Threshold,
+			Detail:    f.Detail,
+			At:        f.At,
+		})
+	}
+	for _, e := range t.Events {
+		resp.Events = append(resp.Events, dto.AttemptEventResponse{
+			ID:         e.ID.String(),
+			Type:       string(e.Type),
+			Detail:     e.Detail,
+			IPAddress:  e.IPAddress,
+			UserAgent:  e.UserAgent,
+			OccurredAt: e.OccurredAt,
+			CreatedAt:  e.CreatedAt,
+		})
+	}
+	return resp
+}
+
+// RecordEvents godoc
+// @Summary Record attempt integrity events
+// @Description Mencatat kejadian integritas (fokus hilang, keluar layar penuh, paste, perubahan IP) untuk attempt yang sedang berjalan. Log hanya bisa ditambah; IP dan user agent diambil dari request.
+// @Tags Quiz Integrity
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param attempt_id path string true "Attempt ID"
+// @Param request body dto.RecordEventsRequest true "Events"
+// @Success 201 {object} dto.RecordEventsResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/attempts/{attempt_id}/events [post]
+func (ctrl *IntegrityController) RecordEvents(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id", "attempt_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	var req dto.RecordEventsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+
+	inputs := make([]attempt.EventInput, 0, len(req.Events))
+	for _, e := range req.Events {
+		inputs = append(inputs, attempt.EventInput{Type: e.Type, Detail: e.Detail, OccurredAt: e.OccurredAt})
+	}
+
+	recorded, err := ctrl.integrityService.RecordEvents(context.Background(), userID, ids[0], ids[1], c.IP(), c.Get(fiber.HeaderUserAgent), inputs)
+	if err != nil {
+		return attemptError(c, err)
+	}
+
+	return utils.Success(c, http.StatusCreated, "Events recorded successfully", dto.RecordEventsResponse{Recorded: recorded}, nil)
+}
+
+// GetAttemptTimeline godoc
+// @Summary Get attempt integrity timeline
+// @Description Menampilkan urutan kejadian integritas attempt beserta tanda perubahan IP dan kejadian yang melewati batas quiz (teacher course atau admin)
+// @Tags Quiz Integrity
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param attempt_id path string true "Attempt ID"
+// @Success 200 {object} dto.IntegrityTimelineResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/attempts/{attempt_id}/events [get]
+func (ctrl *IntegrityController) GetAttemptTimeline(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id", "attempt_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	timeline, err := ctrl.integrityService.GetTimeline(context.Background(), currentCourseRole(c), ids[0], ids[1])
+	if err != nil {
+		return attemptError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Get attempt timeline successfully", toIntegrityTimelineResponse(*timeline), nil)
+}
+
+// GetQuizIntegrity godoc
+// @Summary Get quiz integrity summary
+// @Description Menampilkan jumlah kejadian integritas dan tanda untuk setiap attempt quiz (teacher course atau admin)
+// @Tags Quiz Integrity
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Success 200 {object} utils.SuccessResponse{data=[]dto.IntegrityTimelineResponse}
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id}/integrity [get]
+func (ctrl *IntegrityController) GetQuizIntegrity(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id", "quiz_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	summary, _, err := ctrl.integrityService.GetQuizIntegrity(context.Background(), currentCourseRole(c), ids[0], ids[1])
+	if err != nil {
+		return attemptError(c, err)
+	}
+
+	resp := make([]dto.IntegrityTimelineResponse, 0, len(summary))
+	for _, t := range summary {
+		resp = append(resp, toIntegrityTimelineResponse(t))
+	}
+
+	return utils.Success(c, http.StatusOK, "Get quiz integrity successfully", resp, nil)
+}
@@ -0,0 +1,388 @@
+package handlers
+
+import (
+	"api-shiners/api/handlers/dto"
+	"api-shiners/pkg/live"
+	"api-shiners/pkg/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// liveReadLimit membatasi ukuran pesan client WebSocket (byte)
+const liveReadLimit = 4096
+
+type LiveController struct {
+	liveService live.LiveService
+}
+
+func NewLiveController(liveService live.LiveService) *LiveController {
+	return &LiveController{liveService: liveService}
+}
+
+func liveError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, live.ErrSessionNotFound):
+		return utils.Error(c, http.StatusNotFound, err.Error(), "NotFoundException", nil)
+	case errors.Is(err, live.ErrTicketInvalid):
+		return utils.Error(c, http.StatusUnauthorized, err.Error(), "UnauthorizedException", nil)
+	case errors.Is(err, live.ErrNotStudent):
+		return utils.Error(c, http.StatusForbidden, err.Error(), "ForbiddenException", nil)
+	case errors.Is(err, live.ErrSessionFinished), errors.Is(err, live.ErrNoActiveQuestion), errors.Is(err, live.ErrAlreadyAnswered):
+		return utils.Error(c, http.StatusConflict, err.Error(), "ConflictException", nil)
+	case errors.Is(err, live.ErrInvalidDuration), errors.Is(err, live.ErrInvalidLiveAnswer):
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "BadRequestException", nil)
+	case errors.Is(err, live.ErrNoLiveQuestions):
+		return utils.Error(c, http.StatusUnprocessableEntity, err.Error(), "ValidationException", nil)
+	default:
+		return quizError(c, err)
+	}
+}
+
+func toLivePlayerResponses(players []live.Player) []dto.LivePlayerResponse {
+	resp := make([]dto.LivePlayerResponse, 0, len(players))
+	for i, p := range players {
+		resp = append(resp, dto.LivePlayerResponse{
+			Rank:       i + 1,
+			UserID:     p.UserID.String(),
+			Name:       p.Name,
+			Score:      p.Score,
+			Correct:    p.Correct,
+			LastPoints: p.LastPoints,
+		})
+	}
+	return resp
+}
+
+func toLiveSessionResponse(s *live.Session, leaderboard []live.Player) dto.LiveSessionResponse {
+	resp := dto.LiveSessionResponse{
+		ID:             s.ID,
+		PIN:            s.PIN,
+		QuizID:         s.QuizID.String(),
+		Status:         string(s.Status),
+		QuestionIndex:  s.QuestionIndex,
+		TotalQuestions: len(s.Questions),
+		DurationSec:    s.DurationSec,
+		QuestionEndsAt: s.QuestionEndsAt,
+		CreatedAt:      s.CreatedAt,
+	}
+	if leaderboard != nil {
+		resp.Leaderboard = toLivePlayerResponses(leaderboard)
+	}
+	return resp
+}
+
+// OpenSession godoc
+// @Summary Open live quiz session
+// @Description Membuka sesi live quiz (gaya Kahoot) dengan PIN 6 digit. Hanya soal pilihan milik quiz yang dipakai.
+// @Tags Live Quiz
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Param request body dto.OpenLiveSessionRequest false "Session settings"
+// @Success 201 {object} dto.LiveSessionResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 422 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id}/live [post]
+func (ctrl *LiveController) OpenSession(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id", "quiz_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	var req dto.OpenLiveSessionRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+		}
+	}
+
+	session, err := ctrl.liveService.OpenSession(context.Background(), userID, currentCourseRole(c), ids[0], ids[1], req.DurationSec)
+	if err != nil {
+		return liveError(c, err)
+	}
+
+	return utils.Success(c, http.StatusCreated, "Live session opened successfully", toLiveSessionResponse(session, nil), nil)
+}
+
+// GetSession godoc
+// @Summary Get live quiz session
+// @Description Menampilkan state sesi live beserta leaderboard (teacher course atau admin)
+// @Tags Live Quiz
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param session_id path string true "Session ID"
+// @Success 200 {object} dto.LiveSessionResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/live/{session_id} [get]
+func (ctrl *LiveController) GetSession(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id", "session_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	session, leaderboard, err := ctrl.liveService.GetSession(context.Background(), currentCourseRole(c), ids[0], ids[1].String())
+	if err != nil {
+		return liveError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Get live session successfully", toLiveSessionResponse(session, leaderboard), nil)
+}
+
+// NextQuestion godoc
+// @Summary Push next live question
+// @Description Menutup soal yang berjalan lalu mengirim soal berikutnya ke semua student dengan countdown. Setelah soal terakhir sesi selesai.
+// @Tags Live Quiz
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param session_id path string true "Session ID"
+// @Success 200 {object} dto.LiveSessionResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/live/{session_id}/next [post]
+func (ctrl *LiveController) NextQuestion(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id", "session_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	session, err := ctrl.liveService.NextQuestion(context.Background(), currentCourseRole(c), ids[0], ids[1].String())
+	if err != nil {
+		return liveError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Live session advanced successfully", toLiveSessionResponse(session, nil), nil)
+}
+
+// RevealQuestion godoc
+// @Summary Reveal live question
+// @Description Menutup soal sebelum countdown habis lalu mengirim jawaban benar dan leaderboard
+// @Tags Live Quiz
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param session_id path string true "Session ID"
+// @Success 200 {object} dto.LiveSessionResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/live/{session_id}/reveal [post]
+func (ctrl *LiveController) RevealQuestion(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id", "session_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	session, err := ctrl.liveService.RevealQuestion(context.Background(), currentCourseRole(c), ids[0], ids[1].String())
+	if err != nil {
+		return liveError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Live question revealed successfully", toLiveSessionResponse(session, nil), nil)
+}
+
+// EndSession godoc
+// @Summary End live quiz session
+// @Description Mengakhiri sesi live, membebaskan PIN dan mengirim leaderboard akhir
+// @Tags Live Quiz
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param session_id path string true "Session ID"
+// @Success 200 {object} dto.LiveSessionResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/live/{session_id}/end [post]
+func (ctrl *LiveController) EndSession(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id", "session_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	session, leaderboard, err := ctrl.liveService.EndSession(context.Background(), currentCourseRole(c), ids[0], ids[1].String())
+	if err != nil {
+		return liveError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Live session ended successfully", toLiveSessionResponse(session, leaderboard), nil)
+}
+
+// HostTicket godoc
+// @Summary Get host WebSocket ticket
+// @Description Membuat tiket sekali pakai untuk layar teacher membuka WebSocket sesi live
+// @Tags Live Quiz
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param session_id path string true "Session ID"
+// @Success 200 {object} dto.LiveTicketResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/live/{session_id}/ticket [post]
+func (ctrl *LiveController) HostTicket(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id", "session_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	ticket, err := ctrl.liveService.HostTicket(context.Background(), userID, currentCourseRole(c), ids[0], ids[1].String())
+	if err != nil {
+		return liveError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Live ticket created successfully", dto.LiveTicketResponse{SessionID: ids[1].String(), Ticket: ticket}, nil)
+}
+
+// JoinSession godoc
+// @Summary Join live quiz session
+// @Description Student bergabung ke sesi live dengan PIN dan mendapat tiket WebSocket sekali pakai
+// @Tags Live Quiz
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.JoinLiveSessionRequest true "PIN"
+// @Success 200 {object} dto.LiveTicketResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Router /api/live/join [post]
+func (ctrl *LiveController) JoinSession(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	var req dto.JoinLiveSessionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+
+	session, ticket, err := ctrl.liveService.Join(context.Background(), userID, req.PIN)
+	if err != nil {
+		return liveError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Joined live session successfully", dto.LiveTicketResponse{
+		SessionID: session.ID,
+		QuizID:    session.QuizID.String(),
+		Status:    string(session.Status),
+		Ticket:    ticket,
+	}, nil)
+}
+
+// UpgradeStream menukar tiket dengan identitas koneksi sebelum upgrade ke WebSocket
+func (ctrl *LiveController) UpgradeStream(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return fiber.ErrUpgradeRequired
+	}
+	ticket, err := ctrl.liveService.Connect(context.Background(), c.Query("ticket"))
+	if err != nil {
+		return liveError(c, err)
+	}
+	c.Locals("live_ticket", ticket)
+	return c.Next()
+}
+
+// liveMessage adalah pesan client: {"type": "answer", "choice_ids": ["..."]}
+type liveMessage struct {
+	Type      string   `json:"type"`
+	ChoiceIDs []string `json:"choice_ids"`
+}
+
+// Stream mengirim event sesi live ke client dan menerima jawaban student
+func (ctrl *LiveController) Stream(conn *websocket.Conn) {
+	ticket, ok := conn.Locals("live_ticket").(*live.Ticket)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	send := func(event live.Event) error {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		return conn.WriteMessage(websocket.TextMessage, payload)
+	}
+
+	events, unsubscribe, err := ctrl.liveService.Subscribe(ctx, ticket.SessionID)
+	if err != nil {
+		return
+	}
+	defer unsubscribe()
+
+	if snapshot, err := ctrl.liveService.Snapshot(ctx, ticket.SessionID); err == nil {
+		send(*snapshot)
+	}
+
+	go func() {
+		for payload := range events {
+			mu.Lock()
+			err := conn.WriteMessage(websocket.TextMessage, payload)
+			mu.Unlock()
+			if err != nil {
+				conn.Close()
+				return
+			}
+		}
+	}()
+
+	conn.SetReadLimit(liveReadLimit)
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var msg liveMessage
+		if err := json.Unmarshal(data, &msg); err != nil || msg.Type != "answer" || ticket.IsHost {
+			send(live.Event{Type: "error", Data: fiber.Map{"message": "unsupported message"}})
+			continue
+		}
+
+		choiceIDs := make([]uuid.UUID, 0, len(msg.ChoiceIDs))
+		for _, raw := range msg.ChoiceIDs {
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				choiceIDs = nil
+				break
+			}
+			choiceIDs = append(choiceIDs, id)
+		}
+		if choiceIDs == nil {
+			send(live.Event{Type: "error", Data: fiber.Map{"message": "invalid choice id"}})
+			continue
+		}
+
+		answer, err := ctrl.liveService.SubmitAnswer(ctx, ticket.SessionID, ticket.UserID, choiceIDs)
+		if err != nil {
+			send(live.Event{Type: "error", Data: fiber.Map{"message": err.Error()}})
+			continue
+		}
+		// benar/salah baru diumumkan saat reveal
+		send(live.Event{Type: "answer_accepted", Data: fiber.Map{"answered_at": answer.AnsweredAt}})
+	}
+}
@@ -0,0 +1,924 @@
+package handlers
+
+import (
+	"api-shiners/api/handlers/dto"
+	"api-shiners/pkg/document"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/logbook"
+	"api-shiners/pkg/material"
+	"api-shiners/pkg/utils"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// logBookDateLayout adalah format tanggal periode dan entry di request maupun response
+const logBookDateLayout = "2006-01-02"
+
+type LogBookController struct {
+	logBookService logbook.LogBookService
+	// documentHeader adalah kop sekolah untuk ekspor PDF
+	documentHeader document.Header
+}
+
+func NewLogBookController(logBookService logbook.LogBookService, documentHeader document.Header) *LogBookController {
+	return &LogBookController{logBookService: logBookService, documentHeader: documentHeader}
+}
+
+func logBookError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, logbook.ErrLogBookNotFound), errors.Is(err, logbook.ErrEntryNotFound),
+		errors.Is(err, logbook.ErrAttachmentNotFound):
+		return utils.Error(c, http.StatusNotFound, err.Error(), "NotFoundException", nil)
+	case errors.Is(err, logbook.ErrForbidden):
+		return utils.Error(c, http.StatusForbidden, err.Error(), "ForbiddenException", nil)
+	case errors.Is(err, logbook.ErrInvalidTransition), errors.Is(err, logbook.ErrNotEditable),
+		errors.Is(err, logbook.ErrPeriodOverlap), errors.Is(err, logbook.ErrEntryDateTaken),
+		errors.Is(err, logbook.ErrNotSubmittedYet), errors.Is(err, logbook.ErrTooManyAttachments):
+		return utils.Error(c, http.StatusConflict, err.Error(), "ConflictException", nil)
+	case errors.Is(err, material.ErrFileTooLarge):
+		return utils.Error(c, http.StatusRequestEntityTooLarge, err.Error(), "PayloadTooLarge", nil)
+	case errors.Is(err, material.ErrFileTypeNotAllowed):
+		return utils.Error(c, http.StatusUnsupportedMediaType, err.Error(), "UnsupportedMediaType", nil)
+	case errors.Is(err, logbook.ErrInvalidPeriod), errors.Is(err, logbook.ErrPeriodTooLong),
+		errors.Is(err, logbook.ErrEntryOutOfPeriod), errors.Is(err, logbook.ErrEmptyContent),
+		errors.Is(err, logbook.ErrContentTooLong), errors.Is(err, logbook.ErrNoEntries),
+		errors.Is(err, logbook.ErrInvalidStatus), errors.Is(err, logbook.ErrReasonRequired),
+		errors.Is(err, logbook.ErrEmptyComment), errors.Is(err, logbook.ErrCommentTooLong),
+		errors.Is(err, logbook.ErrInvalidRange), errors.Is(err, logbook.ErrRangeTooLong),
+		errors.Is(err, logbook.ErrInvalidFrequency), errors.Is(err, logbook.ErrInvalidWeekStart),
+		errors.Is(err, logbook.ErrInvalidCloseAction), errors.Is(err, logbook.ErrInvalidGraceDays),
+		errors.Is(err, logbook.ErrInvalidScheduleDate), errors.Is(err, material.ErrFileRequired):
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "BadRequestException", nil)
+	default:
+		// error database dan storage tidak diteruskan ke client
+		return utils.Error(c, http.StatusInternalServerError, "Internal server error", "InternalServerError", nil)
+	}
+}
+
+func parseLogBookDate(field, value string) (time.Time, error) {
+	date, err := time.Parse(logBookDateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must use format YYYY-MM-DD", field)
+	}
+	return date, nil
+}
+
+// toLogBookUserResponse mengembalikan nil bila relasi user tidak dimuat
+func toLogBookUserResponse(u entities.User) *dto.UserResponse {
+	if u.ID == uuid.Nil {
+		return nil
+	}
+	return &dto.UserResponse{
+		ID:       u.ID.String(),
+		Name:     u.Name,
+		Email:    u.Email,
+		IsActive: u.IsActive,
+	}
+}
+
+func toLogBookCommentResponse(cm entities.LogBookComment) dto.LogBookCommentResponse {
+	resp := dto.LogBookCommentResponse{
+		ID:        cm.ID.String(),
+		LogBookID: cm.LogBookID.String(),
+		AuthorID:  cm.AuthorID.String(),
+		Author:    toLogBookUserResponse(cm.Author),
+		Body:      cm.Body,
+		CreatedAt: cm.CreatedAt,
+	}
+	if cm.EntryID != nil {
+		entryID := cm.EntryID.String()
+		resp.EntryID = &entryID
+	}
+	return resp
+}
+
+func toLogBookReviewResponse(r entities.LogBookReview) dto.LogBookReviewResponse {
+	resp := dto.LogBookReviewResponse{
+		ID:         r.ID.String(),
+		Action:     string(r.Action),
+		FromStatus: string(r.FromStatus),
+		ToStatus:   string(r.ToStatus),
+		Reason:     r.Reason,
+		Actor:      toLogBookUserResponse(r.Actor),
+		CreatedAt:  r.CreatedAt,
+	}
+	if r.ActorID != nil {
+		actorID := r.ActorID.String()
+		resp.ActorID = &actorID
+	}
+	return resp
+}
+
+func toLogBookAttachmentResponse(a entities.LogBookAttachment) dto.LogBookAttachmentResponse {
+	return dto.LogBookAttachmentResponse{
+		ID:          a.ID.String(),
+		EntryID:     a.EntryID.String(),
+		FileName:    a.FileName,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		CreatedAt:   a.CreatedAt,
+	}
+}
+
+func toLogBookEntryResponse(e entities.LogBookEntry) dto.LogBookEntryResponse {
+	attachments := make([]dto.LogBookAttachmentResponse, 0, len(e.Attachments))
+	for _, a := range e.Attachments {
+		attachments = append(attachments, toLogBookAttachmentResponse(a))
+	}
+	return dto.LogBookEntryResponse{
+		ID:          e.ID.String(),
+		LogBookID:   e.LogBookID.String(),
+		EntryDate:   e.EntryDate.Format(logBookDateLayout),
+		Content:     e.Content,
+		Attachments: attachments,
+		CreatedAt:   e.CreatedAt,
+		UpdatedAt:   e.UpdatedAt,
+	}
+}
+
+func toLogBookResponse(lb entities.LogBook, withEntries bool) dto.LogBookResponse {
+	resp := dto.LogBookResponse{
+		ID:          lb.ID.String(),
+		CourseID:    lb.CourseID.String(),
+		StudentID:   lb.StudentID.String(),
+		Student:     toLogBookUserResponse(lb.Student),
+		PeriodStart: lb.PeriodStart.Format(logBookDateLayout),
+		PeriodEnd:   lb.PeriodEnd.Format(logBookDateLayout),
+		Status:      string(lb.Status),
+		SubmittedAt: lb.SubmittedAt,
+		LockedAt:    lb.LockedAt,
+		CreatedAt:   lb.CreatedAt,
+		UpdatedAt:   lb.UpdatedAt,
+	}
+	if withEntries {
+		resp.Entries = make([]dto.LogBookEntryResponse, 0, len(lb.Entries))
+		for _, e := range lb.Entries {
+			resp.Entries = append(resp.Entries, toLogBookEntryResponse(e))
+		}
+	}
+	return resp
+}
+
+func toLogBookResponses(logBooks []entities.LogBook) []dto.LogBookResponse {
+	resp := make([]dto.LogBookResponse, 0, len(logBooks))
+	for _, lb := range logBooks {
+		resp = append(resp, toLogBookResponse(lb, false))
+	}
+	return resp
+}
+
+// parseEntryRequest membaca body entry beserta tanggalnya
+func parseEntryRequest(c *fiber.Ctx) (logbook.EntryInput, error) {
+	var req dto.LogBookEntryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return logbook.EntryInput{}, errors.New("Invalid request body")
+	}
+	date, err := parseLogBookDate("entry_date", req.EntryDate)
+	if err != nil {
+		return logbook.EntryInput{}, err
+	}
+	return logbook.EntryInput{EntryDate: date, Content: req.Content}, nil
+}
+
+// CreateLogBook godoc
+// @Summary Create logbook
+// @Description Membuat logbook DRAFT milik student untuk satu periode di course. Periode tidak boleh beririsan dengan logbook lain milik student.
+// @Tags LogBooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param request body dto.CreateLogBookRequest true "Periode logbook"
+// @Success 201 {object} dto.LogBookResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/logbooks [post]
+func (ctrl *LogBookController) CreateLogBook(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	var req dto.CreateLogBookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+	start, err := parseLogBookDate("period_start", req.PeriodStart)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "BadRequestException", nil)
+	}
+	end, err := parseLogBookDate("period_end", req.PeriodEnd)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "BadRequestException", nil)
+	}
+
+	lb, err := ctrl.logBookService.CreateLogBook(context.Background(), userID, ids[0], logbook.PeriodInput{PeriodStart: start, PeriodEnd: end})
+	if err != nil {
+		return logBookError(c, err)
+	}
+
+	return utils.Success(c, http.StatusCreated, "LogBook created successfully", toLogBookResponse(*lb, true), nil)
+}
+
+// GetMyLogBooks godoc
+// @Summary Get my logbooks
+// @Description Menampilkan logbook milik student yang sedang login di course
+// @Tags LogBooks
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Success 200 {object} utils.SuccessResponse{data=[]dto.LogBookResponse}
+// @Router /api/courses/{course_id}/logbooks/me [get]
+func (ctrl *LogBookController) GetMyLogBooks(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	logBooks, err := ctrl.logBookService.GetMyLogBooks(context.Background(), userID, ids[0])
+	if err != nil {
+		return logBookError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Get logbooks successfully", toLogBookResponses(logBooks), nil)
+}
+
+// GetCourseLogBooks godoc
+// @Summary Get course logbooks
+// @Description Menampilkan logbook semua student di course (teacher course atau admin), bisa difilter status dan student
+// @Tags LogBooks
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param status query string false "DRAFT, SUBMITTED atau LOCKED"
+// @Param student_id query string false "Student ID"
+// @Success 200 {object} utils.SuccessResponse{data=[]dto.LogBookResponse}
+// @Failure 403 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/logbooks [get]
+func (ctrl *LogBookController) GetCourseLogBooks(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	status, err := logbook.ParseStatus(c.Query("status"))
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "BadRequestException", nil)
+	}
+	filter := logbook.ListFilter{Status: status}
+	if raw := c.Query("student_id"); raw != "" {
+		studentID, err := uuid.Parse(raw)
+		if err != nil {
+			return utils.Error(c, http.StatusBadRequest, "Invalid student_id format", "InvalidUUID", nil)
+		}
+		filter.StudentID = &studentID
+	}
+
+	logBooks, err := ctrl.logBookService.GetCourseLogBooks(context.Background(), currentCourseRole(c), ids[0], filter)
+	if err != nil {
+		return logBookError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Get logbooks successfully", toLogBookResponses(logBooks), nil)
+}
+
+// GetLogBook godoc
+// @Summary Get logbook detail
+// @Description Menampilkan logbook beserta entry. Student hanya bisa melihat logbook miliknya.
+// @Tags LogBooks
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param logbook_id path string true "LogBook ID"
+// @Success 200 {object} dto.LogBookResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/logbooks/{logbook_id} [get]
+func (ctrl *LogBookController) GetLogBook(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id", "logbook_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	lb, err := ctrl.logBookService.GetLogBook(context.Background(), userID, currentCourseRole(c), ids[0], ids[1])
+	if err != nil {
+		return logBookError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Get logbook successfully", toLogBookResponse(*lb, true), nil)
+}
+
+// AddEntry godoc
+// @Summary Add logbook entry
+// @Description Menambah entry harian ke logbook DRAFT milik student. Tanggal harus di dalam periode dan belum punya entry.
+// @Tags LogBooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param logbook_id path string true "LogBook ID"
+// @Param request body dto.LogBookEntryRequest true "Entry"
+// @Success 201 {object} dto.LogBookEntryResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/logbooks/{logbook_id}/entries [post]
+func (ctrl *LogBookController) AddEntry(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id", "logbook_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	input, err := parseEntryRequest(c)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "BadRequestException", nil)
+	}
+
+	entry, err := ctrl.logBookService.AddEntry(context.Background(), userID, ids[0], ids[1], input)
+	if err != nil {
+		return logBookError(c, err)
+	}
+
+	return utils.Success(c, http.StatusCreated, "Entry created successfully", toLogBookEntryResponse(*entry), nil)
+}
+
+// UpdateEntry godoc
+// @Summary Update logbook entry
+// @Description Mengubah tanggal dan isi entry selama logbook masih DRAFT
+// @Tags LogBooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param logbook_id path string true "LogBook ID"
+// @Param entry_id path string true "Entry ID"
+// @Param request body dto.LogBookEntryRequest true "Entry"
+// @Success 200 {object} dto.LogBookEntryResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/logbooks/{logbook_id}/entries/{entry_id} [put]
+func (ctrl *LogBookController) UpdateEntry(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id", "logbook_id", "entry_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	input, err := parseEntryRequest(c)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "BadRequestException", nil)
+	}
+
+	entry, err := ctrl.logBookService.UpdateEntry(context.Background(), userID, ids[0], ids[1], ids[2], input)
+	if err != nil {
+		return logBookError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Entry updated successfully", toLogBookEntryResponse(*entry), nil)
+}
+
+// DeleteEntry godoc
+// @Summary Delete logbook entry
+// @Description Menghapus entry beserta file lampirannya selama logbook masih DRAFT
+// @Tags LogBooks
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param logbook_id path string true "LogBook ID"
+// @Param entry_id path string true "Entry ID"
+// @Success 200 {object} utils.SuccessResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/logbooks/{logbook_id}/entries/{entry_id} [delete]
+func (ctrl *LogBookController) DeleteEntry(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id", "logbook_id", "entry_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	if err := ctrl.logBookService.DeleteEntry(context.Background(), userID, ids[0], ids[1], ids[2]); err != nil {
+		return logBookError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Entry deleted successfully", nil, nil)
+}
+
+// UploadAttachment godoc
+// @Summary Upload logbook entry attachment
+// @Description Upload file bukti (multipart) ke entry selama logbook masih DRAFT. Ukuran, tipe MIME dan jumlah lampiran per entry dibatasi.
+// @Tags LogBooks
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param logbook_id path string true "LogBook ID"
+// @Param entry_id path string true "Entry ID"
+// @Param file formData file true "File lampiran"
+// @Success 201 {object} dto.LogBookAttachmentResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 413 {object} utils.ErrorResponse
+// @Failure 415 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/logbooks/{logbook_id}/entries/{entry_id}/attachments [post]
+func (ctrl *LogBookController) UploadAttachment(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id", "logbook_id", "entry_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "File is required", "ValidationError", nil)
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Failed to read uploaded file", "BadRequestException", nil)
+	}
+	defer file.Close()
+
+	attachment, err := ctrl.logBookService.AddAttachment(context.Background(), userID, ids[0], ids[1], ids[2], material.FileUpload{
+		FileName: fileHeader.Filename,
+		Size:     fileHeader.Size,
+		Reader:   file,
+	})
+	if err != nil {
+		return logBookError(c, err)
+	}
+
+	return utils.Success(c, http.StatusCreated, "Attachment uploaded successfully", toLogBookAttachmentResponse(*attachment), nil)
+}
+
+// DeleteAttachment godoc
+// @Summary Delete logbook entry attachment
+// @Description Menghapus lampiran beserta file di storage selama logbook masih DRAFT
+// @Tags LogBooks
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param logbook_id path string true "LogBook ID"
+// @Param entry_id path string true "Entry ID"
+// @Param attachment_id path string true "Attachment ID"
+// @Success 200 {object} utils.SuccessResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/logbooks/{logbook_id}/entries/{entry_id}/attachments/{attachment_id} [delete]
+func (ctrl *LogBookController) DeleteAttachment(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id", "logbook_id", "entry_id", "attachment_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	if err := ctrl.logBookService.DeleteAttachment(context.Background(), userID, ids[0], ids[1], ids[2], ids[3]); err != nil {
+		return logBookError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Attachment deleted successfully", nil, nil)
+}
+
+// GetAttachmentURL godoc
+// @Summary Get logbook attachment download URL
+// @Description Menghasilkan URL download bertanda tangan yang berlaku sementara. Aturan akses sama dengan detail logbook.
+// @Tags LogBooks
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param logbook_id path string true "LogBook ID"
+// @Param entry_id path string true "Entry ID"
+// @Param attachment_id path string true "Attachment ID"
+// @Success 200 {object} utils.SuccessResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/logbooks/{logbook_id}/entries/{entry_id}/attachments/{attachment_id}/download-url [get]
+func (ctrl *LogBookController) GetAttachmentURL(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id", "logbook_id", "entry_id", "attachment_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	url, expiresAt, err := ctrl.logBookService.GetAttachmentURL(context.Background(), userID, currentCourseRole(c), ids[0], ids[1], ids[2], ids[3])
+	if err != nil {
+		return logBookError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Download URL generated successfully", fiber.Map{
+		"url":        url,
+		"expires_at": expiresAt.Format(time.RFC3339),
+	}, nil)
+}
+
+// SubmitLogBook godoc
+// @Summary Submit logbook
+// @Description Mengubah logbook DRAFT menjadi SUBMITTED dan mengisi submitted_at. Logbook harus punya minimal satu entry.
+// @Tags LogBooks
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param logbook_id path string true "LogBook ID"
+// @Success 200 {object} dto.LogBookResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/logbooks/{logbook_id}/submit [post]
+func (ctrl *LogBookController) SubmitLogBook(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id", "logbook_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	lb, err := ctrl.logBookService.Submit(context.Background(), userID, ids[0], ids[1])
+	if err != nil {
+		return logBookError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "LogBook submitted successfully", toLogBookResponse(*lb, false), nil)
+}
+
+// LockLogBook godoc
+// @Summary Lock logbook
+// @Description Mengunci logbook SUBMITTED sehingga tidak bisa diubah lagi (teacher course atau admin)
+// @Tags LogBooks
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param logbook_id path string true "LogBook ID"
+// @Success 200 {object} dto.LogBookResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/logbooks/{logbook_id}/lock [post]
+func (ctrl *LogBookController) LockLogBook(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id", "logbook_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	lb, err := ctrl.logBookService.Lock(context.Background(), userID, currentCourseRole(c), ids[0], ids[1])
+	if err != nil {
+		return logBookError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "LogBook locked successfully", toLogBookResponse(*lb, false), nil)
+}
+
+// ApproveLogBook godoc
+// @Summary Approve logbook
+// @Description Menyetujui logbook SUBMITTED: status menjadi LOCKED dan locked_at diisi. Catatan persetujuan opsional (teacher course atau admin).
+// @Tags LogBooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param logbook_id path string true "LogBook ID"
+// @Param request body dto.LogBookReviewRequest false "Catatan persetujuan"
+// @Success 200 {object} dto.LogBookResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/logbooks/{logbook_id}/approve [post]
+func (ctrl *LogBookController) ApproveLogBook(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id", "logbook_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	var req dto.LogBookReviewRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+		}
+	}
+
+	lb, err := ctrl.logBookService.Approve(context.Background(), userID, currentCourseRole(c), ids[0], ids[1], req.Reason)
+	if err != nil {
+		return logBookError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "LogBook approved successfully", toLogBookResponse(*lb, false), nil)
+}
+
+// RequestRevision godoc
+// @Summary Request logbook revision
+// @Description Mengembalikan logbook SUBMITTED ke DRAFT dengan alasan agar student memperbaikinya (teacher course atau admin)
+// @Tags LogBooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param logbook_id path string true "LogBook ID"
+// @Param request body dto.LogBookReviewRequest true "Alasan revisi"
+// @Success 200 {object} dto.LogBookResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/logbooks/{logbook_id}/request-revision [post]
+func (ctrl *LogBookController) RequestRevision(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id", "logbook_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	var req dto.LogBookReviewRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+
+	lb, err := ctrl.logBookService.RequestRevision(context.Background(), userID, currentCourseRole(c), ids[0], ids[1], req.Reason)
+	if err != nil {
+		return logBookError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Revision requested successfully", toLogBookResponse(*lb, false), nil)
+}
+
+// AddComment godoc
+// @Summary Comment on logbook
+// @Description Menambah komentar teacher untuk logbook atau salah satu entry-nya. Logbook harus sudah pernah dikirim.
+// @Tags LogBooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param logbook_id path string true "LogBook ID"
+// @Param request body dto.LogBookCommentRequest true "Komentar"
+// @Success 201 {object} dto.LogBookCommentResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/logbooks/{logbook_id}/comments [post]
+func (ctrl *LogBookController) AddComment(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id", "logbook_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	var req dto.LogBookCommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+	input := logbook.CommentInput{Body: req.Body}
+	if req.EntryID != nil && *req.EntryID != "" {
+		entryID, err := uuid.Parse(*req.EntryID)
+		if err != nil {
+			return utils.Error(c, http.StatusBadRequest, "Invalid entry_id format", "InvalidUUID", nil)
+		}
+		input.EntryID = &entryID
+	}
+
+	comment, err := ctrl.logBookService.AddComment(context.Background(), userID, currentCourseRole(c), ids[0], ids[1], input)
+	if err != nil {
+		return logBookError(c, err)
+	}
+
+	return utils.Success(c, http.StatusCreated, "Comment created successfully", toLogBookCommentResponse(*comment), nil)
+}
+
+// GetComments godoc
+// @Summary Get logbook comments
+// @Description Menampilkan komentar teacher untuk logbook dan entry-nya. Student hanya bisa melihat logbook miliknya.
+// @Tags LogBooks
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param logbook_id path string true "LogBook ID"
+// @Success 200 {object} utils.SuccessResponse{data=[]dto.LogBookCommentResponse}
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/logbooks/{logbook_id}/comments [get]
+func (ctrl *LogBookController) GetComments(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id", "logbook_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	comments, err := ctrl.logBookService.GetComments(context.Background(), userID, currentCourseRole(c), ids[0], ids[1])
+	if err != nil {
+		return logBookError(c, err)
+	}
+
+	resp := make([]dto.LogBookCommentResponse, 0, len(comments))
+	for _, cm := range comments {
+		resp = append(resp, toLogBookCommentResponse(cm))
+	}
+
+	return utils.Success(c, http.StatusOK, "Get comments successfully", resp, nil)
+}
+
+// GetHistory godoc
+// @Summary Get logbook review history
+// @Description Menampilkan riwayat submit, permintaan revisi, persetujuan dan penguncian logbook beserta pelakunya
+// @Tags LogBooks
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param logbook_id path string true "LogBook ID"
+// @Success 200 {object} utils.SuccessResponse{data=[]dto.LogBookReviewResponse}
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/logbooks/{logbook_id}/history [get]
+func (ctrl *LogBookController) GetHistory(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id", "logbook_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	reviews, err := ctrl.logBookService.GetHistory(context.Background(), userID, currentCourseRole(c), ids[0], ids[1])
+	if err != nil {
+		return logBookError(c, err)
+	}
+
+	resp := make([]dto.LogBookReviewResponse, 0, len(reviews))
+	for _, r := range reviews {
+		resp = append(resp, toLogBookReviewResponse(r))
+	}
+
+	return utils.Success(c, http.StatusOK, "Get logbook history successfully", resp, nil)
+}
+
+// GetComplianceReport godoc
+// @Summary Get logbook compliance report
+// @Description Menampilkan tanggal tanpa entry di setiap periode logbook untuk semua student course beserta total dan persentase (teacher course atau admin). Hari setelah hari ini tidak dihitung. Gunakan format=csv untuk unduhan CSV.
+// @Tags LogBooks
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param from query string true "Tanggal awal (YYYY-MM-DD)"
+// @Param to query string true "Tanggal akhir (YYYY-MM-DD)"
+// @Param exclude_weekends query bool false "Abaikan Sabtu dan Minggu"
+// @Param format query string false "json (default) atau csv"
+// @Success 200 {object} utils.SuccessResponse{data=logbook.ComplianceReport}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/logbooks/compliance [get]
+func (ctrl *LogBookController) GetComplianceReport(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	format := c.Query("format", "json")
+	if format != "json" && format != "csv" {
+		return utils.Error(c, http.StatusBadRequest, "format must be json or csv", "BadRequestException", nil)
+	}
+	from, err := parseLogBookDate("from", c.Query("from"))
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "BadRequestException", nil)
+	}
+	to, err := parseLogBookDate("to", c.Query("to"))
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "BadRequestException", nil)
+	}
+
+	report, err := ctrl.logBookService.GetComplianceReport(context.Background(), currentCourseRole(c), ids[0], logbook.ComplianceOptions{
+		From:            from,
+		To:              to,
+		ExcludeWeekends: c.QueryBool("exclude_weekends"),
+	})
+	if err != nil {
+		return logBookError(c, err)
+	}
+
+	if format == "json" {
+		return utils.Success(c, http.StatusOK, "Get logbook compliance report successfully", report, nil)
+	}
+
+	var buf bytes.Buffer
+	if err := logbook.WriteComplianceCSV(&buf, report); err != nil {
+		return utils.Error(c, http.StatusInternalServerError, "Failed to generate report", "InternalServerError", nil)
+	}
+
+	name := "logbook-compliance-" + report.From + "-" + report.To + ".csv"
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	return c.Status(http.StatusOK).Send(buf.Bytes())
+}
+
+// ExportPDF godoc
+// @Summary Export logbook as PDF
+// @Description Mengunduh logbook beserta entry, status dan komentar teacher dalam PDF dengan kop sekolah. Aturan akses sama dengan detail logbook.
+// @Tags LogBooks
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param logbook_id path string true "LogBook ID"
+// @Success 200 {file} file
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/logbooks/{logbook_id}/pdf [get]
+func (ctrl *LogBookController) ExportPDF(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id", "logbook_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	doc, name, err := ctrl.logBookService.ExportPDF(context.Background(), userID, currentCourseRole(c), ids[0], ids[1], ctrl.documentHeader)
+	if err != nil {
+		return logBookError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+
+	// dokumen sudah tersusun dan dicek error-nya, hasil render dialirkan tanpa ditampung utuh
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(doc.Output(pw))
+	}()
+	return c.Status(http.StatusOK).SendStream(pr)
+}
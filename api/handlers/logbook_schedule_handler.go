@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"api-shiners/api/handlers/dto"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/logbook"
+	"api-shiners/pkg/utils"
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type LogBookScheduleController struct {
+	scheduleService logbook.ScheduleService
+}
+
+func NewLogBookScheduleController(scheduleService logbook.ScheduleService) *LogBookScheduleController {
+	return &LogBookScheduleController{scheduleService: scheduleService}
+}
+
+func scheduleError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, logbook.ErrScheduleNotFound) {
+		return utils.Error(c, http.StatusNotFound, err.Error(), "NotFoundException", nil)
+	}
+	return logBookError(c, err)
+}
+
+func toLogBookScheduleResponse(s entities.LogBookSchedule) dto.LogBookScheduleResponse {
+	resp := dto.LogBookScheduleResponse{
+		ID:          s.ID.String(),
+		CourseID:    s.CourseID.String(),
+		Frequency:   string(s.Frequency),
+		WeekStart:   int(s.WeekStart),
+		StartDate:   s.StartDate.Format(logBookDateLayout),
+		CloseAction: string(s.CloseAction),
+		GraceDays:   s.GraceDays,
+		IsActive:    s.IsActive,
+		CreatedByID: s.CreatedByID.String(),
+		CreatedAt:   s.CreatedAt,
+		UpdatedAt:   s.UpdatedAt,
+	}
+	if s.EndDate != nil {
+		end := s.EndDate.Format(logBookDateLayout)
+		resp.EndDate = &end
+	}
+	return resp
+}
+
+// GetSchedule godoc
+// @Summary Get logbook schedule
+// @Description Menampilkan jadwal pembuatan logbook otomatis di course (teacher course atau admin)
+// @Tags LogBooks
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Success 200 {object} dto.LogBookScheduleResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/logbook-schedule [get]
+func (ctrl *LogBookScheduleController) GetSchedule(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	schedule, err := ctrl.scheduleService.GetSchedule(context.Background(), currentCourseRole(c), ids[0])
+	if err != nil {
+		return scheduleError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Get logbook schedule successfully", toLogBookScheduleResponse(*schedule), nil)
+}
+
+// SaveSchedule godoc
+// @Summary Save logbook schedule
+// @Description Membuat atau mengganti jadwal logbook course. Logbook DRAFT periode berjalan langsung dibuat untuk setiap student; job terjadwal membuat periode berikutnya dan menutup logbook setelah masa tenggang.
+// @Tags LogBooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param request body dto.LogBookScheduleRequest true "Jadwal"
+// @Success 200 {object} dto.SaveLogBookScheduleResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/logbook-schedule [put]
+func (ctrl *LogBookScheduleController) SaveSchedule(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	var req dto.LogBookScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+	start, err := parseLogBookDate("start_date", req.StartDate)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "BadRequestException", nil)
+	}
+	input := logbook.ScheduleInput{
+		Frequency:   req.Frequency,
+		WeekStart:   req.WeekStart,
+		StartDate:   start,
+		CloseAction: req.CloseAction,
+		GraceDays:   req.GraceDays,
+		IsActive:    req.IsActive,
+	}
+	if req.EndDate != nil && *req.EndDate != "" {
+		end, err := parseLogBookDate("end_date", *req.EndDate)
+		if err != nil {
+			return utils.Error(c, http.StatusBadRequest, err.Error(), "BadRequestException", nil)
+		}
+		input.EndDate = &end
+	}
+
+	schedule, generated, err := ctrl.scheduleService.SaveSchedule(context.Background(), userID, currentCourseRole(c), ids[0], input)
+	if err != nil {
+		return scheduleError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "LogBook schedule saved successfully", dto.SaveLogBookScheduleResponse{
+		Schedule:  toLogBookScheduleResponse(*schedule),
+		Generated: generated,
+	}, nil)
+}
+
+// DeleteSchedule godoc
+// @Summary Delete logbook schedule
+// @Description Menghentikan jadwal logbook course. Logbook yang sudah dibuat tidak dihapus.
+// @Tags LogBooks
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Success 200 {object} utils.SuccessResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/logbook-schedule [delete]
+func (ctrl *LogBookScheduleController) DeleteSchedule(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	if err := ctrl.scheduleService.DeleteSchedule(context.Background(), currentCourseRole(c), ids[0]); err != nil {
+		return scheduleError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "LogBook schedule deleted successfully", nil, nil)
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"api-shiners/pkg/course"
+	"api-shiners/pkg/material"
+	"api-shiners/pkg/utils"
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type MaterialController struct {
+	materialService material.MaterialService
+}
+
+func NewMaterialController(materialService material.MaterialService) *MaterialController {
+	return &MaterialController{materialService: materialService}
+}
+
+func materialError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, material.ErrMaterialNotFound):
+		return utils.Error(c, http.StatusNotFound, err.Error(), "NotFoundException", nil)
+	case errors.Is(err, material.ErrNotEnrolled):
+		return utils.Error(c, http.StatusForbidden, err.Error(), "ForbiddenException", nil)
+	case errors.Is(err, material.ErrFileTooLarge):
+		return utils.Error(c, http.StatusRequestEntityTooLarge, err.Error(), "PayloadTooLarge", nil)
+	case errors.Is(err, material.ErrFileTypeNotAllowed):
+		return utils.Error(c, http.StatusUnsupportedMediaType, err.Error(), "UnsupportedMediaType", nil)
+	case errors.Is(err, material.ErrFileRequired), errors.Is(err, material.ErrNotAFile):
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "BadRequestException", nil)
+	case errors.Is(err, course.ErrCourseNotFound), errors.Is(err, course.ErrModuleNotFound), errors.Is(err, course.ErrForbidden):
+		return courseError(c, err)
+	default:
+		// error database dan storage tidak diteruskan ke client
+		return utils.Error(c, http.StatusInternalServerError, "Internal server error", "InternalServerError", nil)
+	}
+}
+
+// GetMaterials godoc
+// @Summary Get module materials
+// @Description Menampilkan material di dalam modul (pengelola course atau student yang terdaftar)
+// @Tags Materials
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param module_id path string true "Module ID"
+// @Success 200 {object} utils.SuccessResponse{data=[]entities.Material}
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/modules/{module_id}/materials [get]
+func (ctrl *MaterialController) GetMaterials(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	courseID, err := parseUUIDParam(c, "course_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+
+	moduleID, err := parseUUIDParam(c, "module_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid module ID format", "InvalidUUID", nil)
+	}
+
+	materials, err := ctrl.materialService.GetMaterials(context.Background(), userID, currentCourseRole(c), courseID, moduleID)
+	if err != nil {
+		return materialError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Get materials successfully", materials, nil)
+}
+
+// UploadFile godoc
+// @Summary Upload file material
+// @Description Upload file (multipart) sebagai material bertipe FILE. Ukuran dan tipe MIME dibatasi konfigurasi server
+// @Tags Materials
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param module_id path string true "Module ID"
+// @Param file formData file true "File material"
+// @Param title formData string false "Judul material (default nama file)"
+// @Success 201 {object} entities.Material
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 413 {object} utils.ErrorResponse
+// @Failure 415 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/modules/{module_id}/materials/upload [post]
+func (ctrl *MaterialController) UploadFile(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	courseID, err := parseUUIDParam(c, "course_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+
+	moduleID, err := parseUUIDParam(c, "module_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid module ID format", "InvalidUUID", nil)
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "File is required", "ValidationError", nil)
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Failed to read uploaded file", "BadRequestException", nil)
+	}
+	defer file.Close()
+
+	created, err := ctrl.materialService.UploadFile(context.Background(), userID, currentCourseRole(c), courseID, moduleID, c.FormValue("title"), material.FileUpload{
+		FileName: fileHeader.Filename,
+		Size:     fileHeader.Size,
+		Reader:   file,
+	})
+	if err != nil {
+		return materialError(c, err)
+	}
+
+	return utils.Success(c, http.StatusCreated, "File uploaded successfully", created, nil)
+}
+
+// DeleteMaterial godoc
+// @Summary Delete material
+// @Description Menghapus material beserta file di storage
+// @Tags Materials
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param material_id path string true "Material ID"
+// @Success 200 {object} utils.SuccessResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/materials/{material_id} [delete]
+func (ctrl *MaterialController) DeleteMaterial(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	courseID, err := parseUUIDParam(c, "course_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+
+	materialID, err := parseUUIDParam(c, "material_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid material ID format", "InvalidUUID", nil)
+	}
+
+	if err := ctrl.materialService.DeleteMaterial(context.Background(), userID, currentCourseRole(c), courseID, materialID); err != nil {
+		return materialError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Material deleted successfully", nil, nil)
+}
+
+// GetDownloadURL godoc
+// @Summary Get material download URL
+// @Description Menghasilkan URL download bertanda tangan yang berlaku sementara. Hanya untuk pengelola course atau student yang terdaftar
+// @Tags Materials
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param material_id path string true "Material ID"
+// @Success 200 {object} utils.SuccessResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/materials/{material_id}/download-url [get]
+func (ctrl *MaterialController) GetDownloadURL(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	courseID, err := parseUUIDParam(c, "course_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+
+	materialID, err := parseUUIDParam(c, "material_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid material ID format", "InvalidUUID", nil)
+	}
+
+	url, expiresAt, err := ctrl.materialService.GetDownloadURL(context.Background(), userID, currentCourseRole(c), courseID, materialID)
+	if err != nil {
+		return materialError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Download URL generated successfully", fiber.Map{
+		"url":        url,
+		"expires_at": expiresAt.Format(time.RFC3339),
+	}, nil)
+}
@@ -0,0 +1,430 @@
+package handlers
+
+import (
+	"api-shiners/api/handlers/dto"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/quiz"
+	"api-shiners/pkg/utils"
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// QuestionBankController melayani bank pribadi (/question-banks) dan bank course
+// (/courses/:course_id/question-banks) dengan handler yang sama
+type QuestionBankController struct {
+	bankService quiz.BankService
+}
+
+func NewQuestionBankController(bankService quiz.BankService) *QuestionBankController {
+	return &QuestionBankController{bankService: bankService}
+}
+
+// bankScope: route dengan course_id memakai bank course, selain itu bank pribadi user
+func bankScope(c *fiber.Ctx) (quiz.BankScope, error) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return quiz.BankScope{}, err
+	}
+
+	scope := quiz.BankScope{UserID: userID, CourseRole: currentCourseRole(c)}
+	if c.Params("course_id") != "" {
+		courseID, err := parseUUIDParam(c, "course_id")
+		if err != nil {
+			return quiz.BankScope{}, err
+		}
+		scope.CourseID = &courseID
+	}
+	return scope, nil
+}
+
+func toQuestionBankResponse(bank entities.QuestionBank) dto.QuestionBankResponse {
+	resp := dto.QuestionBankResponse{
+		ID:          bank.ID.String(),
+		OwnerID:     bank.OwnerID.String(),
+		Title:       bank.Title,
+		Description: bank.Description,
+		CreatedAt:   bank.CreatedAt,
+		UpdatedAt:   bank.UpdatedAt,
+	}
+	if bank.CourseID != nil {
+		courseID := bank.CourseID.String()
+		resp.CourseID = &courseID
+	}
+	for _, question := range bank.Questions {
+		resp.Questions = append(resp.Questions, toQuestionResponse(question, true))
+	}
+	return resp
+}
+
+// GetQuestionBanks godoc
+// @Summary Get question banks
+// @Description Menampilkan bank soal pribadi teacher, atau bank soal course bila diakses lewat /courses/{course_id}/question-banks
+// @Tags Question Banks
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.SuccessResponse{data=[]dto.QuestionBankResponse}
+// @Failure 403 {object} utils.ErrorResponse
+// @Router /api/question-banks [get]
+// @Router /api/courses/{course_id}/question-banks [get]
+func (ctrl *QuestionBankController) GetBanks(c *fiber.Ctx) error {
+	scope, err := bankScope(c)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+
+	banks, err := ctrl.bankService.GetBanks(context.Background(), scope)
+	if err != nil {
+		return quizError(c, err)
+	}
+
+	resp := make([]dto.QuestionBankResponse, 0, len(banks))
+	for _, bank := range banks {
+		resp = append(resp, toQuestionBankResponse(bank))
+	}
+	return utils.Success(c, http.StatusOK, "Get question banks successfully", resp, nil)
+}
+
+// GetQuestionBank godoc
+// @Summary Get question bank
+// @Description Menampilkan bank soal beserta soal dan kunci jawabannya. Query tags (dipisah koma) memfilter soal yang punya salah satu tag
+// @Tags Question Banks
+// @Produce json
+// @Security BearerAuth
+// @Param bank_id path string true "Bank ID"
+// @Param tags query string false "Filter tag, contoh: aljabar,persamaan"
+// @Success 200 {object} dto.QuestionBankResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/question-banks/{bank_id} [get]
+// @Router /api/courses/{course_id}/question-banks/{bank_id} [get]
+func (ctrl *QuestionBankController) GetBank(c *fiber.Ctx) error {
+	scope, err := bankScope(c)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+	ids, err := parseUUIDParams(c, "bank_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	var tags []string
+	if raw := c.Query("tags"); raw != "" {
+		tags = strings.Split(raw, ",")
+	}
+
+	bank, err := ctrl.bankService.GetBank(context.Background(), scope, ids[0], tags)
+	if err != nil {
+		return quizError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Get question bank successfully", toQuestionBankResponse(*bank), nil)
+}
+
+// CreateQuestionBank godoc
+// @Summary Create question bank
+// @Description Membuat bank soal pribadi, atau bank soal course bila diakses lewat /courses/{course_id}/question-banks
+// @Tags Question Banks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.QuestionBankRequest true "Bank payload"
+// @Success 201 {object} dto.QuestionBankResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Router /api/question-banks [post]
+// @Router /api/courses/{course_id}/question-banks [post]
+func (ctrl *QuestionBankController) CreateBank(c *fiber.Ctx) error {
+	scope, err := bankScope(c)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+
+	var req dto.QuestionBankRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+
+	created, err := ctrl.bankService.CreateBank(context.Background(), scope, quiz.BankInput{Title: req.Title, Description: req.Description})
+	if err != nil {
+		return quizError(c, err)
+	}
+
+	return utils.Success(c, http.StatusCreated, "Question bank created successfully", toQuestionBankResponse(*created), nil)
+}
+
+// UpdateQuestionBank godoc
+// @Summary Update question bank
+// @Description Mengubah judul dan deskripsi bank soal
+// @Tags Question Banks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param bank_id path string true "Bank ID"
+// @Param request body dto.QuestionBankRequest true "Bank payload"
+// @Success 200 {object} dto.QuestionBankResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/question-banks/{bank_id} [put]
+// @Router /api/courses/{course_id}/question-banks/{bank_id} [put]
+func (ctrl *QuestionBankController) UpdateBank(c *fiber.Ctx) error {
+	scope, err := bankScope(c)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+	ids, err := parseUUIDParams(c, "bank_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	var req dto.QuestionBankRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+
+	updated, err := ctrl.bankService.UpdateBank(context.Background(), scope, ids[0], quiz.BankInput{Title: req.Title, Description: req.Description})
+	if err != nil {
+		return quizError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Question bank updated successfully", toQuestionBankResponse(*updated), nil)
+}
+
+// DeleteQuestionBank godoc
+// @Summary Delete question bank
+// @Description Menghapus bank soal beserta soal dan pool quiz yang memakainya
+// @Tags Question Banks
+// @Produce json
+// @Security BearerAuth
+// @Param bank_id path string true "Bank ID"
+// @Success 200 {object} utils.SuccessResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/question-banks/{bank_id} [delete]
+// @Router /api/courses/{course_id}/question-banks/{bank_id} [delete]
+func (ctrl *QuestionBankController) DeleteBank(c *fiber.Ctx) error {
+	scope, err := bankScope(c)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+	ids, err := parseUUIDParams(c, "bank_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	if err := ctrl.bankService.DeleteBank(context.Background(), scope, ids[0]); err != nil {
+		return quizError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Question bank deleted successfully", nil, nil)
+}
+
+// CreateBankQuestion godoc
+// @Summary Create bank question
+// @Description Menambahkan soal ke bank. Soal boleh belum lengkap, tetapi hanya soal lengkap yang diundi ke quiz
+// @Tags Question Banks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param bank_id path string true "Bank ID"
+// @Param request body dto.QuestionRequest true "Question payload"
+// @Success 201 {object} dto.QuestionResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/question-banks/{bank_id}/questions [post]
+// @Router /api/courses/{course_id}/question-banks/{bank_id}/questions [post]
+func (ctrl *QuestionBankController) CreateQuestion(c *fiber.Ctx) error {
+	scope, err := bankScope(c)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+	ids, err := parseUUIDParams(c, "bank_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	var req dto.QuestionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+
+	created, err := ctrl.bankService.CreateQuestion(context.Background(), scope, ids[0], toQuestionInput(req))
+	if err != nil {
+		return quizError(c, err)
+	}
+
+	return utils.Success(c, http.StatusCreated, "Question created successfully", toQuestionResponse(*created, true), nil)
+}
+
+// UpdateBankQuestion godoc
+// @Summary Update bank question
+// @Description Mengubah soal bank. Attempt yang sudah dimulai tetap memakai soal ini sesuai kondisi terbaru
+// @Tags Question Banks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param bank_id path string true "Bank ID"
+// @Param question_id path string true "Question ID"
+// @Param request body dto.QuestionRequest true "Question payload"
+// @Success 200 {object} dto.QuestionResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/question-banks/{bank_id}/questions/{question_id} [put]
+// @Router /api/courses/{course_id}/question-banks/{bank_id}/questions/{question_id} [put]
+func (ctrl *QuestionBankController) UpdateQuestion(c *fiber.Ctx) error {
+	scope, err := bankScope(c)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+	ids, err := parseUUIDParams(c, "bank_id", "question_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	var req dto.QuestionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+
+	updated, err := ctrl.bankService.UpdateQuestion(context.Background(), scope, ids[0], ids[1], toQuestionInput(req))
+	if err != nil {
+		return quizError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Question updated successfully", toQuestionResponse(*updated, true), nil)
+}
+
+// DeleteBankQuestion godoc
+// @Summary Delete bank question
+// @Description Menghapus soal dari bank
+// @Tags Question Banks
+// @Produce json
+// @Security BearerAuth
+// @Param bank_id path string true "Bank ID"
+// @Param question_id path string true "Question ID"
+// @Success 200 {object} utils.SuccessResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/question-banks/{bank_id}/questions/{question_id} [delete]
+// @Router /api/courses/{course_id}/question-banks/{bank_id}/questions/{question_id} [delete]
+func (ctrl *QuestionBankController) DeleteQuestion(c *fiber.Ctx) error {
+	scope, err := bankScope(c)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+	ids, err := parseUUIDParams(c, "bank_id", "question_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	if err := ctrl.bankService.DeleteQuestion(context.Background(), scope, ids[0], ids[1]); err != nil {
+		return quizError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Question deleted successfully", nil, nil)
+}
+
+// CreateBankChoice godoc
+// @Summary Create bank question choice
+// @Description Menambahkan pilihan ke soal bank, position default di akhir
+// @Tags Question Banks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param bank_id path string true "Bank ID"
+// @Param question_id path string true "Question ID"
+// @Param request body dto.ChoiceRequest true "Choice payload"
+// @Success 201 {object} dto.ChoiceResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/question-banks/{bank_id}/questions/{question_id}/choices [post]
+// @Router /api/courses/{course_id}/question-banks/{bank_id}/questions/{question_id}/choices [post]
+func (ctrl *QuestionBankController) CreateChoice(c *fiber.Ctx) error {
+	scope, err := bankScope(c)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+	ids, err := parseUUIDParams(c, "bank_id", "question_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	var req dto.ChoiceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+
+	created, err := ctrl.bankService.CreateChoice(context.Background(), scope, ids[0], ids[1], toChoiceInput(req))
+	if err != nil {
+		return quizError(c, err)
+	}
+
+	return utils.Success(c, http.StatusCreated, "Choice created successfully", toChoiceResponse(*created, true), nil)
+}
+
+// UpdateBankChoice godoc
+// @Summary Update bank question choice
+// @Description Mengubah pilihan soal bank
+// @Tags Question Banks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param bank_id path string true "Bank ID"
+// @Param question_id path string true "Question ID"
+// @Param choice_id path string true "Choice ID"
+// @Param request body dto.ChoiceRequest true "Choice payload"
+// @Success 200 {object} dto.ChoiceResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/question-banks/{bank_id}/questions/{question_id}/choices/{choice_id} [put]
+// @Router /api/courses/{course_id}/question-banks/{bank_id}/questions/{question_id}/choices/{choice_id} [put]
+func (ctrl *QuestionBankController) UpdateChoice(c *fiber.Ctx) error {
+	scope, err := bankScope(c)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+	ids, err := parseUUIDParams(c, "bank_id", "question_id", "choice_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	var req dto.ChoiceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+
+	updated, err := ctrl.bankService.UpdateChoice(context.Background(), scope, ids[0], ids[1], ids[2], toChoiceInput(req))
+	if err != nil {
+		return quizError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Choice updated successfully", toChoiceResponse(*updated, true), nil)
+}
+
+// DeleteBankChoice godoc
+// @Summary Delete bank question choice
+// @Description Menghapus pilihan dari soal bank
+// @Tags Question Banks
+// @Produce json
+// @Security BearerAuth
+// @Param bank_id path string true "Bank ID"
+// @Param question_id path string true "Question ID"
+// @Param choice_id path string true "Choice ID"
+// @Success 200 {object} utils.SuccessResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/question-banks/{bank_id}/questions/{question_id}/choices/{choice_id} [delete]
+// @Router /api/courses/{course_id}/question-banks/{bank_id}/questions/{question_id}/choices/{choice_id} [delete]
+func (ctrl *QuestionBankController) DeleteChoice(c *fiber.Ctx) error {
+	scope, err := bankScope(c)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+	}
+	ids, err := parseUUIDParams(c, "bank_id", "question_id", "choice_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	if err := ctrl.bankService.DeleteChoice(context.Background(), scope, ids[0], ids[1], ids[2]); err != nil {
+		return quizError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Choice deleted successfully", nil, nil)
+}
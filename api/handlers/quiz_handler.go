@@ -0,0 +1,875 @@
+package handlers
+
+import (
+	"api-shiners/api/handlers/dto"
+	"api-shiners/pkg/course"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/grading"
+	"api-shiners/pkg/quiz"
+	"api-shiners/pkg/utils"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type QuizController struct {
+	quizService quiz.QuizService
+}
+
+func NewQuizController(quizService quiz.QuizService) *QuizController {
+	return &QuizController{quizService: quizService}
+}
+
+func quizError(c *fiber.Ctx, err error) error {
+	var invalid *quiz.ValidationError
+	if errors.As(err, &invalid) {
+		return utils.Error(c, http.StatusUnprocessableEntity, err.Error(), "ValidationError", invalid.Errors)
+	}
+	var input *quiz.InputError
+	if errors.As(err, &input) {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "BadRequestException", nil)
+	}
+
+	switch {
+	case errors.Is(err, quiz.ErrQuizNotFound), errors.Is(err, quiz.ErrQuestionNotFound), errors.Is(err, quiz.ErrChoiceNotFound),
+		errors.Is(err, quiz.ErrBankNotFound), errors.Is(err, quiz.ErrPoolNotFound):
+		return utils.Error(c, http.StatusNotFound, err.Error(), "NotFoundException", nil)
+	case errors.Is(err, quiz.ErrForbidden):
+		return utils.Error(c, http.StatusForbidden, err.Error(), "ForbiddenException", nil)
+	case errors.Is(err, quiz.ErrImportTooLarge):
+		return utils.Error(c, http.StatusRequestEntityTooLarge, err.Error(), "PayloadTooLarge", nil)
+	case errors.Is(err, quiz.ErrChoicesNotUsed), errors.Is(err, quiz.ErrUnknownImportFormat):
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "BadRequestException", nil)
+	case errors.Is(err, course.ErrCourseNotFound), errors.Is(err, course.ErrModuleNotFound):
+		return courseError(c, err)
+	default:
+		// error database dan sejenisnya tidak diteruskan ke client
+		return utils.Error(c, http.StatusInternalServerError, "Internal server error", "InternalServerError", nil)
+	}
+}
+
+func toChoiceResponse(ch entities.Choice, withAnswerKey bool) dto.ChoiceResponse {
+	resp := dto.ChoiceResponse{
+		ID:         ch.ID.String(),
+		QuestionID: ch.QuestionID.String(),
+		Text:       ch.Text,
+		Position:   ch.Position,
+	}
+	if withAnswerKey {
+		isCorrect := ch.IsCorrect
+		resp.IsCorrect = &isCorrect
+	}
+	return resp
+}
+
+func toQuestionResponse(q entities.Question, withAnswerKey bool) dto.QuestionResponse {
+	resp := dto.QuestionResponse{
+		ID:       q.ID.String(),
+		Type:     string(q.Type),
+		Text:     q.Text,
+		Position: q.Position,
+		Points:   grading.MaxPoints(q),
+		Choices:  make([]dto.ChoiceResponse, 0, len(q.Choices)),
+	}
+	if q.QuizID != nil {
+		resp.QuizID = q.QuizID.String()
+	}
+	for _, ch := range q.Choices {
+		resp.Choices = append(resp.Choices, toChoiceResponse(ch, withAnswerKey))
+	}
+	if withAnswerKey {
+		if q.BankID != nil {
+			resp.BankID = q.BankID.String()
+		}
+		resp.Tags = q.Tags
+		resp.AcceptedAnswers = q.AcceptedAnswers
+		resp.AnswerPattern = q.AnswerPattern
+		resp.NumericAnswer = q.NumericAnswer
+		if q.Type == entities.QuestionTypeNumeric {
+			tolerance := q.NumericTolerance
+			resp.NumericTolerance = &tolerance
+		}
+	}
+	return resp
+}
+
+// toQuizResponse: kunci jawaban (is_correct) hanya dikirim ke teacher/admin course
+func toQuizResponse(q entities.Quiz, withAnswerKey bool) dto.QuizResponse {
+	resp := dto.QuizResponse{
+		ID:             q.ID.String(),
+		ModuleID:       q.ModuleID.String(),
+		Title:          q.Title,
+		Instructions:   q.Instructions,
+		OpenAt:         q.OpenAt,
+		CloseAt:        q.CloseAt,
+		TimeLimitSec:   q.TimeLimitSec,
+		AttemptAllowed: q.AttemptAllowed,
+		IsPublished:    q.IsPublished,
+		CreatedAt:      q.CreatedAt,
+		UpdatedAt:      q.UpdatedAt,
+
+		ShuffleQuestions: q.ShuffleQuestions,
+		ShuffleChoices:   q.ShuffleChoices,
+
+		NegativeMarking:    q.NegativeMarking,
+		AllowNegativeScore: q.AllowNegativeScore,
+		MultiSelectScoring: string(q.MultiSelectScoring),
+		ScoreMode:          string(q.ScoreMode),
+
+		ReleasePolicy:     string(q.ReleasePolicy),
+		ReleaseContent:    string(q.ReleaseContent),
+		ResultsReleasedAt: q.ResultsReleasedAt,
+
+		MaxFocusLost:      q.MaxFocusLost,
+		MaxFullscreenExit: q.MaxFullscreenExit,
+		MaxPaste:          q.MaxPaste,
+	}
+	for _, question := range q.Questions {
+		resp.Questions = append(resp.Questions, toQuestionResponse(question, withAnswerKey))
+	}
+	if withAnswerKey {
+		for _, pool := range q.Pools {
+			resp.Pools = append(resp.Pools, toQuizPoolResponse(pool))
+		}
+	}
+	return resp
+}
+
+func toQuizPoolResponse(pool entities.QuizPool) dto.QuizPoolResponse {
+	return dto.QuizPoolResponse{
+		ID:        pool.ID.String(),
+		QuizID:    pool.QuizID.String(),
+		BankID:    pool.BankID.String(),
+		Tags:      pool.Tags,
+		DrawCount: pool.DrawCount,
+		Position:  pool.Position,
+	}
+}
+
+func toQuizInput(req dto.QuizRequest) quiz.QuizInput {
+	return quiz.QuizInput{
+		Title:          req.Title,
+		Instructions:   req.Instructions,
+		OpenAt:         req.OpenAt,
+		CloseAt:        req.CloseAt,
+		TimeLimitSec:   req.TimeLimitSec,
+		AttemptAllowed: req.AttemptAllowed,
+
+		ShuffleQuestions: req.ShuffleQuestions,
+		ShuffleChoices:   req.ShuffleChoices,
+
+		NegativeMarking:    req.NegativeMarking,
+		AllowNegativeScore: req.AllowNegativeScore,
+		MultiSelectScoring: req.MultiSelectScoring,
+		ScoreMode:          req.ScoreMode,
+
+		ReleasePolicy:  req.ReleasePolicy,
+		ReleaseContent: req.ReleaseContent,
+
+		MaxFocusLost:      req.MaxFocusLost,
+		MaxFullscreenExit: req.MaxFullscreenExit,
+		MaxPaste:          req.MaxPaste,
+	}
+}
+
+func toChoiceInput(req dto.ChoiceRequest) quiz.ChoiceInput {
+	return quiz.ChoiceInput{Text: req.Text, IsCorrect: req.IsCorrect, Position: req.Position}
+}
+
+func toQuestionInput(req dto.QuestionRequest) quiz.QuestionInput {
+	input := quiz.QuestionInput{
+		Type:             req.Type,
+		Text:             req.Text,
+		Position:         req.Position,
+		Points:           req.Points,
+		Tags:             req.Tags,
+		AcceptedAnswers:  req.AcceptedAnswers,
+		AnswerPattern:    req.AnswerPattern,
+		NumericAnswer:    req.NumericAnswer,
+		NumericTolerance: req.NumericTolerance,
+	}
+	for _, ch := range req.Choices {
+		input.Choices = append(input.Choices, toChoiceInput(ch))
+	}
+	return input
+}
+
+func toPoolInput(req dto.QuizPoolRequest) (quiz.PoolInput, error) {
+	bankID, err := uuid.Parse(req.BankID)
+	if err != nil {
+		return quiz.PoolInput{}, err
+	}
+	return quiz.PoolInput{BankID: bankID, Tags: req.Tags, DrawCount: req.DrawCount, Position: req.Position}, nil
+}
+
+// GetQuizzes godoc
+// @Summary Get module quizzes
+// @Description Menampilkan quiz di dalam modul, student hanya melihat quiz yang sudah dipublish
+// @Tags Quizzes
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param module_id path string true "Module ID"
+// @Success 200 {object} utils.SuccessResponse{data=[]dto.QuizResponse}
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/modules/{module_id}/quizzes [get]
+func (ctrl *QuizController) GetQuizzes(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id", "module_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	role := currentCourseRole(c)
+	quizzes, err := ctrl.quizService.GetQuizzes(context.Background(), role, ids[0], ids[1])
+	if err != nil {
+		return quizError(c, err)
+	}
+
+	resp := make([]dto.QuizResponse, 0, len(quizzes))
+	for _, q := range quizzes {
+		resp = append(resp, toQuizResponse(q, quiz.CanAuthor(role)))
+	}
+	return utils.Success(c, http.StatusOK, "Get quizzes successfully", resp, nil)
+}
+
+// CreateQuiz godoc
+// @Summary Create quiz
+// @Description Membuat quiz baru di dalam modul (teacher course atau admin)
+// @Tags Quizzes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param module_id path string true "Module ID"
+// @Param request body dto.QuizRequest true "Quiz payload"
+// @Success 201 {object} dto.QuizResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/modules/{module_id}/quizzes [post]
+func (ctrl *QuizController) CreateQuiz(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id", "module_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	var req dto.QuizRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+
+	created, err := ctrl.quizService.CreateQuiz(context.Background(), currentCourseRole(c), ids[0], ids[1], toQuizInput(req))
+	if err != nil {
+		return quizError(c, err)
+	}
+
+	return utils.Success(c, http.StatusCreated, "Quiz created successfully", toQuizResponse(*created, true), nil)
+}
+
+// GetQuiz godoc
+// @Summary Get quiz detail
+// @Description Menampilkan quiz. Soal, pilihan dan kunci jawaban hanya untuk teacher/admin; student hanya menerima pengaturan quiz dan mendapat soal lewat attempt.
+// @Tags Quizzes
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Success 200 {object} dto.QuizResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id} [get]
+func (ctrl *QuizController) GetQuiz(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id", "quiz_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	role := currentCourseRole(c)
+	found, err := ctrl.quizService.GetQuiz(context.Background(), role, ids[0], ids[1])
+	if err != nil {
+		return quizError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Get quiz successfully", toQuizResponse(*found, quiz.CanAuthor(role)), nil)
+}
+
+// UpdateQuiz godoc
+// @Summary Update quiz
+// @Description Mengubah pengaturan quiz (judul, jadwal, batas waktu, jumlah attempt)
+// @Tags Quizzes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Param request body dto.QuizRequest true "Quiz payload"
+// @Success 200 {object} dto.QuizResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id} [put]
+func (ctrl *QuizController) UpdateQuiz(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id", "quiz_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	var req dto.QuizRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+
+	updated, err := ctrl.quizService.UpdateQuiz(context.Background(), currentCourseRole(c), ids[0], ids[1], toQuizInput(req))
+	if err != nil {
+		return quizError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Quiz updated successfully", toQuizResponse(*updated, true), nil)
+}
+
+// DeleteQuiz godoc
+// @Summary Delete quiz
+// @Description Menghapus quiz beserta soal, pilihan dan attempt
+// @Tags Quizzes
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Success 200 {object} utils.SuccessResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id} [delete]
+func (ctrl *QuizController) DeleteQuiz(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id", "quiz_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	if err := ctrl.quizService.DeleteQuiz(context.Background(), currentCourseRole(c), ids[0], ids[1]); err != nil {
+		return quizError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Quiz deleted successfully", nil, nil)
+}
+
+// PublishQuiz godoc
+// @Summary Publish quiz
+// @Description Memvalidasi lalu menampilkan quiz ke student. Soal single-choice wajib punya tepat satu jawaban benar.
+// @Tags Quizzes
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Success 200 {object} dto.QuizResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 422 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id}/publish [post]
+func (ctrl *QuizController) PublishQuiz(c *fiber.Ctx) error {
+	return ctrl.setPublished(c, true, "Quiz published successfully")
+}
+
+// UnpublishQuiz godoc
+// @Summary Unpublish quiz
+// @Description Menyembunyikan quiz dari student
+// @Tags Quizzes
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Success 200 {object} dto.QuizResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id}/unpublish [post]
+func (ctrl *QuizController) UnpublishQuiz(c *fiber.Ctx) error {
+	return ctrl.setPublished(c, false, "Quiz unpublished successfully")
+}
+
+func (ctrl *QuizController) setPublished(c *fiber.Ctx, published bool, message string) error {
+	ids, err := parseUUIDParams(c, "course_id", "quiz_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	updated, err := ctrl.quizService.SetPublished(context.Background(), currentCourseRole(c), ids[0], ids[1], published)
+	if err != nil {
+		return quizError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, message, toQuizResponse(*updated, true), nil)
+}
+
+// ReleaseResults godoc
+// @Summary Release quiz results
+// @Description Merilis hasil quiz ke student sekarang juga, untuk kebijakan MANUAL atau sebelum close_at pada AFTER_CLOSE
+// @Tags Quizzes
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Success 200 {object} dto.QuizResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id}/results/release [post]
+func (ctrl *QuizController) ReleaseResults(c *fiber.Ctx) error {
+	return ctrl.setResultsReleased(c, true, "Quiz results released successfully")
+}
+
+// WithholdResults godoc
+// @Summary Withhold quiz results
+// @Description Menarik kembali rilis manual hasil quiz; kebijakan rilis quiz tetap berlaku
+// @Tags Quizzes
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Success 200 {object} dto.QuizResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id}/results/withhold [post]
+func (ctrl *QuizController) WithholdResults(c *fiber.Ctx) error {
+	return ctrl.setResultsReleased(c, false, "Quiz results withheld successfully")
+}
+
+func (ctrl *QuizController) setResultsReleased(c *fiber.Ctx, released bool, message string) error {
+	ids, err := parseUUIDParams(c, "course_id", "quiz_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	updated, err := ctrl.quizService.SetResultsReleased(context.Background(), currentCourseRole(c), ids[0], ids[1], released)
+	if err != nil {
+		return quizError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, message, toQuizResponse(*updated, true), nil)
+}
+
+// CreateQuestion godoc
+// @Summary Create question
+// @Description Menambahkan soal beserta pilihannya ke quiz, position default di akhir
+// @Tags Quizzes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Param request body dto.QuestionRequest true "Question payload"
+// @Success 201 {object} dto.QuestionResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 422 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id}/questions [post]
+func (ctrl *QuizController) CreateQuestion(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id", "quiz_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	var req dto.QuestionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+
+	created, err := ctrl.quizService.CreateQuestion(context.Background(), currentCourseRole(c), ids[0], ids[1], toQuestionInput(req))
+	if err != nil {
+		return quizError(c, err)
+	}
+
+	return utils.Success(c, http.StatusCreated, "Question created successfully", toQuestionResponse(*created, true), nil)
+}
+
+// ImportQuestions godoc
+// @Summary Import questions
+// @Description Menambahkan soal dari file GIFT, Aiken, CSV atau paket QTI 2.1 (zip) setelah soal yang sudah ada. Dengan dry_run=true file hanya diperiksa; kesalahan dikembalikan per baris.
+// @Tags Quizzes
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Param file formData file true "File soal"
+// @Param format formData string false "gift, aiken, csv atau qti (default dari ekstensi file)"
+// @Param dry_run formData boolean false "Hanya validasi tanpa menyimpan"
+// @Success 200 {object} utils.SuccessResponse{data=dto.ImportQuestionsResponse}
+// @Success 201 {object} utils.SuccessResponse{data=dto.ImportQuestionsResponse}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 413 {object} utils.ErrorResponse
+// @Failure 422 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id}/import [post]
+func (ctrl *QuizController) ImportQuestions(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id", "quiz_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "File is required", "ValidationError", nil)
+	}
+
+	format := strings.ToLower(strings.TrimSpace(c.FormValue("format")))
+	if format == "" {
+		format = importFormatFromName(fileHeader.Filename)
+	}
+
+	dryRun := false
+	if value := c.FormValue("dry_run"); value != "" {
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			return utils.Error(c, http.StatusBadRequest, "dry_run must be true or false", "BadRequestException", nil)
+		}
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Failed to read uploaded file", "BadRequestException", nil)
+	}
+	defer file.Close()
+
+	// baca satu byte lebih agar file yang melewati batas tetap ditolak service
+	data, err := io.ReadAll(io.LimitReader(file, quiz.MaxImportSize+1))
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Failed to read uploaded file", "BadRequestException", nil)
+	}
+
+	result, err := ctrl.quizService.ImportQuestions(context.Background(), currentCourseRole(c), ids[0], ids[1], quiz.ImportInput{
+		Format: quiz.ImportFormat(format),
+		Data:   data,
+		DryRun: dryRun,
+	})
+	if err != nil {
+		return quizError(c, err)
+	}
+
+	resp := dto.ImportQuestionsResponse{DryRun: result.DryRun, Count: len(result.Questions)}
+	for _, q := range result.Questions {
+		question := toQuestionResponse(q, true)
+		if result.DryRun {
+			question.ID = ""
+		}
+		resp.Questions = append(resp.Questions, question)
+	}
+
+	if result.DryRun {
+		return utils.Success(c, http.StatusOK, "Import file is valid", resp, nil)
+	}
+	return utils.Success(c, http.StatusCreated, "Questions imported successfully", resp, nil)
+}
+
+// ExportQTI godoc
+// @Summary Export quiz as QTI 2.1
+// @Description Mengunduh soal quiz (tanpa soal pool dari bank) sebagai paket zip IMS QTI 2.1
+// @Tags Quizzes
+// @Produce application/zip
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Success 200 {file} file
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id}/export/qti [get]
+func (ctrl *QuizController) ExportQTI(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id", "quiz_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	var buf bytes.Buffer
+	q, err := ctrl.quizService.ExportQTI(context.Background(), currentCourseRole(c), ids[0], ids[1], &buf)
+	if err != nil {
+		return quizError(c, err)
+	}
+
+	name := "quiz-" + q.ID.String() + "-qti.zip"
+	c.Set(fiber.HeaderContentType, "application/zip")
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	return c.Status(http.StatusOK).Send(buf.Bytes())
+}
+
+// importFormatFromName menebak format dari ekstensi; file .txt dianggap Aiken
+func importFormatFromName(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".gift":
+		return string(quiz.ImportFormatGIFT)
+	case ".csv":
+		return string(quiz.ImportFormatCSV)
+	case ".txt":
+		return string(quiz.ImportFormatAiken)
+	case ".zip":
+		return string(quiz.ImportFormatQTI)
+	default:
+		return ""
+	}
+}
+
+// UpdateQuestion godoc
+// @Summary Update question
+// @Description Mengubah tipe, teks atau posisi soal. Pilihan dikelola lewat endpoint choices.
+// @Tags Quizzes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Param question_id path string true "Question ID"
+// @Param request body dto.QuestionRequest true "Question payload"
+// @Success 200 {object} dto.QuestionResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 422 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id}/questions/{question_id} [put]
+func (ctrl *QuizController) UpdateQuestion(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id", "quiz_id", "question_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	var req dto.QuestionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+
+	updated, err := ctrl.quizService.UpdateQuestion(context.Background(), currentCourseRole(c), ids[0], ids[1], ids[2], toQuestionInput(req))
+	if err != nil {
+		return quizError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Question updated successfully", toQuestionResponse(*updated, true), nil)
+}
+
+// DeleteQuestion godoc
+// @Summary Delete question
+// @Description Menghapus soal beserta pilihannya
+// @Tags Quizzes
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Param question_id path string true "Question ID"
+// @Success 200 {object} utils.SuccessResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id}/questions/{question_id} [delete]
+func (ctrl *QuizController) DeleteQuestion(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id", "quiz_id", "question_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	if err := ctrl.quizService.DeleteQuestion(context.Background(), currentCourseRole(c), ids[0], ids[1], ids[2]); err != nil {
+		return quizError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Question deleted successfully", nil, nil)
+}
+
+// CreateChoice godoc
+// @Summary Create choice
+// @Description Menambahkan pilihan jawaban ke soal
+// @Tags Quizzes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Param question_id path string true "Question ID"
+// @Param request body dto.ChoiceRequest true "Choice payload"
+// @Success 201 {object} dto.ChoiceResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 422 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id}/questions/{question_id}/choices [post]
+func (ctrl *QuizController) CreateChoice(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id", "quiz_id", "question_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	var req dto.ChoiceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+
+	created, err := ctrl.quizService.CreateChoice(context.Background(), currentCourseRole(c), ids[0], ids[1], ids[2], toChoiceInput(req))
+	if err != nil {
+		return quizError(c, err)
+	}
+
+	return utils.Success(c, http.StatusCreated, "Choice created successfully", toChoiceResponse(*created, true), nil)
+}
+
+// UpdateChoice godoc
+// @Summary Update choice
+// @Description Mengubah teks, kunci jawaban atau posisi pilihan
+// @Tags Quizzes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Param question_id path string true "Question ID"
+// @Param choice_id path string true "Choice ID"
+// @Param request body dto.ChoiceRequest true "Choice payload"
+// @Success 200 {object} dto.ChoiceResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 422 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id}/questions/{question_id}/choices/{choice_id} [put]
+func (ctrl *QuizController) UpdateChoice(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id", "quiz_id", "question_id", "choice_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	var req dto.ChoiceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+
+	updated, err := ctrl.quizService.UpdateChoice(context.Background(), currentCourseRole(c), ids[0], ids[1], ids[2], ids[3], toChoiceInput(req))
+	if err != nil {
+		return quizError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Choice updated successfully", toChoiceResponse(*updated, true), nil)
+}
+
+// DeleteChoice godoc
+// @Summary Delete choice
+// @Description Menghapus pilihan jawaban
+// @Tags Quizzes
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Param question_id path string true "Question ID"
+// @Param choice_id path string true "Choice ID"
+// @Success 200 {object} utils.SuccessResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 422 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id}/questions/{question_id}/choices/{choice_id} [delete]
+func (ctrl *QuizController) DeleteChoice(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id", "quiz_id", "question_id", "choice_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	if err := ctrl.quizService.DeleteChoice(context.Background(), currentCourseRole(c), ids[0], ids[1], ids[2], ids[3]); err != nil {
+		return quizError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Choice deleted successfully", nil, nil)
+}
+
+// CreatePool godoc
+// @Summary Create question pool
+// @Description Menambahkan pool yang mengundi draw_count soal acak dari bank (milik course ini atau bank pribadi teacher) untuk setiap attempt
+// @Tags Quizzes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Param request body dto.QuizPoolRequest true "Pool payload"
+// @Success 201 {object} dto.QuizPoolResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 422 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id}/pools [post]
+func (ctrl *QuizController) CreatePool(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id", "quiz_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	var req dto.QuizPoolRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+	input, err := toPoolInput(req)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid bank ID format", "InvalidUUID", nil)
+	}
+
+	created, err := ctrl.quizService.CreatePool(context.Background(), userID, currentCourseRole(c), ids[0], ids[1], input)
+	if err != nil {
+		return quizError(c, err)
+	}
+
+	return utils.Success(c, http.StatusCreated, "Pool created successfully", toQuizPoolResponse(*created), nil)
+}
+
+// UpdatePool godoc
+// @Summary Update question pool
+// @Description Mengubah bank, tags atau jumlah soal yang diundi. Attempt yang sudah dimulai tetap memakai paper lamanya
+// @Tags Quizzes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Param pool_id path string true "Pool ID"
+// @Param request body dto.QuizPoolRequest true "Pool payload"
+// @Success 200 {object} dto.QuizPoolResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 422 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id}/pools/{pool_id} [put]
+func (ctrl *QuizController) UpdatePool(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id", "quiz_id", "pool_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	var req dto.QuizPoolRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+	input, err := toPoolInput(req)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid bank ID format", "InvalidUUID", nil)
+	}
+
+	updated, err := ctrl.quizService.UpdatePool(context.Background(), userID, currentCourseRole(c), ids[0], ids[1], ids[2], input)
+	if err != nil {
+		return quizError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Pool updated successfully", toQuizPoolResponse(*updated), nil)
+}
+
+// DeletePool godoc
+// @Summary Delete question pool
+// @Description Menghapus pool dari quiz
+// @Tags Quizzes
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Param pool_id path string true "Pool ID"
+// @Success 200 {object} utils.SuccessResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 422 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id}/pools/{pool_id} [delete]
+func (ctrl *QuizController) DeletePool(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id", "quiz_id", "pool_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	if err := ctrl.quizService.DeletePool(context.Background(), currentCourseRole(c), ids[0], ids[1], ids[2]); err != nil {
+		return quizError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Pool deleted successfully", nil, nil)
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"api-shiners/api/handlers/dto"
+	"api-shiners/pkg/attempt"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/utils"
+	"context"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type QuizOverrideController struct {
+	overrideService attempt.OverrideService
+}
+
+func NewQuizOverrideController(overrideService attempt.OverrideService) *QuizOverrideController {
+	return &QuizOverrideController{overrideService: overrideService}
+}
+
+func toQuizOverrideResponse(o entities.QuizOverride) dto.QuizOverrideResponse {
+	resp := dto.QuizOverrideResponse{
+		ID:             o.ID.String(),
+		QuizID:         o.QuizID.String(),
+		StudentID:      o.StudentID.String(),
+		TimeLimitSec:   o.TimeLimitSec,
+		AttemptAllowed: o.AttemptAllowed,
+		OpenAt:         o.OpenAt,
+		CloseAt:        o.CloseAt,
+		Reason:         o.Reason,
+		CreatedByID:    o.CreatedByID.String(),
+		UpdatedAt:      o.UpdatedAt,
+	}
+	if o.Student.ID != uuid.Nil {
+		resp.Student = &dto.UserResponse{
+			ID:       o.Student.ID.String(),
+			Name:     o.Student.Name,
+			Email:    o.Student.Email,
+			IsActive: o.Student.IsActive,
+		}
+	}
+	return resp
+}
+
+func toRosterEntryResponse(entry attempt.RosterEntry) dto.RosterEntryResponse {
+	resp := dto.RosterEntryResponse{
+		Student: dto.UserResponse{
+			ID:       entry.Student.ID.String(),
+			Name:     entry.Student.Name,
+			Email:    entry.Student.Email,
+			IsActive: entry.Student.IsActive,
+		},
+		TimeLimitSec:    entry.Settings.TimeLimitSec,
+		AttemptAllowed:  entry.Settings.AttemptAllowed,
+		OpenAt:          entry.Settings.OpenAt,
+		CloseAt:         entry.Settings.CloseAt,
+		AttemptsUsed:    entry.AttemptsUsed,
+		InProgress:      entry.InProgress,
+		BestScore:       entry.BestScore,
+		LastSubmittedAt: entry.LastSubmittedAt,
+	}
+	if entry.Override != nil {
+		override := toQuizOverrideResponse(*entry.Override)
+		resp.Override = &override
+	}
+	return resp
+}
+
+// GetOverrides godoc
+// @Summary Get quiz overrides
+// @Description Menampilkan semua override per student pada quiz (teacher course atau admin)
+// @Tags Quiz Overrides
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Success 200 {object} utils.SuccessResponse{data=[]dto.QuizOverrideResponse}
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id}/overrides [get]
+func (ctrl *QuizOverrideController) GetOverrides(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id", "quiz_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	overrides, err := ctrl.overrideService.GetOverrides(context.Background(), currentCourseRole(c), ids[0], ids[1])
+	if err != nil {
+		return attemptError(c, err)
+	}
+
+	resp := make([]dto.QuizOverrideResponse, 0, len(overrides))
+	for _, o := range overrides {
+		resp = append(resp, toQuizOverrideResponse(o))
+	}
+	return utils.Success(c, http.StatusOK, "Get overrides successfully", resp, nil)
+}
+
+// SetOverride godoc
+// @Summary Set quiz override for a student
+// @Description Membuat atau mengganti override (waktu, attempt, jendela buka/tutup) untuk satu student. Field yang kosong mengikuti pengaturan quiz.
+// @Tags Quiz Overrides
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Param student_id path string true "Student ID"
+// @Param request body dto.QuizOverrideRequest true "Override"
+// @Success 200 {object} utils.SuccessResponse{data=dto.QuizOverrideResponse}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id}/overrides/{student_id} [put]
+func (ctrl *QuizOverrideController) SetOverride(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+	}
+
+	ids, err := parseUUIDParams(c, "course_id", "quiz_id", "student_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	var req dto.QuizOverrideRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "Invalid request body", "BadRequestException", nil)
+	}
+
+	override, err := ctrl.overrideService.SetOverride(context.Background(), userID, currentCourseRole(c), ids[0], ids[1], ids[2], attempt.OverrideInput{
+		TimeLimitSec:   req.TimeLimitSec,
+		AttemptAllowed: req.AttemptAllowed,
+		OpenAt:         req.OpenAt,
+		CloseAt:        req.CloseAt,
+		Reason:         req.Reason,
+	})
+	if err != nil {
+		return attemptError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Override saved successfully", toQuizOverrideResponse(*override), nil)
+}
+
+// DeleteOverride godoc
+// @Summary Delete quiz override for a student
+// @Description Menghapus override sehingga student kembali mengikuti pengaturan quiz
+// @Tags Quiz Overrides
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Param student_id path string true "Student ID"
+// @Success 200 {object} utils.SuccessResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id}/overrides/{student_id} [delete]
+func (ctrl *QuizOverrideController) DeleteOverride(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id", "quiz_id", "student_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	if err := ctrl.overrideService.DeleteOverride(context.Background(), currentCourseRole(c), ids[0], ids[1], ids[2]); err != nil {
+		return attemptError(c, err)
+	}
+
+	return utils.Success(c, http.StatusOK, "Override deleted successfully", nil, nil)
+}
+
+// GetRoster godoc
+// @Summary Get quiz roster
+// @Description Menampilkan semua student course dengan pengaturan quiz yang berlaku (setelah override) dan ringkasan attempt
+// @Tags Quiz Overrides
+// @Produce json
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Success 200 {object} utils.SuccessResponse{data=[]dto.RosterEntryResponse}
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id}/roster [get]
+func (ctrl *QuizOverrideController) GetRoster(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id", "quiz_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	roster, _, err := ctrl.overrideService.GetRoster(context.Background(), currentCourseRole(c), ids[0], ids[1])
+	if err != nil {
+		return attemptError(c, err)
+	}
+
+	resp := make([]dto.RosterEntryResponse, 0, len(roster))
+	for _, entry := range roster {
+		resp = append(resp, toRosterEntryResponse(entry))
+	}
+	return utils.Success(c, http.StatusOK, "Get roster successfully", resp, nil)
+}
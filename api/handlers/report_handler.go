@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"api-shiners/pkg/report"
+	"api-shiners/pkg/utils"
+	"bytes"
+	"context"
+	"mime"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReportController struct {
+	reportService report.ReportService
+}
+
+func NewReportController(reportService report.ReportService) *ReportController {
+	return &ReportController{reportService: reportService}
+}
+
+// GetItemAnalysis godoc
+// @Summary Get quiz item analysis
+// @Description Statistik soal dari attempt yang sudah dinilai: p-value, point-biserial, sebaran pilihan, histogram nilai dan Cronbach's alpha (teacher course atau admin). Gunakan format=csv untuk unduhan CSV.
+// @Tags Reports
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Param course_id path string true "Course ID"
+// @Param quiz_id path string true "Quiz ID"
+// @Param format query string false "json (default) atau csv"
+// @Success 200 {object} utils.SuccessResponse{data=report.ItemAnalysis}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/courses/{course_id}/quizzes/{quiz_id}/item-analysis [get]
+func (ctrl *ReportController) GetItemAnalysis(c *fiber.Ctx) error {
+	ids, err := parseUUIDParams(c, "course_id", "quiz_id")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error(), "InvalidUUID", nil)
+	}
+
+	format := c.Query("format", "json")
+	if format != "json" && format != "csv" {
+		return utils.Error(c, http.StatusBadRequest, "format must be json or csv", "BadRequestException", nil)
+	}
+
+	analysis, err := ctrl.reportService.GetItemAnalysis(context.Background(), currentCourseRole(c), ids[0], ids[1])
+	if err != nil {
+		return quizError(c, err)
+	}
+
+	if format == "json" {
+		return utils.Success(c, http.StatusOK, "Get item analysis successfully", analysis, nil)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteItemAnalysisCSV(&buf, analysis); err != nil {
+		return utils.Error(c, http.StatusInternalServerError, "Failed to generate report", "InternalServerError", nil)
+	}
+
+	name := "item-analysis-" + analysis.QuizID.String() + ".csv"
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	return c.Status(http.StatusOK).Send(buf.Bytes())
+}
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"api-shiners/api/handlers"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func AttemptRoutes(app *fiber.App, attemptController *handlers.AttemptController, access *middleware.CourseAccess) {
+	api := app.Group("/api")
+
+	member := access.Require(entities.CourseRoleStudent, entities.CourseRoleTeacher)
+	student := access.Require(entities.CourseRoleStudent)
+	teacher := access.Require(entities.CourseRoleTeacher)
+
+	api.Post("/courses/:course_id/quizzes/:quiz_id/attempts", middleware.AuthMiddleware, student, attemptController.StartAttempt)
+	api.Get("/courses/:course_id/quizzes/:quiz_id/attempts/me", middleware.AuthMiddleware, student, attemptController.GetMyAttempts)
+	api.Get("/courses/:course_id/quizzes/:quiz_id/attempts", middleware.TeacherOrAdminMiddleware, teacher, attemptController.GetQuizAttempts)
+
+	api.Get("/courses/:course_id/attempts/:attempt_id", middleware.AuthMiddleware, member, attemptController.GetAttempt)
+	api.Put("/courses/:course_id/attempts/:attempt_id/answers", middleware.AuthMiddleware, student, attemptController.SaveAnswers)
+	api.Post("/courses/:course_id/attempts/:attempt_id/submit", middleware.AuthMiddleware, student, attemptController.SubmitAttempt)
+	api.Get("/courses/:course_id/attempts/:attempt_id/review", middleware.AuthMiddleware, student, attemptController.ReviewAttempt)
+
+	api.Get("/courses/:course_id/quizzes/:quiz_id/grading-queue", middleware.TeacherOrAdminMiddleware, teacher, attemptController.GetGradingQueue)
+	api.Put("/courses/:course_id/answers/:answer_id/grade", middleware.TeacherOrAdminMiddleware, teacher, attemptController.GradeAnswer)
+
+	api.Post("/courses/:course_id/quizzes/:quiz_id/regrade", middleware.TeacherOrAdminMiddleware, teacher, attemptController.RegradeQuiz)
+	api.Get("/courses/:course_id/quizzes/:quiz_id/regrades", middleware.TeacherOrAdminMiddleware, teacher, attemptController.GetRegrades)
+}
@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"api-shiners/api/handlers"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func CourseRoutes(app *fiber.App, courseController *handlers.CourseController, access *middleware.CourseAccess) {
+	api := app.Group("/api")
+
+	member := access.Require(entities.CourseRoleStudent, entities.CourseRoleTeacher)
+	teacher := access.Require(entities.CourseRoleTeacher)
+
+	api.Get("/courses", middleware.AuthMiddleware, courseController.GetAllCourses)
+	api.Post("/courses", middleware.TeacherOrAdminMiddleware, courseController.CreateCourse)
+
+	// detail course yang sudah dipublish tetap bisa dilihat sebelum join
+	api.Get("/courses/:course_id", middleware.AuthMiddleware, courseController.GetCourseByID)
+	api.Put("/courses/:course_id", middleware.TeacherOrAdminMiddleware, teacher, courseController.UpdateCourse)
+	api.Delete("/courses/:course_id", middleware.TeacherOrAdminMiddleware, teacher, courseController.DeleteCourse)
+
+	api.Post("/courses/:course_id/publish", middleware.TeacherOrAdminMiddleware, teacher, courseController.PublishCourse)
+	api.Post("/courses/:course_id/unpublish", middleware.TeacherOrAdminMiddleware, teacher, courseController.UnpublishCourse)
+
+	api.Get("/courses/:course_id/modules", middleware.AuthMiddleware, member, courseController.GetModules)
+	api.Post("/courses/:course_id/modules", middleware.TeacherOrAdminMiddleware, teacher, courseController.CreateModule)
+	// reorder didaftarkan sebelum /:module_id agar tidak tertangkap sebagai module_id
+	api.Put("/courses/:course_id/modules/reorder", middleware.TeacherOrAdminMiddleware, teacher, courseController.ReorderModules)
+	api.Put("/courses/:course_id/modules/:module_id", middleware.TeacherOrAdminMiddleware, teacher, courseController.UpdateModule)
+	api.Delete("/courses/:course_id/modules/:module_id", middleware.TeacherOrAdminMiddleware, teacher, courseController.DeleteModule)
+
+	api.Put("/courses/:course_id/modules/:module_id/materials/reorder", middleware.TeacherOrAdminMiddleware, teacher, courseController.ReorderMaterials)
+}
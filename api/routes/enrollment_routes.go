@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"api-shiners/api/handlers"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func EnrollmentRoutes(app *fiber.App, enrollmentController *handlers.EnrollmentController, access *middleware.CourseAccess) {
+	api := app.Group("/api")
+
+	teacher := access.Require(entities.CourseRoleTeacher)
+
+	api.Get("/enrollments/me", middleware.AuthMiddleware, enrollmentController.GetMyEnrollments)
+	api.Post("/enrollments/join", middleware.AuthMiddleware, enrollmentController.JoinByCode)
+
+	api.Get("/courses/:course_id/enrollments", middleware.TeacherOrAdminMiddleware, teacher, enrollmentController.GetEnrollments)
+	api.Post("/courses/:course_id/enrollments", middleware.TeacherOrAdminMiddleware, teacher, enrollmentController.Enroll)
+	api.Post("/courses/:course_id/enrollments/bulk", middleware.TeacherOrAdminMiddleware, teacher, enrollmentController.BulkEnroll)
+	api.Delete("/courses/:course_id/enrollments/:user_id", middleware.TeacherOrAdminMiddleware, teacher, enrollmentController.Unenroll)
+
+	api.Get("/courses/:course_id/join-code", middleware.TeacherOrAdminMiddleware, teacher, enrollmentController.GetJoinCode)
+	api.Post("/courses/:course_id/join-code/rotate", middleware.TeacherOrAdminMiddleware, teacher, enrollmentController.RotateJoinCode)
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"api-shiners/api/handlers"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func IntegrityRoutes(app *fiber.App, integrityController *handlers.IntegrityController, access *middleware.CourseAccess) {
+	api := app.Group("/api")
+
+	student := access.Require(entities.CourseRoleStudent)
+	teacher := access.Require(entities.CourseRoleTeacher)
+
+	api.Post("/courses/:course_id/attempts/:attempt_id/events", middleware.AuthMiddleware, student, integrityController.RecordEvents)
+	api.Get("/courses/:course_id/attempts/:attempt_id/events", middleware.TeacherOrAdminMiddleware, teacher, integrityController.GetAttemptTimeline)
+	api.Get("/courses/:course_id/quizzes/:quiz_id/integrity", middleware.TeacherOrAdminMiddleware, teacher, integrityController.GetQuizIntegrity)
+}
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"api-shiners/api/handlers"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/middleware"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+func LiveRoutes(app *fiber.App, liveController *handlers.LiveController, access *middleware.CourseAccess) {
+	api := app.Group("/api")
+
+	teacher := access.Require(entities.CourseRoleTeacher)
+
+	api.Post("/courses/:course_id/quizzes/:quiz_id/live", middleware.TeacherOrAdminMiddleware, teacher, liveController.OpenSession)
+	api.Get("/courses/:course_id/live/:session_id", middleware.TeacherOrAdminMiddleware, teacher, liveController.GetSession)
+	api.Post("/courses/:course_id/live/:session_id/next", middleware.TeacherOrAdminMiddleware, teacher, liveController.NextQuestion)
+	api.Post("/courses/:course_id/live/:session_id/reveal", middleware.TeacherOrAdminMiddleware, teacher, liveController.RevealQuestion)
+	api.Post("/courses/:course_id/live/:session_id/end", middleware.TeacherOrAdminMiddleware, teacher, liveController.EndSession)
+	api.Post("/courses/:course_id/live/:session_id/ticket", middleware.TeacherOrAdminMiddleware, teacher, liveController.HostTicket)
+
+	// student bergabung dengan PIN; course diketahui dari sesi sehingga akses dicek di service
+	api.Post("/live/join", middleware.AuthMiddleware, liveController.JoinSession)
+	// WebSocket memakai tiket dari join/ticket karena browser tidak bisa mengirim header Authorization
+	api.Get("/live/ws", liveController.UpgradeStream, websocket.New(liveController.Stream))
+}
@@ -0,0 +1,44 @@
+package routes
+
+import (
+	"api-shiners/api/handlers"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func LogBookRoutes(app *fiber.App, logBookController *handlers.LogBookController, scheduleController *handlers.LogBookScheduleController, access *middleware.CourseAccess) {
+	api := app.Group("/api")
+
+	member := access.Require(entities.CourseRoleStudent, entities.CourseRoleTeacher)
+	student := access.Require(entities.CourseRoleStudent)
+	teacher := access.Require(entities.CourseRoleTeacher)
+
+	api.Post("/courses/:course_id/logbooks", middleware.AuthMiddleware, student, logBookController.CreateLogBook)
+	api.Get("/courses/:course_id/logbooks/me", middleware.AuthMiddleware, student, logBookController.GetMyLogBooks)
+	api.Get("/courses/:course_id/logbooks", middleware.TeacherOrAdminMiddleware, teacher, logBookController.GetCourseLogBooks)
+	api.Get("/courses/:course_id/logbooks/compliance", middleware.TeacherOrAdminMiddleware, teacher, logBookController.GetComplianceReport)
+	api.Get("/courses/:course_id/logbooks/:logbook_id", middleware.AuthMiddleware, member, logBookController.GetLogBook)
+	api.Get("/courses/:course_id/logbooks/:logbook_id/pdf", middleware.AuthMiddleware, member, logBookController.ExportPDF)
+
+	api.Post("/courses/:course_id/logbooks/:logbook_id/entries", middleware.AuthMiddleware, student, logBookController.AddEntry)
+	api.Put("/courses/:course_id/logbooks/:logbook_id/entries/:entry_id", middleware.AuthMiddleware, student, logBookController.UpdateEntry)
+	api.Delete("/courses/:course_id/logbooks/:logbook_id/entries/:entry_id", middleware.AuthMiddleware, student, logBookController.DeleteEntry)
+	api.Post("/courses/:course_id/logbooks/:logbook_id/entries/:entry_id/attachments", middleware.AuthMiddleware, student, logBookController.UploadAttachment)
+	api.Delete("/courses/:course_id/logbooks/:logbook_id/entries/:entry_id/attachments/:attachment_id", middleware.AuthMiddleware, student, logBookController.DeleteAttachment)
+	api.Get("/courses/:course_id/logbooks/:logbook_id/entries/:entry_id/attachments/:attachment_id/download-url", middleware.AuthMiddleware, member, logBookController.GetAttachmentURL)
+
+	api.Post("/courses/:course_id/logbooks/:logbook_id/submit", middleware.AuthMiddleware, student, logBookController.SubmitLogBook)
+	api.Post("/courses/:course_id/logbooks/:logbook_id/lock", middleware.TeacherOrAdminMiddleware, teacher, logBookController.LockLogBook)
+	api.Post("/courses/:course_id/logbooks/:logbook_id/approve", middleware.TeacherOrAdminMiddleware, teacher, logBookController.ApproveLogBook)
+	api.Post("/courses/:course_id/logbooks/:logbook_id/request-revision", middleware.TeacherOrAdminMiddleware, teacher, logBookController.RequestRevision)
+
+	api.Post("/courses/:course_id/logbooks/:logbook_id/comments", middleware.TeacherOrAdminMiddleware, teacher, logBookController.AddComment)
+	api.Get("/courses/:course_id/logbooks/:logbook_id/comments", middleware.AuthMiddleware, member, logBookController.GetComments)
+	api.Get("/courses/:course_id/logbooks/:logbook_id/history", middleware.AuthMiddleware, member, logBookController.GetHistory)
+
+	api.Get("/courses/:course_id/logbook-schedule", middleware.TeacherOrAdminMiddleware, teacher, scheduleController.GetSchedule)
+	api.Put("/courses/:course_id/logbook-schedule", middleware.TeacherOrAdminMiddleware, teacher, scheduleController.SaveSchedule)
+	api.Delete("/courses/:course_id/logbook-schedule", middleware.TeacherOrAdminMiddleware, teacher, scheduleController.DeleteSchedule)
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"api-shiners/api/handlers"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func MaterialRoutes(app *fiber.App, materialController *handlers.MaterialController, fileController *handlers.FileController, access *middleware.CourseAccess) {
+	api := app.Group("/api")
+
+	member := access.Require(entities.CourseRoleStudent, entities.CourseRoleTeacher)
+	teacher := access.Require(entities.CourseRoleTeacher)
+
+	api.Get("/courses/:course_id/modules/:module_id/materials", middleware.AuthMiddleware, member, materialController.GetMaterials)
+	api.Post("/courses/:course_id/modules/:module_id/materials/upload", middleware.TeacherOrAdminMiddleware, teacher, materialController.UploadFile)
+
+	api.Delete("/courses/:course_id/materials/:material_id", middleware.TeacherOrAdminMiddleware, teacher, materialController.DeleteMaterial)
+	api.Get("/courses/:course_id/materials/:material_id/download-url", middleware.AuthMiddleware, member, materialController.GetDownloadURL)
+
+	// URL bertanda tangan, tidak butuh token
+	api.Get("/files/*", fileController.Download)
+}
@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"api-shiners/api/handlers"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func QuestionBankRoutes(app *fiber.App, bankController *handlers.QuestionBankController, access *middleware.CourseAccess) {
+	api := app.Group("/api")
+
+	teacher := access.Require(entities.CourseRoleTeacher)
+
+	// bank pribadi teacher
+	personal := api.Group("/question-banks", middleware.TeacherOrAdminMiddleware)
+	registerQuestionBankRoutes(personal, bankController)
+
+	// bank milik course, dikelola teacher course
+	course := api.Group("/courses/:course_id/question-banks", middleware.TeacherOrAdminMiddleware, teacher)
+	registerQuestionBankRoutes(course, bankController)
+}
+
+func registerQuestionBankRoutes(group fiber.Router, bankController *handlers.QuestionBankController) {
+	group.Get("/", bankController.GetBanks)
+	group.Post("/", bankController.CreateBank)
+	group.Get("/:bank_id", bankController.GetBank)
+	group.Put("/:bank_id", bankController.UpdateBank)
+	group.Delete("/:bank_id", bankController.DeleteBank)
+
+	group.Post("/:bank_id/questions", bankController.CreateQuestion)
+	group.Put("/:bank_id/questions/:question_id", bankController.UpdateQuestion)
+	group.Delete("/:bank_id/questions/:question_id", bankController.DeleteQuestion)
+
+	group.Post("/:bank_id/questions/:question_id/choices", bankController.CreateChoice)
+	group.Put("/:bank_id/questions/:question_id/choices/:choice_id", bankController.UpdateChoice)
+	group.Delete("/:bank_id/questions/:question_id/choices/:choice_id", bankController.DeleteChoice)
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"api-shiners/api/handlers"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func QuizOverrideRoutes(app *fiber.App, overrideController *handlers.QuizOverrideController, access *middleware.CourseAccess) {
+	api := app.Group("/api")
+
+	teacher := access.Require(entities.CourseRoleTeacher)
+
+	api.Get("/courses/:course_id/quizzes/:quiz_id/overrides", middleware.TeacherOrAdminMiddleware, teacher, overrideController.GetOverrides)
+	api.Put("/courses/:course_id/quizzes/:quiz_id/overrides/:student_id", middleware.TeacherOrAdminMiddleware, teacher, overrideController.SetOverride)
+	api.Delete("/courses/:course_id/quizzes/:quiz_id/overrides/:student_id", middleware.TeacherOrAdminMiddleware, teacher, overrideController.DeleteOverride)
+	api.Get("/courses/:course_id/quizzes/:quiz_id/roster", middleware.TeacherOrAdminMiddleware, teacher, overrideController.GetRoster)
+}
@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"api-shiners/api/handlers"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func QuizRoutes(app *fiber.App, quizController *handlers.QuizController, access *middleware.CourseAccess) {
+	api := app.Group("/api")
+
+	member := access.Require(entities.CourseRoleStudent, entities.CourseRoleTeacher)
+	teacher := access.Require(entities.CourseRoleTeacher)
+
+	api.Get("/courses/:course_id/modules/:module_id/quizzes", middleware.AuthMiddleware, member, quizController.GetQuizzes)
+	api.Post("/courses/:course_id/modules/:module_id/quizzes", middleware.TeacherOrAdminMiddleware, teacher, quizController.CreateQuiz)
+
+	api.Get("/courses/:course_id/quizzes/:quiz_id", middleware.AuthMiddleware, member, quizController.GetQuiz)
+	api.Put("/courses/:course_id/quizzes/:quiz_id", middleware.TeacherOrAdminMiddleware, teacher, quizController.UpdateQuiz)
+	api.Delete("/courses/:course_id/quizzes/:quiz_id", middleware.TeacherOrAdminMiddleware, teacher, quizController.DeleteQuiz)
+	api.Post("/courses/:course_id/quizzes/:quiz_id/publish", middleware.TeacherOrAdminMiddleware, teacher, quizController.PublishQuiz)
+	api.Post("/courses/:course_id/quizzes/:quiz_id/unpublish", middleware.TeacherOrAdminMiddleware, teacher, quizController.UnpublishQuiz)
+	api.Post("/courses/:course_id/quizzes/:quiz_id/results/release", middleware.TeacherOrAdminMiddleware, teacher, quizController.ReleaseResults)
+	api.Post("/courses/:course_id/quizzes/:quiz_id/results/withhold", middleware.TeacherOrAdminMiddleware, teacher, quizController.WithholdResults)
+
+	api.Post("/courses/:course_id/quizzes/:quiz_id/questions", middleware.TeacherOrAdminMiddleware, teacher, quizController.CreateQuestion)
+	api.Post("/courses/:course_id/quizzes/:quiz_id/import", middleware.TeacherOrAdminMiddleware, teacher, quizController.ImportQuestions)
+	api.Get("/courses/:course_id/quizzes/:quiz_id/export/qti", middleware.TeacherOrAdminMiddleware, teacher, quizController.ExportQTI)
+	api.Put("/courses/:course_id/quizzes/:quiz_id/questions/:question_id", middleware.TeacherOrAdminMiddleware, teacher, quizController.UpdateQuestion)
+	api.Delete("/courses/:course_id/quizzes/:quiz_id/questions/:question_id", middleware.TeacherOrAdminMiddleware, teacher, quizController.DeleteQuestion)
+
+	api.Post("/courses/:course_id/quizzes/:quiz_id/questions/:question_id/choices", middleware.TeacherOrAdminMiddleware, teacher, quizController.CreateChoice)
+	api.Put("/courses/:course_id/quizzes/:quiz_id/questions/:question_id/choices/:choice_id", middleware.TeacherOrAdminMiddleware, teacher, quizController.UpdateChoice)
+	api.Delete("/courses/:course_id/quizzes/:quiz_id/questions/:question_id/choices/:choice_id", middleware.TeacherOrAdminMiddleware, teacher, quizController.DeleteChoice)
+
+	api.Post("/courses/:course_id/quizzes/:quiz_id/pools", middleware.TeacherOrAdminMiddleware, teacher, quizController.CreatePool)
+	api.Put("/courses/:course_id/quizzes/:quiz_id/pools/:pool_id", middleware.TeacherOrAdminMiddleware, teacher, quizController.UpdatePool)
+	api.Delete("/courses/:course_id/quizzes/:quiz_id/pools/:pool_id", middleware.TeacherOrAdminMiddleware, teacher, quizController.DeletePool)
+}
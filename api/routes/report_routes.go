@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"api-shiners/api/handlers"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func ReportRoutes(app *fiber.App, reportController *handlers.ReportController, access *middleware.CourseAccess) {
+	api := app.Group("/api")
+
+	teacher := access.Require(entities.CourseRoleTeacher)
+
+	api.Get("/courses/:course_id/quizzes/:quiz_id/item-analysis", middleware.TeacherOrAdminMiddleware, teacher, reportController.GetItemAnalysis)
+}
@@ -11,15 +11,27 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"api-shiners/api/handlers"
 	"api-shiners/api/routes"
+	"api-shiners/pkg/attempt"
 	"api-shiners/pkg/auth"
 	"api-shiners/pkg/config"
+	"api-shiners/pkg/course"
+	"api-shiners/pkg/enrollment"
 	"api-shiners/pkg/feedback"
+	"api-shiners/pkg/live"
+	"api-shiners/pkg/logbook"
+	"api-shiners/pkg/material"
+	"api-shiners/pkg/middleware"
+	"api-shiners/pkg/quiz"
+	"api-shiners/pkg/report"
+	"api-shiners/pkg/scheduler"
 	"api-shiners/pkg/user"
 
 	_ "api-shiners/docs"
@@ -40,8 +52,16 @@ func main() {
 	// Koneksi ke Redis
 	config.InitRedis()
 
-	// Buat Fiber app
-	app := fiber.New()
+	// Storage file (lokal / S3)
+	config.InitStorage()
+
+	uploadPolicy := material.UploadPolicyFromEnv()
+	attachmentPolicy := logbook.AttachmentPolicyFromEnv()
+
+	// Buat Fiber app, body limit mengikuti batas upload terbesar (material / lampiran logbook)
+	app := fiber.New(fiber.Config{
+		BodyLimit: int(max(uploadPolicy.MaxBytes, attachmentPolicy.MaxBytes)) + 1<<20,
+	})
 
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     "http://localhost:5173",
@@ -73,7 +93,98 @@ func main() {
 	feedbackService := feedback.NewFeedbackService(feedbackRepo)
 	feedbackController := handlers.NewFeedbackController(feedbackService)
 
+	courseRepo := course.NewCourseRepository(config.DB)
+	courseService := course.NewCourseService(courseRepo)
+	courseController := handlers.NewCourseController(courseService)
+
+	materialRepo := material.NewMaterialRepository(config.DB)
+	materialService := material.NewMaterialService(materialRepo, courseRepo, config.Storage, uploadPolicy)
+	materialController := handlers.NewMaterialController(materialService)
+	fileController := handlers.NewFileController(config.Storage)
+
+	enrollmentRepo := enrollment.NewEnrollmentRepository(config.DB)
+	enrollmentService := enrollment.NewEnrollmentService(enrollmentRepo, courseRepo)
+	enrollmentController := handlers.NewEnrollmentController(enrollmentService)
+
+	// akses per course berdasarkan Enrollment.RoleInCourse
+	courseAccess := middleware.NewCourseAccess(enrollmentService)
+
+	quizRepo := quiz.NewQuizRepository(config.DB)
+	bankRepo := quiz.NewBankRepository(config.DB)
+	quizService := quiz.NewQuizService(quizRepo, bankRepo, courseRepo)
+	quizController := handlers.NewQuizController(quizService)
+	bankService := quiz.NewBankService(bankRepo, quizRepo)
+	bankController := handlers.NewQuestionBankController(bankService)
+
+	attemptRepo := attempt.NewAttemptRepository(config.DB)
+	overrideRepo := attempt.NewOverrideRepository(config.DB)
+	attemptService := attempt.NewAttemptService(attemptRepo, overrideRepo, quizRepo, courseRepo)
+	overrideService := attempt.NewOverrideService(overrideRepo, attemptRepo, quizRepo, enrollmentRepo)
+	overrideController := handlers.NewQuizOverrideController(overrideService)
+	eventRepo := attempt.NewEventRepository(config.DB)
+	integrityService := attempt.NewIntegrityService(eventRepo, attemptRepo, quizRepo)
+	integrityController := handlers.NewIntegrityController(integrityService)
+	attemptController := handlers.NewAttemptController(attemptService, integrityService)
+
+	liveService := live.NewLiveService(live.NewStore(config.RedisClient), quizRepo, enrollmentRepo)
+	liveController := handlers.NewLiveController(liveService)
+
+	logBookRepo := logbook.NewLogBookRepository(config.DB)
+	logBookService := logbook.NewLogBookService(logBookRepo, enrollmentRepo, config.Storage, attachmentPolicy)
+	logBookController := handlers.NewLogBookController(logBookService, config.DocumentHeader())
+	logBookScheduleService := logbook.NewScheduleService(logbook.NewScheduleRepository(config.DB), logBookRepo)
+	logBookScheduleController := handlers.NewLogBookScheduleController(logBookScheduleService)
+
+	reportService := report.NewReportService(attemptRepo, quizRepo)
+	reportController := handlers.NewReportController(reportService)
+
+	// job latar belakang, aman dijalankan di beberapa replica karena memakai lock
+	jobLocker := scheduler.NewLocker(config.RedisClient, config.DB)
+	scheduler.RunEvery(context.Background(), "quiz-auto-submit",
+		scheduler.IntervalFromEnv("QUIZ_AUTOSUBMIT_INTERVAL_SEC", time.Minute), jobLocker,
+		func(ctx context.Context) error {
+			count, err := attemptService.AutoSubmitExpired(ctx)
+			if count > 0 {
+				log.Printf("📝 Auto-submitted %d expired quiz attempts", count)
+			}
+			return err
+		})
+	scheduler.RunEvery(context.Background(), "live-reveal",
+		scheduler.IntervalFromEnv("LIVE_REVEAL_INTERVAL_SEC", time.Second), jobLocker,
+		func(ctx context.Context) error {
+			_, err := liveService.RevealExpired(ctx, time.Now())
+			return err
+		})
+	scheduler.RunEvery(context.Background(), "logbook-periods",
+		scheduler.IntervalFromEnv("LOGBOOK_SCHEDULER_INTERVAL_SEC", time.Hour), jobLocker,
+		func(ctx context.Context) error {
+			now := time.Now()
+			created, err := logBookScheduleService.GeneratePeriods(ctx, now)
+			if created > 0 {
+				log.Printf("📒 Generated %d logbooks from schedules", created)
+			}
+			closed, closeErr := logBookScheduleService.AutoClose(ctx, now)
+			if closed > 0 {
+				log.Printf("🔒 Auto-closed %d logbooks after grace period", closed)
+			}
+			if err != nil {
+				return err
+			}
+			return closeErr
+		})
+
 	routes.FeedbackRoutes(app, feedbackController)
+	routes.CourseRoutes(app, courseController, courseAccess)
+	routes.MaterialRoutes(app, materialController, fileController, courseAccess)
+	routes.EnrollmentRoutes(app, enrollmentController, courseAccess)
+	routes.QuizRoutes(app, quizController, courseAccess)
+	routes.QuestionBankRoutes(app, bankController, courseAccess)
+	routes.AttemptRoutes(app, attemptController, courseAccess)
+	routes.QuizOverrideRoutes(app, overrideController, courseAccess)
+	routes.IntegrityRoutes(app, integrityController, courseAccess)
+	routes.LiveRoutes(app, liveController, courseAccess)
+	routes.ReportRoutes(app, reportController, courseAccess)
+	routes.LogBookRoutes(app, logBookController, logBookScheduleController, courseAccess)
 	routes.UserRoutes(app, userController)
 	routes.HealthRoutes(app, healthController)
 	routes.AuthRoutes(app, authController)
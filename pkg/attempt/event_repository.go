@@ -0,0 +1,51 @@
+package attempt
+
+import (
+	"api-shiners/pkg/entities"
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EventRepository sengaja tidak menyediakan update/delete: log integritas hanya ditambah
+type EventRepository interface {
+	CreateEvents(ctx context.Context, events []entities.AttemptEvent) error
+	GetEventsByAttempt(ctx context.Context, attemptID uuid.UUID) ([]entities.AttemptEvent, error)
+	// GetEventsByQuiz memuat kejadian semua attempt quiz, terurut per attempt lalu waktu server
+	GetEventsByQuiz(ctx context.Context, quizID uuid.UUID) ([]entities.AttemptEvent, error)
+}
+
+type eventRepository struct {
+	db *gorm.DB
+}
+
+func NewEventRepository(db *gorm.DB) EventRepository {
+	return &eventRepository{db}
+}
+
+func (r *eventRepository) CreateEvents(ctx context.Context, events []entities.AttemptEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Omit("Attempt").Create(&events).Error
+}
+
+func (r *eventRepository) GetEventsByAttempt(ctx context.Context, attemptID uuid.UUID) ([]entities.AttemptEvent, error) {
+	var events []entities.AttemptEvent
+	err := r.db.WithContext(ctx).
+		Where("attempt_id = ?", attemptID).
+		Order("created_at ASC, occurred_at ASC").
+		Find(&events).Error
+	return events, err
+}
+
+func (r *eventRepository) GetEventsByQuiz(ctx context.Context, quizID uuid.UUID) ([]entities.AttemptEvent, error) {
+	var events []entities.AttemptEvent
+	err := r.db.WithContext(ctx).
+		Joins("JOIN quiz_attempts ON quiz_attempts.id = attempt_events.attempt_id").
+		Where("quiz_attempts.quiz_id = ?", quizID).
+		Order("attempt_events.attempt_id, attempt_events.created_at ASC, attempt_events.occurred_at ASC").
+		Find(&events).Error
+	return events, err
+}
@@ -0,0 +1,276 @@
+package attempt
+
+import (
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/quiz"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxEventBatch membatasi jumlah kejadian per request agar log tidak dibanjiri client
+	maxEventBatch = 50
+	// maxEventDetail membatasi panjang detail kejadian (dalam karakter)
+	maxEventDetail = 500
+	// eventClockSkew adalah toleransi jam client; di luar itu dipakai waktu server
+	eventClockSkew = 5 * time.Minute
+)
+
+var (
+	ErrInvalidEvent  = errors.New("event type must be FOCUS_LOST, FULLSCREEN_EXIT, PASTE or IP_CHANGE")
+	ErrTooManyEvents = errors.New("too many events in one request")
+)
+
+// EventInput adalah kejadian yang dilaporkan client. OccurredAt nil berarti waktu server.
+type EventInput struct {
+	Type       string
+	Detail     string
+	OccurredAt *time.Time
+}
+
+// FlagType adalah alasan attempt ditandai untuk ditinjau teacher
+type FlagType string
+
+const (
+	FlagIPChanged         FlagType = "IP_CHANGED"
+	FlagThresholdExceeded FlagType = "THRESHOLD_EXCEEDED"
+)
+
+// Flag: EventType, Count dan Threshold hanya diisi untuk THRESHOLD_EXCEEDED
+type Flag struct {
+	Type      FlagType
+	EventType entities.AttemptEventType
+	Count     int
+	Threshold int
+	Detail    string
+	At        time.Time
+}
+
+// Timeline adalah log integritas satu attempt beserta ringkasan dan tandanya
+type Timeline struct {
+	Attempt entities.QuizAttempt
+	Events  []entities.AttemptEvent
+	Counts  map[entities.AttemptEventType]int
+	Flags   []Flag
+}
+
+// IntegrityService mencatat kejadian integritas dari client ujian dan menyajikannya ke teacher
+type IntegrityService interface {
+	// RecordEvents menambahkan kejadian ke attempt milik student yang belum disubmit
+	RecordEvents(ctx context.Context, userID uuid.UUID, courseID, attemptID uuid.UUID, ip, userAgent string, inputs []EventInput) (int, error)
+	// RecordRequest mencatat request attempt (mulai, simpan, submit) dengan IP yang dilihat server,
+	// sehingga perubahan IP tetap terdeteksi walau client tidak mengirim kejadian
+	RecordRequest(ctx context.Context, attemptID uuid.UUID, eventType entities.AttemptEventType, ip, userAgent string) error
+	GetTimeline(ctx context.Context, courseRole string, courseID, attemptID uuid.UUID) (*Timeline, error)
+	// GetQuizIntegrity meringkas jumlah kejadian dan tanda per attempt quiz, tanpa daftar kejadian
+	GetQuizIntegrity(ctx context.Context, courseRole string, courseID, quizID uuid.UUID) ([]Timeline, *entities.Quiz, error)
+}
+
+type integrityService struct {
+	repo        EventRepository
+	attemptRepo AttemptRepository
+	quizRepo    quiz.QuizRepository
+}
+
+func NewIntegrityService(repo EventRepository, attemptRepo AttemptRepository, quizRepo quiz.QuizRepository) IntegrityService {
+	return &integrityService{
+		repo:        repo,
+		attemptRepo: attemptRepo,
+		quizRepo:    quizRepo,
+	}
+}
+
+// Analyze menghitung kejadian per jenis dan menandai perubahan IP di tengah attempt
+// serta jenis kejadian yang melewati batas quiz. events harus terurut waktu server (CreatedAt).
+func Analyze(q *entities.Quiz, events []entities.AttemptEvent) (map[entities.AttemptEventType]int, []Flag) {
+	limits := map[entities.AttemptEventType]*int{
+		entities.AttemptEventFocusLost:      q.MaxFocusLost,
+		entities.AttemptEventFullscreenExit: q.MaxFullscreenExit,
+		entities.AttemptEventPaste:          q.MaxPaste,
+	}
+
+	counts := make(map[entities.AttemptEventType]int)
+	flags := []Flag{}
+	exceeded := make(map[entities.AttemptEventType]int)
+	lastIP := ""
+	for _, e := range events {
+		counts[e.Type]++
+
+		ipChanged := false
+		if e.IPAddress != "" {
+			if lastIP != "" && e.IPAddress != lastIP {
+				ipChanged = true
+				flags = append(flags, Flag{Type: FlagIPChanged, Detail: fmt.Sprintf("%s -> %s", lastIP, e.IPAddress), At: e.OccurredAt})
+			}
+			lastIP = e.IPAddress
+		}
+		// laporan client hanya ditandai bila server belum melihat perubahan IP pada kejadian ini
+		if e.Type == entities.AttemptEventIPChange && !ipChanged {
+			flags = append(flags, Flag{Type: FlagIPChanged, Detail: e.Detail, At: e.OccurredAt})
+		}
+
+		limit := limits[e.Type]
+		if limit == nil {
+			continue
+		}
+		if i, ok := exceeded[e.Type]; ok {
+			flags[i].Count = counts[e.Type]
+		} else if counts[e.Type] > *limit {
+			exceeded[e.Type] = len(flags)
+			flags = append(flags, Flag{Type: FlagThresholdExceeded, EventType: e.Type, Count: counts[e.Type], Threshold: *limit, At: e.OccurredAt})
+		}
+	}
+	return counts, flags
+}
+
+func toEventType(raw string) (entities.AttemptEventType, error) {
+	eventType := entities.AttemptEventType(strings.ToUpper(strings.TrimSpace(raw)))
+	switch eventType {
+	case entities.AttemptEventFocusLost, entities.AttemptEventFullscreenExit,
+		entities.AttemptEventPaste, entities.AttemptEventIPChange:
+		return eventType, nil
+	}
+	return "", ErrInvalidEvent
+}
+
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
+
+func (s *integrityService) findAttempt(ctx context.Context, courseID, attemptID uuid.UUID) (*entities.QuizAttempt, *entities.Quiz, error) {
+	attempt, err := s.attemptRepo.GetAttemptByID(ctx, attemptID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrAttemptNotFound
+		}
+		return nil, nil, err
+	}
+	q, err := s.quizRepo.GetQuizByID(ctx, attempt.QuizID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrAttemptNotFound
+		}
+		return nil, nil, err
+	}
+	if q.Module.CourseID != courseID {
+		return nil, nil, ErrAttemptNotFound
+	}
+	return attempt, q, nil
+}
+
+func (s *integrityService) RecordEvents(ctx context.Context, userID uuid.UUID, courseID, attemptID uuid.UUID, ip, userAgent string, inputs []EventInput) (int, error) {
+	if len(inputs) > maxEventBatch {
+		return 0, ErrTooManyEvents
+	}
+	attempt, _, err := s.findAttempt(ctx, courseID, attemptID)
+	if err != nil {
+		return 0, err
+	}
+	if attempt.StudentID != userID {
+		return 0, ErrAttemptNotFound
+	}
+	if attempt.SubmittedAt != nil {
+		return 0, ErrAlreadySubmitted
+	}
+
+	now := time.Now()
+	events := make([]entities.AttemptEvent, 0, len(inputs))
+	for _, in := range inputs {
+		eventType, err := toEventType(in.Type)
+		if err != nil {
+			return 0, err
+		}
+		// jam client hanya dipercaya bila masuk akal terhadap jendela attempt
+		occurredAt := now
+		if in.OccurredAt != nil && !in.OccurredAt.After(now.Add(eventClockSkew)) &&
+			!in.OccurredAt.Before(attempt.StartedAt.Add(-eventClockSkew)) {
+			occurredAt = *in.OccurredAt
+		}
+		events = append(events, entities.AttemptEvent{
+			AttemptID:  attempt.ID,
+			Type:       eventType,
+			Detail:     truncate(strings.TrimSpace(in.Detail), maxEventDetail),
+			IPAddress:  ip,
+			UserAgent:  truncate(userAgent, maxEventDetail),
+			OccurredAt: occurredAt,
+		})
+	}
+
+	if err := s.repo.CreateEvents(ctx, events); err != nil {
+		return 0, err
+	}
+	return len(events), nil
+}
+
+func (s *integrityService) RecordRequest(ctx context.Context, attemptID uuid.UUID, eventType entities.AttemptEventType, ip, userAgent string) error {
+	return s.repo.CreateEvents(ctx, []entities.AttemptEvent{{
+		AttemptID:  attemptID,
+		Type:       eventType,
+		IPAddress:  ip,
+		UserAgent:  truncate(userAgent, maxEventDetail),
+		OccurredAt: time.Now(),
+	}})
+}
+
+func (s *integrityService) GetTimeline(ctx context.Context, courseRole string, courseID, attemptID uuid.UUID) (*Timeline, error) {
+	if !quiz.CanAuthor(courseRole) {
+		return nil, quiz.ErrForbidden
+	}
+	attempt, q, err := s.findAttempt(ctx, courseID, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.repo.GetEventsByAttempt(ctx, attemptID)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, flags := Analyze(q, events)
+	return &Timeline{Attempt: *attempt, Events: events, Counts: counts, Flags: flags}, nil
+}
+
+func (s *integrityService) GetQuizIntegrity(ctx context.Context, courseRole string, courseID, quizID uuid.UUID) ([]Timeline, *entities.Quiz, error) {
+	if !quiz.CanAuthor(courseRole) {
+		return nil, nil, quiz.ErrForbidden
+	}
+	q, err := s.quizRepo.GetQuizByID(ctx, quizID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, quiz.ErrQuizNotFound
+		}
+		return nil, nil, err
+	}
+	if q.Module.CourseID != courseID {
+		return nil, nil, quiz.ErrQuizNotFound
+	}
+
+	attempts, err := s.attemptRepo.GetAttemptsByQuiz(ctx, quizID)
+	if err != nil {
+		return nil, nil, err
+	}
+	events, err := s.repo.GetEventsByQuiz(ctx, quizID)
+	if err != nil {
+		return nil, nil, err
+	}
+	byAttempt := make(map[uuid.UUID][]entities.AttemptEvent)
+	for _, e := range events {
+		byAttempt[e.AttemptID] = append(byAttempt[e.AttemptID], e)
+	}
+
+	summary := make([]Timeline, 0, len(attempts))
+	for _, a := range attempts {
+		counts, flags := Analyze(q, byAttempt[a.ID])
+		summary = append(summary, Timeline{Attempt: a, Counts: counts, Flags: flags})
+	}
+	return summary, q, nil
+}
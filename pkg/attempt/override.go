@@ -0,0 +1,45 @@
+package attempt
+
+import (
+	"api-shiners/pkg/entities"
+
+	"github.com/google/uuid"
+)
+
+// ApplyOverride mengembalikan salinan quiz dengan pengaturan override student.
+// Field override yang nil tetap memakai nilai quiz.
+func ApplyOverride(q *entities.Quiz, override *entities.QuizOverride) *entities.Quiz {
+	if override == nil {
+		return q
+	}
+	copied := *q
+	if override.TimeLimitSec != nil {
+		copied.TimeLimitSec = override.TimeLimitSec
+	}
+	if override.AttemptAllowed != nil {
+		copied.AttemptAllowed = *override.AttemptAllowed
+	}
+	if override.OpenAt != nil {
+		copied.OpenAt = override.OpenAt
+	}
+	if override.CloseAt != nil {
+		copied.CloseAt = override.CloseAt
+	}
+	return &copied
+}
+
+// Overrides adalah override quiz per StudentID
+type Overrides map[uuid.UUID]*entities.QuizOverride
+
+func NewOverrides(overrides []entities.QuizOverride) Overrides {
+	byStudent := make(Overrides, len(overrides))
+	for i := range overrides {
+		byStudent[overrides[i].StudentID] = &overrides[i]
+	}
+	return byStudent
+}
+
+// For mengembalikan pengaturan quiz yang berlaku untuk student
+func (o Overrides) For(q *entities.Quiz, studentID uuid.UUID) *entities.Quiz {
+	return ApplyOverride(q, o[studentID])
+}
@@ -0,0 +1,80 @@
+package attempt
+
+import (
+	"api-shiners/pkg/entities"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OverrideRepository interface {
+	GetOverride(ctx context.Context, quizID, studentID uuid.UUID) (*entities.QuizOverride, error)
+	GetOverridesByQuiz(ctx context.Context, quizID uuid.UUID) ([]entities.QuizOverride, error)
+	// SaveOverride membuat atau mengganti override untuk pasangan (quiz, student)
+	SaveOverride(ctx context.Context, override *entities.QuizOverride) error
+	DeleteOverride(ctx context.Context, quizID, studentID uuid.UUID) (int64, error)
+	// GetLatestCloseAt mengembalikan close_at terakhir quiz termasuk override; nil bila quiz tanpa close_at
+	GetLatestCloseAt(ctx context.Context, quizID uuid.UUID) (*time.Time, error)
+}
+
+type overrideRepository struct {
+	db *gorm.DB
+}
+
+func NewOverrideRepository(db *gorm.DB) OverrideRepository {
+	return &overrideRepository{db}
+}
+
+func (r *overrideRepository) GetOverride(ctx context.Context, quizID, studentID uuid.UUID) (*entities.QuizOverride, error) {
+	var override entities.QuizOverride
+	err := r.db.WithContext(ctx).
+		Where("quiz_id = ? AND student_id = ?", quizID, studentID).
+		First(&override).Error
+	if err != nil {
+		return nil, err
+	}
+	return &override, nil
+}
+
+func (r *overrideRepository) GetOverridesByQuiz(ctx context.Context, quizID uuid.UUID) ([]entities.QuizOverride, error) {
+	var overrides []entities.QuizOverride
+	err := r.db.WithContext(ctx).
+		Preload("Student").
+		Where("quiz_id = ?", quizID).
+		Order("created_at ASC").
+		Find(&overrides).Error
+	return overrides, err
+}
+
+func (r *overrideRepository) SaveOverride(ctx context.Context, override *entities.QuizOverride) error {
+	override.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).Omit("Quiz", "Student").
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "quiz_id"}, {Name: "student_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"time_limit_sec", "attempt_allowed", "open_at", "close_at", "reason", "created_by_id", "updated_at"}),
+		}).
+		Create(override).Error
+}
+
+func (r *overrideRepository) DeleteOverride(ctx context.Context, quizID, studentID uuid.UUID) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("quiz_id = ? AND student_id = ?", quizID, studentID).
+		Delete(&entities.QuizOverride{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *overrideRepository) GetLatestCloseAt(ctx context.Context, quizID uuid.UUID) (*time.Time, error) {
+	var closeAt *time.Time
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT CASE WHEN q.close_at IS NULL THEN NULL
+			ELSE GREATEST(q.close_at, MAX(o.close_at)) END
+		FROM quizzes q
+		LEFT JOIN quiz_overrides o ON o.quiz_id = q.id
+		WHERE q.id = ?
+		GROUP BY q.id, q.close_at`, quizID).
+		Scan(&closeAt).Error
+	return closeAt, err
+}
@@ -0,0 +1,212 @@
+package attempt
+
+import (
+	"api-shiners/pkg/enrollment"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/quiz"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOverrideNotFound   = errors.New("override not found")
+	ErrStudentNotEnrolled = errors.New("student is not enrolled in this course")
+)
+
+// OverrideInput: field nil mengikuti pengaturan quiz
+type OverrideInput struct {
+	TimeLimitSec   *int
+	AttemptAllowed *int
+	OpenAt         *time.Time
+	CloseAt        *time.Time
+	Reason         string
+}
+
+// RosterEntry adalah satu student di course beserta pengaturan quiz yang berlaku
+// untuknya (setelah override) dan ringkasan attempt-nya
+type RosterEntry struct {
+	Student         entities.User
+	Override        *entities.QuizOverride
+	Settings        *entities.Quiz
+	AttemptsUsed    int
+	InProgress      bool
+	BestScore       *float64
+	LastSubmittedAt *time.Time
+}
+
+// OverrideService mengelola akomodasi per student (teacher course atau admin)
+type OverrideService interface {
+	GetOverrides(ctx context.Context, courseRole string, courseID, quizID uuid.UUID) ([]entities.QuizOverride, error)
+	SetOverride(ctx context.Context, teacherID uuid.UUID, courseRole string, courseID, quizID, studentID uuid.UUID, input OverrideInput) (*entities.QuizOverride, error)
+	DeleteOverride(ctx context.Context, courseRole string, courseID, quizID, studentID uuid.UUID) error
+	GetRoster(ctx context.Context, courseRole string, courseID, quizID uuid.UUID) ([]RosterEntry, *entities.Quiz, error)
+}
+
+type overrideService struct {
+	repo           OverrideRepository
+	attemptRepo    AttemptRepository
+	quizRepo       quiz.QuizRepository
+	enrollmentRepo enrollment.EnrollmentRepository
+}
+
+func NewOverrideService(repo OverrideRepository, attemptRepo AttemptRepository, quizRepo quiz.QuizRepository, enrollmentRepo enrollment.EnrollmentRepository) OverrideService {
+	return &overrideService{
+		repo:           repo,
+		attemptRepo:    attemptRepo,
+		quizRepo:       quizRepo,
+		enrollmentRepo: enrollmentRepo,
+	}
+}
+
+func (s *overrideService) findAuthoredQuiz(ctx context.Context, courseRole string, courseID, quizID uuid.UUID) (*entities.Quiz, error) {
+	if !quiz.CanAuthor(courseRole) {
+		return nil, quiz.ErrForbidden
+	}
+	found, err := s.quizRepo.GetQuizByID(ctx, quizID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, quiz.ErrQuizNotFound
+		}
+		return nil, err
+	}
+	if found.Module.CourseID != courseID {
+		return nil, quiz.ErrQuizNotFound
+	}
+	return found, nil
+}
+
+func (s *overrideService) GetOverrides(ctx context.Context, courseRole string, courseID, quizID uuid.UUID) ([]entities.QuizOverride, error) {
+	if _, err := s.findAuthoredQuiz(ctx, courseRole, courseID, quizID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetOverridesByQuiz(ctx, quizID)
+}
+
+func (s *overrideService) SetOverride(ctx context.Context, teacherID uuid.UUID, courseRole string, courseID, quizID, studentID uuid.UUID, input OverrideInput) (*entities.QuizOverride, error) {
+	q, err := s.findAuthoredQuiz(ctx, courseRole, courseID, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	enrolled, err := s.enrollmentRepo.GetEnrollment(ctx, courseID, studentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrStudentNotEnrolled
+		}
+		return nil, err
+	}
+	if enrolled.RoleInCourse != entities.CourseRoleStudent {
+		return nil, ErrStudentNotEnrolled
+	}
+
+	override := &entities.QuizOverride{
+		QuizID:         quizID,
+		StudentID:      studentID,
+		TimeLimitSec:   input.TimeLimitSec,
+		AttemptAllowed: input.AttemptAllowed,
+		OpenAt:         input.OpenAt,
+		CloseAt:        input.CloseAt,
+		Reason:         strings.TrimSpace(input.Reason),
+		CreatedByID:    teacherID,
+	}
+	if err := validateOverride(q, override); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SaveOverride(ctx, override); err != nil {
+		return nil, err
+	}
+	return s.repo.GetOverride(ctx, quizID, studentID)
+}
+
+func validateOverride(q *entities.Quiz, override *entities.QuizOverride) error {
+	if override.TimeLimitSec == nil && override.AttemptAllowed == nil && override.OpenAt == nil && override.CloseAt == nil {
+		return quiz.InvalidInput("override must change at least one setting")
+	}
+	if override.TimeLimitSec != nil && *override.TimeLimitSec <= 0 {
+		return quiz.InvalidInput("time_limit_sec must be greater than 0")
+	}
+	if override.AttemptAllowed != nil && *override.AttemptAllowed < 1 {
+		return quiz.InvalidInput("attempt_allowed must be at least 1")
+	}
+
+	// jendela waktu dicek setelah digabung dengan pengaturan quiz
+	effective := ApplyOverride(q, override)
+	if effective.OpenAt != nil && effective.CloseAt != nil && !effective.CloseAt.After(*effective.OpenAt) {
+		return quiz.InvalidInput("close_at must be after open_at")
+	}
+	return nil
+}
+
+func (s *overrideService) DeleteOverride(ctx context.Context, courseRole string, courseID, quizID, studentID uuid.UUID) error {
+	if _, err := s.findAuthoredQuiz(ctx, courseRole, courseID, quizID); err != nil {
+		return err
+	}
+	deleted, err := s.repo.DeleteOverride(ctx, quizID, studentID)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrOverrideNotFound
+	}
+	return nil
+}
+
+func (s *overrideService) GetRoster(ctx context.Context, courseRole string, courseID, quizID uuid.UUID) ([]RosterEntry, *entities.Quiz, error) {
+	q, err := s.findAuthoredQuiz(ctx, courseRole, courseID, quizID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	enrollments, err := s.enrollmentRepo.GetEnrollmentsByCourse(ctx, courseID)
+	if err != nil {
+		return nil, nil, err
+	}
+	overrideList, err := s.repo.GetOverridesByQuiz(ctx, quizID)
+	if err != nil {
+		return nil, nil, err
+	}
+	attempts, err := s.attemptRepo.GetAttemptsByQuiz(ctx, quizID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	overrides := NewOverrides(overrideList)
+	byStudent := make(map[uuid.UUID][]entities.QuizAttempt)
+	for _, a := range attempts {
+		byStudent[a.StudentID] = append(byStudent[a.StudentID], a)
+	}
+
+	roster := make([]RosterEntry, 0, len(enrollments))
+	for _, e := range enrollments {
+		if e.RoleInCourse != entities.CourseRoleStudent {
+			continue
+		}
+		entry := RosterEntry{
+			Student:  e.User,
+			Override: overrides[e.UserID],
+			Settings: overrides.For(q, e.UserID),
+		}
+		for _, a := range byStudent[e.UserID] {
+			entry.AttemptsUsed++
+			if a.SubmittedAt == nil {
+				entry.InProgress = true
+				continue
+			}
+			if entry.BestScore == nil || a.Score > *entry.BestScore {
+				score := a.Score
+				entry.BestScore = &score
+			}
+			if entry.LastSubmittedAt == nil || a.SubmittedAt.After(*entry.LastSubmittedAt) {
+				entry.LastSubmittedAt = a.SubmittedAt
+			}
+		}
+		roster = append(roster, entry)
+	}
+	return roster, q, nil
+}
@@ -0,0 +1,189 @@
+package attempt
+
+import (
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/grading"
+	"api-shiners/pkg/quiz"
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// regradeBatch membatasi jumlah attempt per transaksi regrade
+const regradeBatch = 100
+
+// RegradeInput: QuestionID nil berarti seluruh soal quiz dinilai ulang
+type RegradeInput struct {
+	QuestionID *uuid.UUID
+	Reason     string
+	DryRun     bool
+}
+
+func (s *attemptService) Regrade(ctx context.Context, teacherID uuid.UUID, courseRole string, courseID, quizID uuid.UUID, input RegradeInput) (*entities.QuizRegrade, error) {
+	if !quiz.CanAuthor(courseRole) {
+		return nil, quiz.ErrForbidden
+	}
+	q, err := s.findQuiz(ctx, courseID, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := s.repo.GetSubmittedAttemptsWithAnswers(ctx, quizID)
+	if err != nil {
+		return nil, err
+	}
+	papers, err := s.loadPapers(ctx, q, attempts)
+	if err != nil {
+		return nil, err
+	}
+
+	only := func(uuid.UUID) bool { return true }
+	if input.QuestionID != nil {
+		questionID := *input.QuestionID
+		only = func(id uuid.UUID) bool { return id == questionID }
+		if !onPaper(q.Questions, only) && !anyPaper(papers, only) {
+			return nil, quiz.ErrQuestionNotFound
+		}
+	}
+
+	regrade := &entities.QuizRegrade{
+		QuizID:       quizID,
+		QuestionID:   input.QuestionID,
+		RegradedByID: teacherID,
+		Reason:       strings.TrimSpace(input.Reason),
+		Changes:      []entities.RegradeChange{},
+	}
+
+	// kebijakan penilaian quiz saat ini, termasuk bobot soal yang baru diubah
+	policy := grading.PolicyFor(q)
+
+	// hanya attempt yang paper-nya memuat soal terkait
+	var targets []uuid.UUID
+	for i := range attempts {
+		a := &attempts[i]
+		if !onPaper(papers[a.ID], only) {
+			continue
+		}
+		targets = append(targets, a.ID)
+
+		if input.DryRun {
+			preview := *a
+			answers := append([]entities.Answer(nil), a.Answers...)
+			if change, ok := regradeAttempt(policy, papers[a.ID], &preview, answers, only); ok {
+				regrade.Changes = append(regrade.Changes, change)
+			}
+		}
+	}
+	regrade.AttemptsChecked = len(targets)
+	if input.DryRun {
+		regrade.AttemptsChanged = len(regrade.Changes)
+		return regrade, nil
+	}
+
+	// nilai dihitung ulang dari data terkunci di dalam transaksi agar tidak
+	// menimpa penilaian essay yang berjalan bersamaan
+	var batchErr error
+	for start := 0; start < len(targets); start += regradeBatch {
+		end := start + regradeBatch
+		if end > len(targets) {
+			end = len(targets)
+		}
+
+		var changes []entities.RegradeChange
+		_, err := s.repo.RegradeAttempts(ctx, targets[start:end], func(attempt *entities.QuizAttempt, answers []entities.Answer) error {
+			if change, ok := regradeAttempt(policy, papers[attempt.ID], attempt, answers, only); ok {
+				changes = append(changes, change)
+			}
+			return nil
+		})
+		if err != nil {
+			batchErr = err
+			break
+		}
+		regrade.Changes = append(regrade.Changes, changes...)
+	}
+	regrade.AttemptsChanged = len(regrade.Changes)
+
+	// batch yang sudah tersimpan tetap dicatat walaupun batch berikutnya gagal
+	if batchErr != nil && regrade.AttemptsChanged == 0 {
+		return nil, batchErr
+	}
+	if err := s.repo.CreateRegrade(ctx, regrade); err != nil {
+		return nil, err
+	}
+	if batchErr != nil {
+		return nil, batchErr
+	}
+	return regrade, nil
+}
+
+// regradeAttempt menilai ulang satu attempt dan mengembalikan perubahannya.
+// ok false bila nilai attempt maupun jawabannya tidak berubah.
+func regradeAttempt(policy grading.Policy, paper []entities.Question, attempt *entities.QuizAttempt, answers []entities.Answer, only func(uuid.UUID) bool) (entities.RegradeChange, bool) {
+	type graded struct {
+		isCorrect bool
+		points    *float64
+	}
+	before := make([]graded, len(answers))
+	for i, a := range answers {
+		before[i] = graded{a.IsCorrect, a.Points}
+	}
+	oldMax := attempt.MaxPoints
+	change := entities.RegradeChange{
+		AttemptID: attempt.ID,
+		StudentID: attempt.StudentID,
+		OldScore:  attempt.Score,
+		OldPoints: attempt.Points,
+	}
+
+	result := policy.Regrade(paper, answers, only)
+	grading.Apply(result, answers)
+	applyResult(attempt, result)
+	change.NewScore = attempt.Score
+	change.NewPoints = attempt.Points
+
+	for i, a := range answers {
+		if a.IsCorrect != before[i].isCorrect || !samePoints(a.Points, before[i].points) {
+			change.AnswersChanged++
+		}
+	}
+	changed := change.AnswersChanged > 0 || change.OldScore != change.NewScore ||
+		change.OldPoints != change.NewPoints || oldMax != attempt.MaxPoints
+	return change, changed
+}
+
+func samePoints(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func onPaper(paper []entities.Question, only func(uuid.UUID) bool) bool {
+	for _, question := range paper {
+		if only(question.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+func anyPaper(papers map[uuid.UUID][]entities.Question, only func(uuid.UUID) bool) bool {
+	for _, paper := range papers {
+		if onPaper(paper, only) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *attemptService) GetRegrades(ctx context.Context, courseRole string, courseID, quizID uuid.UUID) ([]entities.QuizRegrade, error) {
+	if !quiz.CanAuthor(courseRole) {
+		return nil, quiz.ErrForbidden
+	}
+	if _, err := s.findQuiz(ctx, courseID, quizID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetRegradesByQuiz(ctx, quizID)
+}
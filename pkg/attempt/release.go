@@ -0,0 +1,82 @@
+package attempt
+
+import (
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/quiz"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Review menentukan bagian hasil attempt yang boleh dilihat student
+type Review struct {
+	Released bool
+	// ReleaseAt adalah perkiraan waktu rilis untuk kebijakan AFTER_CLOSE
+	ReleaseAt       *time.Time
+	ShowScore       bool
+	ShowCorrectness bool
+	ShowAnswerKey   bool
+}
+
+// FullReview dipakai teacher/admin yang selalu melihat seluruh hasil
+var FullReview = Review{Released: true, ShowScore: true, ShowCorrectness: true, ShowAnswerKey: true}
+
+// ReviewOf menghitung visibilitas hasil berdasarkan kebijakan rilis quiz.
+// lastCloseAt adalah close_at terakhir termasuk override student, agar kunci
+// jawaban tidak bocor sebelum semua student selesai.
+func ReviewOf(q *entities.Quiz, lastCloseAt *time.Time, now time.Time) Review {
+	var review Review
+	switch q.ReleasePolicy {
+	case entities.ReleaseManual:
+		review.Released = q.ResultsReleasedAt != nil
+	case entities.ReleaseAfterClose:
+		review.ReleaseAt = lastCloseAt
+		review.Released = q.ResultsReleasedAt != nil || (lastCloseAt != nil && !now.Before(*lastCloseAt))
+	default:
+		review.Released = true
+	}
+	if !review.Released {
+		return review
+	}
+
+	review.ShowScore = true
+	switch q.ReleaseContent {
+	case entities.ReleaseFull:
+		review.ShowCorrectness = true
+		review.ShowAnswerKey = true
+	case entities.ReleaseCorrectness:
+		review.ShowCorrectness = true
+	}
+	return review
+}
+
+func (s *attemptService) GetReview(ctx context.Context, courseRole string, q *entities.Quiz) (Review, error) {
+	if quiz.CanAuthor(courseRole) {
+		return FullReview, nil
+	}
+	var lastCloseAt *time.Time
+	if q.ReleasePolicy == entities.ReleaseAfterClose {
+		var err error
+		lastCloseAt, err = s.overrideRepo.GetLatestCloseAt(ctx, q.ID)
+		if err != nil {
+			return Review{}, err
+		}
+	}
+	return ReviewOf(q, lastCloseAt, time.Now()), nil
+}
+
+func (s *attemptService) ReviewAttempt(ctx context.Context, userID uuid.UUID, courseID, attemptID uuid.UUID) (*entities.QuizAttempt, *entities.Quiz, Review, error) {
+	attempt, q, err := s.GetAttempt(ctx, userID, string(entities.CourseRoleStudent), courseID, attemptID)
+	if err != nil {
+		return nil, nil, Review{}, err
+	}
+	if attempt.SubmittedAt == nil {
+		return nil, nil, Review{}, ErrNotSubmitted
+	}
+	review, err := s.GetReview(ctx, string(entities.CourseRoleStudent), q)
+	if err != nil {
+		return nil, nil, Review{}, err
+	}
+	return attempt, q, review, nil
+}
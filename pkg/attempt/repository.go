@@ -0,0 +1,347 @@
+package attempt
+
+import (
+	"api-shiners/pkg/entities"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FinalizeFunc dipanggil di dalam transaksi dengan attempt yang sudah dikunci.
+// Fungsi boleh mengubah nilai attempt serta hasil penilaian pada answers
+// (IsCorrect, Points, Comment, GradedByID, GradedAt); perubahan tersebut disimpan.
+type FinalizeFunc func(attempt *entities.QuizAttempt, answers []entities.Answer) error
+
+type AttemptRepository interface {
+	GetAttemptByID(ctx context.Context, id uuid.UUID) (*entities.QuizAttempt, error)
+	GetAttemptWithAnswers(ctx context.Context, id uuid.UUID) (*entities.QuizAttempt, error)
+	GetAttemptsByStudent(ctx context.Context, quizID, studentID uuid.UUID) ([]entities.QuizAttempt, error)
+	GetAttemptsByQuiz(ctx context.Context, quizID uuid.UUID) ([]entities.QuizAttempt, error)
+	// GetSubmittedAttemptsWithAnswers memuat attempt yang sudah disubmit beserta jawabannya
+	GetSubmittedAttemptsWithAnswers(ctx context.Context, quizID uuid.UUID) ([]entities.QuizAttempt, error)
+	GetOpenAttempt(ctx context.Context, quizID, studentID uuid.UUID) (*entities.QuizAttempt, error)
+	// GetExpiredOpenAttempts mencari attempt belum disubmit yang deadline-nya sebelum waktu before
+	GetExpiredOpenAttempts(ctx context.Context, before time.Time, limit int) ([]entities.QuizAttempt, error)
+	// CreateAttempt menyimpan attempt baru bila jumlah attempt student masih di bawah maxAttempts
+	CreateAttempt(ctx context.Context, attempt *entities.QuizAttempt, maxAttempts int) error
+	// SaveAnswers menyimpan (upsert) jawaban selama attempt belum disubmit
+	SaveAnswers(ctx context.Context, attemptID uuid.UUID, answers []entities.Answer) error
+	Finalize(ctx context.Context, attemptID uuid.UUID, fn FinalizeFunc) (*entities.QuizAttempt, error)
+	// Rescore menghitung ulang nilai attempt yang sudah disubmit
+	Rescore(ctx context.Context, attemptID uuid.UUID, fn FinalizeFunc) (*entities.QuizAttempt, error)
+
+	// RegradeAttempts menjalankan fn untuk setiap attempt yang sudah disubmit dalam satu
+	// transaksi dan menyimpan hasilnya. Attempt yang belum disubmit dilewati.
+	RegradeAttempts(ctx context.Context, attemptIDs []uuid.UUID, fn FinalizeFunc) ([]entities.QuizAttempt, error)
+	CreateRegrade(ctx context.Context, regrade *entities.QuizRegrade) error
+	GetRegradesByQuiz(ctx context.Context, quizID uuid.UUID) ([]entities.QuizRegrade, error)
+
+	GetAnswerByID(ctx context.Context, id uuid.UUID) (*entities.Answer, error)
+	// GetPendingManualAnswers: jawaban essay dari attempt yang sudah disubmit dan belum dinilai
+	GetPendingManualAnswers(ctx context.Context, quizID uuid.UUID) ([]entities.Answer, error)
+}
+
+type attemptRepository struct {
+	db *gorm.DB
+}
+
+func NewAttemptRepository(db *gorm.DB) AttemptRepository {
+	return &attemptRepository{db}
+}
+
+func (r *attemptRepository) GetAttemptByID(ctx context.Context, id uuid.UUID) (*entities.QuizAttempt, error) {
+	var attempt entities.QuizAttempt
+	if err := r.db.WithContext(ctx).First(&attempt, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+func (r *attemptRepository) GetAttemptWithAnswers(ctx context.Context, id uuid.UUID) (*entities.QuizAttempt, error) {
+	var attempt entities.QuizAttempt
+	if err := r.db.WithContext(ctx).Preload("Answers").First(&attempt, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+func (r *attemptRepository) GetAttemptsByStudent(ctx context.Context, quizID, studentID uuid.UUID) ([]entities.QuizAttempt, error) {
+	var attempts []entities.QuizAttempt
+	err := r.db.WithContext(ctx).
+		Where("quiz_id = ? AND student_id = ?", quizID, studentID).
+		Order("started_at ASC").
+		Find(&attempts).Error
+	return attempts, err
+}
+
+func (r *attemptRepository) GetAttemptsByQuiz(ctx context.Context, quizID uuid.UUID) ([]entities.QuizAttempt, error) {
+	var attempts []entities.QuizAttempt
+	err := r.db.WithContext(ctx).
+		Where("quiz_id = ?", quizID).
+		Order("started_at ASC").
+		Find(&attempts).Error
+	return attempts, err
+}
+
+func (r *attemptRepository) GetSubmittedAttemptsWithAnswers(ctx context.Context, quizID uuid.UUID) ([]entities.QuizAttempt, error) {
+	var attempts []entities.QuizAttempt
+	err := r.db.WithContext(ctx).
+		Preload("Answers").
+		Where("quiz_id = ? AND submited_at IS NOT NULL", quizID).
+		Order("submited_at ASC").
+		Find(&attempts).Error
+	return attempts, err
+}
+
+func (r *attemptRepository) GetOpenAttempt(ctx context.Context, quizID, studentID uuid.UUID) (*entities.QuizAttempt, error) {
+	var attempt entities.QuizAttempt
+	err := r.db.WithContext(ctx).
+		Where("quiz_id = ? AND student_id = ? AND submited_at IS NULL", quizID, studentID).
+		Order("started_at DESC").
+		First(&attempt).Error
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+func (r *attemptRepository) GetExpiredOpenAttempts(ctx context.Context, before time.Time, limit int) ([]entities.QuizAttempt, error) {
+	var attempts []entities.QuizAttempt
+	err := r.db.WithContext(ctx).
+		Joins("JOIN quizzes ON quizzes.id = quiz_attempts.quiz_id").
+		// override per student menggantikan time_limit_sec dan close_at quiz
+		Joins("LEFT JOIN quiz_overrides ON quiz_overrides.quiz_id = quiz_attempts.quiz_id AND quiz_overrides.student_id = quiz_attempts.student_id").
+		Where("quiz_attempts.submited_at IS NULL").
+		Where(`(COALESCE(quiz_overrides.time_limit_sec, quizzes.time_limit_sec) IS NOT NULL
+				AND quiz_attempts.started_at + COALESCE(quiz_overrides.time_limit_sec, quizzes.time_limit_sec) * INTERVAL '1 second' < ?)
+			OR (COALESCE(quiz_overrides.close_at, quizzes.close_at) IS NOT NULL AND COALESCE(quiz_overrides.close_at, quizzes.close_at) < ?)`, before, before).
+		Order("quiz_attempts.started_at ASC").
+		Limit(limit).
+		Find(&attempts).Error
+	return attempts, err
+}
+
+func (r *attemptRepository) CreateAttempt(ctx context.Context, attempt *entities.QuizAttempt, maxAttempts int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// serialisasi start attempt per (quiz, student) agar batas attempt tidak terlewati
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", attempt.QuizID.String()+attempt.StudentID.String()).Error; err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&entities.QuizAttempt{}).
+			Where("quiz_id = ? AND student_id = ?", attempt.QuizID, attempt.StudentID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if maxAttempts > 0 && int(count) >= maxAttempts {
+			return ErrNoAttemptsLeft
+		}
+
+		var open int64
+		if err := tx.Model(&entities.QuizAttempt{}).
+			Where("quiz_id = ? AND student_id = ? AND submited_at IS NULL", attempt.QuizID, attempt.StudentID).
+			Count(&open).Error; err != nil {
+			return err
+		}
+		if open > 0 {
+			return ErrAttemptInProgress
+		}
+
+		return tx.Omit("Quiz", "Student", "Answers").Create(attempt).Error
+	})
+}
+
+func lockAttempt(tx *gorm.DB, attemptID uuid.UUID) (*entities.QuizAttempt, error) {
+	var attempt entities.QuizAttempt
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&attempt, "id = ?", attemptID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+func (r *attemptRepository) SaveAnswers(ctx context.Context, attemptID uuid.UUID, answers []entities.Answer) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		attempt, err := lockAttempt(tx, attemptID)
+		if err != nil {
+			return err
+		}
+		if attempt.SubmittedAt != nil {
+			return ErrAlreadySubmitted
+		}
+
+		now := time.Now()
+		for i := range answers {
+			answers[i].AttemptID = attemptID
+			answers[i].UpdatedAt = now
+		}
+		if len(answers) == 0 {
+			return nil
+		}
+
+		return tx.Omit("Attempt", "Question", "Choice").
+			Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "attempt_id"}, {Name: "question_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"choice_id", "choice_ids", "text_answer", "numeric_answer", "updated_at"}),
+			}).
+			Create(&answers).Error
+	})
+}
+
+func (r *attemptRepository) Finalize(ctx context.Context, attemptID uuid.UUID, fn FinalizeFunc) (*entities.QuizAttempt, error) {
+	return r.updateLocked(ctx, attemptID, false, fn)
+}
+
+func (r *attemptRepository) Rescore(ctx context.Context, attemptID uuid.UUID, fn FinalizeFunc) (*entities.QuizAttempt, error) {
+	return r.updateLocked(ctx, attemptID, true, fn)
+}
+
+// updateLocked mengunci attempt, menjalankan fn lalu menyimpan hasil penilaian.
+// submitted menentukan status attempt yang diharapkan (belum/sudah disubmit).
+func (r *attemptRepository) updateLocked(ctx context.Context, attemptID uuid.UUID, submitted bool, fn FinalizeFunc) (*entities.QuizAttempt, error) {
+	var result *entities.QuizAttempt
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		attempt, err := lockAttempt(tx, attemptID)
+		if err != nil {
+			return err
+		}
+		if !submitted && attempt.SubmittedAt != nil {
+			return ErrAlreadySubmitted
+		}
+		if submitted && attempt.SubmittedAt == nil {
+			return ErrNotSubmitted
+		}
+
+		var answers []entities.Answer
+		if err := tx.Where("attempt_id = ?", attemptID).Find(&answers).Error; err != nil {
+			return err
+		}
+
+		if err := fn(attempt, answers); err != nil {
+			return err
+		}
+		if err := saveGrading(tx, attempt, answers); err != nil {
+			return err
+		}
+
+		attempt.Answers = answers
+		result = attempt
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// saveGrading menyimpan hasil penilaian answers dan nilai attempt
+func saveGrading(tx *gorm.DB, attempt *entities.QuizAttempt, answers []entities.Answer) error {
+	for _, a := range answers {
+		if err := tx.Model(&entities.Answer{}).
+			Where("id = ?", a.ID).
+			Updates(map[string]interface{}{
+				"is_correct":   a.IsCorrect,
+				"points":       a.Points,
+				"comment":      a.Comment,
+				"graded_by_id": a.GradedByID,
+				"graded_at":    a.GradedAt,
+			}).Error; err != nil {
+			return err
+		}
+	}
+
+	return tx.Model(&entities.QuizAttempt{}).
+		Where("id = ?", attempt.ID).
+		Updates(map[string]interface{}{
+			"submited_at":    attempt.SubmittedAt,
+			"score":          attempt.Score,
+			"points":         attempt.Points,
+			"max_points":     attempt.MaxPoints,
+			"duration_sec":   attempt.DurationSec,
+			"is_late":        attempt.IsLate,
+			"pending_review": attempt.PendingReview,
+			"updated_at":     gorm.Expr("now()"),
+		}).Error
+}
+
+func (r *attemptRepository) RegradeAttempts(ctx context.Context, attemptIDs []uuid.UUID, fn FinalizeFunc) ([]entities.QuizAttempt, error) {
+	var regraded []entities.QuizAttempt
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var attempts []entities.QuizAttempt
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ? AND submited_at IS NOT NULL", attemptIDs).
+			Order("id").
+			Find(&attempts).Error; err != nil {
+			return err
+		}
+		if len(attempts) == 0 {
+			return nil
+		}
+
+		var answers []entities.Answer
+		if err := tx.Where("attempt_id IN ?", attemptIDs).Find(&answers).Error; err != nil {
+			return err
+		}
+		byAttempt := make(map[uuid.UUID][]entities.Answer, len(attempts))
+		for _, a := range answers {
+			byAttempt[a.AttemptID] = append(byAttempt[a.AttemptID], a)
+		}
+
+		for i := range attempts {
+			attempt := &attempts[i]
+			attemptAnswers := byAttempt[attempt.ID]
+			if err := fn(attempt, attemptAnswers); err != nil {
+				return err
+			}
+			if err := saveGrading(tx, attempt, attemptAnswers); err != nil {
+				return err
+			}
+			attempt.Answers = attemptAnswers
+		}
+		regraded = attempts
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return regraded, nil
+}
+
+func (r *attemptRepository) CreateRegrade(ctx context.Context, regrade *entities.QuizRegrade) error {
+	return r.db.WithContext(ctx).Omit("Quiz").Create(regrade).Error
+}
+
+func (r *attemptRepository) GetRegradesByQuiz(ctx context.Context, quizID uuid.UUID) ([]entities.QuizRegrade, error) {
+	var regrades []entities.QuizRegrade
+	err := r.db.WithContext(ctx).
+		Where("quiz_id = ?", quizID).
+		Order("created_at DESC").
+		Find(&regrades).Error
+	return regrades, err
+}
+
+func (r *attemptRepository) GetAnswerByID(ctx context.Context, id uuid.UUID) (*entities.Answer, error) {
+	var answer entities.Answer
+	if err := r.db.WithContext(ctx).Preload("Attempt").First(&answer, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &answer, nil
+}
+
+func (r *attemptRepository) GetPendingManualAnswers(ctx context.Context, quizID uuid.UUID) ([]entities.Answer, error) {
+	var answers []entities.Answer
+	err := r.db.WithContext(ctx).
+		Joins("JOIN quiz_attempts ON quiz_attempts.id = answers.attempt_id").
+		Joins("JOIN questions ON questions.id = answers.question_id").
+		Where("quiz_attempts.quiz_id = ? AND quiz_attempts.submited_at IS NOT NULL", quizID).
+		Where("questions.type = ? AND answers.graded_at IS NULL", entities.QuestionTypeEssay).
+		Preload("Attempt.Student").
+		Preload("Question").
+		Order("quiz_attempts.submited_at ASC").
+		Find(&answers).Error
+	return answers, err
+}
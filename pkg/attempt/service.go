@@ -0,0 +1,693 @@
+package attempt
+
+import (
+	"api-shiners/pkg/course"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/grading"
+	"api-shiners/pkg/quiz"
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// submitGrace memberi toleransi latensi jaringan sebelum submit dianggap terlambat
+	submitGrace = 30 * time.Second
+	// autoSubmitBatch membatasi jumlah attempt yang ditutup per putaran worker
+	autoSubmitBatch = 200
+)
+
+var (
+	ErrAttemptNotFound   = errors.New("attempt not found")
+	ErrForbidden         = errors.New("only students enrolled in this course can take this quiz")
+	ErrQuizNotOpen       = errors.New("quiz is not open yet")
+	ErrQuizClosed        = errors.New("quiz is already closed")
+	ErrNoAttemptsLeft    = errors.New("no attempts left for this quiz")
+	ErrAttemptInProgress = errors.New("another attempt is still in progress")
+	ErrAlreadySubmitted  = errors.New("attempt has already been submitted")
+	ErrAttemptExpired    = errors.New("time for this attempt is over")
+	ErrInvalidAnswer     = errors.New("answer does not match a question or choice of this quiz")
+	ErrNotSubmitted      = errors.New("attempt has not been submitted yet")
+	ErrNotManualGraded   = errors.New("only essay answers can be graded manually")
+	ErrInvalidPoints     = errors.New("points are out of range for this question")
+)
+
+// AnswerInput: isi field sesuai tipe soal (ChoiceID, ChoiceIDs, Text atau Number)
+type AnswerInput struct {
+	QuestionID uuid.UUID
+	ChoiceID   *uuid.UUID
+	ChoiceIDs  []uuid.UUID
+	Text       *string
+	Number     *float64
+}
+
+// ManualGradeInput adalah nilai dan komentar teacher untuk jawaban essay
+type ManualGradeInput struct {
+	Points  float64
+	Comment string
+}
+
+type AttemptService interface {
+	StartAttempt(ctx context.Context, userID uuid.UUID, courseRole string, courseID, quizID uuid.UUID) (*entities.QuizAttempt, *entities.Quiz, error)
+	GetMyAttempts(ctx context.Context, userID uuid.UUID, courseID, quizID uuid.UUID) ([]entities.QuizAttempt, *entities.Quiz, error)
+	// GetQuizAttempts juga mengembalikan override student agar deadline tiap attempt bisa dihitung
+	GetQuizAttempts(ctx context.Context, courseRole string, courseID, quizID uuid.UUID) ([]entities.QuizAttempt, *entities.Quiz, Overrides, error)
+	GetAttempt(ctx context.Context, userID uuid.UUID, courseRole string, courseID, attemptID uuid.UUID) (*entities.QuizAttempt, *entities.Quiz, error)
+	SaveAnswers(ctx context.Context, userID uuid.UUID, courseID, attemptID uuid.UUID, answers []AnswerInput) (*entities.QuizAttempt, *entities.Quiz, error)
+	SubmitAttempt(ctx context.Context, userID uuid.UUID, courseID, attemptID uuid.UUID, answers []AnswerInput) (*entities.QuizAttempt, *entities.Quiz, error)
+
+	// AutoSubmitExpired menilai dan menutup attempt yang ditinggal student setelah deadline.
+	// Dipanggil oleh worker terjadwal, mengembalikan jumlah attempt yang ditutup.
+	AutoSubmitExpired(ctx context.Context) (int, error)
+
+	// Regrade menilai ulang attempt yang sudah disubmit setelah kunci jawaban diubah,
+	// untuk satu soal atau seluruh quiz. DryRun hanya menghitung pratinjau perubahan nilai.
+	Regrade(ctx context.Context, teacherID uuid.UUID, courseRole string, courseID, quizID uuid.UUID, input RegradeInput) (*entities.QuizRegrade, error)
+	GetRegrades(ctx context.Context, courseRole string, courseID, quizID uuid.UUID) ([]entities.QuizRegrade, error)
+
+	// GetReview menentukan hasil yang boleh dilihat berdasarkan kebijakan rilis quiz
+	GetReview(ctx context.Context, courseRole string, q *entities.Quiz) (Review, error)
+	// ReviewAttempt memuat attempt milik student yang sudah disubmit beserta visibilitas hasilnya
+	ReviewAttempt(ctx context.Context, userID uuid.UUID, courseID, attemptID uuid.UUID) (*entities.QuizAttempt, *entities.Quiz, Review, error)
+
+	GetGradingQueue(ctx context.Context, courseRole string, courseID, quizID uuid.UUID) ([]entities.Answer, *entities.Quiz, error)
+	GradeAnswer(ctx context.Context, graderID uuid.UUID, courseRole string, courseID, answerID uuid.UUID, input ManualGradeInput) (*entities.QuizAttempt, *entities.Quiz, error)
+}
+
+type attemptService struct {
+	repo         AttemptRepository
+	overrideRepo OverrideRepository
+	quizRepo     quiz.QuizRepository
+	courseRepo   course.CourseRepository
+}
+
+func NewAttemptService(repo AttemptRepository, overrideRepo OverrideRepository, quizRepo quiz.QuizRepository, courseRepo course.CourseRepository) AttemptService {
+	return &attemptService{
+		repo:         repo,
+		overrideRepo: overrideRepo,
+		quizRepo:     quizRepo,
+		courseRepo:   courseRepo,
+	}
+}
+
+// Deadline adalah batas waktu server untuk attempt: StartedAt + TimeLimitSec
+// atau CloseAt quiz, mana yang lebih dulu. Nil berarti tanpa batas waktu.
+func Deadline(q *entities.Quiz, attempt *entities.QuizAttempt) *time.Time {
+	var deadline *time.Time
+	if q.TimeLimitSec != nil {
+		limit := attempt.StartedAt.Add(time.Duration(*q.TimeLimitSec) * time.Second)
+		deadline = &limit
+	}
+	if q.CloseAt != nil && (deadline == nil || q.CloseAt.Before(*deadline)) {
+		closeAt := *q.CloseAt
+		deadline = &closeAt
+	}
+	return deadline
+}
+
+// isExpired: waktu attempt habis (sudah lewat deadline + toleransi)
+func isExpired(q *entities.Quiz, attempt *entities.QuizAttempt, now time.Time) bool {
+	deadline := Deadline(q, attempt)
+	return deadline != nil && now.After(deadline.Add(submitGrace))
+}
+
+func (s *attemptService) findQuiz(ctx context.Context, courseID, quizID uuid.UUID) (*entities.Quiz, error) {
+	found, err := s.quizRepo.GetQuizWithQuestions(ctx, quizID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, quiz.ErrQuizNotFound
+		}
+		return nil, err
+	}
+	if found.Module.CourseID != courseID {
+		return nil, quiz.ErrQuizNotFound
+	}
+	return found, nil
+}
+
+// findPublishedQuiz: student hanya boleh mengerjakan quiz yang dipublish di course yang dipublish
+func (s *attemptService) findPublishedQuiz(ctx context.Context, courseID, quizID uuid.UUID) (*entities.Quiz, error) {
+	found, err := s.courseRepo.GetCourseByID(ctx, courseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, course.ErrCourseNotFound
+		}
+		return nil, err
+	}
+	if !found.IsPublished {
+		return nil, course.ErrCourseNotFound
+	}
+
+	q, err := s.findQuiz(ctx, courseID, quizID)
+	if err != nil {
+		return nil, err
+	}
+	if !q.IsPublished {
+		return nil, quiz.ErrQuizNotFound
+	}
+	return q, nil
+}
+
+// findOwnAttempt memuat attempt milik student beserta quiz-nya
+func (s *attemptService) findOwnAttempt(ctx context.Context, userID, courseID, attemptID uuid.UUID) (*entities.QuizAttempt, *entities.Quiz, error) {
+	attempt, err := s.repo.GetAttemptByID(ctx, attemptID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrAttemptNotFound
+		}
+		return nil, nil, err
+	}
+	if attempt.StudentID != userID {
+		return nil, nil, ErrAttemptNotFound
+	}
+
+	q, err := s.findQuiz(ctx, courseID, attempt.QuizID)
+	if err != nil {
+		if errors.Is(err, quiz.ErrQuizNotFound) {
+			return nil, nil, ErrAttemptNotFound
+		}
+		return nil, nil, err
+	}
+	q, err = s.forStudent(ctx, q, attempt.StudentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attempt, q, nil
+}
+
+// forStudent menerapkan override student (bila ada) pada pengaturan quiz
+func (s *attemptService) forStudent(ctx context.Context, q *entities.Quiz, studentID uuid.UUID) (*entities.Quiz, error) {
+	override, err := s.overrideRepo.GetOverride(ctx, q.ID, studentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return q, nil
+		}
+		return nil, err
+	}
+	return ApplyOverride(q, override), nil
+}
+
+// loadPaper menyusun soal paper attempt (hasil undian dan urutan dari seed)
+func (s *attemptService) loadPaper(ctx context.Context, q *entities.Quiz, attempt *entities.QuizAttempt) ([]entities.Question, error) {
+	papers, err := s.loadPapers(ctx, q, []entities.QuizAttempt{*attempt})
+	if err != nil {
+		return nil, err
+	}
+	return papers[attempt.ID], nil
+}
+
+// loadPapers menyusun paper beberapa attempt sekaligus dengan satu query soal bank
+func (s *attemptService) loadPapers(ctx context.Context, q *entities.Quiz, attempts []entities.QuizAttempt) (map[uuid.UUID][]entities.Question, error) {
+	inQuiz := make(map[uuid.UUID]bool, len(q.Questions))
+	for _, question := range q.Questions {
+		inQuiz[question.ID] = true
+	}
+	var bankIDs []uuid.UUID
+	for _, attempt := range attempts {
+		for _, id := range attempt.QuestionIDs {
+			if !inQuiz[id] {
+				inQuiz[id] = true
+				bankIDs = append(bankIDs, id)
+			}
+		}
+	}
+
+	var bankQuestions []entities.Question
+	if len(bankIDs) > 0 {
+		var err error
+		bankQuestions, err = s.quizRepo.GetQuestionsByIDs(ctx, bankIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	papers := make(map[uuid.UUID][]entities.Question, len(attempts))
+	for _, attempt := range attempts {
+		papers[attempt.ID] = quiz.BuildPaper(q, attempt.QuestionIDs, bankQuestions, attempt.Seed)
+	}
+	return papers, nil
+}
+
+// withPaper mengembalikan salinan quiz dengan soal diganti paper attempt
+func withPaper(q *entities.Quiz, paper []entities.Question) *entities.Quiz {
+	copied := *q
+	copied.Questions = paper
+	return &copied
+}
+
+// finalize menilai jawaban yang tersimpan terhadap paper attempt dan menutup attempt pada waktu submittedAt
+func (s *attemptService) finalize(ctx context.Context, q *entities.Quiz, paper []entities.Question, attemptID uuid.UUID, submittedAt time.Time) (*entities.QuizAttempt, error) {
+	return s.repo.Finalize(ctx, attemptID, func(attempt *entities.QuizAttempt, answers []entities.Answer) error {
+		result := grading.PolicyFor(q).Grade(paper, answers)
+		grading.Apply(result, answers)
+
+		duration := int(submittedAt.Sub(attempt.StartedAt).Seconds())
+		if duration < 0 {
+			duration = 0
+		}
+
+		attempt.SubmittedAt = &submittedAt
+		attempt.DurationSec = &duration
+		applyResult(attempt, result)
+		attempt.IsLate = isExpired(q, attempt, submittedAt)
+		return nil
+	})
+}
+
+// applyResult menyalin hasil penilaian ke nilai attempt
+func applyResult(attempt *entities.QuizAttempt, result grading.Result) {
+	attempt.Score = result.Score
+	attempt.Points = result.Earned
+	attempt.MaxPoints = result.Total
+	attempt.PendingReview = result.Pending > 0
+}
+
+func (s *attemptService) StartAttempt(ctx context.Context, userID uuid.UUID, courseRole string, courseID, quizID uuid.UUID) (*entities.QuizAttempt, *entities.Quiz, error) {
+	if courseRole != string(entities.CourseRoleStudent) {
+		return nil, nil, ErrForbidden
+	}
+
+	q, err := s.findPublishedQuiz(ctx, courseID, quizID)
+	if err != nil {
+		return nil, nil, err
+	}
+	q, err = s.forStudent(ctx, q, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+
+	// lanjutkan attempt yang masih berjalan, tutup yang waktunya sudah habis
+	open, err := s.repo.GetOpenAttempt(ctx, quizID, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, err
+	}
+	if open != nil {
+		paper, err := s.loadPaper(ctx, q, open)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !isExpired(q, open, now) {
+			return open, withPaper(q, paper), nil
+		}
+		if _, err := s.finalize(ctx, q, paper, open.ID, *Deadline(q, open)); err != nil && !errors.Is(err, ErrAlreadySubmitted) {
+			return nil, nil, err
+		}
+	}
+
+	if q.OpenAt != nil && now.Before(*q.OpenAt) {
+		return nil, nil, ErrQuizNotOpen
+	}
+	if q.CloseAt != nil && !now.Before(*q.CloseAt) {
+		return nil, nil, ErrQuizClosed
+	}
+
+	// undian soal pool dan urutan paper ditentukan sekali saat attempt dimulai
+	candidates, err := quiz.LoadPoolCandidates(ctx, s.quizRepo, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	seed := rand.Int63()
+
+	attempt := &entities.QuizAttempt{
+		QuizID:      quizID,
+		StudentID:   userID,
+		StartedAt:   now,
+		Seed:        seed,
+		QuestionIDs: quiz.DrawPaper(q, candidates, seed),
+	}
+	if err := s.repo.CreateAttempt(ctx, attempt, q.AttemptAllowed); err != nil {
+		if errors.Is(err, ErrAttemptInProgress) {
+			// request lain sudah memulai attempt secara bersamaan
+			open, openErr := s.repo.GetOpenAttempt(ctx, quizID, userID)
+			if openErr != nil {
+				return nil, nil, openErr
+			}
+			paper, err := s.loadPaper(ctx, q, open)
+			if err != nil {
+				return nil, nil, err
+			}
+			return open, withPaper(q, paper), nil
+		}
+		return nil, nil, err
+	}
+
+	paper, err := s.loadPaper(ctx, q, attempt)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attempt, withPaper(q, paper), nil
+}
+
+func (s *attemptService) GetMyAttempts(ctx context.Context, userID uuid.UUID, courseID, quizID uuid.UUID) ([]entities.QuizAttempt, *entities.Quiz, error) {
+	q, err := s.findPublishedQuiz(ctx, courseID, quizID)
+	if err != nil {
+		return nil, nil, err
+	}
+	q, err = s.forStudent(ctx, q, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	attempts, err := s.repo.GetAttemptsByStudent(ctx, quizID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attempts, q, nil
+}
+
+func (s *attemptService) GetQuizAttempts(ctx context.Context, courseRole string, courseID, quizID uuid.UUID) ([]entities.QuizAttempt, *entities.Quiz, Overrides, error) {
+	if !quiz.CanAuthor(courseRole) {
+		return nil, nil, nil, quiz.ErrForbidden
+	}
+	q, err := s.findQuiz(ctx, courseID, quizID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	attempts, err := s.repo.GetAttemptsByQuiz(ctx, quizID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	overrides, err := s.overrideRepo.GetOverridesByQuiz(ctx, quizID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return attempts, q, NewOverrides(overrides), nil
+}
+
+func (s *attemptService) GetAttempt(ctx context.Context, userID uuid.UUID, courseRole string, courseID, attemptID uuid.UUID) (*entities.QuizAttempt, *entities.Quiz, error) {
+	attempt, err := s.repo.GetAttemptWithAnswers(ctx, attemptID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrAttemptNotFound
+		}
+		return nil, nil, err
+	}
+	if !quiz.CanAuthor(courseRole) && attempt.StudentID != userID {
+		return nil, nil, ErrAttemptNotFound
+	}
+
+	q, err := s.findQuiz(ctx, courseID, attempt.QuizID)
+	if err != nil {
+		if errors.Is(err, quiz.ErrQuizNotFound) {
+			return nil, nil, ErrAttemptNotFound
+		}
+		return nil, nil, err
+	}
+	q, err = s.forStudent(ctx, q, attempt.StudentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// review menampilkan paper yang sama persis dengan saat dikerjakan
+	paper, err := s.loadPaper(ctx, q, attempt)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attempt, withPaper(q, paper), nil
+}
+
+// toAnswers memvalidasi jawaban terhadap soal paper; jawaban ganda untuk soal yang sama, yang terakhir dipakai
+func toAnswers(paper []entities.Question, inputs []AnswerInput) ([]entities.Answer, error) {
+	questions := make(map[uuid.UUID]*entities.Question, len(paper))
+	for i := range paper {
+		questions[paper[i].ID] = &paper[i]
+	}
+
+	index := make(map[uuid.UUID]int, len(inputs))
+	answers := make([]entities.Answer, 0, len(inputs))
+	for _, in := range inputs {
+		question, ok := questions[in.QuestionID]
+		if !ok {
+			return nil, ErrInvalidAnswer
+		}
+
+		answer, err := toAnswer(question, in)
+		if err != nil {
+			return nil, err
+		}
+
+		if i, seen := index[in.QuestionID]; seen {
+			answers[i] = answer
+			continue
+		}
+		index[in.QuestionID] = len(answers)
+		answers = append(answers, answer)
+	}
+	return answers, nil
+}
+
+// toAnswer hanya menyimpan field yang relevan dengan tipe soal
+func toAnswer(question *entities.Question, in AnswerInput) (entities.Answer, error) {
+	answer := entities.Answer{QuestionID: question.ID}
+
+	switch question.Type {
+	case entities.QuestionTypeSingleChoice, entities.QuestionTypeTrueFalse:
+		if in.ChoiceID != nil && !hasChoice(question, *in.ChoiceID) {
+			return answer, ErrInvalidAnswer
+		}
+		answer.ChoiceID = in.ChoiceID
+
+	case entities.QuestionTypeMultipleChoice:
+		seen := make(map[uuid.UUID]bool, len(in.ChoiceIDs))
+		for _, id := range in.ChoiceIDs {
+			if !hasChoice(question, id) {
+				return answer, ErrInvalidAnswer
+			}
+			if !seen[id] {
+				seen[id] = true
+				answer.ChoiceIDs = append(answer.ChoiceIDs, id)
+			}
+		}
+
+	case entities.QuestionTypeShortAnswer, entities.QuestionTypeEssay:
+		if in.Text != nil {
+			answer.TextAnswer = strings.TrimSpace(*in.Text)
+		}
+
+	case entities.QuestionTypeNumeric:
+		answer.NumericAnswer = in.Number
+	}
+
+	return answer, nil
+}
+
+func hasChoice(question *entities.Question, choiceID uuid.UUID) bool {
+	for _, c := range question.Choices {
+		if c.ID == choiceID {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *attemptService) SaveAnswers(ctx context.Context, userID uuid.UUID, courseID, attemptID uuid.UUID, inputs []AnswerInput) (*entities.QuizAttempt, *entities.Quiz, error) {
+	attempt, q, err := s.findOwnAttempt(ctx, userID, courseID, attemptID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attempt.SubmittedAt != nil {
+		return nil, nil, ErrAlreadySubmitted
+	}
+	if isExpired(q, attempt, time.Now()) {
+		return nil, nil, ErrAttemptExpired
+	}
+
+	paper, err := s.loadPaper(ctx, q, attempt)
+	if err != nil {
+		return nil, nil, err
+	}
+	answers, err := toAnswers(paper, inputs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.repo.SaveAnswers(ctx, attemptID, answers); err != nil {
+		return nil, nil, err
+	}
+
+	saved, err := s.repo.GetAttemptWithAnswers(ctx, attemptID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return saved, withPaper(q, paper), nil
+}
+
+func (s *attemptService) SubmitAttempt(ctx context.Context, userID uuid.UUID, courseID, attemptID uuid.UUID, inputs []AnswerInput) (*entities.QuizAttempt, *entities.Quiz, error) {
+	attempt, q, err := s.findOwnAttempt(ctx, userID, courseID, attemptID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attempt.SubmittedAt != nil {
+		return nil, nil, ErrAlreadySubmitted
+	}
+
+	now := time.Now()
+
+	paper, err := s.loadPaper(ctx, q, attempt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// jawaban yang dikirim setelah deadline diabaikan; yang dinilai hanya jawaban tersimpan
+	if len(inputs) > 0 && !isExpired(q, attempt, now) {
+		answers, err := toAnswers(paper, inputs)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := s.repo.SaveAnswers(ctx, attemptID, answers); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	submitted, err := s.finalize(ctx, q, paper, attemptID, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	return submitted, withPaper(q, paper), nil
+}
+
+func (s *attemptService) AutoSubmitExpired(ctx context.Context) (int, error) {
+	now := time.Now()
+	attempts, err := s.repo.GetExpiredOpenAttempts(ctx, now.Add(-submitGrace), autoSubmitBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	quizzes := make(map[uuid.UUID]*entities.Quiz)
+	submitted := 0
+	var firstErr error
+	for i := range attempts {
+		a := &attempts[i]
+
+		q, ok := quizzes[a.QuizID]
+		if !ok {
+			q, err = s.quizRepo.GetQuizWithQuestions(ctx, a.QuizID)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			quizzes[a.QuizID] = q
+		}
+
+		q, err = s.forStudent(ctx, q, a.StudentID)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if !isExpired(q, a, now) {
+			continue
+		}
+
+		paper, err := s.loadPaper(ctx, q, a)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		// dinilai dengan jawaban tersimpan, dicatat tepat pada deadline
+		if _, err := s.finalize(ctx, q, paper, a.ID, *Deadline(q, a)); err != nil {
+			if errors.Is(err, ErrAlreadySubmitted) {
+				continue
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		submitted++
+	}
+
+	return submitted, firstErr
+}
+
+func (s *attemptService) GetGradingQueue(ctx context.Context, courseRole string, courseID, quizID uuid.UUID) ([]entities.Answer, *entities.Quiz, error) {
+	if !quiz.CanAuthor(courseRole) {
+		return nil, nil, quiz.ErrForbidden
+	}
+	q, err := s.findQuiz(ctx, courseID, quizID)
+	if err != nil {
+		return nil, nil, err
+	}
+	answers, err := s.repo.GetPendingManualAnswers(ctx, quizID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return answers, q, nil
+}
+
+func (s *attemptService) GradeAnswer(ctx context.Context, graderID uuid.UUID, courseRole string, courseID, answerID uuid.UUID, input ManualGradeInput) (*entities.QuizAttempt, *entities.Quiz, error) {
+	if !quiz.CanAuthor(courseRole) {
+		return nil, nil, quiz.ErrForbidden
+	}
+
+	answer, err := s.repo.GetAnswerByID(ctx, answerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrAttemptNotFound
+		}
+		return nil, nil, err
+	}
+
+	q, err := s.findQuiz(ctx, courseID, answer.Attempt.QuizID)
+	if err != nil {
+		if errors.Is(err, quiz.ErrQuizNotFound) {
+			return nil, nil, ErrAttemptNotFound
+		}
+		return nil, nil, err
+	}
+	q, err = s.forStudent(ctx, q, answer.Attempt.StudentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	paper, err := s.loadPaper(ctx, q, &answer.Attempt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var question *entities.Question
+	for i := range paper {
+		if paper[i].ID == answer.QuestionID {
+			question = &paper[i]
+		}
+	}
+	if question == nil || question.Type != entities.QuestionTypeEssay {
+		return nil, nil, ErrNotManualGraded
+	}
+	if input.Points < 0 || input.Points > grading.MaxPoints(*question) {
+		return nil, nil, ErrInvalidPoints
+	}
+
+	graded, err := s.repo.Rescore(ctx, answer.AttemptID, func(attempt *entities.QuizAttempt, answers []entities.Answer) error {
+		now := time.Now()
+		for i := range answers {
+			if answers[i].ID == answerID {
+				points := grading.Round2(input.Points)
+				answers[i].Points = &points
+				answers[i].Comment = strings.TrimSpace(input.Comment)
+				answers[i].GradedByID = &graderID
+				answers[i].GradedAt = &now
+			}
+		}
+
+		result := grading.PolicyFor(q).Grade(paper, answers)
+		grading.Apply(result, answers)
+		applyResult(attempt, result)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return graded, withPaper(q, paper), nil
+}
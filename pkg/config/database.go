@@ -52,20 +52,71 @@ func ConnectDatabase() {
 		&entities.Answer{},
 		&entities.Choice{},
 		&entities.Question{},
+		&entities.QuestionBank{},
+		&entities.QuizPool{},
+		&entities.QuizOverride{},
+		&entities.QuizRegrade{},
+		&entities.AttemptEvent{},
 		&entities.Course{},
 		&entities.CourseModule{},
+		&entities.LogBook{},
+		&entities.LogBookEntry{},
+		&entities.LogBookReview{},
+		&entities.LogBookComment{},
+		&entities.LogBookAttachment{},
+		&entities.LogBookSchedule{},
 		&entities.FeedbackQuestion{},
 		&entities.FeedbackAnswer{},
 	)
 	if err != nil {
 		log.Fatal("❌ Failed to migrate:", err)
 	}
+	if err := syncForeignKeyRules(db); err != nil {
+		log.Fatal("❌ Failed to update foreign keys:", err)
+	}
 
 	log.Println("✅ Database connected and migrated successfully!")
 
 	seedRoles(db)
 }
 
+// foreignKeyRules berisi constraint yang aturan ON DELETE-nya pernah diubah.
+// AutoMigrate tidak mengubah constraint yang sudah ada, jadi constraint lama dibuat ulang.
+var foreignKeyRules = []struct {
+	model    interface{}
+	name     string
+	onDelete string // kode confdeltype di pg_constraint: c = CASCADE, n = SET NULL
+}{
+	{&entities.LogBook{}, "fk_log_books_entries", "c"},
+	{&entities.LogBookEntry{}, "fk_log_book_entries_attachments", "c"},
+	{&entities.LogBookReview{}, "fk_log_book_reviews_actor", "n"},
+	{&entities.LogBookComment{}, "fk_log_book_comments_entry", "n"},
+}
+
+func syncForeignKeyRules(db *gorm.DB) error {
+	for _, fk := range foreignKeyRules {
+		var current string
+		if err := db.Raw("SELECT confdeltype FROM pg_constraint WHERE conname = ?", fk.name).Scan(&current).Error; err != nil {
+			return err
+		}
+		if current == "" || current == fk.onDelete {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropConstraint(fk.model, fk.name); err != nil {
+				return err
+			}
+			return tx.Migrator().CreateConstraint(fk.model, fk.name)
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %v", fk.name, err)
+		}
+		log.Printf("✅ Foreign key %s diperbarui", fk.name)
+	}
+	return nil
+}
+
 func seedRoles(db *gorm.DB) {
 	roles := []entities.Role{
 		{
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"api-shiners/pkg/document"
+	"log"
+	"os"
+)
+
+// DocumentHeader membaca kop sekolah untuk dokumen cetak. Logo yang tidak bisa
+// dibaca diabaikan agar dokumen tetap bisa dibuat.
+func DocumentHeader() document.Header {
+	header := document.Header{
+		SchoolName: os.Getenv("SCHOOL_NAME"),
+		Address:    os.Getenv("SCHOOL_ADDRESS"),
+		Contact:    os.Getenv("SCHOOL_CONTACT"),
+		LogoPath:   os.Getenv("SCHOOL_LOGO_PATH"),
+	}
+	if header.SchoolName == "" {
+		header.SchoolName = "Shiners"
+	}
+	if header.LogoPath != "" {
+		if _, err := os.Stat(header.LogoPath); err != nil {
+			log.Printf("⚠️ School logo ignored: %v\n", err)
+			header.LogoPath = ""
+		}
+	}
+	return header
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"api-shiners/pkg/storage"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+var Storage storage.Storage
+
+func InitStorage() {
+	driver := strings.ToLower(os.Getenv("STORAGE_DRIVER"))
+
+	switch driver {
+	case "s3":
+		s3, err := storage.NewS3Storage(storage.S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			UseSSL:    os.Getenv("S3_USE_SSL") == "true",
+		})
+		if err != nil {
+			log.Fatalf("❌ Failed to init S3 storage: %v", err)
+		}
+		Storage = s3
+		log.Println("✅ Using S3 storage")
+
+	default:
+		dir := os.Getenv("STORAGE_LOCAL_DIR")
+		if dir == "" {
+			dir = "./uploads"
+		}
+
+		local, err := storage.NewLocalStorage(dir, AppURL()+"/api/files", FileSigningSecret())
+		if err != nil {
+			log.Fatalf("❌ Failed to init local storage: %v", err)
+		}
+		Storage = local
+		log.Printf("✅ Using local storage at %s\n", dir)
+	}
+}
+
+// AppURL adalah base URL publik API, dipakai untuk membentuk URL download
+func AppURL() string {
+	if url := os.Getenv("APP_URL"); url != "" {
+		return strings.TrimRight(url, "/")
+	}
+	port := os.Getenv("APP_PORT")
+	if port == "" {
+		port = "3000"
+	}
+	return fmt.Sprintf("http://localhost:%s", port)
+}
+
+// FileSigningSecret dipakai untuk menandatangani URL download storage lokal
+func FileSigningSecret() string {
+	if secret := os.Getenv("FILE_SIGNING_SECRET"); secret != "" {
+		return secret
+	}
+	return os.Getenv("JWT_SECRET")
+}
@@ -0,0 +1,297 @@
+package course
+
+import (
+	"api-shiners/pkg/entities"
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CourseFilter membatasi hasil GetAllCourses
+type CourseFilter struct {
+	OwnerTeacherID *uuid.UUID
+	PublishedOnly  bool
+}
+
+type CourseRepository interface {
+	CreateCourse(ctx context.Context, course *entities.Course) error
+	GetCourseByID(ctx context.Context, id uuid.UUID) (*entities.Course, error)
+	GetCourseByCode(ctx context.Context, code string) (*entities.Course, error)
+	GetAllCourses(ctx context.Context, filter CourseFilter, page, perPage int) ([]entities.Course, int64, error)
+	UpdateCourse(ctx context.Context, course *entities.Course) error
+	DeleteCourse(ctx context.Context, id uuid.UUID) error
+
+	CreateModule(ctx context.Context, module *entities.CourseModule) error
+	GetModuleByID(ctx context.Context, id uuid.UUID) (*entities.CourseModule, error)
+	GetModulesByCourse(ctx context.Context, courseID uuid.UUID) ([]entities.CourseModule, error)
+	UpdateModule(ctx context.Context, module *entities.CourseModule) error
+	DeleteModule(ctx context.Context, id uuid.UUID) error
+	ReorderModules(ctx context.Context, courseID uuid.UUID, orderedIDs []uuid.UUID) error
+
+	GetMaterialsByModule(ctx context.Context, moduleID uuid.UUID) ([]entities.Material, error)
+	ReorderMaterials(ctx context.Context, moduleID uuid.UUID, orderedIDs []uuid.UUID) error
+}
+
+type courseRepository struct {
+	db *gorm.DB
+}
+
+func NewCourseRepository(db *gorm.DB) CourseRepository {
+	return &courseRepository{db}
+}
+
+func (r *courseRepository) CreateCourse(ctx context.Context, course *entities.Course) error {
+	return r.db.WithContext(ctx).Create(course).Error
+}
+
+func (r *courseRepository) GetCourseByID(ctx context.Context, id uuid.UUID) (*entities.Course, error) {
+	var course entities.Course
+	err := r.db.WithContext(ctx).
+		Preload("Modules", func(db *gorm.DB) *gorm.DB {
+			return db.Order("order_no ASC, created_at ASC")
+		}).
+		First(&course, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &course, nil
+}
+
+func (r *courseRepository) GetCourseByCode(ctx context.Context, code string) (*entities.Course, error) {
+	var course entities.Course
+	if err := r.db.WithContext(ctx).Where("code = ?", code).First(&course).Error; err != nil {
+		return nil, err
+	}
+	return &course, nil
+}
+
+func (r *courseRepository) GetAllCourses(ctx context.Context, filter CourseFilter, page, perPage int) ([]entities.Course, int64, error) {
+	var courses []entities.Course
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entities.Course{})
+	if filter.OwnerTeacherID != nil {
+		query = query.Where("owner_teacher_id = ?", *filter.OwnerTeacherID)
+	}
+	if filter.PublishedOnly {
+		query = query.Where("is_published = ?", true)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * perPage
+	err := query.Order("created_at DESC").Limit(perPage).Offset(offset).Find(&courses).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return courses, total, nil
+}
+
+func (r *courseRepository) UpdateCourse(ctx context.Context, course *entities.Course) error {
+	return r.db.WithContext(ctx).
+		Model(&entities.Course{}).
+		Where("id = ?", course.ID).
+		Updates(map[string]interface{}{
+			"code":             course.Code,
+			"title":            course.Title,
+			"description":      course.Description,
+			"owner_teacher_id": course.OwnerTeacherID,
+			"is_published":     course.IsPublished,
+			"updated_at":       gorm.Expr("now()"),
+		}).Error
+}
+
+func (r *courseRepository) DeleteCourse(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&entities.Course{}, "id = ?", id).Error
+}
+
+// CreateModule menaruh modul baru di urutan terakhir. order_no dihitung setelah
+// course dikunci agar dua modul yang dibuat bersamaan tidak mendapat nomor yang sama.
+func (r *courseRepository) CreateModule(ctx context.Context, module *entities.CourseModule) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockRow(tx, &entities.Course{}, module.CourseID); err != nil {
+			return err
+		}
+
+		var maxOrder int
+		if err := tx.Model(&entities.CourseModule{}).
+			Where("course_id = ?", module.CourseID).
+			Select("COALESCE(MAX(order_no), 0)").
+			Scan(&maxOrder).Error; err != nil {
+			return err
+		}
+
+		module.OrderNo = maxOrder + 1
+		return tx.Create(module).Error
+	})
+}
+
+func (r *courseRepository) GetModuleByID(ctx context.Context, id uuid.UUID) (*entities.CourseModule, error) {
+	var module entities.CourseModule
+	if err := r.db.WithContext(ctx).First(&module, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &module, nil
+}
+
+func (r *courseRepository) GetModulesByCourse(ctx context.Context, courseID uuid.UUID) ([]entities.CourseModule, error) {
+	var modules []entities.CourseModule
+	err := r.db.WithContext(ctx).
+		Where("course_id = ?", courseID).
+		Order("order_no ASC, created_at ASC").
+		Find(&modules).Error
+	return modules, err
+}
+
+func (r *courseRepository) UpdateModule(ctx context.Context, module *entities.CourseModule) error {
+	return r.db.WithContext(ctx).
+		Model(&entities.CourseModule{}).
+		Where("id = ?", module.ID).
+		Updates(map[string]interface{}{
+			"title":      module.Title,
+			"updated_at": gorm.Expr("now()"),
+		}).Error
+}
+
+func (r *courseRepository) DeleteModule(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var module entities.CourseModule
+		if err := tx.First(&module, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		// kunci course agar tidak bentrok dengan reorder yang sedang berjalan
+		if err := lockRow(tx, &entities.Course{}, module.CourseID); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&entities.CourseModule{}, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		// rapatkan kembali order_no setelah modul dihapus
+		return tx.Exec(`
+			UPDATE course_modules m SET order_no = s.rn
+			FROM (
+				SELECT id, ROW_NUMBER() OVER (ORDER BY order_no ASC, created_at ASC) AS rn
+				FROM course_modules WHERE course_id = ?
+			) s
+			WHERE m.id = s.id`, module.CourseID).Error
+	})
+}
+
+// ReorderModules menulis ulang order_no seluruh modul course dalam satu transaksi.
+// Baris course dikunci (SELECT ... FOR UPDATE) sehingga dua reorder yang berjalan
+// bersamaan diproses bergantian dan selalu divalidasi terhadap data terbaru.
+func (r *courseRepository) ReorderModules(ctx context.Context, courseID uuid.UUID, orderedIDs []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockRow(tx, &entities.Course{}, courseID); err != nil {
+			return err
+		}
+
+		var currentIDs []uuid.UUID
+		if err := tx.Model(&entities.CourseModule{}).
+			Where("course_id = ?", courseID).
+			Pluck("id", &currentIDs).Error; err != nil {
+			return err
+		}
+
+		if err := compareOrder(currentIDs, orderedIDs); err != nil {
+			return err
+		}
+
+		for i, id := range orderedIDs {
+			if err := tx.Model(&entities.CourseModule{}).
+				Where("id = ?", id).
+				Updates(map[string]interface{}{
+					"order_no":   i + 1,
+					"updated_at": gorm.Expr("now()"),
+				}).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *courseRepository) GetMaterialsByModule(ctx context.Context, moduleID uuid.UUID) ([]entities.Material, error) {
+	var materials []entities.Material
+	err := r.db.WithContext(ctx).
+		Where("module_id = ?", moduleID).
+		Order("position ASC, created_at ASC").
+		Find(&materials).Error
+	return materials, err
+}
+
+// ReorderMaterials menulis ulang position seluruh material di dalam modul dalam satu transaksi
+func (r *courseRepository) ReorderMaterials(ctx context.Context, moduleID uuid.UUID, orderedIDs []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockRow(tx, &entities.CourseModule{}, moduleID); err != nil {
+			return err
+		}
+
+		var currentIDs []uuid.UUID
+		if err := tx.Model(&entities.Material{}).
+			Where("module_id = ?", moduleID).
+			Pluck("id", &currentIDs).Error; err != nil {
+			return err
+		}
+
+		if err := compareOrder(currentIDs, orderedIDs); err != nil {
+			return err
+		}
+
+		for i, id := range orderedIDs {
+			if err := tx.Model(&entities.Material{}).
+				Where("id = ?", id).
+				Update("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func lockRow(tx *gorm.DB, model interface{}, id uuid.UUID) error {
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		First(model, "id = ?", id).Error
+}
+
+// compareOrder memastikan daftar baru berisi tepat semua ID milik parent
+func compareOrder(currentIDs, orderedIDs []uuid.UUID) error {
+	current := make(map[uuid.UUID]bool, len(currentIDs))
+	for _, id := range currentIDs {
+		current[id] = true
+	}
+
+	mismatch := &OrderMismatchError{}
+	seen := make(map[uuid.UUID]bool, len(orderedIDs))
+	for _, id := range orderedIDs {
+		if seen[id] {
+			mismatch.Duplicate = append(mismatch.Duplicate, id)
+			continue
+		}
+		seen[id] = true
+		if !current[id] {
+			mismatch.Foreign = append(mismatch.Foreign, id)
+		}
+	}
+	for _, id := range currentIDs {
+		if !seen[id] {
+			mismatch.Missing = append(mismatch.Missing, id)
+		}
+	}
+
+	if len(mismatch.Missing) > 0 || len(mismatch.Foreign) > 0 || len(mismatch.Duplicate) > 0 {
+		return mismatch
+	}
+	return nil
+}
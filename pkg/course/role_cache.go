@@ -0,0 +1,37 @@
+package course
+
+import (
+	"api-shiners/pkg/config"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// RoleCacheKey adalah key Redis untuk role user di course, dipakai enrollment saat resolve role
+func RoleCacheKey(courseID, userID uuid.UUID) string {
+	return fmt.Sprintf("course_role:%s:%s", courseID, userID)
+}
+
+// InvalidateRoles menghapus cache role user tertentu di course
+func InvalidateRoles(ctx context.Context, courseID uuid.UUID, userIDs ...uuid.UUID) {
+	if config.RedisClient == nil || len(userIDs) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		keys = append(keys, RoleCacheKey(courseID, id))
+	}
+	config.RedisClient.Del(ctx, keys...)
+}
+
+// invalidateAllRoles menghapus seluruh cache role di course, misal saat course dihapus
+func invalidateAllRoles(ctx context.Context, courseID uuid.UUID) {
+	if config.RedisClient == nil {
+		return
+	}
+	iter := config.RedisClient.Scan(ctx, 0, fmt.Sprintf("course_role:%s:*", courseID), 100).Iterator()
+	for iter.Next(ctx) {
+		config.RedisClient.Del(ctx, iter.Val())
+	}
+}
@@ -0,0 +1,372 @@
+package course
+
+import (
+	"api-shiners/pkg/entities"
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCourseNotFound  = errors.New("course not found")
+	ErrModuleNotFound  = errors.New("module not found")
+	ErrCourseCodeTaken = errors.New("course code already used")
+	ErrForbidden       = errors.New("you are not allowed to manage this course")
+	ErrInvalidOrder    = errors.New("ordered list must contain every item exactly once")
+	ErrCourseRequired  = errors.New("code and title are required")
+	ErrTitleRequired   = errors.New("title is required")
+)
+
+// OrderMismatchError menjelaskan kenapa daftar urutan ditolak
+type OrderMismatchError struct {
+	Missing   []uuid.UUID
+	Foreign   []uuid.UUID
+	Duplicate []uuid.UUID
+}
+
+func (e *OrderMismatchError) Error() string {
+	return ErrInvalidOrder.Error()
+}
+
+func (e *OrderMismatchError) Is(target error) bool {
+	return target == ErrInvalidOrder
+}
+
+type CourseInput struct {
+	Code           string
+	Title          string
+	Description    *string // nil berarti deskripsi tidak diubah saat update
+	OwnerTeacherID *uuid.UUID
+}
+
+// ModuleInput tidak membawa order_no; urutan modul hanya diubah lewat ReorderModules
+type ModuleInput struct {
+	Title string
+}
+
+type CourseService interface {
+	CreateCourse(ctx context.Context, userID uuid.UUID, role string, input CourseInput) (*entities.Course, error)
+	GetAllCourses(ctx context.Context, userID uuid.UUID, role string, page, perPage int) ([]entities.Course, int64, error)
+	GetCourseByID(ctx context.Context, userID uuid.UUID, role string, courseID uuid.UUID) (*entities.Course, error)
+	UpdateCourse(ctx context.Context, userID uuid.UUID, role string, courseID uuid.UUID, input CourseInput) (*entities.Course, error)
+	DeleteCourse(ctx context.Context, userID uuid.UUID, role string, courseID uuid.UUID) error
+	SetPublished(ctx context.Context, userID uuid.UUID, role string, courseID uuid.UUID, published bool) (*entities.Course, error)
+
+	GetModules(ctx context.Context, userID uuid.UUID, role string, courseID uuid.UUID) ([]entities.CourseModule, error)
+	CreateModule(ctx context.Context, courseRole string, courseID uuid.UUID, input ModuleInput) (*entities.CourseModule, error)
+	UpdateModule(ctx context.Context, courseRole string, courseID, moduleID uuid.UUID, input ModuleInput) (*entities.CourseModule, error)
+	DeleteModule(ctx context.Context, courseRole string, courseID, moduleID uuid.UUID) error
+	ReorderModules(ctx context.Context, courseRole string, courseID uuid.UUID, orderedIDs []uuid.UUID) ([]entities.CourseModule, error)
+	ReorderMaterials(ctx context.Context, courseRole string, courseID, moduleID uuid.UUID, orderedIDs []uuid.UUID) ([]entities.Material, error)
+}
+
+type courseService struct {
+	repo CourseRepository
+}
+
+func NewCourseService(repo CourseRepository) CourseService {
+	return &courseService{repo}
+}
+
+// IsAdmin mengecek role global ADMIN
+func IsAdmin(role string) bool {
+	return strings.EqualFold(role, string(entities.ADMIN))
+}
+
+// CanManage: ADMIN boleh mengelola semua course, TEACHER hanya course miliknya
+func CanManage(course *entities.Course, userID uuid.UUID, role string) bool {
+	if IsAdmin(role) {
+		return true
+	}
+	return strings.EqualFold(role, string(entities.TEACHER)) && course.OwnerTeacherID == userID
+}
+
+// CanTeach: role di course (dari CourseAccess) yang boleh mengelola isi course, termasuk co-teacher
+func CanTeach(courseRole string) bool {
+	return courseRole == string(entities.ADMIN) || courseRole == string(entities.CourseRoleTeacher)
+}
+
+func canView(course *entities.Course, userID uuid.UUID, role string) bool {
+	return course.IsPublished || CanManage(course, userID, role)
+}
+
+func (s *courseService) findCourse(ctx context.Context, courseID uuid.UUID) (*entities.Course, error) {
+	course, err := s.repo.GetCourseByID(ctx, courseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCourseNotFound
+		}
+		return nil, err
+	}
+	return course, nil
+}
+
+func (s *courseService) findManagedCourse(ctx context.Context, userID uuid.UUID, role string, courseID uuid.UUID) (*entities.Course, error) {
+	course, err := s.findCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if !CanManage(course, userID, role) {
+		return nil, ErrForbidden
+	}
+	return course, nil
+}
+
+// findTaughtCourse dipakai untuk modul dan urutan isi course yang boleh dikelola semua teacher course
+func (s *courseService) findTaughtCourse(ctx context.Context, courseRole string, courseID uuid.UUID) (*entities.Course, error) {
+	if !CanTeach(courseRole) {
+		return nil, ErrForbidden
+	}
+	return s.findCourse(ctx, courseID)
+}
+
+func (s *courseService) findModule(ctx context.Context, courseID, moduleID uuid.UUID) (*entities.CourseModule, error) {
+	module, err := s.repo.GetModuleByID(ctx, moduleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrModuleNotFound
+		}
+		return nil, err
+	}
+	if module.CourseID != courseID {
+		return nil, ErrModuleNotFound
+	}
+	return module, nil
+}
+
+func (s *courseService) ensureCodeAvailable(ctx context.Context, code string, exceptID uuid.UUID) error {
+	existing, err := s.repo.GetCourseByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if existing.ID != exceptID {
+		return ErrCourseCodeTaken
+	}
+	return nil
+}
+
+func (s *courseService) CreateCourse(ctx context.Context, userID uuid.UUID, role string, input CourseInput) (*entities.Course, error) {
+	input.Code = strings.TrimSpace(input.Code)
+	input.Title = strings.TrimSpace(input.Title)
+	if input.Code == "" || input.Title == "" {
+		return nil, ErrCourseRequired
+	}
+
+	if err := s.ensureCodeAvailable(ctx, input.Code, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	ownerID := userID
+	if IsAdmin(role) && input.OwnerTeacherID != nil {
+		ownerID = *input.OwnerTeacherID
+	}
+
+	course := &entities.Course{
+		Code:           input.Code,
+		Title:          input.Title,
+		OwnerTeacherID: ownerID,
+	}
+	if input.Description != nil {
+		course.Description = *input.Description
+	}
+	if err := s.repo.CreateCourse(ctx, course); err != nil {
+		return nil, err
+	}
+
+	return course, nil
+}
+
+func (s *courseService) GetAllCourses(ctx context.Context, userID uuid.UUID, role string, page, perPage int) ([]entities.Course, int64, error) {
+	filter := CourseFilter{}
+
+	switch {
+	case IsAdmin(role):
+		// admin melihat semua course
+	case strings.EqualFold(role, string(entities.TEACHER)):
+		filter.OwnerTeacherID = &userID
+	default:
+		filter.PublishedOnly = true
+	}
+
+	return s.repo.GetAllCourses(ctx, filter, page, perPage)
+}
+
+func (s *courseService) GetCourseByID(ctx context.Context, userID uuid.UUID, role string, courseID uuid.UUID) (*entities.Course, error) {
+	course, err := s.findCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if !canView(course, userID, role) {
+		return nil, ErrCourseNotFound
+	}
+	return course, nil
+}
+
+func (s *courseService) UpdateCourse(ctx context.Context, userID uuid.UUID, role string, courseID uuid.UUID, input CourseInput) (*entities.Course, error) {
+	course, err := s.findManagedCourse(ctx, userID, role, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if code := strings.TrimSpace(input.Code); code != "" && code != course.Code {
+		if err := s.ensureCodeAvailable(ctx, code, course.ID); err != nil {
+			return nil, err
+		}
+		course.Code = code
+	}
+	if title := strings.TrimSpace(input.Title); title != "" {
+		course.Title = title
+	}
+	if input.Description != nil {
+		course.Description = *input.Description
+	}
+
+	// hanya admin yang boleh memindahkan kepemilikan course
+	previousOwner := course.OwnerTeacherID
+	if input.OwnerTeacherID != nil {
+		if !IsAdmin(role) {
+			return nil, ErrForbidden
+		}
+		course.OwnerTeacherID = *input.OwnerTeacherID
+	}
+
+	if err := s.repo.UpdateCourse(ctx, course); err != nil {
+		return nil, err
+	}
+	// role TEACHER owner lama dan role owner baru yang sudah di-cache tidak lagi berlaku
+	if course.OwnerTeacherID != previousOwner {
+		InvalidateRoles(ctx, courseID, previousOwner, course.OwnerTeacherID)
+	}
+
+	return s.findCourse(ctx, courseID)
+}
+
+func (s *courseService) DeleteCourse(ctx context.Context, userID uuid.UUID, role string, courseID uuid.UUID) error {
+	if _, err := s.findManagedCourse(ctx, userID, role, courseID); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteCourse(ctx, courseID); err != nil {
+		return err
+	}
+	invalidateAllRoles(ctx, courseID)
+	return nil
+}
+
+func (s *courseService) SetPublished(ctx context.Context, userID uuid.UUID, role string, courseID uuid.UUID, published bool) (*entities.Course, error) {
+	course, err := s.findManagedCourse(ctx, userID, role, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if course.IsPublished == published {
+		return course, nil
+	}
+
+	course.IsPublished = published
+	if err := s.repo.UpdateCourse(ctx, course); err != nil {
+		return nil, err
+	}
+
+	return course, nil
+}
+
+func (s *courseService) GetModules(ctx context.Context, userID uuid.UUID, role string, courseID uuid.UUID) ([]entities.CourseModule, error) {
+	if _, err := s.GetCourseByID(ctx, userID, role, courseID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetModulesByCourse(ctx, courseID)
+}
+
+func (s *courseService) CreateModule(ctx context.Context, courseRole string, courseID uuid.UUID, input ModuleInput) (*entities.CourseModule, error) {
+	if _, err := s.findTaughtCourse(ctx, courseRole, courseID); err != nil {
+		return nil, err
+	}
+
+	title := strings.TrimSpace(input.Title)
+	if title == "" {
+		return nil, ErrTitleRequired
+	}
+
+	module := &entities.CourseModule{
+		CourseID: courseID,
+		Title:    title,
+	}
+	if err := s.repo.CreateModule(ctx, module); err != nil {
+		return nil, err
+	}
+
+	return module, nil
+}
+
+func (s *courseService) UpdateModule(ctx context.Context, courseRole string, courseID, moduleID uuid.UUID, input ModuleInput) (*entities.CourseModule, error) {
+	if _, err := s.findTaughtCourse(ctx, courseRole, courseID); err != nil {
+		return nil, err
+	}
+
+	module, err := s.findModule(ctx, courseID, moduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if title := strings.TrimSpace(input.Title); title != "" {
+		module.Title = title
+	}
+
+	if err := s.repo.UpdateModule(ctx, module); err != nil {
+		return nil, err
+	}
+
+	return module, nil
+}
+
+func (s *courseService) DeleteModule(ctx context.Context, courseRole string, courseID, moduleID uuid.UUID) error {
+	if _, err := s.findTaughtCourse(ctx, courseRole, courseID); err != nil {
+		return err
+	}
+
+	if _, err := s.findModule(ctx, courseID, moduleID); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteModule(ctx, moduleID)
+}
+
+func (s *courseService) ReorderModules(ctx context.Context, courseRole string, courseID uuid.UUID, orderedIDs []uuid.UUID) ([]entities.CourseModule, error) {
+	if _, err := s.findTaughtCourse(ctx, courseRole, courseID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ReorderModules(ctx, courseID, orderedIDs); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCourseNotFound
+		}
+		return nil, err
+	}
+
+	return s.repo.GetModulesByCourse(ctx, courseID)
+}
+
+func (s *courseService) ReorderMaterials(ctx context.Context, courseRole string, courseID, moduleID uuid.UUID, orderedIDs []uuid.UUID) ([]entities.Material, error) {
+	if _, err := s.findTaughtCourse(ctx, courseRole, courseID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.findModule(ctx, courseID, moduleID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ReorderMaterials(ctx, moduleID, orderedIDs); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrModuleNotFound
+		}
+		return nil, err
+	}
+
+	return s.repo.GetMaterialsByModule(ctx, moduleID)
+}
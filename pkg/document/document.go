@@ -0,0 +1,286 @@
+// Package document merender dokumen cetak (PDF) dengan kop sekolah. Murni Go,
+// tanpa binary eksternal, dan bisa dipakai ulang oleh fitur lain selain logbook.
+package document
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	pageMargin = 15.0
+	lineHeight = 5.0
+	labelWidth = 40.0
+	cellPad    = 1.5
+	fontFamily = "DejaVu"
+)
+
+// Font UTF-8 ikut di-embed agar nama dan isi berhuruf non-Latin (misal é, ğ, Cyrillic)
+// tercetak apa adanya. Huruf Arab dan aksara lain yang butuh shaping/RTL tetap
+// tercetak per huruf tanpa disambung karena fpdf tidak melakukan shaping.
+var (
+	//go:embed fonts/DejaVuSansCondensed.ttf
+	fontRegular []byte
+	//go:embed fonts/DejaVuSansCondensed-Bold.ttf
+	fontBold []byte
+	//go:embed fonts/DejaVuSansCondensed-Oblique.ttf
+	fontItalic []byte
+)
+
+// Header adalah kop sekolah yang dicetak di setiap halaman. LogoPath opsional (PNG/JPG).
+type Header struct {
+	SchoolName string
+	Address    string
+	Contact    string
+	LogoPath   string
+}
+
+// Field adalah pasangan label-nilai pada blok identitas dokumen
+type Field struct {
+	Label string
+	Value string
+}
+
+// Column: Width dalam mm, 0 berarti membagi rata sisa lebar halaman
+type Column struct {
+	Title string
+	Width float64
+}
+
+// Document membungkus fpdf. Error penulisan dikumpulkan dan dicek lewat Err
+// sebelum dokumen dikirim.
+type Document struct {
+	pdf *fpdf.Fpdf
+}
+
+// printable mengganti karakter di luar Basic Multilingual Plane (misal emoji) yang
+// tidak didukung tabel lebar font fpdf
+func printable(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r > 0xFFFF {
+			return utf8.RuneError
+		}
+		return r
+	}, s)
+}
+
+// New membuat dokumen A4 dengan kop sekolah, judul, serta nomor halaman dan waktu cetak di footer
+func New(header Header, title string, printedAt time.Time) *Document {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pageMargin, pageMargin, pageMargin)
+	pdf.SetAutoPageBreak(true, pageMargin+5)
+	pdf.SetTitle(title, true)
+	pdf.SetCreator(header.SchoolName, true)
+	pdf.AliasNbPages("")
+	pdf.AddUTF8FontFromBytes(fontFamily, "", fontRegular)
+	pdf.AddUTF8FontFromBytes(fontFamily, "B", fontBold)
+	pdf.AddUTF8FontFromBytes(fontFamily, "I", fontItalic)
+
+	d := &Document{pdf: pdf}
+	pdf.SetHeaderFunc(func() { d.drawHeader(header) })
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-pageMargin)
+		pdf.SetFont(fontFamily, "I", 8)
+		pdf.SetTextColor(110, 110, 110)
+		pdf.CellFormat(0, lineHeight, printable("Printed "+printedAt.Format("2006-01-02 15:04 MST")), "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, lineHeight, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	})
+
+	pdf.AddPage()
+	pdf.SetFont(fontFamily, "B", 13)
+	pdf.CellFormat(0, 8, printable(title), "", 1, "C", false, 0, "")
+	pdf.Ln(2)
+	return d
+}
+
+func (d *Document) drawHeader(header Header) {
+	pdf := d.pdf
+	left, top, right, _ := pdf.GetMargins()
+	pageWidth, _ := pdf.GetPageSize()
+
+	if header.LogoPath != "" {
+		pdf.ImageOptions(header.LogoPath, left, top, 0, 18, false, fpdf.ImageOptions{ReadDpi: true}, 0, "")
+	}
+
+	pdf.SetY(top)
+	pdf.SetFont(fontFamily, "B", 14)
+	pdf.CellFormat(0, 7, printable(header.SchoolName), "", 1, "C", false, 0, "")
+	pdf.SetFont(fontFamily, "", 9)
+	for _, line := range []string{header.Address, header.Contact} {
+		if line != "" {
+			pdf.CellFormat(0, 4.5, printable(line), "", 1, "C", false, 0, "")
+		}
+	}
+
+	y := pdf.GetY() + 2
+	if header.LogoPath != "" && y < top+20 {
+		y = top + 20
+	}
+	pdf.SetLineWidth(0.6)
+	pdf.Line(left, y, pageWidth-right, y)
+	pdf.SetLineWidth(0.2)
+	pdf.SetY(y + 4)
+}
+
+// Fields menulis blok label-nilai, misal identitas siswa
+func (d *Document) Fields(fields ...Field) {
+	pdf := d.pdf
+	for _, f := range fields {
+		pdf.SetFont(fontFamily, "B", 10)
+		pdf.CellFormat(labelWidth, lineHeight+1, printable(f.Label), "", 0, "L", false, 0, "")
+		pdf.SetFont(fontFamily, "", 10)
+		pdf.MultiCell(0, lineHeight+1, printable(": "+f.Value), "", "L", false)
+	}
+	pdf.Ln(3)
+}
+
+// Heading menulis judul bagian
+func (d *Document) Heading(text string) {
+	pdf := d.pdf
+	pdf.Ln(2)
+	pdf.SetFont(fontFamily, "B", 11)
+	pdf.CellFormat(0, 7, printable(text), "B", 1, "L", false, 0, "")
+	pdf.Ln(2)
+}
+
+// Paragraph menulis teks bebas yang dibungkus otomatis
+func (d *Document) Paragraph(text string) {
+	d.pdf.SetFont(fontFamily, "", 10)
+	d.pdf.MultiCell(0, lineHeight, printable(text), "", "L", false)
+	d.pdf.Ln(2)
+}
+
+// Table menulis tabel bergaris. Isi sel dibungkus per baris dan judul kolom
+// diulang bila tabel berlanjut ke halaman berikutnya.
+func (d *Document) Table(columns []Column, rows [][]string) {
+	pdf := d.pdf
+	widths := d.columnWidths(columns)
+
+	titles := make([]string, len(columns))
+	for i, col := range columns {
+		titles[i] = col.Title
+	}
+	pdf.SetFont(fontFamily, "B", 9)
+	pdf.SetFillColor(230, 230, 230)
+	if d.needsBreak(d.rowHeight(widths, titles) + lineHeight + 2*cellPad) {
+		pdf.AddPage()
+	}
+	d.row(widths, titles, true)
+
+	pdf.SetFont(fontFamily, "", 9)
+	for _, cells := range rows {
+		if d.needsBreak(d.rowHeight(widths, cells)) {
+			pdf.AddPage()
+			pdf.SetFont(fontFamily, "B", 9)
+			d.row(widths, titles, true)
+			pdf.SetFont(fontFamily, "", 9)
+		}
+		d.row(widths, cells, false)
+	}
+	pdf.Ln(3)
+}
+
+func (d *Document) columnWidths(columns []Column) []float64 {
+	pageWidth, _ := d.pdf.GetPageSize()
+	left, _, right, _ := d.pdf.GetMargins()
+	remaining := pageWidth - left - right
+
+	flexible := 0
+	for _, col := range columns {
+		if col.Width > 0 {
+			remaining -= col.Width
+		} else {
+			flexible++
+		}
+	}
+
+	widths := make([]float64, len(columns))
+	for i, col := range columns {
+		widths[i] = col.Width
+		if col.Width <= 0 && flexible > 0 {
+			widths[i] = remaining / float64(flexible)
+		}
+	}
+	return widths
+}
+
+func (d *Document) rowHeight(widths []float64, cells []string) float64 {
+	lines := 1
+	for i, w := range widths {
+		if i >= len(cells) {
+			break
+		}
+		if n := len(d.pdf.SplitText(printable(cells[i]), w-2*cellPad)); n > lines {
+			lines = n
+		}
+	}
+	return float64(lines)*lineHeight + 2*cellPad
+}
+
+func (d *Document) needsBreak(height float64) bool {
+	_, pageHeight := d.pdf.GetPageSize()
+	_, bottom := d.pdf.GetAutoPageBreak()
+	return d.pdf.GetY()+height > pageHeight-bottom
+}
+
+// row menggambar satu baris dengan tinggi sel yang sama
+func (d *Document) row(widths []float64, cells []string, fill bool) {
+	pdf := d.pdf
+	height := d.rowHeight(widths, cells)
+	x, y := pdf.GetX(), pdf.GetY()
+
+	for i, w := range widths {
+		style := "D"
+		if fill {
+			style = "FD"
+		}
+		pdf.Rect(x, y, w, height, style)
+
+		text := ""
+		if i < len(cells) {
+			text = cells[i]
+		}
+		pdf.SetXY(x+cellPad, y+cellPad)
+		pdf.MultiCell(w-2*cellPad, lineHeight, printable(text), "", "L", false)
+		x += w
+	}
+	left, _, _, _ := pdf.GetMargins()
+	pdf.SetXY(left, y+height)
+}
+
+// Err mengembalikan error pertama saat menyusun dokumen, misal logo tidak terbaca
+func (d *Document) Err() error {
+	return d.pdf.Error()
+}
+
+// Output menulis PDF ke w
+func (d *Document) Output(w io.Writer) error {
+	return d.pdf.Output(w)
+}
+
+// FileName membentuk nama file aman dari bagian-bagian nama, misal ("logbook", "Budi", "2025-01-06")
+func FileName(ext string, parts ...string) string {
+	cleaned := make([]string, 0, len(parts))
+	for _, p := range parts {
+		p = strings.Map(func(r rune) rune {
+			switch {
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-':
+				return r
+			case r == ' ' || r == '_' || r == '.':
+				return '-'
+			}
+			return -1
+		}, strings.TrimSpace(p))
+		if p != "" {
+			cleaned = append(cleaned, strings.ToLower(p))
+		}
+	}
+	return strings.Join(cleaned, "-") + "." + ext
+}
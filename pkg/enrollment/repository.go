@@ -0,0 +1,116 @@
+package enrollment
+
+import (
+	"api-shiners/pkg/entities"
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EnrollmentRepository interface {
+	GetEnrollmentsByCourse(ctx context.Context, courseID uuid.UUID) ([]entities.Enrollment, error)
+	GetEnrollmentsByUser(ctx context.Context, userID uuid.UUID) ([]entities.Enrollment, error)
+	GetEnrollment(ctx context.Context, courseID, userID uuid.UUID) (*entities.Enrollment, error)
+	// CreateEnrollment mengembalikan false bila pasangan (user, course) sudah ada
+	CreateEnrollment(ctx context.Context, enrollment *entities.Enrollment) (bool, error)
+	DeleteEnrollment(ctx context.Context, courseID, userID uuid.UUID) (int64, error)
+
+	GetUserByID(ctx context.Context, id uuid.UUID) (*entities.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*entities.User, error)
+	GetCourseByJoinCode(ctx context.Context, code string) (*entities.Course, error)
+	SetJoinCode(ctx context.Context, courseID uuid.UUID, code string) error
+}
+
+type enrollmentRepository struct {
+	db *gorm.DB
+}
+
+func NewEnrollmentRepository(db *gorm.DB) EnrollmentRepository {
+	return &enrollmentRepository{db}
+}
+
+func (r *enrollmentRepository) GetEnrollmentsByCourse(ctx context.Context, courseID uuid.UUID) ([]entities.Enrollment, error) {
+	var enrollments []entities.Enrollment
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("course_id = ?", courseID).
+		Order("role_in_course DESC, enrolled_at ASC").
+		Find(&enrollments).Error
+	return enrollments, err
+}
+
+func (r *enrollmentRepository) GetEnrollmentsByUser(ctx context.Context, userID uuid.UUID) ([]entities.Enrollment, error) {
+	var enrollments []entities.Enrollment
+	err := r.db.WithContext(ctx).
+		Preload("Course").
+		Where("user_id = ?", userID).
+		Order("enrolled_at DESC").
+		Find(&enrollments).Error
+	return enrollments, err
+}
+
+func (r *enrollmentRepository) GetEnrollment(ctx context.Context, courseID, userID uuid.UUID) (*entities.Enrollment, error) {
+	var enrollment entities.Enrollment
+	err := r.db.WithContext(ctx).
+		Where("course_id = ? AND user_id = ?", courseID, userID).
+		First(&enrollment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &enrollment, nil
+}
+
+func (r *enrollmentRepository) CreateEnrollment(ctx context.Context, enrollment *entities.Enrollment) (bool, error) {
+	// unique index (user_id, course_id) menjadi penjaga terakhir bila ada request bersamaan
+	result := r.db.WithContext(ctx).
+		Omit("User", "Course").
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "course_id"}},
+			DoNothing: true,
+		}).
+		Create(enrollment)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *enrollmentRepository) DeleteEnrollment(ctx context.Context, courseID, userID uuid.UUID) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("course_id = ? AND user_id = ?", courseID, userID).
+		Delete(&entities.Enrollment{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *enrollmentRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
+	var user entities.User
+	if err := r.db.WithContext(ctx).First(&user, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *enrollmentRepository) GetUserByEmail(ctx context.Context, email string) (*entities.User, error) {
+	var user entities.User
+	if err := r.db.WithContext(ctx).Where("LOWER(email) = LOWER(?)", email).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *enrollmentRepository) GetCourseByJoinCode(ctx context.Context, code string) (*entities.Course, error) {
+	var course entities.Course
+	if err := r.db.WithContext(ctx).Where("join_code = ?", code).First(&course).Error; err != nil {
+		return nil, err
+	}
+	return &course, nil
+}
+
+func (r *enrollmentRepository) SetJoinCode(ctx context.Context, courseID uuid.UUID, code string) error {
+	return r.db.WithContext(ctx).
+		Model(&entities.Course{}).
+		Where("id = ?", courseID).
+		Update("join_code", code).Error
+}
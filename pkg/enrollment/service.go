@@ -0,0 +1,416 @@
+package enrollment
+
+import (
+	"api-shiners/pkg/config"
+	"api-shiners/pkg/course"
+	"api-shiners/pkg/entities"
+	"context"
+	"crypto/rand"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	joinCodeLength  = 8
+	joinCodeCharset = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // tanpa O/0/I/1 agar mudah dibaca
+	maxBulkRows     = 1000
+
+	courseRoleCacheTTL     = 5 * time.Minute
+	courseRoleNoneCacheTTL = time.Minute
+	courseRoleNone         = "NONE"
+)
+
+var (
+	ErrAlreadyEnrolled    = errors.New("user already enrolled in this course")
+	ErrEnrollmentNotFound = errors.New("enrollment not found")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrInvalidJoinCode    = errors.New("invalid join code")
+	ErrInvalidCourseRole  = errors.New("role_in_course must be STUDENT or TEACHER")
+	ErrTooManyRows        = fmt.Errorf("csv file exceeds %d rows", maxBulkRows)
+	ErrInvalidCSV         = errors.New("invalid csv file")
+)
+
+// Status hasil per baris bulk enrollment
+const (
+	BulkStatusEnrolled        = "ENROLLED"
+	BulkStatusAlreadyEnrolled = "ALREADY_ENROLLED"
+	BulkStatusFailed          = "FAILED"
+)
+
+type BulkEnrollResult struct {
+	Row     int    `json:"row"`
+	Email   string `json:"email"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+type EnrollmentService interface {
+	GetEnrollments(ctx context.Context, courseRole string, courseID uuid.UUID) ([]entities.Enrollment, error)
+	GetMyEnrollments(ctx context.Context, userID uuid.UUID) ([]entities.Enrollment, error)
+	Enroll(ctx context.Context, courseRole string, courseID, targetUserID uuid.UUID, roleInCourse entities.CourseRole) (*entities.Enrollment, error)
+	Unenroll(ctx context.Context, courseRole string, courseID, targetUserID uuid.UUID) error
+	BulkEnroll(ctx context.Context, courseRole string, courseID uuid.UUID, file io.Reader) ([]BulkEnrollResult, error)
+
+	GetJoinCode(ctx context.Context, courseRole string, courseID uuid.UUID) (string, error)
+	RotateJoinCode(ctx context.Context, courseRole string, courseID uuid.UUID) (string, error)
+	JoinByCode(ctx context.Context, userID uuid.UUID, code string) (*entities.Enrollment, error)
+
+	// GetCourseRole mengembalikan role user di course ("" bila tidak terdaftar).
+	// Owner teacher course selalu dianggap TEACHER.
+	GetCourseRole(ctx context.Context, courseID, userID uuid.UUID) (entities.CourseRole, error)
+}
+
+type enrollmentService struct {
+	repo       EnrollmentRepository
+	courseRepo course.CourseRepository
+}
+
+func NewEnrollmentService(repo EnrollmentRepository, courseRepo course.CourseRepository) EnrollmentService {
+	return &enrollmentService{
+		repo:       repo,
+		courseRepo: courseRepo,
+	}
+}
+
+// findManagedCourse: enrollment boleh dikelola owner maupun teacher lain yang terdaftar di course
+func (s *enrollmentService) findManagedCourse(ctx context.Context, courseRole string, courseID uuid.UUID) (*entities.Course, error) {
+	if !course.CanTeach(courseRole) {
+		return nil, course.ErrForbidden
+	}
+	found, err := s.courseRepo.GetCourseByID(ctx, courseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, course.ErrCourseNotFound
+		}
+		return nil, err
+	}
+	return found, nil
+}
+
+func parseCourseRole(value entities.CourseRole) (entities.CourseRole, error) {
+	switch entities.CourseRole(strings.ToUpper(strings.TrimSpace(string(value)))) {
+	case "", entities.CourseRoleStudent:
+		return entities.CourseRoleStudent, nil
+	case entities.CourseRoleTeacher:
+		return entities.CourseRoleTeacher, nil
+	default:
+		return "", ErrInvalidCourseRole
+	}
+}
+
+func (s *enrollmentService) GetEnrollments(ctx context.Context, courseRole string, courseID uuid.UUID) ([]entities.Enrollment, error) {
+	if _, err := s.findManagedCourse(ctx, courseRole, courseID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetEnrollmentsByCourse(ctx, courseID)
+}
+
+func (s *enrollmentService) GetMyEnrollments(ctx context.Context, userID uuid.UUID) ([]entities.Enrollment, error) {
+	return s.repo.GetEnrollmentsByUser(ctx, userID)
+}
+
+func (s *enrollmentService) Enroll(ctx context.Context, courseRole string, courseID, targetUserID uuid.UUID, roleInCourse entities.CourseRole) (*entities.Enrollment, error) {
+	if _, err := s.findManagedCourse(ctx, courseRole, courseID); err != nil {
+		return nil, err
+	}
+
+	roleInCourse, err := parseCourseRole(roleInCourse)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.GetUserByID(ctx, targetUserID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return s.create(ctx, courseID, targetUserID, roleInCourse)
+}
+
+func (s *enrollmentService) create(ctx context.Context, courseID, userID uuid.UUID, courseRole entities.CourseRole) (*entities.Enrollment, error) {
+	enrollment := &entities.Enrollment{
+		UserID:       userID,
+		CourseID:     courseID,
+		RoleInCourse: courseRole,
+	}
+
+	created, err := s.repo.CreateEnrollment(ctx, enrollment)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, ErrAlreadyEnrolled
+	}
+
+	course.InvalidateRoles(ctx, courseID, userID)
+	return enrollment, nil
+}
+
+func (s *enrollmentService) Unenroll(ctx context.Context, courseRole string, courseID, targetUserID uuid.UUID) error {
+	if _, err := s.findManagedCourse(ctx, courseRole, courseID); err != nil {
+		return err
+	}
+
+	deleted, err := s.repo.DeleteEnrollment(ctx, courseID, targetUserID)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrEnrollmentNotFound
+	}
+
+	course.InvalidateRoles(ctx, courseID, targetUserID)
+	return nil
+}
+
+// BulkEnroll membaca CSV berisi kolom email (dan opsional role_in_course).
+// Baris header boleh ada. Setiap baris dilaporkan hasilnya tanpa menggagalkan baris lain.
+func (s *enrollmentService) BulkEnroll(ctx context.Context, courseRole string, courseID uuid.UUID, file io.Reader) ([]BulkEnrollResult, error) {
+	if _, err := s.findManagedCourse(ctx, courseRole, courseID); err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+	}
+
+	emailCol, roleCol, start := 0, -1, 0
+	if len(records) > 0 {
+		for i, col := range records[0] {
+			switch strings.ToLower(strings.TrimSpace(strings.TrimPrefix(col, "\ufeff"))) {
+			case "email":
+				emailCol, start = i, 1
+			case "role", "role_in_course":
+				roleCol = i
+			}
+		}
+		if start == 0 {
+			// tanpa header: kolom pertama email, kolom kedua (opsional) role
+			emailCol, roleCol = 0, 1
+		}
+	}
+
+	if len(records)-start > maxBulkRows {
+		return nil, ErrTooManyRows
+	}
+
+	results := make([]BulkEnrollResult, 0, len(records)-start)
+	seen := make(map[string]bool)
+
+	for i := start; i < len(records); i++ {
+		record := records[i]
+		result := BulkEnrollResult{Row: i + 1}
+
+		if emailCol < len(record) {
+			result.Email = strings.ToLower(strings.TrimSpace(record[emailCol]))
+		}
+
+		if result.Email == "" {
+			if isBlank(record) {
+				continue
+			}
+			result.Status, result.Message = BulkStatusFailed, "email is required"
+			results = append(results, result)
+			continue
+		}
+
+		if _, err := mail.ParseAddress(result.Email); err != nil {
+			result.Status, result.Message = BulkStatusFailed, "invalid email format"
+			results = append(results, result)
+			continue
+		}
+
+		if seen[result.Email] {
+			result.Status, result.Message = BulkStatusAlreadyEnrolled, "duplicate email in file"
+			results = append(results, result)
+			continue
+		}
+		seen[result.Email] = true
+
+		courseRole := entities.CourseRoleStudent
+		if roleCol >= 0 && roleCol < len(record) {
+			parsed, err := parseCourseRole(entities.CourseRole(record[roleCol]))
+			if err != nil {
+				result.Status, result.Message = BulkStatusFailed, err.Error()
+				results = append(results, result)
+				continue
+			}
+			courseRole = parsed
+		}
+
+		user, err := s.repo.GetUserByEmail(ctx, result.Email)
+		if err != nil {
+			result.Status = BulkStatusFailed
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				result.Message = ErrUserNotFound.Error()
+			} else {
+				result.Message = err.Error()
+			}
+			results = append(results, result)
+			continue
+		}
+
+		if _, err := s.create(ctx, courseID, user.ID, courseRole); err != nil {
+			if errors.Is(err, ErrAlreadyEnrolled) {
+				result.Status = BulkStatusAlreadyEnrolled
+			} else {
+				result.Status, result.Message = BulkStatusFailed, err.Error()
+			}
+			results = append(results, result)
+			continue
+		}
+
+		result.Status = BulkStatusEnrolled
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func isBlank(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *enrollmentService) GetJoinCode(ctx context.Context, courseRole string, courseID uuid.UUID) (string, error) {
+	found, err := s.findManagedCourse(ctx, courseRole, courseID)
+	if err != nil {
+		return "", err
+	}
+
+	if found.JoinCode != nil && *found.JoinCode != "" {
+		return *found.JoinCode, nil
+	}
+
+	return s.assignJoinCode(ctx, courseID)
+}
+
+func (s *enrollmentService) RotateJoinCode(ctx context.Context, courseRole string, courseID uuid.UUID) (string, error) {
+	if _, err := s.findManagedCourse(ctx, courseRole, courseID); err != nil {
+		return "", err
+	}
+	return s.assignJoinCode(ctx, courseID)
+}
+
+func (s *enrollmentService) assignJoinCode(ctx context.Context, courseID uuid.UUID) (string, error) {
+	var lastErr error
+	// unique index join_code bisa bentrok walau kecil kemungkinannya, coba ulang beberapa kali
+	for attempt := 0; attempt < 5; attempt++ {
+		code, err := GenerateJoinCode()
+		if err != nil {
+			return "", err
+		}
+		if lastErr = s.repo.SetJoinCode(ctx, courseID, code); lastErr == nil {
+			return code, nil
+		}
+	}
+	return "", fmt.Errorf("failed to generate join code: %v", lastErr)
+}
+
+func (s *enrollmentService) JoinByCode(ctx context.Context, userID uuid.UUID, code string) (*entities.Enrollment, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return nil, ErrInvalidJoinCode
+	}
+
+	found, err := s.repo.GetCourseByJoinCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidJoinCode
+		}
+		return nil, err
+	}
+
+	if !found.IsPublished {
+		return nil, ErrInvalidJoinCode
+	}
+
+	return s.create(ctx, found.ID, userID, entities.CourseRoleStudent)
+}
+
+// GenerateJoinCode membuat kode acak yang mudah diketik siswa
+func GenerateJoinCode() (string, error) {
+	code := make([]byte, joinCodeLength)
+	max := big.NewInt(int64(len(joinCodeCharset)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = joinCodeCharset[n.Int64()]
+	}
+	return string(code), nil
+}
+
+func (s *enrollmentService) GetCourseRole(ctx context.Context, courseID, userID uuid.UUID) (entities.CourseRole, error) {
+	cacheKey := course.RoleCacheKey(courseID, userID)
+
+	if config.RedisClient != nil {
+		val, err := config.RedisClient.Get(ctx, cacheKey).Result()
+		if err == nil && val != "" {
+			if val == courseRoleNone {
+				return "", nil
+			}
+			return entities.CourseRole(val), nil
+		}
+	}
+
+	role, err := s.loadCourseRole(ctx, courseID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	if config.RedisClient != nil {
+		if role == "" {
+			config.RedisClient.Set(ctx, cacheKey, courseRoleNone, courseRoleNoneCacheTTL)
+		} else {
+			config.RedisClient.Set(ctx, cacheKey, string(role), courseRoleCacheTTL)
+		}
+	}
+
+	return role, nil
+}
+
+func (s *enrollmentService) loadCourseRole(ctx context.Context, courseID, userID uuid.UUID) (entities.CourseRole, error) {
+	found, err := s.courseRepo.GetCourseByID(ctx, courseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", course.ErrCourseNotFound
+		}
+		return "", err
+	}
+
+	if found.OwnerTeacherID == userID {
+		return entities.CourseRoleTeacher, nil
+	}
+
+	enrollment, err := s.repo.GetEnrollment(ctx, courseID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return enrollment.RoleInCourse, nil
+}
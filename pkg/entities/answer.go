@@ -7,15 +7,28 @@ import (
 )
 
 type Answer struct {
-	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	AttemptID  uuid.UUID `gorm:"type:uuid;not null"`
-	QuestionID uuid.UUID `gorm:"type:uuid;not null"`
-	ChoiceID   *uuid.UUID
-	IsCorrect  bool      `gorm:"default:false"`
-	CreatedAt  time.Time `gorm:"default:now()"`
-	UpdatedAt  time.Time `gorm:"default:now()"`
-
-	Attempt  QuizAttempt `gorm:"foreignKey:AttemptID;constraint:OnDelete:CASCADE"`
-	Question Question    `gorm:"foreignKey:QuestionID;constraint:OnDelete:CASCADE"`
-	Choice   *Choice     `gorm:"foreignKey:ChoiceID"`
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	AttemptID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_answer_attempt_question" json:"attempt_id"`
+	QuestionID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_answer_attempt_question" json:"question_id"`
+
+	// Isi jawaban sesuai tipe soal
+	ChoiceID      *uuid.UUID  `json:"choice_id"`                                              // SINGLE_CHOICE, TRUE_FALSE
+	ChoiceIDs     []uuid.UUID `gorm:"serializer:json;type:jsonb" json:"choice_ids,omitempty"` // MULTIPLE_CHOICE
+	TextAnswer    string      `gorm:"type:text" json:"text_answer,omitempty"`                 // SHORT_ANSWER, ESSAY
+	NumericAnswer *float64    `json:"numeric_answer,omitempty"`                               // NUMERIC
+
+	IsCorrect bool     `gorm:"default:false" json:"is_correct"`
+	Points    *float64 `gorm:"type:numeric(6,2)" json:"points"` // nil = belum dinilai
+
+	// Penilaian manual (ESSAY)
+	Comment    string     `gorm:"type:text" json:"comment,omitempty"`
+	GradedByID *uuid.UUID `gorm:"type:uuid" json:"graded_by_id,omitempty"`
+	GradedAt   *time.Time `json:"graded_at,omitempty"`
+
+	CreatedAt time.Time `gorm:"default:now()" json:"created_at"`
+	UpdatedAt time.Time `gorm:"default:now()" json:"updated_at"`
+
+	Attempt  QuizAttempt `gorm:"foreignKey:AttemptID;constraint:OnDelete:CASCADE" json:"-"`
+	Question Question    `gorm:"foreignKey:QuestionID;constraint:OnDelete:CASCADE" json:"-"`
+	Choice   *Choice     `gorm:"foreignKey:ChoiceID" json:"-"`
 }
@@ -0,0 +1,38 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AttemptEventType adalah jenis kejadian integritas yang dilaporkan client selama ujian
+type AttemptEventType string
+
+const (
+	AttemptEventFocusLost      AttemptEventType = "FOCUS_LOST"      // tab/jendela ujian kehilangan fokus
+	AttemptEventFullscreenExit AttemptEventType = "FULLSCREEN_EXIT" // keluar dari mode layar penuh
+	AttemptEventPaste          AttemptEventType = "PASTE"           // menempelkan teks ke jawaban
+	AttemptEventIPChange       AttemptEventType = "IP_CHANGE"       // client mendeteksi perubahan jaringan
+
+	// dicatat server dari request attempt, tidak bisa dikirim client
+	AttemptEventStarted   AttemptEventType = "ATTEMPT_STARTED"   // attempt dimulai atau dilanjutkan
+	AttemptEventSaved     AttemptEventType = "ANSWERS_SAVED"     // jawaban disimpan
+	AttemptEventSubmitted AttemptEventType = "ATTEMPT_SUBMITTED" // attempt disubmit student
+)
+
+// AttemptEvent adalah log integritas attempt yang hanya ditambah (append-only).
+// IPAddress dan UserAgent dicatat server dari request, bukan dari client.
+// Urutan kejadian memakai CreatedAt (jam server); OccurredAt adalah waktu yang dilaporkan client.
+type AttemptEvent struct {
+	ID         uuid.UUID        `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	AttemptID  uuid.UUID        `gorm:"type:uuid;not null;index:idx_attempt_event_created" json:"attempt_id"`
+	Type       AttemptEventType `gorm:"type:varchar(30);not null" json:"type"`
+	Detail     string           `gorm:"type:text" json:"detail"`
+	IPAddress  string           `gorm:"type:varchar(45)" json:"ip_address"`
+	UserAgent  string           `gorm:"type:text" json:"user_agent"`
+	OccurredAt time.Time        `gorm:"not null" json:"occurred_at"`
+	CreatedAt  time.Time        `gorm:"default:now();index:idx_attempt_event_created" json:"created_at"`
+
+	Attempt QuizAttempt `gorm:"foreignKey:AttemptID;constraint:OnDelete:CASCADE" json:"-"`
+}
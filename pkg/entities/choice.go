@@ -7,13 +7,13 @@ import (
 )
 
 type Choice struct {
-	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	QuestionID uuid.UUID `gorm:"type:uuid;not null"`
-	Text       string    `gorm:"type:text;not null"`
-	IsCorrect  bool      `gorm:"default:false"`
-	Position   *int
-	CreatedAt  time.Time `gorm:"default:now()"`
-	UpdatedAt  time.Time `gorm:"default:now()"`
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	QuestionID uuid.UUID `gorm:"type:uuid;not null" json:"question_id"`
+	Text       string    `gorm:"type:text;not null" json:"text"`
+	IsCorrect  bool      `gorm:"default:false" json:"is_correct"`
+	Position   *int      `json:"position"`
+	CreatedAt  time.Time `gorm:"default:now()" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"default:now()" json:"updated_at"`
 
-	Question Question `gorm:"foreignKey:QuestionID;constraint:OnDelete:CASCADE"`
+	Question Question `gorm:"foreignKey:QuestionID;constraint:OnDelete:CASCADE" json:"-"`
 }
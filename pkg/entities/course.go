@@ -13,6 +13,7 @@ type Course struct {
 	Description    string    `gorm:"type:text" json:"description,omitempty"`
 	OwnerTeacherID uuid.UUID `gorm:"type:uuid" json:"owner_teacher_id"`
 	IsPublished    bool      `gorm:"default:false" json:"is_published"`
+	JoinCode       *string   `gorm:"size:16;uniqueIndex" json:"-"`
 	CreatedAt      time.Time `gorm:"default:now()" json:"created_at"`
 	UpdatedAt      time.Time `gorm:"default:now()" json:"updated_at"`
 
@@ -14,12 +14,12 @@ const (
 )
 
 type Enrollment struct {
-	ID           uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	UserID       uuid.UUID  `gorm:"type:uuid;not null"`
-	CourseID     uuid.UUID  `gorm:"type:uuid;not null"`
-	RoleInCourse CourseRole `gorm:"type:varchar(50);not null"` // pakai VARCHAR
-	EnrolledAt   time.Time  `gorm:"default:now()"`
+	ID           uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID       uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_enrollment_user_course" json:"user_id"`
+	CourseID     uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_enrollment_user_course" json:"course_id"`
+	RoleInCourse CourseRole `gorm:"type:varchar(50);not null" json:"role_in_course"` // pakai VARCHAR
+	EnrolledAt   time.Time  `gorm:"default:now()" json:"enrolled_at"`
 
-	User   User   `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
-	Course Course `gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE"`
+	User   User   `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Course Course `gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE" json:"-"`
 }
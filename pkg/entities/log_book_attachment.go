@@ -0,0 +1,23 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LogBookAttachment adalah file bukti (foto, dokumen) pada satu entry logbook.
+// File disimpan di storage dengan key FileKey.
+type LogBookAttachment struct {
+	ID           uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	EntryID      uuid.UUID `gorm:"type:uuid;not null;index"`
+	FileName     string    `gorm:"type:varchar(255);not null"`
+	FileKey      string    `gorm:"type:varchar(512);not null"`
+	ContentType  string    `gorm:"type:varchar(255);not null"`
+	Size         int64     `gorm:"not null"`
+	UploadedByID uuid.UUID `gorm:"type:uuid;not null"`
+	CreatedAt    time.Time `gorm:"default:now()"`
+
+	Entry      LogBookEntry `gorm:"foreignKey:EntryID;constraint:OnDelete:CASCADE"`
+	UploadedBy User         `gorm:"foreignKey:UploadedByID;constraint:OnDelete:CASCADE"`
+}
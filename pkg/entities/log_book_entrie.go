@@ -7,12 +7,13 @@ import (
 )
 
 type LogBookEntry struct {
-	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	LogBookID  uuid.UUID `gorm:"type:uuid;not null"`
-	EntryDate  time.Time `gorm:"type:date;not null"`
-	Content    string    `gorm:"type:text"`
-	CreatedAt  time.Time `gorm:"default:now()"`
-	UpdatedAt  time.Time `gorm:"default:now()"`
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	LogBookID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_logbook_entry_date"`
+	EntryDate time.Time `gorm:"type:date;not null;uniqueIndex:idx_logbook_entry_date"`
+	Content   string    `gorm:"type:text"`
+	CreatedAt time.Time `gorm:"default:now()"`
+	UpdatedAt time.Time `gorm:"default:now()"`
 
-	LogBook LogBook `gorm:"foreignKey:LogBookID;constraint:OnDelete:CASCADE"`
+	LogBook     LogBook             `gorm:"foreignKey:LogBookID;constraint:OnDelete:CASCADE"`
+	Attachments []LogBookAttachment `gorm:"foreignKey:EntryID;constraint:OnDelete:CASCADE"`
 }
@@ -0,0 +1,50 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LogBookAction adalah aksi yang tercatat di riwayat review logbook
+type LogBookAction string
+
+const (
+	LogBookActionSubmitted         LogBookAction = "SUBMITTED"          // student mengirim logbook
+	LogBookActionRevisionRequested LogBookAction = "REVISION_REQUESTED" // teacher mengembalikan ke DRAFT
+	LogBookActionApproved          LogBookAction = "APPROVED"           // teacher menyetujui dan mengunci
+	LogBookActionLocked            LogBookAction = "LOCKED"             // dikunci tanpa persetujuan eksplisit
+	LogBookActionAutoSubmitted     LogBookAction = "AUTO_SUBMITTED"     // dikirim job terjadwal setelah masa tenggang
+	LogBookActionAutoLocked        LogBookAction = "AUTO_LOCKED"        // dikunci job terjadwal setelah masa tenggang
+)
+
+// LogBookReview adalah riwayat perpindahan status logbook, hanya ditambah.
+// ActorID nil berarti perubahan dilakukan job terjadwal atau akun pelakunya sudah dihapus.
+type LogBookReview struct {
+	ID         uuid.UUID     `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	LogBookID  uuid.UUID     `gorm:"type:uuid;not null;index" json:"logbook_id"`
+	ActorID    *uuid.UUID    `gorm:"type:uuid" json:"actor_id"`
+	Action     LogBookAction `gorm:"type:varchar(30);not null" json:"action"`
+	FromStatus StatusType    `gorm:"type:varchar(20);not null" json:"from_status"`
+	ToStatus   StatusType    `gorm:"type:varchar(20);not null" json:"to_status"`
+	Reason     string        `gorm:"type:text" json:"reason"`
+	CreatedAt  time.Time     `gorm:"default:now()" json:"created_at"`
+
+	LogBook LogBook `gorm:"foreignKey:LogBookID;constraint:OnDelete:CASCADE" json:"-"`
+	Actor   User    `gorm:"foreignKey:ActorID;constraint:OnDelete:SET NULL" json:"-"`
+}
+
+// LogBookComment adalah komentar teacher untuk logbook; EntryID diisi bila komentar untuk satu entry
+// dan menjadi nil bila entry tersebut dihapus, komentarnya tetap disimpan
+type LogBookComment struct {
+	ID        uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	LogBookID uuid.UUID  `gorm:"type:uuid;not null;index" json:"logbook_id"`
+	EntryID   *uuid.UUID `gorm:"type:uuid" json:"entry_id"`
+	AuthorID  uuid.UUID  `gorm:"type:uuid;not null" json:"author_id"`
+	Body      string     `gorm:"type:text;not null" json:"body"`
+	CreatedAt time.Time  `gorm:"default:now()" json:"created_at"`
+
+	LogBook LogBook       `gorm:"foreignKey:LogBookID;constraint:OnDelete:CASCADE" json:"-"`
+	Entry   *LogBookEntry `gorm:"foreignKey:EntryID;constraint:OnDelete:SET NULL" json:"-"`
+	Author  User          `gorm:"foreignKey:AuthorID;constraint:OnDelete:CASCADE" json:"-"`
+}
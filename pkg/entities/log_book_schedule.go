@@ -0,0 +1,42 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LogBookFrequency menentukan panjang periode logbook yang dibuat otomatis
+type LogBookFrequency string
+
+const (
+	LogBookWeekly  LogBookFrequency = "WEEKLY"  // satu logbook per minggu mulai WeekStart
+	LogBookMonthly LogBookFrequency = "MONTHLY" // satu logbook per bulan kalender
+)
+
+// LogBookCloseAction adalah tindakan job terjadwal setelah PeriodEnd + GraceDays
+type LogBookCloseAction string
+
+const (
+	LogBookAutoSubmit LogBookCloseAction = "AUTO_SUBMIT" // DRAFT dikirim untuk direview
+	LogBookAutoLock   LogBookCloseAction = "AUTO_LOCK"   // DRAFT dan SUBMITTED langsung dikunci
+)
+
+// LogBookSchedule adalah jadwal pembuatan logbook DRAFT untuk semua student di course.
+// Satu course hanya punya satu jadwal. EndDate nil berarti tanpa batas akhir.
+type LogBookSchedule struct {
+	ID          uuid.UUID          `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	CourseID    uuid.UUID          `gorm:"type:uuid;not null;uniqueIndex" json:"course_id"`
+	Frequency   LogBookFrequency   `gorm:"type:varchar(20);default:'WEEKLY'" json:"frequency"`
+	WeekStart   time.Weekday       `gorm:"not null" json:"week_start"` // 0 = Minggu, 1 = Senin
+	StartDate   time.Time          `gorm:"type:date;not null" json:"start_date"`
+	EndDate     *time.Time         `gorm:"type:date" json:"end_date"`
+	CloseAction LogBookCloseAction `gorm:"type:varchar(20);default:'AUTO_SUBMIT'" json:"close_action"`
+	GraceDays   int                `gorm:"default:0" json:"grace_days"`
+	IsActive    bool               `gorm:"not null" json:"is_active"`
+	CreatedByID uuid.UUID          `gorm:"type:uuid;not null" json:"created_by_id"`
+	CreatedAt   time.Time          `gorm:"default:now()" json:"created_at"`
+	UpdatedAt   time.Time          `gorm:"default:now()" json:"updated_at"`
+
+	Course Course `gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE" json:"-"`
+}
@@ -6,14 +6,56 @@ import (
 	"github.com/google/uuid"
 )
 
+type QuestionType string
+
+const (
+	QuestionTypeSingleChoice   QuestionType = "SINGLE_CHOICE"
+	QuestionTypeMultipleChoice QuestionType = "MULTIPLE_CHOICE" // multi-select, nilai parsial
+	QuestionTypeTrueFalse      QuestionType = "TRUE_FALSE"
+	QuestionTypeShortAnswer    QuestionType = "SHORT_ANSWER"
+	QuestionTypeNumeric        QuestionType = "NUMERIC"
+	QuestionTypeEssay          QuestionType = "ESSAY" // dinilai manual oleh teacher
+)
+
+// IsValid mengecek tipe soal yang didukung
+func (t QuestionType) IsValid() bool {
+	switch t {
+	case QuestionTypeSingleChoice, QuestionTypeMultipleChoice, QuestionTypeTrueFalse,
+		QuestionTypeShortAnswer, QuestionTypeNumeric, QuestionTypeEssay:
+		return true
+	default:
+		return false
+	}
+}
+
+// UsesChoices: tipe soal yang dijawab dengan memilih Choice
+func (t QuestionType) UsesChoices() bool {
+	return t == QuestionTypeSingleChoice || t == QuestionTypeMultipleChoice || t == QuestionTypeTrueFalse
+}
+
+// Question milik sebuah quiz (QuizID) atau sebuah bank soal (BankID)
 type Question struct {
-	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	QuizID    uuid.UUID `gorm:"type:uuid;not null"`
-	Type      string    `gorm:"type:varchar(50);not null"`
-	Text      string    `gorm:"type:text;not null"`
-	Position  *int
-	CreatedAt time.Time `gorm:"default:now()"`
-	UpdatedAt time.Time `gorm:"default:now()"`
-
-	Quiz Quiz `gorm:"foreignKey:QuizID;constraint:OnDelete:CASCADE"`
+	ID        uuid.UUID    `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	QuizID    *uuid.UUID   `gorm:"type:uuid;index" json:"quiz_id"`
+	BankID    *uuid.UUID   `gorm:"type:uuid;index" json:"bank_id,omitempty"`
+	Tags      []string     `gorm:"serializer:json;type:jsonb" json:"tags,omitempty"` // dipakai QuizPool untuk memilih soal bank
+	Type      QuestionType `gorm:"type:varchar(50);not null" json:"type"`
+	Text      string       `gorm:"type:text;not null" json:"text"`
+	Position  *int         `json:"position"`
+	Points    float64      `gorm:"type:numeric(6,2);default:1" json:"points"` // nilai maksimal soal
+	CreatedAt time.Time    `gorm:"default:now()" json:"created_at"`
+	UpdatedAt time.Time    `gorm:"default:now()" json:"updated_at"`
+
+	// Kunci jawaban SHORT_ANSWER: dicocokkan tanpa membedakan huruf besar/kecil,
+	// atau dengan regex AnswerPattern
+	AcceptedAnswers []string `gorm:"serializer:json;type:jsonb" json:"accepted_answers,omitempty"`
+	AnswerPattern   string   `gorm:"type:varchar(500)" json:"answer_pattern,omitempty"`
+
+	// Kunci jawaban NUMERIC: benar bila |jawaban - NumericAnswer| <= NumericTolerance
+	NumericAnswer    *float64 `json:"numeric_answer,omitempty"`
+	NumericTolerance float64  `gorm:"default:0" json:"numeric_tolerance,omitempty"`
+
+	Quiz    *Quiz         `gorm:"foreignKey:QuizID;constraint:OnDelete:CASCADE" json:"-"`
+	Bank    *QuestionBank `gorm:"foreignKey:BankID;constraint:OnDelete:CASCADE" json:"-"`
+	Choices []Choice      `gorm:"foreignKey:QuestionID" json:"choices,omitempty"`
 }
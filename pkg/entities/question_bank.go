@@ -0,0 +1,23 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QuestionBank adalah kumpulan soal yang bisa dipakai ulang oleh banyak quiz.
+// CourseID nil berarti bank pribadi milik teacher (OwnerID).
+type QuestionBank struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	OwnerID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"owner_id"`
+	CourseID    *uuid.UUID `gorm:"type:uuid;index" json:"course_id"`
+	Title       string     `gorm:"type:varchar(255);not null" json:"title"`
+	Description string     `gorm:"type:text" json:"description"`
+	CreatedAt   time.Time  `gorm:"default:now()" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"default:now()" json:"updated_at"`
+
+	Owner     User       `gorm:"foreignKey:OwnerID;constraint:OnDelete:CASCADE" json:"-"`
+	Course    *Course    `gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE" json:"-"`
+	Questions []Question `gorm:"foreignKey:BankID" json:"questions,omitempty"`
+}
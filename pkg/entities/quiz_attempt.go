@@ -7,17 +7,26 @@ import (
 )
 
 type QuizAttempt struct {
-	ID          uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	QuizID      uuid.UUID  `gorm:"type:uuid;not null"`
-	StudentID   uuid.UUID  `gorm:"type:uuid;not null"`
-	StartedAt   time.Time  `gorm:"default:now()"`
-	SubmittedAt *time.Time `gorm:"column:submited_at"` // sesuai kolom SQL
-	Score       float64    `gorm:"type:numeric(5,2)"`
-	DurationSec *int
-	CreatedAt   time.Time  `gorm:"default:now()"`
-	UpdatedAt   time.Time  `gorm:"default:now()"`
+	ID            uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	QuizID        uuid.UUID  `gorm:"type:uuid;not null;index:idx_attempt_quiz_student" json:"quiz_id"`
+	StudentID     uuid.UUID  `gorm:"type:uuid;not null;index:idx_attempt_quiz_student" json:"student_id"`
+	StartedAt     time.Time  `gorm:"default:now()" json:"started_at"`
+	SubmittedAt   *time.Time `gorm:"column:submited_at" json:"submitted_at"` // sesuai kolom SQL
+	Score         float64    `gorm:"type:numeric(5,2)" json:"score"`         // persentase 0-100
+	Points        float64    `gorm:"type:numeric(8,2);default:0" json:"points"`
+	MaxPoints     float64    `gorm:"type:numeric(8,2);default:0" json:"max_points"`
+	DurationSec   *int       `json:"duration_sec"`
+	IsLate        bool       `gorm:"default:false" json:"is_late"`        // disubmit setelah deadline server
+	PendingReview bool       `gorm:"default:false" json:"pending_review"` // ada essay yang belum dinilai
+	CreatedAt     time.Time  `gorm:"default:now()" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"default:now()" json:"updated_at"`
 
-	Quiz    Quiz  `gorm:"foreignKey:QuizID;constraint:OnDelete:CASCADE"`
-	Student User  `gorm:"foreignKey:StudentID;constraint:OnDelete:CASCADE"`
-	Answers []Answer `gorm:"foreignKey:AttemptID"`
+	// Seed dan QuestionIDs menyimpan paper attempt: soal hasil undian pool beserta urutannya.
+	// Urutan pilihan diturunkan ulang dari Seed saat review.
+	Seed        int64       `gorm:"default:0" json:"seed"`
+	QuestionIDs []uuid.UUID `gorm:"serializer:json;type:jsonb" json:"question_ids,omitempty"`
+
+	Quiz    Quiz     `gorm:"foreignKey:QuizID;constraint:OnDelete:CASCADE" json:"-"`
+	Student User     `gorm:"foreignKey:StudentID;constraint:OnDelete:CASCADE" json:"-"`
+	Answers []Answer `gorm:"foreignKey:AttemptID" json:"answers,omitempty"`
 }
@@ -0,0 +1,26 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QuizOverride mengubah pengaturan quiz untuk satu student (akomodasi), misalnya
+// tambahan waktu atau attempt. Field nil berarti mengikuti nilai di Quiz.
+type QuizOverride struct {
+	ID             uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	QuizID         uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_override_quiz_student" json:"quiz_id"`
+	StudentID      uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_override_quiz_student" json:"student_id"`
+	TimeLimitSec   *int       `json:"time_limit_sec"`
+	AttemptAllowed *int       `json:"attempt_allowed"`
+	OpenAt         *time.Time `json:"open_at"`
+	CloseAt        *time.Time `json:"close_at"`
+	Reason         string     `gorm:"type:text" json:"reason"`
+	CreatedByID    uuid.UUID  `gorm:"type:uuid;not null" json:"created_by_id"`
+	CreatedAt      time.Time  `gorm:"default:now()" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"default:now()" json:"updated_at"`
+
+	Quiz    Quiz `gorm:"foreignKey:QuizID;constraint:OnDelete:CASCADE" json:"-"`
+	Student User `gorm:"foreignKey:StudentID;constraint:OnDelete:CASCADE" json:"-"`
+}
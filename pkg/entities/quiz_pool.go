@@ -0,0 +1,23 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QuizPool mengundi DrawCount soal acak dari bank untuk setiap attempt.
+// Tags kosong berarti semua soal bank, selain itu soal yang punya salah satu tag.
+type QuizPool struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	QuizID    uuid.UUID `gorm:"type:uuid;not null;index" json:"quiz_id"`
+	BankID    uuid.UUID `gorm:"type:uuid;not null" json:"bank_id"`
+	Tags      []string  `gorm:"serializer:json;type:jsonb" json:"tags"`
+	DrawCount int       `gorm:"not null" json:"draw_count"`
+	Position  *int      `json:"position"`
+	CreatedAt time.Time `gorm:"default:now()" json:"created_at"`
+	UpdatedAt time.Time `gorm:"default:now()" json:"updated_at"`
+
+	Quiz Quiz         `gorm:"foreignKey:QuizID;constraint:OnDelete:CASCADE" json:"-"`
+	Bank QuestionBank `gorm:"foreignKey:BankID;constraint:OnDelete:CASCADE" json:"-"`
+}
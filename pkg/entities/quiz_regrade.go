@@ -0,0 +1,35 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RegradeChange mencatat perubahan nilai satu attempt akibat regrade
+type RegradeChange struct {
+	AttemptID uuid.UUID `json:"attempt_id"`
+	StudentID uuid.UUID `json:"student_id"`
+	OldScore  float64   `json:"old_score"`
+	NewScore  float64   `json:"new_score"`
+	OldPoints float64   `json:"old_points"`
+	NewPoints float64   `json:"new_points"`
+	// AnswersChanged adalah jumlah jawaban yang IsCorrect atau Points-nya berubah
+	AnswersChanged int `json:"answers_changed"`
+}
+
+// QuizRegrade adalah catatan audit penilaian ulang quiz setelah kunci jawaban
+// diperbaiki. QuestionID nil berarti seluruh soal quiz dinilai ulang.
+type QuizRegrade struct {
+	ID              uuid.UUID       `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	QuizID          uuid.UUID       `gorm:"type:uuid;not null;index" json:"quiz_id"`
+	QuestionID      *uuid.UUID      `gorm:"type:uuid" json:"question_id"`
+	RegradedByID    uuid.UUID       `gorm:"type:uuid;not null" json:"regraded_by_id"`
+	Reason          string          `gorm:"type:text" json:"reason"`
+	AttemptsChecked int             `json:"attempts_checked"`
+	AttemptsChanged int             `json:"attempts_changed"`
+	Changes         []RegradeChange `gorm:"serializer:json;type:jsonb" json:"changes"`
+	CreatedAt       time.Time       `gorm:"default:now()" json:"created_at"`
+
+	Quiz Quiz `gorm:"foreignKey:QuizID;constraint:OnDelete:CASCADE" json:"-"`
+}
@@ -6,19 +6,77 @@ import (
 	"github.com/google/uuid"
 )
 
+// MultiSelectScoring menentukan nilai soal MULTIPLE_CHOICE yang tidak tepat seluruhnya
+type MultiSelectScoring string
+
+const (
+	MultiSelectPartial      MultiSelectScoring = "PARTIAL"        // nilai parsial per pilihan
+	MultiSelectAllOrNothing MultiSelectScoring = "ALL_OR_NOTHING" // nilai penuh hanya bila tepat
+)
+
+// ScoreMode menentukan bentuk nilai attempt yang ditampilkan
+type ScoreMode string
+
+const (
+	ScoreModePercentage ScoreMode = "PERCENTAGE" // 0-100
+	ScoreModePoints     ScoreMode = "POINTS"     // jumlah poin mentah
+)
+
+// ReleasePolicy menentukan kapan hasil attempt ditampilkan ke student
+type ReleasePolicy string
+
+const (
+	ReleaseImmediate  ReleasePolicy = "IMMEDIATE"   // segera setelah submit
+	ReleaseAfterClose ReleasePolicy = "AFTER_CLOSE" // setelah CloseAt (termasuk override student)
+	ReleaseManual     ReleasePolicy = "MANUAL"      // saat teacher merilis
+)
+
+// ReleaseContent menentukan bagian hasil yang ditampilkan setelah dirilis
+type ReleaseContent string
+
+const (
+	ReleaseScore       ReleaseContent = "SCORE"       // nilai saja
+	ReleaseCorrectness ReleaseContent = "CORRECTNESS" // nilai dan benar/salah tiap jawaban
+	ReleaseFull        ReleaseContent = "FULL"        // termasuk kunci jawaban
+)
+
 type Quiz struct {
-	ID              uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	ModuleID        uuid.UUID  `gorm:"type:uuid;not null"`
-	Title           string     `gorm:"type:varchar(255);not null"`
-	Instructions    string     `gorm:"type:text"`
-	OpenAt          *time.Time `gorm:"type:timestamp"`
-	CloseAt         *time.Time `gorm:"type:timestamp"`
-	TimeLimitSec    *int       `gorm:"column:time_limit_sec"`
-	AttemptAllowed  int        `gorm:"default:1"`
-	IsPublished     bool       `gorm:"default:false"`
-	CreatedAt       time.Time  `gorm:"default:now()"`
-	UpdatedAt       time.Time  `gorm:"default:now()"`
-
-	Module    CourseModule  `gorm:"foreignKey:ModuleID;constraint:OnDelete:CASCADE"`
-	Questions []Question    `gorm:"foreignKey:QuizID"`
+	ID             uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ModuleID       uuid.UUID  `gorm:"type:uuid;not null" json:"module_id"`
+	Title          string     `gorm:"type:varchar(255);not null" json:"title"`
+	Instructions   string     `gorm:"type:text" json:"instructions"`
+	OpenAt         *time.Time `gorm:"type:timestamp" json:"open_at"`
+	CloseAt        *time.Time `gorm:"type:timestamp" json:"close_at"`
+	TimeLimitSec   *int       `gorm:"column:time_limit_sec" json:"time_limit_sec"`
+	AttemptAllowed int        `gorm:"default:1" json:"attempt_allowed"`
+	IsPublished    bool       `gorm:"default:false" json:"is_published"`
+	CreatedAt      time.Time  `gorm:"default:now()" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"default:now()" json:"updated_at"`
+
+	// acak urutan soal/pilihan per attempt, seed disimpan di QuizAttempt
+	ShuffleQuestions bool `gorm:"default:false" json:"shuffle_questions"`
+	ShuffleChoices   bool `gorm:"default:false" json:"shuffle_choices"`
+
+	// Kebijakan penilaian. NegativeMarking adalah proporsi nilai soal (0-1) yang
+	// dikurangi untuk pilihan salah; total nilai attempt minimal 0 kecuali AllowNegativeScore.
+	NegativeMarking    float64            `gorm:"type:numeric(4,2);default:0" json:"negative_marking"`
+	AllowNegativeScore bool               `gorm:"default:false" json:"allow_negative_score"`
+	MultiSelectScoring MultiSelectScoring `gorm:"type:varchar(20);default:'PARTIAL'" json:"multi_select_scoring"`
+	ScoreMode          ScoreMode          `gorm:"type:varchar(20);default:'PERCENTAGE'" json:"score_mode"`
+
+	// Rilis hasil ke student. ResultsReleasedAt diisi saat teacher merilis manual
+	// (juga mempercepat rilis AFTER_CLOSE).
+	ReleasePolicy     ReleasePolicy  `gorm:"type:varchar(20);default:'IMMEDIATE'" json:"release_policy"`
+	ReleaseContent    ReleaseContent `gorm:"type:varchar(20);default:'SCORE'" json:"release_content"`
+	ResultsReleasedAt *time.Time     `json:"results_released_at"`
+
+	// Batas kejadian integritas per attempt sebelum ditandai untuk ditinjau teacher.
+	// Nil berarti jenis kejadian tersebut tidak pernah ditandai.
+	MaxFocusLost      *int `json:"max_focus_lost"`
+	MaxFullscreenExit *int `json:"max_fullscreen_exit"`
+	MaxPaste          *int `json:"max_paste"`
+
+	Module    CourseModule `gorm:"foreignKey:ModuleID;constraint:OnDelete:CASCADE" json:"-"`
+	Questions []Question   `gorm:"foreignKey:QuizID" json:"questions,omitempty"`
+	Pools     []QuizPool   `gorm:"foreignKey:QuizID" json:"pools,omitempty"`
 }
@@ -0,0 +1,305 @@
+package grading
+
+import (
+	"api-shiners/pkg/entities"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// defaultMaxPoints adalah nilai maksimal tiap soal
+const defaultMaxPoints = 1.0
+
+// Outcome adalah hasil penilaian satu jawaban
+type Outcome struct {
+	Points    float64
+	MaxPoints float64
+	IsCorrect bool
+	Pending   bool // essay yang belum dinilai teacher
+}
+
+// Result adalah hasil penilaian satu attempt
+type Result struct {
+	Score   float64 // persentase 0-100, dibulatkan 2 desimal
+	Earned  float64
+	Total   float64 // jumlah nilai maksimal semua soal
+	Correct int
+	Pending int
+	// Outcomes per QuestionID untuk soal yang dijawab
+	Outcomes map[uuid.UUID]Outcome
+}
+
+// Policy adalah kebijakan penilaian tingkat quiz
+type Policy struct {
+	// NegativeMarking: proporsi nilai soal (0-1) yang dikurangi untuk pilihan salah
+	NegativeMarking float64
+	// AllowNegative: total nilai attempt boleh di bawah 0
+	AllowNegative bool
+	// PartialCredit: nilai parsial untuk MULTIPLE_CHOICE
+	PartialCredit bool
+}
+
+// DefaultPolicy: tanpa pengurangan nilai, dengan nilai parsial multi-select
+var DefaultPolicy = Policy{PartialCredit: true}
+
+// PolicyFor membaca kebijakan penilaian quiz
+func PolicyFor(q *entities.Quiz) Policy {
+	return Policy{
+		NegativeMarking: q.NegativeMarking,
+		AllowNegative:   q.AllowNegativeScore,
+		PartialCredit:   q.MultiSelectScoring != entities.MultiSelectAllOrNothing,
+	}
+}
+
+// MaxPoints adalah nilai maksimal sebuah soal (default 1)
+func MaxPoints(question entities.Question) float64 {
+	if question.Points > 0 {
+		return question.Points
+	}
+	return defaultMaxPoints
+}
+
+// DisplayScore mengembalikan nilai attempt beserta nilai maksimalnya sesuai ScoreMode quiz
+func DisplayScore(q *entities.Quiz, attempt *entities.QuizAttempt) (float64, float64) {
+	if q.ScoreMode == entities.ScoreModePoints {
+		return attempt.Points, attempt.MaxPoints
+	}
+	return attempt.Score, 100
+}
+
+// Grade menilai semua jawaban dengan DefaultPolicy. Soal yang tidak dijawab bernilai 0.
+func Grade(questions []entities.Question, answers []entities.Answer) Result {
+	return DefaultPolicy.Grade(questions, answers)
+}
+
+// GradeAnswer menilai satu jawaban dengan DefaultPolicy
+func GradeAnswer(question entities.Question, answer *entities.Answer) Outcome {
+	return DefaultPolicy.GradeAnswer(question, answer)
+}
+
+// Grade menilai semua jawaban. Soal yang tidak dijawab bernilai 0.
+func (p Policy) Grade(questions []entities.Question, answers []entities.Answer) Result {
+	return p.grade(questions, answers, p.GradeAnswer)
+}
+
+// Regrade seperti Grade, tetapi hanya soal dengan only(QuestionID) true yang
+// dinilai ulang; soal lain memakai nilai yang tersimpan pada jawaban.
+func (p Policy) Regrade(questions []entities.Question, answers []entities.Answer, only func(questionID uuid.UUID) bool) Result {
+	return p.grade(questions, answers, func(question entities.Question, answer *entities.Answer) Outcome {
+		if only(question.ID) {
+			return p.GradeAnswer(question, answer)
+		}
+		return storedOutcome(question, answer)
+	})
+}
+
+func (p Policy) grade(questions []entities.Question, answers []entities.Answer, gradeAnswer func(entities.Question, *entities.Answer) Outcome) Result {
+	byQuestion := make(map[uuid.UUID]*entities.Answer, len(answers))
+	for i := range answers {
+		byQuestion[answers[i].QuestionID] = &answers[i]
+	}
+
+	result := Result{Outcomes: make(map[uuid.UUID]Outcome, len(answers))}
+	for _, q := range questions {
+		result.Total += MaxPoints(q)
+
+		answer, ok := byQuestion[q.ID]
+		if !ok {
+			continue
+		}
+
+		outcome := gradeAnswer(q, answer)
+		result.Outcomes[q.ID] = outcome
+		result.Earned += outcome.Points
+		if outcome.IsCorrect {
+			result.Correct++
+		}
+		if outcome.Pending {
+			result.Pending++
+		}
+	}
+
+	if result.Earned < 0 && !p.AllowNegative {
+		result.Earned = 0
+	}
+	result.Earned = Round2(result.Earned)
+	if result.Total > 0 {
+		result.Score = Round2(result.Earned / result.Total * 100)
+	}
+	return result
+}
+
+// Apply menyalin hasil penilaian ke Points dan IsCorrect pada answers.
+// Nilai essay yang belum dinilai dibiarkan nil.
+func Apply(result Result, answers []entities.Answer) {
+	for i := range answers {
+		outcome, ok := result.Outcomes[answers[i].QuestionID]
+		if !ok {
+			continue
+		}
+		answers[i].IsCorrect = outcome.IsCorrect
+		if outcome.Pending {
+			answers[i].Points = nil
+			continue
+		}
+		points := Round2(outcome.Points)
+		answers[i].Points = &points
+	}
+}
+
+// GradeAnswer menilai satu jawaban sesuai tipe soal
+func (p Policy) GradeAnswer(question entities.Question, answer *entities.Answer) Outcome {
+	max := MaxPoints(question)
+	outcome := Outcome{MaxPoints: max}
+	penalty := -p.NegativeMarking * max
+
+	switch question.Type {
+	case entities.QuestionTypeSingleChoice, entities.QuestionTypeTrueFalse:
+		outcome.IsCorrect = isChoiceCorrect(question, answer.ChoiceID)
+		if outcome.IsCorrect {
+			outcome.Points = max
+		} else if answer.ChoiceID != nil {
+			outcome.Points = penalty
+		}
+
+	case entities.QuestionTypeMultipleChoice:
+		credit, exact := multiSelectCredit(question, answer.ChoiceIDs)
+		outcome.IsCorrect = exact
+		switch {
+		case exact:
+			outcome.Points = max
+		case len(answer.ChoiceIDs) == 0:
+			// tidak menjawab tidak dikurangi
+		case p.PartialCredit:
+			outcome.Points = math.Max(credit*max, penalty)
+		default:
+			outcome.Points = penalty
+		}
+
+	case entities.QuestionTypeShortAnswer:
+		outcome.IsCorrect = MatchShortAnswer(question, answer.TextAnswer)
+		if outcome.IsCorrect {
+			outcome.Points = max
+		}
+
+	case entities.QuestionTypeNumeric:
+		outcome.IsCorrect = matchNumeric(question, answer.NumericAnswer)
+		if outcome.IsCorrect {
+			outcome.Points = max
+		}
+
+	case entities.QuestionTypeEssay:
+		if answer.GradedAt == nil || answer.Points == nil {
+			outcome.Pending = true
+			return outcome
+		}
+		outcome.Points = math.Max(0, math.Min(*answer.Points, max))
+		outcome.IsCorrect = outcome.Points >= max
+	}
+
+	return outcome
+}
+
+// storedOutcome membaca hasil penilaian yang sudah tersimpan pada jawaban
+func storedOutcome(question entities.Question, answer *entities.Answer) Outcome {
+	outcome := Outcome{MaxPoints: MaxPoints(question), IsCorrect: answer.IsCorrect}
+	if answer.Points == nil {
+		outcome.Pending = question.Type == entities.QuestionTypeEssay
+		return outcome
+	}
+	outcome.Points = *answer.Points
+	return outcome
+}
+
+func isChoiceCorrect(question entities.Question, choiceID *uuid.UUID) bool {
+	if choiceID == nil {
+		return false
+	}
+	for _, c := range question.Choices {
+		if c.ID == *choiceID {
+			return c.IsCorrect
+		}
+	}
+	return false
+}
+
+// multiSelectCredit memberi nilai parsial: proporsi pilihan benar yang dipilih
+// dikurangi proporsi pilihan salah yang dipilih. Nilai bisa negatif; batas
+// bawahnya ditentukan oleh NegativeMarking pada Policy.
+func multiSelectCredit(question entities.Question, selected []uuid.UUID) (float64, bool) {
+	picked := make(map[uuid.UUID]bool, len(selected))
+	for _, id := range selected {
+		picked[id] = true
+	}
+
+	var correct, wrong, hits, misses int
+	for _, c := range question.Choices {
+		if c.IsCorrect {
+			correct++
+			if picked[c.ID] {
+				hits++
+			}
+		} else {
+			wrong++
+			if picked[c.ID] {
+				misses++
+			}
+		}
+	}
+	if correct == 0 {
+		return 0, false
+	}
+
+	credit := float64(hits) / float64(correct)
+	if wrong > 0 {
+		credit -= float64(misses) / float64(wrong)
+	}
+	return credit, hits == correct && misses == 0
+}
+
+// normalizeText: abaikan huruf besar/kecil dan spasi berlebih
+func normalizeText(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// CompileAnswerPattern mengompilasi regex kunci jawaban: harus cocok seluruh jawaban, tanpa membedakan huruf besar/kecil
+func CompileAnswerPattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile(`(?i)^(?:` + pattern + `)$`)
+}
+
+// MatchShortAnswer mencocokkan jawaban singkat dengan AcceptedAnswers atau AnswerPattern
+func MatchShortAnswer(question entities.Question, text string) bool {
+	given := normalizeText(text)
+	if given == "" {
+		return false
+	}
+
+	for _, accepted := range question.AcceptedAnswers {
+		if normalizeText(accepted) == given {
+			return true
+		}
+	}
+
+	if question.AnswerPattern != "" {
+		re, err := CompileAnswerPattern(question.AnswerPattern)
+		if err == nil && re.MatchString(strings.TrimSpace(text)) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchNumeric(question entities.Question, value *float64) bool {
+	if value == nil || question.NumericAnswer == nil {
+		return false
+	}
+	// epsilon kecil agar toleransi 0.01 tidak gagal karena pembulatan float
+	return math.Abs(*value-*question.NumericAnswer) <= question.NumericTolerance+1e-9
+}
+
+// Round2 membulatkan ke 2 desimal sesuai kolom numeric(5,2)
+func Round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
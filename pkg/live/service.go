@@ -0,0 +1,610 @@
+package live
+
+import (
+	"api-shiners/pkg/enrollment"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/grading"
+	"api-shiners/pkg/quiz"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultDurationSec = 20
+	minDurationSec     = 5
+	maxDurationSec     = 120
+	// answerGrace menoleransi latensi jaringan di akhir countdown
+	answerGrace = time.Second
+	pinDigits   = 6
+	// pinRetries: PIN diacak ulang bila bentrok dengan sesi aktif lain
+	pinRetries = 5
+)
+
+var (
+	ErrNotStudent        = errors.New("only students enrolled in this course can join")
+	ErrNoLiveQuestions   = errors.New("quiz has no choice questions for live mode")
+	ErrInvalidDuration   = fmt.Errorf("duration_sec must be between %d and %d", minDurationSec, maxDurationSec)
+	ErrSessionFinished   = errors.New("live session has finished")
+	ErrNoActiveQuestion  = errors.New("no question is accepting answers")
+	ErrAlreadyAnswered   = errors.New("question has already been answered")
+	ErrInvalidLiveAnswer = errors.New("answer does not match the choices of the current question")
+)
+
+// Jenis event yang dikirim ke client WebSocket
+const (
+	EventLobby        = "lobby"
+	EventPlayerJoined = "player_joined"
+	EventQuestion     = "question"
+	EventAnswerCount  = "answer_count"
+	EventReveal       = "reveal"
+	EventFinished     = "finished"
+)
+
+// Event adalah pesan server ke client: {"type": "...", "data": {...}}
+type Event struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data,omitempty"`
+}
+
+type ChoicePayload struct {
+	ID   uuid.UUID `json:"id"`
+	Text string    `json:"text"`
+}
+
+// QuestionPayload tidak memuat kunci jawaban
+type QuestionPayload struct {
+	Index       int                   `json:"index"`
+	Total       int                   `json:"total"`
+	QuestionID  uuid.UUID             `json:"question_id"`
+	Type        entities.QuestionType `json:"type"`
+	Text        string                `json:"text"`
+	Choices     []ChoicePayload       `json:"choices"`
+	DurationSec int                   `json:"duration_sec"`
+	EndsAt      time.Time             `json:"ends_at"`
+}
+
+type AnswerCountPayload struct {
+	Index    int `json:"index"`
+	Answered int `json:"answered"`
+	Players  int `json:"players"`
+}
+
+type RevealPayload struct {
+	Index            int            `json:"index"`
+	QuestionID       uuid.UUID      `json:"question_id"`
+	CorrectChoiceIDs []uuid.UUID    `json:"correct_choice_ids"`
+	ChoiceCounts     map[string]int `json:"choice_counts"`
+	Leaderboard      []Player       `json:"leaderboard"`
+}
+
+type LeaderboardPayload struct {
+	Status      Status   `json:"status"`
+	Leaderboard []Player `json:"leaderboard"`
+}
+
+type LiveService interface {
+	// OpenSession membuat sesi baru dengan PIN untuk quiz (teacher course atau admin)
+	OpenSession(ctx context.Context, hostID uuid.UUID, courseRole string, courseID, quizID uuid.UUID, durationSec int) (*Session, error)
+	GetSession(ctx context.Context, courseRole string, courseID uuid.UUID, sessionID string) (*Session, []Player, error)
+	// NextQuestion menutup soal yang berjalan (bila ada) lalu mengirim soal berikutnya;
+	// setelah soal terakhir sesi selesai
+	NextQuestion(ctx context.Context, courseRole string, courseID uuid.UUID, sessionID string) (*Session, error)
+	// RevealQuestion menutup soal sebelum countdown habis
+	RevealQuestion(ctx context.Context, courseRole string, courseID uuid.UUID, sessionID string) (*Session, error)
+	// RevealExpired menutup soal yang countdown-nya sudah habis pada now, dari
+	// instance mana pun (dijalankan berkala oleh scheduler)
+	RevealExpired(ctx context.Context, now time.Time) (int, error)
+	EndSession(ctx context.Context, courseRole string, courseID uuid.UUID, sessionID string) (*Session, []Player, error)
+	// HostTicket membuat tiket WebSocket untuk layar teacher
+	HostTicket(ctx context.Context, hostID uuid.UUID, courseRole string, courseID uuid.UUID, sessionID string) (string, error)
+
+	// Join mendaftarkan student ke sesi dengan PIN dan mengembalikan tiket WebSocket
+	Join(ctx context.Context, userID uuid.UUID, pin string) (*Session, string, error)
+	// Connect menukar tiket WebSocket (sekali pakai) dengan identitas koneksi
+	Connect(ctx context.Context, token string) (*Ticket, error)
+	// Snapshot adalah state terkini untuk client yang baru (re)connect
+	Snapshot(ctx context.Context, sessionID string) (*Event, error)
+	SubmitAnswer(ctx context.Context, sessionID string, userID uuid.UUID, choiceIDs []uuid.UUID) (*Answer, error)
+	Subscribe(ctx context.Context, sessionID string) (<-chan []byte, func(), error)
+}
+
+type liveService struct {
+	store          Store
+	quizRepo       quiz.QuizRepository
+	enrollmentRepo enrollment.EnrollmentRepository
+}
+
+func NewLiveService(store Store, quizRepo quiz.QuizRepository, enrollmentRepo enrollment.EnrollmentRepository) LiveService {
+	return &liveService{
+		store:          store,
+		quizRepo:       quizRepo,
+		enrollmentRepo: enrollmentRepo,
+	}
+}
+
+func randomPIN() (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < pinDigits; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", pinDigits, n), nil
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (s *liveService) publish(ctx context.Context, sessionID string, event Event) {
+	payload, err := json.Marshal(event)
+	if err == nil {
+		err = s.store.Publish(ctx, sessionID, payload)
+	}
+	if err != nil {
+		log.Printf("⚠️ Failed to publish live event %s: %v", event.Type, err)
+	}
+}
+
+// findSession memuat sesi milik course untuk teacher/admin
+func (s *liveService) findSession(ctx context.Context, courseRole string, courseID uuid.UUID, sessionID string) (*Session, error) {
+	if !quiz.CanAuthor(courseRole) {
+		return nil, quiz.ErrForbidden
+	}
+	session, err := s.store.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.CourseID != courseID {
+		return nil, ErrSessionNotFound
+	}
+	return session, nil
+}
+
+func (s *liveService) OpenSession(ctx context.Context, hostID uuid.UUID, courseRole string, courseID, quizID uuid.UUID, durationSec int) (*Session, error) {
+	if !quiz.CanAuthor(courseRole) {
+		return nil, quiz.ErrForbidden
+	}
+	if durationSec == 0 {
+		durationSec = DefaultDurationSec
+	}
+	if durationSec < minDurationSec || durationSec > maxDurationSec {
+		return nil, ErrInvalidDuration
+	}
+
+	q, err := s.quizRepo.GetQuizWithQuestions(ctx, quizID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, quiz.ErrQuizNotFound
+		}
+		return nil, err
+	}
+	if q.Module.CourseID != courseID {
+		return nil, quiz.ErrQuizNotFound
+	}
+
+	// mode live hanya memakai soal pilihan milik quiz (tanpa pool bank soal)
+	var questions []entities.Question
+	for _, question := range q.Questions {
+		if question.Type.UsesChoices() && len(question.Choices) > 0 {
+			questions = append(questions, question)
+		}
+	}
+	if len(questions) == 0 {
+		return nil, ErrNoLiveQuestions
+	}
+
+	session := &Session{
+		ID:            uuid.NewString(),
+		QuizID:        q.ID,
+		CourseID:      courseID,
+		HostID:        hostID,
+		Questions:     questions,
+		DurationSec:   durationSec,
+		Status:        StatusLobby,
+		QuestionIndex: -1,
+		CreatedAt:     time.Now(),
+	}
+	for i := 0; i < pinRetries; i++ {
+		if session.PIN, err = randomPIN(); err != nil {
+			return nil, err
+		}
+		err = s.store.CreateSession(ctx, session)
+		if !errors.Is(err, ErrPINTaken) {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (s *liveService) GetSession(ctx context.Context, courseRole string, courseID uuid.UUID, sessionID string) (*Session, []Player, error) {
+	session, err := s.findSession(ctx, courseRole, courseID, sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	players, err := s.store.GetPlayers(ctx, sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return session, Leaderboard(players), nil
+}
+
+func questionPayload(session *Session) QuestionPayload {
+	question := session.Questions[session.QuestionIndex]
+	payload := QuestionPayload{
+		Index:       session.QuestionIndex,
+		Total:       len(session.Questions),
+		QuestionID:  question.ID,
+		Type:        question.Type,
+		Text:        question.Text,
+		DurationSec: session.DurationSec,
+		EndsAt:      *session.QuestionEndsAt,
+	}
+	for _, choice := range question.Choices {
+		payload.Choices = append(payload.Choices, ChoicePayload{ID: choice.ID, Text: choice.Text})
+	}
+	return payload
+}
+
+func (s *liveService) NextQuestion(ctx context.Context, courseRole string, courseID uuid.UUID, sessionID string) (*Session, error) {
+	session, err := s.findSession(ctx, courseRole, courseID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status == StatusFinished {
+		return nil, ErrSessionFinished
+	}
+	if session.Status == StatusQuestion {
+		if session, err = s.reveal(ctx, session); err != nil {
+			return nil, err
+		}
+	}
+
+	if session.QuestionIndex+1 >= len(session.Questions) {
+		return s.finish(ctx, session)
+	}
+
+	now := time.Now()
+	endsAt := now.Add(time.Duration(session.DurationSec) * time.Second)
+	session.QuestionIndex++
+	session.Status = StatusQuestion
+	session.QuestionStartedAt = &now
+	session.QuestionEndsAt = &endsAt
+	if err := s.store.SaveSession(ctx, session); err != nil {
+		return nil, err
+	}
+	// batas waktu disimpan di store, bukan timer lokal, agar soal tetap ditutup
+	// walau instance yang mengirimnya mati
+	if err := s.store.ScheduleReveal(ctx, sessionID, session.QuestionIndex, endsAt.Add(answerGrace)); err != nil {
+		return nil, err
+	}
+	s.publish(ctx, sessionID, Event{Type: EventQuestion, Data: questionPayload(session)})
+	return session, nil
+}
+
+func (s *liveService) RevealExpired(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.store.DueReveals(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	revealed := 0
+	for _, d := range due {
+		session, err := s.store.GetSession(ctx, d.SessionID)
+		if err != nil && !errors.Is(err, ErrSessionNotFound) {
+			log.Printf("⚠️ Failed to load live session %s: %v", d.SessionID, err)
+			continue
+		}
+		if err == nil && session.Status == StatusQuestion && session.QuestionIndex == d.Index {
+			if _, err := s.reveal(ctx, session); err != nil {
+				log.Printf("⚠️ Failed to reveal live question %d of session %s: %v", d.Index, d.SessionID, err)
+				continue
+			}
+			revealed++
+		}
+		// soal yang sudah ditutup manual atau sesinya kedaluwarsa cukup dibuang dari jadwal
+		if err := s.store.UnscheduleReveal(ctx, d.SessionID, d.Index); err != nil {
+			return revealed, err
+		}
+	}
+	return revealed, nil
+}
+
+// reveal menutup soal berjalan, menambahkan poinnya ke skor player (sekali saja
+// walau dipanggil bersamaan dari beberapa instance) lalu mengirim leaderboard
+func (s *liveService) reveal(ctx context.Context, session *Session) (*Session, error) {
+	index := session.QuestionIndex
+	first, err := s.store.MarkRevealed(ctx, session.ID, index)
+	if err != nil {
+		return nil, err
+	}
+	if !first {
+		return s.store.GetSession(ctx, session.ID)
+	}
+
+	answers, err := s.store.GetAnswers(ctx, session.ID, index)
+	if err != nil {
+		return nil, err
+	}
+	players, err := s.store.GetPlayers(ctx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	byUser := make(map[uuid.UUID]Answer, len(answers))
+	counts := make(map[string]int)
+	for _, a := range answers {
+		byUser[a.UserID] = a
+		for _, id := range a.ChoiceIDs {
+			counts[id.String()]++
+		}
+	}
+	for i := range players {
+		a := byUser[players[i].UserID]
+		players[i].LastPoints = a.Points
+		players[i].Score += a.Points
+		if a.IsCorrect {
+			players[i].Correct++
+		}
+	}
+	if err := s.store.SavePlayers(ctx, session.ID, players); err != nil {
+		return nil, err
+	}
+
+	// dibaca ulang agar tidak menimpa soal berikutnya yang sudah dikirim instance lain
+	current, err := s.store.GetSession(ctx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	if current.Status == StatusQuestion && current.QuestionIndex == index {
+		current.Status = StatusReveal
+		if err := s.store.SaveSession(ctx, current); err != nil {
+			return nil, err
+		}
+	}
+
+	question := current.Questions[index]
+	payload := RevealPayload{
+		Index:            index,
+		QuestionID:       question.ID,
+		CorrectChoiceIDs: []uuid.UUID{},
+		ChoiceCounts:     counts,
+		Leaderboard:      Leaderboard(players),
+	}
+	for _, choice := range question.Choices {
+		if choice.IsCorrect {
+			payload.CorrectChoiceIDs = append(payload.CorrectChoiceIDs, choice.ID)
+		}
+	}
+	s.publish(ctx, session.ID, Event{Type: EventReveal, Data: payload})
+	return current, nil
+}
+
+func (s *liveService) RevealQuestion(ctx context.Context, courseRole string, courseID uuid.UUID, sessionID string) (*Session, error) {
+	session, err := s.findSession(ctx, courseRole, courseID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != StatusQuestion {
+		return nil, ErrNoActiveQuestion
+	}
+	return s.reveal(ctx, session)
+}
+
+func (s *liveService) finish(ctx context.Context, session *Session) (*Session, error) {
+	session.Status = StatusFinished
+	session.QuestionStartedAt = nil
+	session.QuestionEndsAt = nil
+	if err := s.store.SaveSession(ctx, session); err != nil {
+		return nil, err
+	}
+	if err := s.store.ReleasePIN(ctx, session.PIN); err != nil {
+		return nil, err
+	}
+
+	players, err := s.store.GetPlayers(ctx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.publish(ctx, session.ID, Event{Type: EventFinished, Data: LeaderboardPayload{Status: StatusFinished, Leaderboard: Leaderboard(players)}})
+	return session, nil
+}
+
+func (s *liveService) EndSession(ctx context.Context, courseRole string, courseID uuid.UUID, sessionID string) (*Session, []Player, error) {
+	session, err := s.findSession(ctx, courseRole, courseID, sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if session.Status == StatusFinished {
+		return nil, nil, ErrSessionFinished
+	}
+	if session.Status == StatusQuestion {
+		if session, err = s.reveal(ctx, session); err != nil {
+			return nil, nil, err
+		}
+	}
+	if session, err = s.finish(ctx, session); err != nil {
+		return nil, nil, err
+	}
+
+	players, err := s.store.GetPlayers(ctx, sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return session, Leaderboard(players), nil
+}
+
+func (s *liveService) HostTicket(ctx context.Context, hostID uuid.UUID, courseRole string, courseID uuid.UUID, sessionID string) (string, error) {
+	session, err := s.findSession(ctx, courseRole, courseID, sessionID)
+	if err != nil {
+		return "", err
+	}
+	token, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	if err := s.store.CreateTicket(ctx, token, Ticket{SessionID: session.ID, UserID: hostID, IsHost: true}); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *liveService) Join(ctx context.Context, userID uuid.UUID, pin string) (*Session, string, error) {
+	session, err := s.store.FindByPIN(ctx, strings.TrimSpace(pin))
+	if err != nil {
+		return nil, "", err
+	}
+	if session.Status == StatusFinished {
+		return nil, "", ErrSessionFinished
+	}
+
+	enrolled, err := s.enrollmentRepo.GetEnrollment(ctx, session.CourseID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", ErrNotStudent
+		}
+		return nil, "", err
+	}
+	if enrolled.RoleInCourse != entities.CourseRoleStudent {
+		return nil, "", ErrNotStudent
+	}
+	user, err := s.enrollmentRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	player := Player{UserID: userID, Name: user.Name, JoinedAt: time.Now()}
+	added, err := s.store.AddPlayer(ctx, session.ID, player)
+	if err != nil {
+		return nil, "", err
+	}
+	if added {
+		s.publish(ctx, session.ID, Event{Type: EventPlayerJoined, Data: player})
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.store.CreateTicket(ctx, token, Ticket{SessionID: session.ID, UserID: userID}); err != nil {
+		return nil, "", err
+	}
+	return session, token, nil
+}
+
+func (s *liveService) Connect(ctx context.Context, token string) (*Ticket, error) {
+	return s.store.ConsumeTicket(ctx, token)
+}
+
+func (s *liveService) Snapshot(ctx context.Context, sessionID string) (*Event, error) {
+	session, err := s.store.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status == StatusQuestion {
+		return &Event{Type: EventQuestion, Data: questionPayload(session)}, nil
+	}
+
+	players, err := s.store.GetPlayers(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	eventType := EventLobby
+	if session.Status == StatusFinished {
+		eventType = EventFinished
+	}
+	return &Event{Type: eventType, Data: LeaderboardPayload{Status: session.Status, Leaderboard: Leaderboard(players)}}, nil
+}
+
+func (s *liveService) SubmitAnswer(ctx context.Context, sessionID string, userID uuid.UUID, choiceIDs []uuid.UUID) (*Answer, error) {
+	session, err := s.store.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if session.Status != StatusQuestion || now.After(session.QuestionEndsAt.Add(answerGrace)) {
+		return nil, ErrNoActiveQuestion
+	}
+
+	question := session.Questions[session.QuestionIndex]
+	answer := &entities.Answer{ChoiceIDs: choiceIDs}
+	for _, id := range choiceIDs {
+		valid := false
+		for _, choice := range question.Choices {
+			valid = valid || choice.ID == id
+		}
+		if !valid {
+			return nil, ErrInvalidLiveAnswer
+		}
+	}
+	if question.Type != entities.QuestionTypeMultipleChoice {
+		if len(choiceIDs) != 1 {
+			return nil, ErrInvalidLiveAnswer
+		}
+		answer.ChoiceID = &choiceIDs[0]
+	}
+
+	// multi-select dinilai semua-atau-tidak sama sekali, seperti kuis live pada umumnya
+	outcome := grading.Policy{}.GradeAnswer(question, answer)
+	recorded := Answer{
+		UserID:     userID,
+		ChoiceIDs:  choiceIDs,
+		IsCorrect:  outcome.IsCorrect,
+		Points:     Score(outcome.IsCorrect, now.Sub(*session.QuestionStartedAt), time.Duration(session.DurationSec)*time.Second, grading.MaxPoints(question)),
+		AnsweredAt: now,
+	}
+	// ditolak store bila soal sudah di-reveal sejak sesi dibaca di atas
+	added, err := s.store.RecordAnswer(ctx, sessionID, session.QuestionIndex, recorded)
+	if err != nil {
+		return nil, err
+	}
+	if !added {
+		return nil, ErrAlreadyAnswered
+	}
+
+	answers, err := s.store.GetAnswers(ctx, sessionID, session.QuestionIndex)
+	if err != nil {
+		return nil, err
+	}
+	players, err := s.store.GetPlayers(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	s.publish(ctx, sessionID, Event{Type: EventAnswerCount, Data: AnswerCountPayload{
+		Index:    session.QuestionIndex,
+		Answered: len(answers),
+		Players:  len(players),
+	}})
+
+	// soal ditutup lebih awal bila semua player sudah menjawab
+	if len(answers) >= len(players) {
+		if _, err := s.reveal(ctx, session); err != nil {
+			return nil, err
+		}
+	}
+	return &recorded, nil
+}
+
+func (s *liveService) Subscribe(ctx context.Context, sessionID string) (<-chan []byte, func(), error) {
+	return s.store.Subscribe(ctx, sessionID)
+}
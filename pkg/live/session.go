@@ -0,0 +1,95 @@
+package live
+
+import (
+	"api-shiners/pkg/entities"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status adalah tahap sesi live quiz
+type Status string
+
+const (
+	StatusLobby    Status = "LOBBY"    // menunggu student bergabung
+	StatusQuestion Status = "QUESTION" // soal sedang berjalan
+	StatusReveal   Status = "REVEAL"   // jawaban benar dan leaderboard ditampilkan
+	StatusFinished Status = "FINISHED"
+)
+
+// Session adalah state sesi live quiz yang dibagi antar instance API lewat Store
+type Session struct {
+	ID       string    `json:"id"`
+	PIN      string    `json:"pin"`
+	QuizID   uuid.UUID `json:"quiz_id"`
+	CourseID uuid.UUID `json:"course_id"`
+	HostID   uuid.UUID `json:"host_id"`
+	// Questions adalah salinan soal saat sesi dibuka, agar perubahan quiz tidak
+	// memengaruhi sesi yang sedang berjalan
+	Questions   []entities.Question `json:"questions"`
+	DurationSec int                 `json:"duration_sec"`
+	Status      Status              `json:"status"`
+	// QuestionIndex -1 selama lobby
+	QuestionIndex     int        `json:"question_index"`
+	QuestionStartedAt *time.Time `json:"question_started_at"`
+	QuestionEndsAt    *time.Time `json:"question_ends_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// Player adalah student yang bergabung ke sesi beserta skor kumulatifnya
+type Player struct {
+	UserID     uuid.UUID `json:"user_id"`
+	Name       string    `json:"name"`
+	Score      int       `json:"score"`
+	Correct    int       `json:"correct"`
+	LastPoints int       `json:"last_points"`
+	JoinedAt   time.Time `json:"joined_at"`
+}
+
+// Answer adalah jawaban pertama student untuk satu soal; jawaban berikutnya diabaikan
+type Answer struct {
+	UserID     uuid.UUID   `json:"user_id"`
+	ChoiceIDs  []uuid.UUID `json:"choice_ids"`
+	IsCorrect  bool        `json:"is_correct"`
+	Points     int         `json:"points"`
+	AnsweredAt time.Time   `json:"answered_at"`
+}
+
+// Ticket menukar login REST dengan koneksi WebSocket, karena browser tidak bisa
+// mengirim header Authorization saat membuka WebSocket
+type Ticket struct {
+	SessionID string    `json:"session_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	IsHost    bool      `json:"is_host"`
+}
+
+// basePoints adalah poin maksimal jawaban benar untuk soal bernilai 1
+const basePoints = 1000
+
+// Score memberi poin jawaban benar berdasarkan kecepatan: penuh bila dijawab
+// seketika, turun linear sampai setengahnya di akhir countdown. weight adalah
+// nilai soal (Question.Points).
+func Score(correct bool, elapsed, limit time.Duration, weight float64) int {
+	if !correct || limit <= 0 {
+		return 0
+	}
+	ratio := math.Min(math.Max(float64(elapsed)/float64(limit), 0), 1)
+	return int(math.Round(basePoints * weight * (1 - ratio/2)))
+}
+
+// Leaderboard mengurutkan player berdasarkan skor, lalu jumlah benar, lalu waktu bergabung
+func Leaderboard(players []Player) []Player {
+	sorted := append([]Player(nil), players...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Score != sorted[j].Score {
+			return sorted[i].Score > sorted[j].Score
+		}
+		if sorted[i].Correct != sorted[j].Correct {
+			return sorted[i].Correct > sorted[j].Correct
+		}
+		return sorted[i].JoinedAt.Before(sorted[j].JoinedAt)
+	})
+	return sorted
+}
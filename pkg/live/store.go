@@ -0,0 +1,554 @@
+package live
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// sessionTTL: sesi yang ditinggal dihapus otomatis dari store
+	sessionTTL = 4 * time.Hour
+	// ticketTTL: tiket WebSocket harus dipakai segera setelah join
+	ticketTTL = time.Minute
+)
+
+var (
+	ErrSessionNotFound = errors.New("live session not found")
+	ErrPINTaken        = errors.New("live session pin is already in use")
+	ErrTicketInvalid   = errors.New("websocket ticket is invalid or expired")
+)
+
+// Store menyimpan state sesi live dan menyalurkan event ke semua instance API
+type Store interface {
+	// CreateSession gagal dengan ErrPINTaken bila PIN dipakai sesi lain yang masih aktif
+	CreateSession(ctx context.Context, session *Session) error
+	GetSession(ctx context.Context, id string) (*Session, error)
+	FindByPIN(ctx context.Context, pin string) (*Session, error)
+	SaveSession(ctx context.Context, session *Session) error
+	// ReleasePIN membebaskan PIN sesi yang sudah selesai
+	ReleasePIN(ctx context.Context, pin string) error
+
+	// AddPlayer tidak menimpa player yang sudah ada, agar skor tetap saat reconnect
+	AddPlayer(ctx context.Context, sessionID string, player Player) (bool, error)
+	GetPlayers(ctx context.Context, sessionID string) ([]Player, error)
+	SavePlayers(ctx context.Context, sessionID string, players []Player) error
+
+	// RecordAnswer hanya menyimpan jawaban pertama student untuk soal index. Gagal
+	// dengan ErrNoActiveQuestion bila soal sudah di-reveal; pengecekan dan penulisan
+	// dilakukan atomik agar jawaban tidak masuk setelah skor dihitung.
+	RecordAnswer(ctx context.Context, sessionID string, index int, answer Answer) (bool, error)
+	GetAnswers(ctx context.Context, sessionID string, index int) ([]Answer, error)
+	// MarkRevealed bernilai true hanya untuk pemanggil pertama, agar skor soal dihitung sekali
+	MarkRevealed(ctx context.Context, sessionID string, index int) (bool, error)
+
+	// ScheduleReveal mencatat batas waktu soal agar instance mana pun bisa menutupnya
+	ScheduleReveal(ctx context.Context, sessionID string, index int, at time.Time) error
+	// DueReveals mengembalikan soal yang batas waktunya sudah lewat pada now
+	DueReveals(ctx context.Context, now time.Time) ([]Deadline, error)
+	UnscheduleReveal(ctx context.Context, sessionID string, index int) error
+
+	CreateTicket(ctx context.Context, token string, ticket Ticket) error
+	// ConsumeTicket mengambil sekaligus menghapus tiket (sekali pakai)
+	ConsumeTicket(ctx context.Context, token string) (*Ticket, error)
+
+	Publish(ctx context.Context, sessionID string, payload []byte) error
+	// Subscribe mengembalikan event sesi sampai cancel dipanggil
+	Subscribe(ctx context.Context, sessionID string) (<-chan []byte, func(), error)
+}
+
+// Deadline adalah soal yang harus ditutup otomatis setelah countdown habis
+type Deadline struct {
+	SessionID string
+	Index     int
+}
+
+// NewStore memakai Redis bila tersedia; tanpa Redis sesi hanya berlaku di satu instance
+func NewStore(client *redis.Client) Store {
+	if client != nil {
+		return &RedisStore{client: client}
+	}
+	return NewMemoryStore()
+}
+
+//
+// ===== REDIS =====
+//
+
+type RedisStore struct {
+	client *redis.Client
+}
+
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+func sessionKey(id string) string         { return "live:session:" + id }
+func pinKey(pin string) string            { return "live:pin:" + pin }
+func playersKey(id string) string         { return sessionKey(id) + ":players" }
+func answersKey(id string, i int) string  { return fmt.Sprintf("%s:answers:%d", sessionKey(id), i) }
+func revealedKey(id string, i int) string { return fmt.Sprintf("%s:revealed:%d", sessionKey(id), i) }
+func ticketKey(token string) string       { return "live:ticket:" + token }
+func eventsChannel(id string) string      { return sessionKey(id) + ":events" }
+
+// revealsKey adalah sorted set "<session>:<index>" dengan skor batas waktu (unix ms)
+const revealsKey = "live:reveals"
+
+func deadlineMember(id string, i int) string { return fmt.Sprintf("%s:%d", id, i) }
+
+func (s *RedisStore) CreateSession(ctx context.Context, session *Session) error {
+	ok, err := s.client.SetNX(ctx, pinKey(session.PIN), session.ID, sessionTTL).Result()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrPINTaken
+	}
+	return s.SaveSession(ctx, session)
+}
+
+func (s *RedisStore) GetSession(ctx context.Context, id string) (*Session, error) {
+	data, err := s.client.Get(ctx, sessionKey(id)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, err
+	}
+	var session Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (s *RedisStore) FindByPIN(ctx context.Context, pin string) (*Session, error) {
+	id, err := s.client.Get(ctx, pinKey(pin)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, err
+	}
+	return s.GetSession(ctx, id)
+}
+
+func (s *RedisStore) SaveSession(ctx context.Context, session *Session) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, sessionKey(session.ID), data, sessionTTL).Err()
+}
+
+func (s *RedisStore) ReleasePIN(ctx context.Context, pin string) error {
+	return s.client.Del(ctx, pinKey(pin)).Err()
+}
+
+func (s *RedisStore) AddPlayer(ctx context.Context, sessionID string, player Player) (bool, error) {
+	data, err := json.Marshal(player)
+	if err != nil {
+		return false, err
+	}
+	added, err := s.client.HSetNX(ctx, playersKey(sessionID), player.UserID.String(), data).Result()
+	if err != nil {
+		return false, err
+	}
+	s.client.Expire(ctx, playersKey(sessionID), sessionTTL)
+	return added, nil
+}
+
+func (s *RedisStore) GetPlayers(ctx context.Context, sessionID string) ([]Player, error) {
+	values, err := s.client.HVals(ctx, playersKey(sessionID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	players := make([]Player, 0, len(values))
+	for _, v := range values {
+		var p Player
+		if err := json.Unmarshal([]byte(v), &p); err != nil {
+			return nil, err
+		}
+		players = append(players, p)
+	}
+	return players, nil
+}
+
+func (s *RedisStore) SavePlayers(ctx context.Context, sessionID string, players []Player) error {
+	if len(players) == 0 {
+		return nil
+	}
+	fields := make(map[string]interface{}, len(players))
+	for _, p := range players {
+		data, err := json.Marshal(p)
+		if err != nil {
+			return err
+		}
+		fields[p.UserID.String()] = data
+	}
+	return s.client.HSet(ctx, playersKey(sessionID), fields).Err()
+}
+
+// recordAnswerScript menolak jawaban bila soal sudah di-reveal (-1), selain itu
+// hasil HSETNX: 1 tersimpan, 0 student sudah menjawab
+var recordAnswerScript = redis.NewScript(`
+if redis.call("exists", KEYS[1]) == 1 then
+	return -1
+end
+local added = redis.call("hsetnx", KEYS[2], ARGV[1], ARGV[2])
+redis.call("expire", KEYS[2], ARGV[3])
+return added
+`)
+
+func (s *RedisStore) RecordAnswer(ctx context.Context, sessionID string, index int, answer Answer) (bool, error) {
+	data, err := json.Marshal(answer)
+	if err != nil {
+		return false, err
+	}
+	keys := []string{revealedKey(sessionID, index), answersKey(sessionID, index)}
+	result, err := recordAnswerScript.Run(ctx, s.client, keys, answer.UserID.String(), data, int(sessionTTL.Seconds())).Int()
+	if err != nil {
+		return false, err
+	}
+	if result < 0 {
+		return false, ErrNoActiveQuestion
+	}
+	return result == 1, nil
+}
+
+func (s *RedisStore) GetAnswers(ctx context.Context, sessionID string, index int) ([]Answer, error) {
+	values, err := s.client.HVals(ctx, answersKey(sessionID, index)).Result()
+	if err != nil {
+		return nil, err
+	}
+	answers := make([]Answer, 0, len(values))
+	for _, v := range values {
+		var a Answer
+		if err := json.Unmarshal([]byte(v), &a); err != nil {
+			return nil, err
+		}
+		answers = append(answers, a)
+	}
+	return answers, nil
+}
+
+func (s *RedisStore) MarkRevealed(ctx context.Context, sessionID string, index int) (bool, error) {
+	return s.client.SetNX(ctx, revealedKey(sessionID, index), 1, sessionTTL).Result()
+}
+
+func (s *RedisStore) ScheduleReveal(ctx context.Context, sessionID string, index int, at time.Time) error {
+	return s.client.ZAdd(ctx, revealsKey, redis.Z{Score: float64(at.UnixMilli()), Member: deadlineMember(sessionID, index)}).Err()
+}
+
+func (s *RedisStore) DueReveals(ctx context.Context, now time.Time) ([]Deadline, error) {
+	members, err := s.client.ZRangeByScore(ctx, revealsKey, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(now.UnixMilli(), 10),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	deadlines := make([]Deadline, 0, len(members))
+	for _, m := range members {
+		sep := strings.LastIndex(m, ":")
+		index, err := strconv.Atoi(m[sep+1:])
+		if sep < 0 || err != nil {
+			// member rusak dibuang agar tidak dibaca terus-menerus
+			s.client.ZRem(ctx, revealsKey, m)
+			continue
+		}
+		deadlines = append(deadlines, Deadline{SessionID: m[:sep], Index: index})
+	}
+	return deadlines, nil
+}
+
+func (s *RedisStore) UnscheduleReveal(ctx context.Context, sessionID string, index int) error {
+	return s.client.ZRem(ctx, revealsKey, deadlineMember(sessionID, index)).Err()
+}
+
+func (s *RedisStore) CreateTicket(ctx context.Context, token string, ticket Ticket) error {
+	data, err := json.Marshal(ticket)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, ticketKey(token), data, ticketTTL).Err()
+}
+
+func (s *RedisStore) ConsumeTicket(ctx context.Context, token string) (*Ticket, error) {
+	data, err := s.client.GetDel(ctx, ticketKey(token)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrTicketInvalid
+		}
+		return nil, err
+	}
+	var ticket Ticket
+	if err := json.Unmarshal(data, &ticket); err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+func (s *RedisStore) Publish(ctx context.Context, sessionID string, payload []byte) error {
+	return s.client.Publish(ctx, eventsChannel(sessionID), payload).Err()
+}
+
+func (s *RedisStore) Subscribe(ctx context.Context, sessionID string) (<-chan []byte, func(), error) {
+	pubsub := s.client.Subscribe(ctx, eventsChannel(sessionID))
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, nil, err
+	}
+
+	out := make(chan []byte, 16)
+	go func() {
+		defer close(out)
+		for msg := range pubsub.Channel() {
+			select {
+			case out <- []byte(msg.Payload):
+			default:
+			}
+		}
+	}()
+	return out, func() { pubsub.Close() }, nil
+}
+
+//
+// ===== MEMORY =====
+//
+
+// MemoryStore dipakai bila Redis tidak tersedia (mode dev) dan di test
+type MemoryStore struct {
+	mu          sync.Mutex
+	sessions    map[string]Session
+	pins        map[string]string
+	players     map[string]map[uuid.UUID]Player
+	answers     map[string]map[uuid.UUID]Answer
+	revealed    map[string]bool
+	deadlines   map[Deadline]time.Time
+	tickets     map[string]memoryTicket
+	subscribers map[string]map[chan []byte]struct{}
+}
+
+type memoryTicket struct {
+	ticket    Ticket
+	expiresAt time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		sessions:    make(map[string]Session),
+		pins:        make(map[string]string),
+		players:     make(map[string]map[uuid.UUID]Player),
+		answers:     make(map[string]map[uuid.UUID]Answer),
+		revealed:    make(map[string]bool),
+		deadlines:   make(map[Deadline]time.Time),
+		tickets:     make(map[string]memoryTicket),
+		subscribers: make(map[string]map[chan []byte]struct{}),
+	}
+}
+
+func (s *MemoryStore) CreateSession(_ context.Context, session *Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.pins[session.PIN]; ok {
+		return ErrPINTaken
+	}
+	s.pins[session.PIN] = session.ID
+	s.sessions[session.ID] = *session
+	return nil
+}
+
+func (s *MemoryStore) GetSession(_ context.Context, id string) (*Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[id]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	return &session, nil
+}
+
+func (s *MemoryStore) FindByPIN(ctx context.Context, pin string) (*Session, error) {
+	s.mu.Lock()
+	id, ok := s.pins[pin]
+	s.mu.Unlock()
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	return s.GetSession(ctx, id)
+}
+
+func (s *MemoryStore) SaveSession(_ context.Context, session *Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[session.ID] = *session
+	return nil
+}
+
+func (s *MemoryStore) ReleasePIN(_ context.Context, pin string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.pins, pin)
+	return nil
+}
+
+func (s *MemoryStore) AddPlayer(_ context.Context, sessionID string, player Player) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.players[sessionID] == nil {
+		s.players[sessionID] = make(map[uuid.UUID]Player)
+	}
+	if _, ok := s.players[sessionID][player.UserID]; ok {
+		return false, nil
+	}
+	s.players[sessionID][player.UserID] = player
+	return true, nil
+}
+
+func (s *MemoryStore) GetPlayers(_ context.Context, sessionID string) ([]Player, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	players := make([]Player, 0, len(s.players[sessionID]))
+	for _, p := range s.players[sessionID] {
+		players = append(players, p)
+	}
+	return players, nil
+}
+
+func (s *MemoryStore) SavePlayers(_ context.Context, sessionID string, players []Player) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.players[sessionID] == nil {
+		s.players[sessionID] = make(map[uuid.UUID]Player)
+	}
+	for _, p := range players {
+		s.players[sessionID][p.UserID] = p
+	}
+	return nil
+}
+
+func (s *MemoryStore) RecordAnswer(_ context.Context, sessionID string, index int, answer Answer) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.revealed[revealedKey(sessionID, index)] {
+		return false, ErrNoActiveQuestion
+	}
+	key := answersKey(sessionID, index)
+	if s.answers[key] == nil {
+		s.answers[key] = make(map[uuid.UUID]Answer)
+	}
+	if _, ok := s.answers[key][answer.UserID]; ok {
+		return false, nil
+	}
+	s.answers[key][answer.UserID] = answer
+	return true, nil
+}
+
+func (s *MemoryStore) GetAnswers(_ context.Context, sessionID string, index int) ([]Answer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	byUser := s.answers[answersKey(sessionID, index)]
+	answers := make([]Answer, 0, len(byUser))
+	for _, a := range byUser {
+		answers = append(answers, a)
+	}
+	return answers, nil
+}
+
+func (s *MemoryStore) MarkRevealed(_ context.Context, sessionID string, index int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := revealedKey(sessionID, index)
+	if s.revealed[key] {
+		return false, nil
+	}
+	s.revealed[key] = true
+	return true, nil
+}
+
+func (s *MemoryStore) ScheduleReveal(_ context.Context, sessionID string, index int, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deadlines[Deadline{SessionID: sessionID, Index: index}] = at
+	return nil
+}
+
+func (s *MemoryStore) DueReveals(_ context.Context, now time.Time) ([]Deadline, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var due []Deadline
+	for d, at := range s.deadlines {
+		if !at.After(now) {
+			due = append(due, d)
+		}
+	}
+	return due, nil
+}
+
+func (s *MemoryStore) UnscheduleReveal(_ context.Context, sessionID string, index int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.deadlines, Deadline{SessionID: sessionID, Index: index})
+	return nil
+}
+
+func (s *MemoryStore) CreateTicket(_ context.Context, token string, ticket Ticket) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tickets[token] = memoryTicket{ticket: ticket, expiresAt: time.Now().Add(ticketTTL)}
+	return nil
+}
+
+func (s *MemoryStore) ConsumeTicket(_ context.Context, token string) (*Ticket, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.tickets[token]
+	delete(s.tickets, token)
+	if !ok || time.Now().After(stored.expiresAt) {
+		return nil, ErrTicketInvalid
+	}
+	return &stored.ticket, nil
+}
+
+func (s *MemoryStore) Publish(_ context.Context, sessionID string, payload []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.subscribers[sessionID] {
+		// subscriber yang lambat dilewati agar tidak menahan sesi
+		select {
+		case ch <- payload:
+		default:
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) Subscribe(_ context.Context, sessionID string) (<-chan []byte, func(), error) {
+	ch := make(chan []byte, 16)
+	s.mu.Lock()
+	if s.subscribers[sessionID] == nil {
+		s.subscribers[sessionID] = make(map[chan []byte]struct{})
+	}
+	s.subscribers[sessionID][ch] = struct{}{}
+	s.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			s.mu.Lock()
+			delete(s.subscribers[sessionID], ch)
+			s.mu.Unlock()
+			close(ch)
+		})
+	}
+	return ch, cancel, nil
+}
@@ -0,0 +1,173 @@
+package logbook
+
+import (
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/material"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxAttachmentsPerEntry membatasi jumlah lampiran per entry
+	maxAttachmentsPerEntry = 5
+	attachmentURLTTL       = 15 * time.Minute
+)
+
+var (
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	ErrTooManyAttachments = fmt.Errorf("an entry can have at most %d attachments", maxAttachmentsPerEntry)
+)
+
+// defaultAttachmentTypes: foto dan dokumen yang umum dipakai sebagai bukti kegiatan
+var defaultAttachmentTypes = []string{
+	"image/jpeg",
+	"image/png",
+	"image/gif",
+	"image/webp",
+	"application/pdf",
+	"text/plain",
+	"application/msword",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	"application/vnd.ms-excel",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// AttachmentPolicyFromEnv membaca LOGBOOK_ATTACHMENT_MAX_MB dan LOGBOOK_ATTACHMENT_ALLOWED_MIME
+func AttachmentPolicyFromEnv() material.UploadPolicy {
+	return material.LoadUploadPolicy("LOGBOOK_ATTACHMENT_MAX_MB", "LOGBOOK_ATTACHMENT_ALLOWED_MIME", 10, defaultAttachmentTypes)
+}
+
+func (s *logBookService) findAttachment(ctx context.Context, entryID, attachmentID uuid.UUID) (*entities.LogBookAttachment, error) {
+	attachment, err := s.repo.GetAttachmentByID(ctx, attachmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAttachmentNotFound
+		}
+		return nil, err
+	}
+	if attachment.EntryID != entryID {
+		return nil, ErrAttachmentNotFound
+	}
+	return attachment, nil
+}
+
+func (s *logBookService) AddAttachment(ctx context.Context, studentID, courseID, logBookID, entryID uuid.UUID, upload material.FileUpload) (*entities.LogBookAttachment, error) {
+	logBook, err := s.findDraft(ctx, studentID, courseID, logBookID)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := s.findEntry(ctx, logBook.ID, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if upload.Reader == nil || upload.Size <= 0 {
+		return nil, material.ErrFileRequired
+	}
+	if upload.Size > s.attachmentPolicy.MaxBytes {
+		return nil, material.ErrFileTooLarge
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(upload.Reader, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	head = head[:n]
+
+	contentType, err := s.attachmentPolicy.DetectContentType(upload.FileName, head)
+	if err != nil {
+		return nil, err
+	}
+
+	fileName := material.SanitizeFileName(upload.FileName)
+	attachmentID := uuid.New()
+	key := fmt.Sprintf("logbooks/%s/%s/%s/%s/%s", courseID, logBook.ID, entry.ID, attachmentID, fileName)
+
+	body := io.MultiReader(bytes.NewReader(head), upload.Reader)
+	if err := s.store.Put(ctx, key, body, upload.Size, contentType); err != nil {
+		return nil, fmt.Errorf("failed to store file: %v", err)
+	}
+
+	attachment := &entities.LogBookAttachment{
+		ID:           attachmentID,
+		EntryID:      entry.ID,
+		FileName:     fileName,
+		FileKey:      key,
+		ContentType:  contentType,
+		Size:         upload.Size,
+		UploadedByID: studentID,
+	}
+	// repository menolak bila logbook sudah disubmit atau entry sudah penuh; file dibuang lagi
+	if err := s.repo.CreateAttachment(ctx, logBook.ID, attachment); err != nil {
+		_ = s.store.Delete(ctx, key)
+		return nil, err
+	}
+	return attachment, nil
+}
+
+func (s *logBookService) DeleteAttachment(ctx context.Context, studentID, courseID, logBookID, entryID, attachmentID uuid.UUID) error {
+	logBook, err := s.findDraft(ctx, studentID, courseID, logBookID)
+	if err != nil {
+		return err
+	}
+	entry, err := s.findEntry(ctx, logBook.ID, entryID)
+	if err != nil {
+		return err
+	}
+	attachment, err := s.findAttachment(ctx, entry.ID, attachmentID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteAttachment(ctx, logBook.ID, attachment.ID); err != nil {
+		return err
+	}
+	if err := s.store.Delete(ctx, attachment.FileKey); err != nil {
+		return fmt.Errorf("attachment deleted but failed to remove file: %v", err)
+	}
+	return nil
+}
+
+func (s *logBookService) GetAttachmentURL(ctx context.Context, userID uuid.UUID, courseRole string, courseID, logBookID, entryID, attachmentID uuid.UUID) (string, time.Time, error) {
+	logBook, err := s.GetLogBook(ctx, userID, courseRole, courseID, logBookID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	entry, err := s.findEntry(ctx, logBook.ID, entryID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	attachment, err := s.findAttachment(ctx, entry.ID, attachmentID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	expiresAt := time.Now().Add(attachmentURLTTL)
+	url, err := s.store.SignedURL(ctx, attachment.FileKey, attachmentURLTTL, attachment.FileName)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return url, expiresAt, nil
+}
+
+// removeAttachmentFiles menghapus file lampiran entry yang barisnya sudah terhapus bersama entry
+func (s *logBookService) removeAttachmentFiles(ctx context.Context, attachments []entities.LogBookAttachment) error {
+	var firstErr error
+	for _, a := range attachments {
+		if err := s.store.Delete(ctx, a.FileKey); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		return fmt.Errorf("entry deleted but failed to remove attachment files: %v", firstErr)
+	}
+	return nil
+}
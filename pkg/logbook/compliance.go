@@ -0,0 +1,219 @@
+package logbook
+
+import (
+	"api-shiners/pkg/entities"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxReportDays membatasi rentang tanggal laporan kepatuhan
+const maxReportDays = 366
+
+var (
+	ErrInvalidRange = errors.New("to must not be before from")
+	ErrRangeTooLong = fmt.Errorf("report range must not exceed %d days", maxReportDays)
+)
+
+// ComplianceOptions: rentang tanggal inklusif. Hari setelah hari ini tidak dihitung
+// karena belum bisa dianggap terlewat.
+type ComplianceOptions struct {
+	From            time.Time
+	To              time.Time
+	ExcludeWeekends bool
+}
+
+// PeriodCompliance adalah kepatuhan satu logbook, dihitung pada irisan periode dengan rentang laporan
+type PeriodCompliance struct {
+	LogBookID    uuid.UUID           `json:"logbook_id"`
+	PeriodStart  string              `json:"period_start"`
+	PeriodEnd    string              `json:"period_end"`
+	Status       entities.StatusType `json:"status"`
+	ExpectedDays int                 `json:"expected_days"`
+	FilledDays   int                 `json:"filled_days"`
+	MissingDates []string            `json:"missing_dates"`
+}
+
+// StudentCompliance: CompliancePercent nil bila student tidak punya hari yang wajib diisi
+// (misal belum punya logbook di rentang laporan)
+type StudentCompliance struct {
+	StudentID         uuid.UUID          `json:"student_id"`
+	Name              string             `json:"name"`
+	Email             string             `json:"email"`
+	LogBooks          int                `json:"logbooks"`
+	ExpectedDays      int                `json:"expected_days"`
+	FilledDays        int                `json:"filled_days"`
+	MissingDays       int                `json:"missing_days"`
+	CompliancePercent *float64           `json:"compliance_percent"`
+	MissingDates      []string           `json:"missing_dates"`
+	Periods           []PeriodCompliance `json:"periods"`
+}
+
+type ComplianceTotals struct {
+	Students               int      `json:"students"`
+	StudentsWithoutLogBook int      `json:"students_without_logbook"`
+	StudentsFullyCompliant int      `json:"students_fully_compliant"`
+	ExpectedDays           int      `json:"expected_days"`
+	FilledDays             int      `json:"filled_days"`
+	MissingDays            int      `json:"missing_days"`
+	CompliancePercent      *float64 `json:"compliance_percent"`
+}
+
+// ComplianceReport berisi tanggal tanpa entry di setiap periode logbook untuk semua student course
+type ComplianceReport struct {
+	CourseID        uuid.UUID           `json:"course_id"`
+	From            string              `json:"from"`
+	To              string              `json:"to"`
+	ExcludeWeekends bool                `json:"exclude_weekends"`
+	GeneratedAt     time.Time           `json:"generated_at"`
+	Totals          ComplianceTotals    `json:"totals"`
+	Students        []StudentCompliance `json:"students"`
+}
+
+// percent dibulatkan dua desimal, nil bila tidak ada hari yang wajib diisi
+func percent(filled, expected int) *float64 {
+	if expected == 0 {
+		return nil
+	}
+	p := math.Round(float64(filled)/float64(expected)*10000) / 100
+	return &p
+}
+
+// periodCompliance menghitung hari wajib dan hari tanpa entry pada logbook di antara from dan until
+func periodCompliance(lb *entities.LogBook, from, until time.Time, excludeWeekends bool) PeriodCompliance {
+	result := PeriodCompliance{
+		LogBookID:    lb.ID,
+		PeriodStart:  DateOnly(lb.PeriodStart).Format(dateLayout),
+		PeriodEnd:    DateOnly(lb.PeriodEnd).Format(dateLayout),
+		Status:       lb.Status,
+		MissingDates: []string{},
+	}
+
+	filled := make(map[time.Time]bool, len(lb.Entries))
+	for _, e := range lb.Entries {
+		filled[DateOnly(e.EntryDate)] = true
+	}
+
+	start, end := DateOnly(lb.PeriodStart), DateOnly(lb.PeriodEnd)
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(until) {
+		end = until
+	}
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if excludeWeekends && (d.Weekday() == time.Saturday || d.Weekday() == time.Sunday) {
+			continue
+		}
+		result.ExpectedDays++
+		if filled[d] {
+			result.FilledDays++
+		} else {
+			result.MissingDates = append(result.MissingDates, d.Format(dateLayout))
+		}
+	}
+	return result
+}
+
+// BuildComplianceReport menyusun laporan dari daftar student dan logbook course. Logbook
+// milik user yang tidak lagi terdaftar sebagai student diabaikan.
+func BuildComplianceReport(courseID uuid.UUID, students []entities.User, logBooks []entities.LogBook, opts ComplianceOptions, now time.Time) *ComplianceReport {
+	from, to := DateOnly(opts.From), DateOnly(opts.To)
+	until := to
+	if today := DateOnly(now); today.Before(until) {
+		until = today
+	}
+
+	byStudent := make(map[uuid.UUID][]*entities.LogBook, len(students))
+	for i := range logBooks {
+		byStudent[logBooks[i].StudentID] = append(byStudent[logBooks[i].StudentID], &logBooks[i])
+	}
+
+	report := &ComplianceReport{
+		CourseID:        courseID,
+		From:            from.Format(dateLayout),
+		To:              to.Format(dateLayout),
+		ExcludeWeekends: opts.ExcludeWeekends,
+		GeneratedAt:     now,
+		Students:        make([]StudentCompliance, 0, len(students)),
+	}
+
+	for _, student := range students {
+		sc := StudentCompliance{
+			StudentID:    student.ID,
+			Name:         student.Name,
+			Email:        student.Email,
+			MissingDates: []string{},
+			Periods:      []PeriodCompliance{},
+		}
+		owned := byStudent[student.ID]
+		sort.Slice(owned, func(i, j int) bool { return owned[i].PeriodStart.Before(owned[j].PeriodStart) })
+
+		for _, lb := range owned {
+			pc := periodCompliance(lb, from, until, opts.ExcludeWeekends)
+			sc.LogBooks++
+			sc.ExpectedDays += pc.ExpectedDays
+			sc.FilledDays += pc.FilledDays
+			sc.MissingDates = append(sc.MissingDates, pc.MissingDates...)
+			sc.Periods = append(sc.Periods, pc)
+		}
+		sc.MissingDays = len(sc.MissingDates)
+		sc.CompliancePercent = percent(sc.FilledDays, sc.ExpectedDays)
+
+		report.Totals.Students++
+		if sc.LogBooks == 0 {
+			report.Totals.StudentsWithoutLogBook++
+		} else if sc.ExpectedDays > 0 && sc.MissingDays == 0 {
+			report.Totals.StudentsFullyCompliant++
+		}
+		report.Totals.ExpectedDays += sc.ExpectedDays
+		report.Totals.FilledDays += sc.FilledDays
+		report.Totals.MissingDays += sc.MissingDays
+		report.Students = append(report.Students, sc)
+	}
+	report.Totals.CompliancePercent = percent(report.Totals.FilledDays, report.Totals.ExpectedDays)
+
+	sort.SliceStable(report.Students, func(i, j int) bool {
+		return strings.ToLower(report.Students[i].Name) < strings.ToLower(report.Students[j].Name)
+	})
+	return report
+}
+
+func (s *logBookService) GetComplianceReport(ctx context.Context, courseRole string, courseID uuid.UUID, opts ComplianceOptions) (*ComplianceReport, error) {
+	if !CanReview(courseRole) {
+		return nil, ErrForbidden
+	}
+	from, to := DateOnly(opts.From), DateOnly(opts.To)
+	if to.Before(from) {
+		return nil, ErrInvalidRange
+	}
+	if to.Sub(from) >= maxReportDays*24*time.Hour {
+		return nil, ErrRangeTooLong
+	}
+
+	enrollments, err := s.enrollmentRepo.GetEnrollmentsByCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	students := make([]entities.User, 0, len(enrollments))
+	for _, e := range enrollments {
+		if e.RoleInCourse == entities.CourseRoleStudent {
+			student := e.User
+			student.ID = e.UserID
+			students = append(students, student)
+		}
+	}
+
+	logBooks, err := s.repo.GetLogBooksInRange(ctx, courseID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildComplianceReport(courseID, students, logBooks, opts, time.Now()), nil
+}
@@ -0,0 +1,47 @@
+package logbook
+
+import (
+	"api-shiners/pkg/utils"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteComplianceCSV menulis satu baris per student. Tanggal tanpa entry ditulis dalam
+// satu kolom dengan format "2025-01-07; 2025-01-09".
+func WriteComplianceCSV(w io.Writer, report *ComplianceReport) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{"student_id", "name", "email", "logbooks", "expected_days", "filled_days", "missing_days", "compliance_percent", "missing_dates"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, s := range report.Students {
+		record := []string{
+			s.StudentID.String(),
+			utils.SafeCSVCell(s.Name),
+			utils.SafeCSVCell(s.Email),
+			strconv.Itoa(s.LogBooks),
+			strconv.Itoa(s.ExpectedDays),
+			strconv.Itoa(s.FilledDays),
+			strconv.Itoa(s.MissingDays),
+			formatPercent(s.CompliancePercent),
+			strings.Join(s.MissingDates, "; "),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatPercent(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
@@ -0,0 +1,104 @@
+package logbook
+
+import (
+	"api-shiners/pkg/document"
+	"api-shiners/pkg/entities"
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const printTimeLayout = "2006-01-02 15:04"
+
+func (s *logBookService) ExportPDF(ctx context.Context, userID uuid.UUID, courseRole string, courseID, logBookID uuid.UUID, header document.Header) (*document.Document, string, error) {
+	logBook, err := s.GetLogBook(ctx, userID, courseRole, courseID, logBookID)
+	if err != nil {
+		return nil, "", err
+	}
+	comments, err := s.repo.GetComments(ctx, logBook.ID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	doc := RenderLogBookPDF(header, logBook, comments, time.Now())
+	if err := doc.Err(); err != nil {
+		return nil, "", err
+	}
+	return doc, PDFFileName(logBook), nil
+}
+
+// PDFFileName: logbook-<nama student>-<awal periode>.pdf
+func PDFFileName(lb *entities.LogBook) string {
+	return document.FileName("pdf", "logbook", lb.Student.Name, DateOnly(lb.PeriodStart).Format(dateLayout))
+}
+
+func formatPrintTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format(printTimeLayout)
+}
+
+// RenderLogBookPDF menyusun logbook beserta entry, status dan komentar teacher.
+// Komentar untuk entry tertentu ditandai dengan tanggal entry-nya; lampiran hanya dicantumkan namanya.
+func RenderLogBookPDF(header document.Header, lb *entities.LogBook, comments []entities.LogBookComment, now time.Time) *document.Document {
+	doc := document.New(header, "Student LogBook", now)
+
+	course := lb.Course.Title
+	if lb.Course.Code != "" {
+		course = lb.Course.Code + " - " + course
+	}
+	doc.Fields(
+		document.Field{Label: "Student", Value: lb.Student.Name},
+		document.Field{Label: "Email", Value: lb.Student.Email},
+		document.Field{Label: "Course", Value: course},
+		document.Field{Label: "Period", Value: DateOnly(lb.PeriodStart).Format(dateLayout) + " to " + DateOnly(lb.PeriodEnd).Format(dateLayout)},
+		document.Field{Label: "Status", Value: string(lb.Status)},
+		document.Field{Label: "Submitted at", Value: formatPrintTime(lb.SubmittedAt)},
+		document.Field{Label: "Locked at", Value: formatPrintTime(lb.LockedAt)},
+	)
+
+	entryDates := make(map[uuid.UUID]string, len(lb.Entries))
+	doc.Heading("Entries")
+	if len(lb.Entries) == 0 {
+		doc.Paragraph("No entries.")
+	} else {
+		rows := make([][]string, 0, len(lb.Entries))
+		for _, e := range lb.Entries {
+			date := DateOnly(e.EntryDate).Format(dateLayout)
+			entryDates[e.ID] = date
+			activity := e.Content
+			if len(e.Attachments) > 0 {
+				names := make([]string, 0, len(e.Attachments))
+				for _, att := range e.Attachments {
+					names = append(names, att.FileName)
+				}
+				activity += "\nAttachments: " + strings.Join(names, ", ")
+			}
+			rows = append(rows, []string{date, DateOnly(e.EntryDate).Weekday().String(), activity})
+		}
+		doc.Table([]document.Column{{Title: "Date", Width: 24}, {Title: "Day", Width: 22}, {Title: "Activity"}}, rows)
+	}
+
+	doc.Heading("Teacher Comments")
+	if len(comments) == 0 {
+		doc.Paragraph("No comments.")
+	} else {
+		rows := make([][]string, 0, len(comments))
+		for _, c := range comments {
+			entry := "-"
+			if c.EntryID != nil {
+				// entry yang sudah dihapus tidak lagi punya tanggal
+				if date, ok := entryDates[*c.EntryID]; ok {
+					entry = date
+				}
+			}
+			rows = append(rows, []string{c.CreatedAt.Format(printTimeLayout), c.Author.Name, entry, c.Body})
+		}
+		doc.Table([]document.Column{{Title: "Time", Width: 30}, {Title: "Teacher", Width: 35}, {Title: "Entry", Width: 24}, {Title: "Comment"}}, rows)
+	}
+
+	return doc
+}
@@ -0,0 +1,293 @@
+package logbook
+
+import (
+	"api-shiners/pkg/entities"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ListFilter: field kosong berarti tidak difilter
+type ListFilter struct {
+	StudentID *uuid.UUID
+	Status    entities.StatusType
+}
+
+type LogBookRepository interface {
+	CreateLogBook(ctx context.Context, logBook *entities.LogBook) error
+	// GetLogBookByID memuat logbook beserta course, student dan entry terurut tanggal (dengan lampirannya)
+	GetLogBookByID(ctx context.Context, id uuid.UUID) (*entities.LogBook, error)
+	GetLogBooksByCourse(ctx context.Context, courseID uuid.UUID, filter ListFilter) ([]entities.LogBook, error)
+	// GetLogBooksInRange memuat logbook course (beserta entry) yang periodenya beririsan dengan rentang
+	GetLogBooksInRange(ctx context.Context, courseID uuid.UUID, from, to time.Time) ([]entities.LogBook, error)
+	// HasOverlappingPeriod mengecek logbook lain milik student di course yang periodenya beririsan
+	HasOverlappingPeriod(ctx context.Context, courseID, studentID uuid.UUID, start, end time.Time) (bool, error)
+	// UpdateStatus hanya berhasil bila status di database masih from, false bila sudah berubah.
+	// Riwayat review disimpan dalam transaksi yang sama.
+	UpdateStatus(ctx context.Context, logBook *entities.LogBook, from entities.StatusType, review *entities.LogBookReview) (bool, error)
+	GetReviews(ctx context.Context, logBookID uuid.UUID) ([]entities.LogBookReview, error)
+
+	CreateComment(ctx context.Context, comment *entities.LogBookComment) error
+	GetComments(ctx context.Context, logBookID uuid.UUID) ([]entities.LogBookComment, error)
+
+	// CreateEntry, UpdateEntry dan DeleteEntry mengunci baris logbook selama menulis dan
+	// gagal dengan ErrNotEditable bila logbook sudah tidak DRAFT
+	CreateEntry(ctx context.Context, entry *entities.LogBookEntry) error
+	// GetEntryByID memuat entry beserta lampirannya
+	GetEntryByID(ctx context.Context, id uuid.UUID) (*entities.LogBookEntry, error)
+	UpdateEntry(ctx context.Context, entry *entities.LogBookEntry) error
+	// DeleteEntry menghapus entry beserta baris lampirannya dalam satu transaksi
+	DeleteEntry(ctx context.Context, logBookID, id uuid.UUID) error
+	CountEntries(ctx context.Context, logBookID uuid.UUID) (int64, error)
+	// EntryDateTaken mengecek entry lain (selain excludeID) pada tanggal yang sama
+	EntryDateTaken(ctx context.Context, logBookID uuid.UUID, date time.Time, excludeID uuid.UUID) (bool, error)
+
+	// CreateAttachment dan DeleteAttachment juga mengunci logbook pemilik entry (lihat CreateEntry).
+	// Batas jumlah lampiran per entry dicek CreateAttachment di dalam kunci yang sama.
+	CreateAttachment(ctx context.Context, logBookID uuid.UUID, attachment *entities.LogBookAttachment) error
+	GetAttachmentByID(ctx context.Context, id uuid.UUID) (*entities.LogBookAttachment, error)
+	GetAttachmentsByEntry(ctx context.Context, entryID uuid.UUID) ([]entities.LogBookAttachment, error)
+	DeleteAttachment(ctx context.Context, logBookID, id uuid.UUID) error
+}
+
+type logBookRepository struct {
+	db *gorm.DB
+}
+
+func NewLogBookRepository(db *gorm.DB) LogBookRepository {
+	return &logBookRepository{db}
+}
+
+func orderByEntryDate(db *gorm.DB) *gorm.DB {
+	return db.Order("entry_date ASC, created_at ASC")
+}
+
+func orderByCreatedAt(db *gorm.DB) *gorm.DB {
+	return db.Order("created_at ASC")
+}
+
+func (r *logBookRepository) CreateLogBook(ctx context.Context, logBook *entities.LogBook) error {
+	return r.db.WithContext(ctx).Omit("Course", "Student", "Entries").Create(logBook).Error
+}
+
+func (r *logBookRepository) GetLogBookByID(ctx context.Context, id uuid.UUID) (*entities.LogBook, error) {
+	var logBook entities.LogBook
+	err := r.db.WithContext(ctx).
+		Preload("Course").
+		Preload("Student").
+		Preload("Entries", orderByEntryDate).
+		Preload("Entries.Attachments", orderByCreatedAt).
+		First(&logBook, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &logBook, nil
+}
+
+func (r *logBookRepository) GetLogBooksByCourse(ctx context.Context, courseID uuid.UUID, filter ListFilter) ([]entities.LogBook, error) {
+	query := r.db.WithContext(ctx).Preload("Student").Where("course_id = ?", courseID)
+	if filter.StudentID != nil {
+		query = query.Where("student_id_id = ?", *filter.StudentID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	var logBooks []entities.LogBook
+	if err := query.Order("period_start DESC, created_at DESC").Find(&logBooks).Error; err != nil {
+		return nil, err
+	}
+	return logBooks, nil
+}
+
+func (r *logBookRepository) GetLogBooksInRange(ctx context.Context, courseID uuid.UUID, from, to time.Time) ([]entities.LogBook, error) {
+	var logBooks []entities.LogBook
+	err := r.db.WithContext(ctx).
+		Preload("Entries", orderByEntryDate).
+		Where("course_id = ?", courseID).
+		Where("period_start <= ?::date AND period_end >= ?::date", to.Format(dateLayout), from.Format(dateLayout)).
+		Order("period_start ASC").
+		Find(&logBooks).Error
+	return logBooks, err
+}
+
+func (r *logBookRepository) HasOverlappingPeriod(ctx context.Context, courseID, studentID uuid.UUID, start, end time.Time) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entities.LogBook{}).
+		Where("course_id = ? AND student_id_id = ?", courseID, studentID).
+		Where("period_start <= ? AND period_end >= ?", end, start).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *logBookRepository) UpdateStatus(ctx context.Context, logBook *entities.LogBook, from entities.StatusType, review *entities.LogBookReview) (bool, error) {
+	updated := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.LogBook{}).
+			Where("id = ? AND status = ?", logBook.ID, from).
+			Updates(map[string]interface{}{
+				"status":      logBook.Status,
+				"submited_at": logBook.SubmittedAt,
+				"locked_at":   logBook.LockedAt,
+				"updated_at":  gorm.Expr("now()"),
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		updated = true
+		return tx.Omit("LogBook", "Actor").Create(review).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return updated, nil
+}
+
+func (r *logBookRepository) GetReviews(ctx context.Context, logBookID uuid.UUID) ([]entities.LogBookReview, error) {
+	var reviews []entities.LogBookReview
+	err := r.db.WithContext(ctx).
+		Preload("Actor").
+		Where("log_book_id = ?", logBookID).
+		Order("created_at ASC").
+		Find(&reviews).Error
+	return reviews, err
+}
+
+func (r *logBookRepository) CreateComment(ctx context.Context, comment *entities.LogBookComment) error {
+	return r.db.WithContext(ctx).Omit("LogBook", "Entry", "Author").Create(comment).Error
+}
+
+func (r *logBookRepository) GetComments(ctx context.Context, logBookID uuid.UUID) ([]entities.LogBookComment, error) {
+	var comments []entities.LogBookComment
+	err := r.db.WithContext(ctx).
+		Preload("Author").
+		Where("log_book_id = ?", logBookID).
+		Order("created_at ASC").
+		Find(&comments).Error
+	return comments, err
+}
+
+// lockDraft mengunci baris logbook sampai transaksi selesai dan memastikan masih DRAFT,
+// sehingga submit tidak bisa menyela di antara pengecekan status dan penulisan isi logbook
+func lockDraft(tx *gorm.DB, logBookID uuid.UUID) error {
+	var logBook entities.LogBook
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "status").
+		First(&logBook, "id = ?", logBookID).Error
+	if err != nil {
+		return err
+	}
+	if logBook.Status != entities.StatusDraft {
+		return ErrNotEditable
+	}
+	return nil
+}
+
+func (r *logBookRepository) CreateEntry(ctx context.Context, entry *entities.LogBookEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockDraft(tx, entry.LogBookID); err != nil {
+			return err
+		}
+		return tx.Omit("LogBook").Create(entry).Error
+	})
+}
+
+func (r *logBookRepository) GetEntryByID(ctx context.Context, id uuid.UUID) (*entities.LogBookEntry, error) {
+	var entry entities.LogBookEntry
+	if err := r.db.WithContext(ctx).Preload("Attachments", orderByCreatedAt).First(&entry, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *logBookRepository) UpdateEntry(ctx context.Context, entry *entities.LogBookEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockDraft(tx, entry.LogBookID); err != nil {
+			return err
+		}
+		return tx.Model(&entities.LogBookEntry{}).
+			Where("id = ?", entry.ID).
+			Updates(map[string]interface{}{
+				"entry_date": entry.EntryDate,
+				"content":    entry.Content,
+				"updated_at": gorm.Expr("now()"),
+			}).Error
+	})
+}
+
+func (r *logBookRepository) DeleteEntry(ctx context.Context, logBookID, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockDraft(tx, logBookID); err != nil {
+			return err
+		}
+		if err := tx.Where("entry_id = ?", id).Delete(&entities.LogBookAttachment{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entities.LogBookEntry{}, "id = ?", id).Error
+	})
+}
+
+func (r *logBookRepository) CountEntries(ctx context.Context, logBookID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entities.LogBookEntry{}).
+		Where("log_book_id = ?", logBookID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *logBookRepository) EntryDateTaken(ctx context.Context, logBookID uuid.UUID, date time.Time, excludeID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entities.LogBookEntry{}).
+		Where("log_book_id = ? AND entry_date = ? AND id <> ?", logBookID, date, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *logBookRepository) CreateAttachment(ctx context.Context, logBookID uuid.UUID, attachment *entities.LogBookAttachment) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockDraft(tx, logBookID); err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&entities.LogBookAttachment{}).
+			Where("entry_id = ?", attachment.EntryID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= maxAttachmentsPerEntry {
+			return ErrTooManyAttachments
+		}
+
+		return tx.Omit("Entry", "UploadedBy").Create(attachment).Error
+	})
+}
+
+func (r *logBookRepository) GetAttachmentByID(ctx context.Context, id uuid.UUID) (*entities.LogBookAttachment, error) {
+	var attachment entities.LogBookAttachment
+	if err := r.db.WithContext(ctx).First(&attachment, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (r *logBookRepository) GetAttachmentsByEntry(ctx context.Context, entryID uuid.UUID) ([]entities.LogBookAttachment, error) {
+	var attachments []entities.LogBookAttachment
+	err := r.db.WithContext(ctx).
+		Where("entry_id = ?", entryID).
+		Order("created_at ASC").
+		Find(&attachments).Error
+	return attachments, err
+}
+
+func (r *logBookRepository) DeleteAttachment(ctx context.Context, logBookID, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockDraft(tx, logBookID); err != nil {
+			return err
+		}
+		return tx.Delete(&entities.LogBookAttachment{}, "id = ?", id).Error
+	})
+}
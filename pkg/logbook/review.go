@@ -0,0 +1,117 @@
+package logbook
+
+import (
+	"api-shiners/pkg/entities"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// maxCommentBody membatasi panjang komentar dan alasan revisi (dalam karakter)
+const maxCommentBody = 5000
+
+var (
+	ErrReasonRequired  = errors.New("reason is required when requesting a revision")
+	ErrEmptyComment    = errors.New("comment body is required")
+	ErrCommentTooLong  = fmt.Errorf("comment must not exceed %d characters", maxCommentBody)
+	ErrNotSubmittedYet = errors.New("logbook has not been submitted for review")
+)
+
+// CommentInput: EntryID nil berarti komentar untuk logbook secara keseluruhan
+type CommentInput struct {
+	EntryID *uuid.UUID
+	Body    string
+}
+
+// review memindahkan status logbook atas nama teacher course atau admin
+func (s *logBookService) review(ctx context.Context, userID uuid.UUID, courseRole string, courseID, logBookID uuid.UUID, to entities.StatusType, action entities.LogBookAction, reason string) (*entities.LogBook, error) {
+	if !CanReview(courseRole) {
+		return nil, ErrForbidden
+	}
+	if len([]rune(reason)) > maxCommentBody {
+		return nil, ErrCommentTooLong
+	}
+	logBook, err := s.findLogBook(ctx, courseID, logBookID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.changeStatus(ctx, logBook, to, userID, action, reason); err != nil {
+		return nil, err
+	}
+	return logBook, nil
+}
+
+func (s *logBookService) Approve(ctx context.Context, userID uuid.UUID, courseRole string, courseID, logBookID uuid.UUID, note string) (*entities.LogBook, error) {
+	return s.review(ctx, userID, courseRole, courseID, logBookID, entities.StatusLocked, entities.LogBookActionApproved, strings.TrimSpace(note))
+}
+
+func (s *logBookService) RequestRevision(ctx context.Context, userID uuid.UUID, courseRole string, courseID, logBookID uuid.UUID, reason string) (*entities.LogBook, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrReasonRequired
+	}
+	return s.review(ctx, userID, courseRole, courseID, logBookID, entities.StatusDraft, entities.LogBookActionRevisionRequested, reason)
+}
+
+func (s *logBookService) AddComment(ctx context.Context, userID uuid.UUID, courseRole string, courseID, logBookID uuid.UUID, input CommentInput) (*entities.LogBookComment, error) {
+	if !CanReview(courseRole) {
+		return nil, ErrForbidden
+	}
+	body := strings.TrimSpace(input.Body)
+	if body == "" {
+		return nil, ErrEmptyComment
+	}
+	if len([]rune(body)) > maxCommentBody {
+		return nil, ErrCommentTooLong
+	}
+
+	logBook, err := s.findLogBook(ctx, courseID, logBookID)
+	if err != nil {
+		return nil, err
+	}
+	// komentar tetap boleh pada DRAFT hasil permintaan revisi, tapi tidak pada DRAFT yang belum pernah dikirim
+	if logBook.Status == entities.StatusDraft {
+		reviews, err := s.repo.GetReviews(ctx, logBook.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(reviews) == 0 {
+			return nil, ErrNotSubmittedYet
+		}
+	}
+	if input.EntryID != nil {
+		if _, err := s.findEntry(ctx, logBook.ID, *input.EntryID); err != nil {
+			return nil, err
+		}
+	}
+
+	comment := &entities.LogBookComment{
+		LogBookID: logBook.ID,
+		EntryID:   input.EntryID,
+		AuthorID:  userID,
+		Body:      body,
+	}
+	if err := s.repo.CreateComment(ctx, comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (s *logBookService) GetComments(ctx context.Context, userID uuid.UUID, courseRole string, courseID, logBookID uuid.UUID) ([]entities.LogBookComment, error) {
+	logBook, err := s.GetLogBook(ctx, userID, courseRole, courseID, logBookID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetComments(ctx, logBook.ID)
+}
+
+func (s *logBookService) GetHistory(ctx context.Context, userID uuid.UUID, courseRole string, courseID, logBookID uuid.UUID) ([]entities.LogBookReview, error) {
+	logBook, err := s.GetLogBook(ctx, userID, courseRole, courseID, logBookID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetReviews(ctx, logBook.ID)
+}
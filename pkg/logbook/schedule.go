@@ -0,0 +1,303 @@
+package logbook
+
+import (
+	"api-shiners/pkg/entities"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxGraceDays membatasi masa tenggang sebelum logbook ditutup otomatis
+	maxGraceDays = 30
+	// autoCloseBatch membatasi jumlah logbook yang ditutup per course per putaran job
+	autoCloseBatch = 500
+)
+
+var (
+	ErrScheduleNotFound    = errors.New("logbook schedule not found")
+	ErrInvalidFrequency    = errors.New("frequency must be WEEKLY or MONTHLY")
+	ErrInvalidWeekStart    = errors.New("week_start must be between 0 (Sunday) and 6 (Saturday)")
+	ErrInvalidCloseAction  = errors.New("close_action must be AUTO_SUBMIT or AUTO_LOCK")
+	ErrInvalidGraceDays    = fmt.Errorf("grace_days must be between 0 and %d", maxGraceDays)
+	ErrInvalidScheduleDate = errors.New("end_date must not be before start_date")
+)
+
+// ScheduleInput: field nil memakai nilai default (WEEKLY mulai Senin, AUTO_SUBMIT, tanpa tenggang, aktif)
+type ScheduleInput struct {
+	Frequency   *string
+	WeekStart   *int
+	StartDate   time.Time
+	EndDate     *time.Time
+	CloseAction *string
+	GraceDays   *int
+	IsActive    *bool
+}
+
+type ScheduleService interface {
+	GetSchedule(ctx context.Context, courseRole string, courseID uuid.UUID) (*entities.LogBookSchedule, error)
+	// SaveSchedule membuat atau mengganti jadwal course lalu langsung membuat logbook periode berjalan.
+	// created adalah jumlah logbook baru.
+	SaveSchedule(ctx context.Context, userID uuid.UUID, courseRole string, courseID uuid.UUID, input ScheduleInput) (schedule *entities.LogBookSchedule, created int, err error)
+	// DeleteSchedule menghentikan jadwal; logbook yang sudah dibuat tetap ada
+	DeleteSchedule(ctx context.Context, courseRole string, courseID uuid.UUID) error
+
+	// GeneratePeriods membuat logbook periode berjalan untuk semua jadwal aktif.
+	// Dipanggil worker terjadwal, aman diulang.
+	GeneratePeriods(ctx context.Context, now time.Time) (int, error)
+	// AutoClose menutup logbook yang melewati PeriodEnd + GraceDays sesuai CloseAction jadwal.
+	// Dipanggil worker terjadwal, aman diulang.
+	AutoClose(ctx context.Context, now time.Time) (int, error)
+}
+
+type scheduleService struct {
+	repo        ScheduleRepository
+	logBookRepo LogBookRepository
+}
+
+func NewScheduleService(repo ScheduleRepository, logBookRepo LogBookRepository) ScheduleService {
+	return &scheduleService{
+		repo:        repo,
+		logBookRepo: logBookRepo,
+	}
+}
+
+// PeriodFor menghitung periode jadwal yang memuat tanggal date, dipotong oleh
+// StartDate/EndDate jadwal. ok false bila date di luar rentang jadwal.
+func PeriodFor(schedule *entities.LogBookSchedule, date time.Time) (start, end time.Time, ok bool) {
+	date = DateOnly(date)
+	first := DateOnly(schedule.StartDate)
+	if date.Before(first) {
+		return time.Time{}, time.Time{}, false
+	}
+	var last time.Time
+	if schedule.EndDate != nil {
+		last = DateOnly(*schedule.EndDate)
+		if date.After(last) {
+			return time.Time{}, time.Time{}, false
+		}
+	}
+
+	switch schedule.Frequency {
+	case entities.LogBookMonthly:
+		start = time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+		end = start.AddDate(0, 1, -1)
+	default:
+		offset := (int(date.Weekday()) - int(schedule.WeekStart) + 7) % 7
+		start = date.AddDate(0, 0, -offset)
+		end = start.AddDate(0, 0, 6)
+	}
+
+	if start.Before(first) {
+		start = first
+	}
+	if !last.IsZero() && end.After(last) {
+		end = last
+	}
+	return start, end, true
+}
+
+func (s *scheduleService) GetSchedule(ctx context.Context, courseRole string, courseID uuid.UUID) (*entities.LogBookSchedule, error) {
+	if !CanReview(courseRole) {
+		return nil, ErrForbidden
+	}
+	schedule, err := s.repo.GetScheduleByCourse(ctx, courseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrScheduleNotFound
+		}
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// applyScheduleInput memvalidasi input lalu mengisinya ke jadwal
+func applyScheduleInput(schedule *entities.LogBookSchedule, input ScheduleInput) error {
+	schedule.Frequency = entities.LogBookWeekly
+	if input.Frequency != nil {
+		switch f := entities.LogBookFrequency(strings.ToUpper(strings.TrimSpace(*input.Frequency))); f {
+		case entities.LogBookWeekly, entities.LogBookMonthly:
+			schedule.Frequency = f
+		default:
+			return ErrInvalidFrequency
+		}
+	}
+
+	schedule.WeekStart = time.Monday
+	if input.WeekStart != nil {
+		if *input.WeekStart < 0 || *input.WeekStart > 6 {
+			return ErrInvalidWeekStart
+		}
+		schedule.WeekStart = time.Weekday(*input.WeekStart)
+	}
+
+	schedule.StartDate = DateOnly(input.StartDate)
+	schedule.EndDate = nil
+	if input.EndDate != nil {
+		end := DateOnly(*input.EndDate)
+		if end.Before(schedule.StartDate) {
+			return ErrInvalidScheduleDate
+		}
+		schedule.EndDate = &end
+	}
+
+	schedule.CloseAction = entities.LogBookAutoSubmit
+	if input.CloseAction != nil {
+		switch a := entities.LogBookCloseAction(strings.ToUpper(strings.TrimSpace(*input.CloseAction))); a {
+		case entities.LogBookAutoSubmit, entities.LogBookAutoLock:
+			schedule.CloseAction = a
+		default:
+			return ErrInvalidCloseAction
+		}
+	}
+
+	schedule.GraceDays = 0
+	if input.GraceDays != nil {
+		if *input.GraceDays < 0 || *input.GraceDays > maxGraceDays {
+			return ErrInvalidGraceDays
+		}
+		schedule.GraceDays = *input.GraceDays
+	}
+
+	schedule.IsActive = input.IsActive == nil || *input.IsActive
+	return nil
+}
+
+func (s *scheduleService) SaveSchedule(ctx context.Context, userID uuid.UUID, courseRole string, courseID uuid.UUID, input ScheduleInput) (*entities.LogBookSchedule, int, error) {
+	if !CanReview(courseRole) {
+		return nil, 0, ErrForbidden
+	}
+
+	schedule, err := s.repo.GetScheduleByCourse(ctx, courseID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, err
+		}
+		schedule = &entities.LogBookSchedule{CourseID: courseID, CreatedByID: userID}
+	}
+
+	if err := applyScheduleInput(schedule, input); err != nil {
+		return nil, 0, err
+	}
+	if err := s.repo.SaveSchedule(ctx, schedule); err != nil {
+		return nil, 0, err
+	}
+
+	created, err := s.generate(ctx, schedule, time.Now())
+	if err != nil {
+		return nil, 0, err
+	}
+	return schedule, created, nil
+}
+
+func (s *scheduleService) DeleteSchedule(ctx context.Context, courseRole string, courseID uuid.UUID) error {
+	if !CanReview(courseRole) {
+		return ErrForbidden
+	}
+	deleted, err := s.repo.DeleteSchedule(ctx, courseID)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrScheduleNotFound
+	}
+	return nil
+}
+
+// generate membuat logbook periode yang memuat now untuk satu jadwal aktif
+func (s *scheduleService) generate(ctx context.Context, schedule *entities.LogBookSchedule, now time.Time) (int, error) {
+	if !schedule.IsActive {
+		return 0, nil
+	}
+	start, end, ok := PeriodFor(schedule, now)
+	if !ok {
+		return 0, nil
+	}
+	created, err := s.repo.CreatePeriodLogBooks(ctx, schedule.CourseID, start, end)
+	return int(created), err
+}
+
+func (s *scheduleService) GeneratePeriods(ctx context.Context, now time.Time) (int, error) {
+	schedules, err := s.repo.GetActiveSchedules(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	var firstErr error
+	for i := range schedules {
+		created, err := s.generate(ctx, &schedules[i], now)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		total += created
+	}
+	return total, firstErr
+}
+
+func (s *scheduleService) AutoClose(ctx context.Context, now time.Time) (int, error) {
+	schedules, err := s.repo.GetActiveSchedules(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	closed := 0
+	var firstErr error
+	for i := range schedules {
+		count, err := s.closeSchedule(ctx, &schedules[i], now)
+		closed += count
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return closed, firstErr
+}
+
+// closeSchedule menutup logbook satu course. Penutupan oleh sistem tidak melalui
+// transitions: AUTO_LOCK boleh mengunci DRAFT secara langsung.
+func (s *scheduleService) closeSchedule(ctx context.Context, schedule *entities.LogBookSchedule, now time.Time) (int, error) {
+	endBefore := DateOnly(now).AddDate(0, 0, -schedule.GraceDays)
+	lock := schedule.CloseAction == entities.LogBookAutoLock
+
+	logBooks, err := s.repo.GetLogBooksToClose(ctx, schedule.CourseID, endBefore, lock, autoCloseBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	closed := 0
+	for i := range logBooks {
+		lb := &logBooks[i]
+		from := lb.Status
+		review := &entities.LogBookReview{LogBookID: lb.ID, FromStatus: from}
+
+		closedAt := now
+		if lock {
+			lb.Status = entities.StatusLocked
+			lb.LockedAt = &closedAt
+			review.Action = entities.LogBookActionAutoLocked
+		} else {
+			lb.Status = entities.StatusSubmitted
+			lb.SubmittedAt = &closedAt
+			review.Action = entities.LogBookActionAutoSubmitted
+		}
+		review.ToStatus = lb.Status
+
+		// false berarti status sudah diubah user di antara query dan update: dilewati
+		ok, err := s.logBookRepo.UpdateStatus(ctx, lb, from, review)
+		if err != nil {
+			return closed, err
+		}
+		if ok {
+			closed++
+		}
+	}
+	return closed, nil
+}
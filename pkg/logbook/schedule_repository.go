@@ -0,0 +1,114 @@
+package logbook
+
+import (
+	"api-shiners/pkg/entities"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// dateLayout dipakai saat tanggal dikirim sebagai parameter ::date di SQL mentah
+const dateLayout = "2006-01-02"
+
+type ScheduleRepository interface {
+	GetScheduleByCourse(ctx context.Context, courseID uuid.UUID) (*entities.LogBookSchedule, error)
+	// SaveSchedule membuat jadwal baru bila ID kosong, selain itu memperbarui
+	SaveSchedule(ctx context.Context, schedule *entities.LogBookSchedule) error
+	DeleteSchedule(ctx context.Context, courseID uuid.UUID) (int64, error)
+	GetActiveSchedules(ctx context.Context) ([]entities.LogBookSchedule, error)
+
+	// CreatePeriodLogBooks membuat logbook DRAFT untuk setiap student course yang belum
+	// punya logbook beririsan dengan periode. Aman diulang: tidak pernah membuat duplikat.
+	CreatePeriodLogBooks(ctx context.Context, courseID uuid.UUID, start, end time.Time) (int64, error)
+	// GetLogBooksToClose memuat logbook yang periodenya berakhir sebelum endBefore dan belum
+	// ditutup. DRAFT yang pernah direview (misal dikembalikan untuk revisi) tidak ikut.
+	GetLogBooksToClose(ctx context.Context, courseID uuid.UUID, endBefore time.Time, includeSubmitted bool, limit int) ([]entities.LogBook, error)
+}
+
+type scheduleRepository struct {
+	db *gorm.DB
+}
+
+func NewScheduleRepository(db *gorm.DB) ScheduleRepository {
+	return &scheduleRepository{db}
+}
+
+func (r *scheduleRepository) GetScheduleByCourse(ctx context.Context, courseID uuid.UUID) (*entities.LogBookSchedule, error) {
+	var schedule entities.LogBookSchedule
+	if err := r.db.WithContext(ctx).First(&schedule, "course_id = ?", courseID).Error; err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (r *scheduleRepository) SaveSchedule(ctx context.Context, schedule *entities.LogBookSchedule) error {
+	if schedule.ID == uuid.Nil {
+		return r.db.WithContext(ctx).Omit("Course").Create(schedule).Error
+	}
+	return r.db.WithContext(ctx).Model(&entities.LogBookSchedule{}).
+		Where("id = ?", schedule.ID).
+		Updates(map[string]interface{}{
+			"frequency":    schedule.Frequency,
+			"week_start":   schedule.WeekStart,
+			"start_date":   schedule.StartDate,
+			"end_date":     schedule.EndDate,
+			"close_action": schedule.CloseAction,
+			"grace_days":   schedule.GraceDays,
+			"is_active":    schedule.IsActive,
+			"updated_at":   gorm.Expr("now()"),
+		}).Error
+}
+
+func (r *scheduleRepository) DeleteSchedule(ctx context.Context, courseID uuid.UUID) (int64, error) {
+	result := r.db.WithContext(ctx).Delete(&entities.LogBookSchedule{}, "course_id = ?", courseID)
+	return result.RowsAffected, result.Error
+}
+
+func (r *scheduleRepository) GetActiveSchedules(ctx context.Context) ([]entities.LogBookSchedule, error) {
+	var schedules []entities.LogBookSchedule
+	err := r.db.WithContext(ctx).
+		Where("is_active = ?", true).
+		Order("created_at ASC").
+		Find(&schedules).Error
+	return schedules, err
+}
+
+func (r *scheduleRepository) CreatePeriodLogBooks(ctx context.Context, courseID uuid.UUID, start, end time.Time) (int64, error) {
+	startDate, endDate := start.Format(dateLayout), end.Format(dateLayout)
+	result := r.db.WithContext(ctx).Exec(`
+		INSERT INTO log_books (course_id, student_id_id, period_start, period_end, status)
+		SELECT e.course_id, e.user_id, ?::date, ?::date, ?
+		FROM enrollments e
+		WHERE e.course_id = ? AND e.role_in_course = ?
+		  AND NOT EXISTS (
+			SELECT 1 FROM log_books lb
+			WHERE lb.course_id = e.course_id AND lb.student_id_id = e.user_id
+			  AND lb.period_start <= ?::date AND lb.period_end >= ?::date
+		  )
+		ON CONFLICT DO NOTHING`,
+		startDate, endDate, entities.StatusDraft,
+		courseID, entities.CourseRoleStudent,
+		endDate, startDate,
+	)
+	return result.RowsAffected, result.Error
+}
+
+func (r *scheduleRepository) GetLogBooksToClose(ctx context.Context, courseID uuid.UUID, endBefore time.Time, includeSubmitted bool, limit int) ([]entities.LogBook, error) {
+	neverReviewedDraft := r.db.Where("status = ?", entities.StatusDraft).
+		Where("NOT EXISTS (SELECT 1 FROM log_book_reviews r WHERE r.log_book_id = log_books.id)")
+	statusCond := neverReviewedDraft
+	if includeSubmitted {
+		statusCond = neverReviewedDraft.Or("status = ?", entities.StatusSubmitted)
+	}
+
+	var logBooks []entities.LogBook
+	err := r.db.WithContext(ctx).
+		Where("course_id = ? AND period_end < ?::date", courseID, endBefore.Format(dateLayout)).
+		Where(statusCond).
+		Order("period_end ASC, id ASC").
+		Limit(limit).
+		Find(&logBooks).Error
+	return logBooks, err
+}
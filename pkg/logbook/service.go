@@ -0,0 +1,382 @@
+package logbook
+
+import (
+	"api-shiners/pkg/document"
+	"api-shiners/pkg/enrollment"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/material"
+	"api-shiners/pkg/storage"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxPeriodDays membatasi panjang periode satu logbook (inklusif)
+	maxPeriodDays = 366
+	// maxEntryContent membatasi panjang isi entry (dalam karakter)
+	maxEntryContent = 10000
+)
+
+var (
+	ErrLogBookNotFound  = errors.New("logbook not found")
+	ErrEntryNotFound    = errors.New("logbook entry not found")
+	ErrForbidden        = errors.New("you are not allowed to review logbooks in this course")
+	ErrInvalidPeriod    = errors.New("period_end must not be before period_start")
+	ErrPeriodTooLong    = fmt.Errorf("logbook period must not exceed %d days", maxPeriodDays)
+	ErrPeriodOverlap    = errors.New("a logbook for an overlapping period already exists")
+	ErrNotEditable      = errors.New("logbook entries can only be changed while the logbook is DRAFT")
+	ErrEntryOutOfPeriod = errors.New("entry_date must be within the logbook period")
+	ErrEntryDateTaken   = errors.New("an entry for this date already exists")
+	ErrEmptyContent     = errors.New("content is required")
+	ErrContentTooLong   = fmt.Errorf("content must not exceed %d characters", maxEntryContent)
+	ErrNoEntries        = errors.New("cannot submit a logbook without entries")
+	ErrInvalidStatus    = errors.New("status must be DRAFT, SUBMITTED or LOCKED")
+)
+
+type PeriodInput struct {
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+}
+
+type EntryInput struct {
+	EntryDate time.Time
+	Content   string
+}
+
+type LogBookService interface {
+	CreateLogBook(ctx context.Context, studentID, courseID uuid.UUID, input PeriodInput) (*entities.LogBook, error)
+	GetMyLogBooks(ctx context.Context, studentID, courseID uuid.UUID) ([]entities.LogBook, error)
+	GetCourseLogBooks(ctx context.Context, courseRole string, courseID uuid.UUID, filter ListFilter) ([]entities.LogBook, error)
+	// GetLogBook: student hanya bisa melihat logbook miliknya, teacher semua logbook di course
+	GetLogBook(ctx context.Context, userID uuid.UUID, courseRole string, courseID, logBookID uuid.UUID) (*entities.LogBook, error)
+
+	AddEntry(ctx context.Context, studentID, courseID, logBookID uuid.UUID, input EntryInput) (*entities.LogBookEntry, error)
+	UpdateEntry(ctx context.Context, studentID, courseID, logBookID, entryID uuid.UUID, input EntryInput) (*entities.LogBookEntry, error)
+	// DeleteEntry menghapus entry beserta file lampirannya
+	DeleteEntry(ctx context.Context, studentID, courseID, logBookID, entryID uuid.UUID) error
+
+	// AddAttachment menyimpan file bukti ke entry logbook DRAFT, ukuran dan tipe dibatasi attachmentPolicy
+	AddAttachment(ctx context.Context, studentID, courseID, logBookID, entryID uuid.UUID, upload material.FileUpload) (*entities.LogBookAttachment, error)
+	DeleteAttachment(ctx context.Context, studentID, courseID, logBookID, entryID, attachmentID uuid.UUID) error
+	// GetAttachmentURL menghasilkan URL download sementara dengan aturan akses yang sama dengan GetLogBook
+	GetAttachmentURL(ctx context.Context, userID uuid.UUID, courseRole string, courseID, logBookID, entryID, attachmentID uuid.UUID) (string, time.Time, error)
+
+	Submit(ctx context.Context, studentID, courseID, logBookID uuid.UUID) (*entities.LogBook, error)
+	Lock(ctx context.Context, userID uuid.UUID, courseRole string, courseID, logBookID uuid.UUID) (*entities.LogBook, error)
+
+	// Approve mengunci logbook SUBMITTED dengan catatan persetujuan opsional
+	Approve(ctx context.Context, userID uuid.UUID, courseRole string, courseID, logBookID uuid.UUID, note string) (*entities.LogBook, error)
+	// RequestRevision mengembalikan logbook SUBMITTED ke DRAFT, alasan wajib diisi
+	RequestRevision(ctx context.Context, userID uuid.UUID, courseRole string, courseID, logBookID uuid.UUID, reason string) (*entities.LogBook, error)
+	AddComment(ctx context.Context, userID uuid.UUID, courseRole string, courseID, logBookID uuid.UUID, input CommentInput) (*entities.LogBookComment, error)
+	GetComments(ctx context.Context, userID uuid.UUID, courseRole string, courseID, logBookID uuid.UUID) ([]entities.LogBookComment, error)
+	GetHistory(ctx context.Context, userID uuid.UUID, courseRole string, courseID, logBookID uuid.UUID) ([]entities.LogBookReview, error)
+
+	// GetComplianceReport menampilkan tanggal tanpa entry di setiap periode logbook untuk semua student course
+	GetComplianceReport(ctx context.Context, courseRole string, courseID uuid.UUID, opts ComplianceOptions) (*ComplianceReport, error)
+	// ExportPDF merender logbook beserta entry dan komentar teacher dengan aturan akses yang sama dengan GetLogBook.
+	// fileName adalah nama file unduhan yang disarankan.
+	ExportPDF(ctx context.Context, userID uuid.UUID, courseRole string, courseID, logBookID uuid.UUID, header document.Header) (doc *document.Document, fileName string, err error)
+}
+
+type logBookService struct {
+	repo             LogBookRepository
+	enrollmentRepo   enrollment.EnrollmentRepository
+	store            storage.Storage
+	attachmentPolicy material.UploadPolicy
+}
+
+func NewLogBookService(repo LogBookRepository, enrollmentRepo enrollment.EnrollmentRepository, store storage.Storage, attachmentPolicy material.UploadPolicy) LogBookService {
+	return &logBookService{
+		repo:             repo,
+		enrollmentRepo:   enrollmentRepo,
+		store:            store,
+		attachmentPolicy: attachmentPolicy,
+	}
+}
+
+// CanReview: role di course (dari CourseAccess) yang boleh melihat dan mengunci logbook student
+func CanReview(courseRole string) bool {
+	return courseRole == string(entities.ADMIN) || courseRole == string(entities.CourseRoleTeacher)
+}
+
+// DateOnly membuang komponen jam agar tanggal bisa dibandingkan dengan kolom date
+func DateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// ParseStatus memvalidasi filter status, string kosong berarti semua status
+func ParseStatus(status string) (entities.StatusType, error) {
+	switch s := entities.StatusType(strings.ToUpper(strings.TrimSpace(status))); s {
+	case "", entities.StatusDraft, entities.StatusSubmitted, entities.StatusLocked:
+		return s, nil
+	default:
+		return "", ErrInvalidStatus
+	}
+}
+
+func (s *logBookService) findLogBook(ctx context.Context, courseID, logBookID uuid.UUID) (*entities.LogBook, error) {
+	logBook, err := s.repo.GetLogBookByID(ctx, logBookID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLogBookNotFound
+		}
+		return nil, err
+	}
+	if logBook.CourseID != courseID {
+		return nil, ErrLogBookNotFound
+	}
+	return logBook, nil
+}
+
+// findOwnLogBook menyembunyikan logbook student lain sebagai not found
+func (s *logBookService) findOwnLogBook(ctx context.Context, studentID, courseID, logBookID uuid.UUID) (*entities.LogBook, error) {
+	logBook, err := s.findLogBook(ctx, courseID, logBookID)
+	if err != nil {
+		return nil, err
+	}
+	if logBook.StudentID != studentID {
+		return nil, ErrLogBookNotFound
+	}
+	return logBook, nil
+}
+
+// findDraft memuat logbook milik student yang masih boleh diubah isinya
+func (s *logBookService) findDraft(ctx context.Context, studentID, courseID, logBookID uuid.UUID) (*entities.LogBook, error) {
+	logBook, err := s.findOwnLogBook(ctx, studentID, courseID, logBookID)
+	if err != nil {
+		return nil, err
+	}
+	if logBook.Status != entities.StatusDraft {
+		return nil, ErrNotEditable
+	}
+	return logBook, nil
+}
+
+func (s *logBookService) CreateLogBook(ctx context.Context, studentID, courseID uuid.UUID, input PeriodInput) (*entities.LogBook, error) {
+	start, end := DateOnly(input.PeriodStart), DateOnly(input.PeriodEnd)
+	if end.Before(start) {
+		return nil, ErrInvalidPeriod
+	}
+	if end.Sub(start) >= maxPeriodDays*24*time.Hour {
+		return nil, ErrPeriodTooLong
+	}
+
+	overlap, err := s.repo.HasOverlappingPeriod(ctx, courseID, studentID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if overlap {
+		return nil, ErrPeriodOverlap
+	}
+
+	logBook := &entities.LogBook{
+		CourseID:    courseID,
+		StudentID:   studentID,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Status:      entities.StatusDraft,
+	}
+	if err := s.repo.CreateLogBook(ctx, logBook); err != nil {
+		return nil, err
+	}
+	return logBook, nil
+}
+
+func (s *logBookService) GetMyLogBooks(ctx context.Context, studentID, courseID uuid.UUID) ([]entities.LogBook, error) {
+	return s.repo.GetLogBooksByCourse(ctx, courseID, ListFilter{StudentID: &studentID})
+}
+
+func (s *logBookService) GetCourseLogBooks(ctx context.Context, courseRole string, courseID uuid.UUID, filter ListFilter) ([]entities.LogBook, error) {
+	if !CanReview(courseRole) {
+		return nil, ErrForbidden
+	}
+	return s.repo.GetLogBooksByCourse(ctx, courseID, filter)
+}
+
+func (s *logBookService) GetLogBook(ctx context.Context, userID uuid.UUID, courseRole string, courseID, logBookID uuid.UUID) (*entities.LogBook, error) {
+	if CanReview(courseRole) {
+		return s.findLogBook(ctx, courseID, logBookID)
+	}
+	return s.findOwnLogBook(ctx, userID, courseID, logBookID)
+}
+
+// validateEntry memeriksa isi dan tanggal entry terhadap periode logbook
+func (s *logBookService) validateEntry(ctx context.Context, logBook *entities.LogBook, entryID uuid.UUID, input EntryInput) (time.Time, string, error) {
+	content := strings.TrimSpace(input.Content)
+	if content == "" {
+		return time.Time{}, "", ErrEmptyContent
+	}
+	if len([]rune(content)) > maxEntryContent {
+		return time.Time{}, "", ErrContentTooLong
+	}
+
+	date := DateOnly(input.EntryDate)
+	if date.Before(DateOnly(logBook.PeriodStart)) || date.After(DateOnly(logBook.PeriodEnd)) {
+		return time.Time{}, "", ErrEntryOutOfPeriod
+	}
+
+	taken, err := s.repo.EntryDateTaken(ctx, logBook.ID, date, entryID)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	if taken {
+		return time.Time{}, "", ErrEntryDateTaken
+	}
+	return date, content, nil
+}
+
+func (s *logBookService) AddEntry(ctx context.Context, studentID, courseID, logBookID uuid.UUID, input EntryInput) (*entities.LogBookEntry, error) {
+	logBook, err := s.findDraft(ctx, studentID, courseID, logBookID)
+	if err != nil {
+		return nil, err
+	}
+
+	date, content, err := s.validateEntry(ctx, logBook, uuid.Nil, input)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &entities.LogBookEntry{
+		LogBookID: logBook.ID,
+		EntryDate: date,
+		Content:   content,
+	}
+	if err := s.repo.CreateEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *logBookService) findEntry(ctx context.Context, logBookID, entryID uuid.UUID) (*entities.LogBookEntry, error) {
+	entry, err := s.repo.GetEntryByID(ctx, entryID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEntryNotFound
+		}
+		return nil, err
+	}
+	if entry.LogBookID != logBookID {
+		return nil, ErrEntryNotFound
+	}
+	return entry, nil
+}
+
+func (s *logBookService) UpdateEntry(ctx context.Context, studentID, courseID, logBookID, entryID uuid.UUID, input EntryInput) (*entities.LogBookEntry, error) {
+	logBook, err := s.findDraft(ctx, studentID, courseID, logBookID)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := s.findEntry(ctx, logBook.ID, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	date, content, err := s.validateEntry(ctx, logBook, entry.ID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.EntryDate = date
+	entry.Content = content
+	if err := s.repo.UpdateEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *logBookService) DeleteEntry(ctx context.Context, studentID, courseID, logBookID, entryID uuid.UUID) error {
+	logBook, err := s.findDraft(ctx, studentID, courseID, logBookID)
+	if err != nil {
+		return err
+	}
+	entry, err := s.findEntry(ctx, logBook.ID, entryID)
+	if err != nil {
+		return err
+	}
+
+	attachments, err := s.repo.GetAttachmentsByEntry(ctx, entry.ID)
+	if err != nil {
+		return err
+	}
+	// baris lampiran dihapus bersama entry, file di storage dihapus setelah transaksi selesai
+	if err := s.repo.DeleteEntry(ctx, logBook.ID, entry.ID); err != nil {
+		return err
+	}
+	return s.removeAttachmentFiles(ctx, attachments)
+}
+
+// changeStatus menyimpan perpindahan status beserta riwayatnya; bila status sudah diubah
+// request lain di antara baca dan tulis, error menyebut status terbaru
+func (s *logBookService) changeStatus(ctx context.Context, logBook *entities.LogBook, to entities.StatusType, actorID uuid.UUID, action entities.LogBookAction, reason string) error {
+	from := logBook.Status
+	if err := checkTransition(from, to); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	logBook.Status = to
+	switch to {
+	case entities.StatusSubmitted:
+		logBook.SubmittedAt = &now
+	case entities.StatusLocked:
+		logBook.LockedAt = &now
+	case entities.StatusDraft:
+		// dikembalikan untuk revisi: akan diisi lagi saat student submit ulang
+		logBook.SubmittedAt = nil
+	}
+
+	review := &entities.LogBookReview{
+		LogBookID:  logBook.ID,
+		ActorID:    &actorID,
+		Action:     action,
+		FromStatus: from,
+		ToStatus:   to,
+		Reason:     reason,
+	}
+	ok, err := s.repo.UpdateStatus(ctx, logBook, from, review)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		current, err := s.findLogBook(ctx, logBook.CourseID, logBook.ID)
+		if err != nil {
+			return err
+		}
+		return &TransitionError{From: current.Status, To: to}
+	}
+	return nil
+}
+
+func (s *logBookService) Submit(ctx context.Context, studentID, courseID, logBookID uuid.UUID) (*entities.LogBook, error) {
+	logBook, err := s.findOwnLogBook(ctx, studentID, courseID, logBookID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTransition(logBook.Status, entities.StatusSubmitted); err != nil {
+		return nil, err
+	}
+
+	count, err := s.repo.CountEntries(ctx, logBook.ID)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, ErrNoEntries
+	}
+
+	if err := s.changeStatus(ctx, logBook, entities.StatusSubmitted, studentID, entities.LogBookActionSubmitted, ""); err != nil {
+		return nil, err
+	}
+	return logBook, nil
+}
+
+func (s *logBookService) Lock(ctx context.Context, userID uuid.UUID, courseRole string, courseID, logBookID uuid.UUID) (*entities.LogBook, error) {
+	return s.review(ctx, userID, courseRole, courseID, logBookID, entities.StatusLocked, entities.LogBookActionLocked, "")
+}
@@ -0,0 +1,47 @@
+package logbook
+
+import (
+	"api-shiners/pkg/entities"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidTransition = errors.New("invalid logbook status transition")
+
+// transitions berisi perpindahan status yang sah: DRAFT -> SUBMITTED -> LOCKED,
+// atau SUBMITTED -> DRAFT saat teacher meminta revisi
+var transitions = map[entities.StatusType][]entities.StatusType{
+	entities.StatusDraft:     {entities.StatusSubmitted},
+	entities.StatusSubmitted: {entities.StatusLocked, entities.StatusDraft},
+}
+
+// TransitionError menjelaskan perpindahan status yang ditolak, cocok dengan ErrInvalidTransition
+type TransitionError struct {
+	From entities.StatusType
+	To   entities.StatusType
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("cannot change logbook status from %s to %s", e.From, e.To)
+}
+
+func (e *TransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// CanTransition mengecek apakah status boleh berpindah dari from ke to
+func CanTransition(from, to entities.StatusType) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+func checkTransition(from, to entities.StatusType) error {
+	if !CanTransition(from, to) {
+		return &TransitionError{From: from, To: to}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package material
+
+import (
+	"api-shiners/pkg/entities"
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type MaterialRepository interface {
+	CreateMaterial(ctx context.Context, material *entities.Material) error
+	GetMaterialByID(ctx context.Context, id uuid.UUID) (*entities.Material, error)
+	GetMaterialsByModule(ctx context.Context, moduleID uuid.UUID) ([]entities.Material, error)
+	DeleteMaterial(ctx context.Context, id uuid.UUID) error
+	GetMaxPosition(ctx context.Context, moduleID uuid.UUID) (int, error)
+	IsEnrolled(ctx context.Context, courseID, userID uuid.UUID) (bool, error)
+}
+
+type materialRepository struct {
+	db *gorm.DB
+}
+
+func NewMaterialRepository(db *gorm.DB) MaterialRepository {
+	return &materialRepository{db}
+}
+
+func (r *materialRepository) CreateMaterial(ctx context.Context, material *entities.Material) error {
+	return r.db.WithContext(ctx).Omit("Module").Create(material).Error
+}
+
+func (r *materialRepository) GetMaterialByID(ctx context.Context, id uuid.UUID) (*entities.Material, error) {
+	var material entities.Material
+	if err := r.db.WithContext(ctx).Preload("Module").First(&material, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &material, nil
+}
+
+func (r *materialRepository) GetMaterialsByModule(ctx context.Context, moduleID uuid.UUID) ([]entities.Material, error) {
+	var materials []entities.Material
+	err := r.db.WithContext(ctx).
+		Where("module_id = ?", moduleID).
+		Order("position ASC, created_at ASC").
+		Find(&materials).Error
+	return materials, err
+}
+
+func (r *materialRepository) DeleteMaterial(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&entities.Material{}, "id = ?", id).Error
+}
+
+func (r *materialRepository) GetMaxPosition(ctx context.Context, moduleID uuid.UUID) (int, error) {
+	var maxPosition int
+	err := r.db.WithContext(ctx).
+		Model(&entities.Material{}).
+		Where("module_id = ?", moduleID).
+		Select("COALESCE(MAX(position), 0)").
+		Scan(&maxPosition).Error
+	return maxPosition, err
+}
+
+func (r *materialRepository) IsEnrolled(ctx context.Context, courseID, userID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&entities.Enrollment{}).
+		Where("course_id = ? AND user_id = ?", courseID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
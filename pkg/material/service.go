@@ -0,0 +1,355 @@
+package material
+
+import (
+	"api-shiners/pkg/course"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/storage"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const downloadURLTTL = 15 * time.Minute
+
+var (
+	ErrMaterialNotFound   = errors.New("material not found")
+	ErrNotEnrolled        = errors.New("you are not enrolled in this course")
+	ErrNotAFile           = errors.New("material is not a file")
+	ErrFileRequired       = errors.New("file is required")
+	ErrFileTooLarge       = errors.New("file exceeds the maximum upload size")
+	ErrFileTypeNotAllowed = errors.New("file type is not allowed")
+)
+
+var defaultAllowedTypes = []string{
+	"application/pdf",
+	"image/jpeg",
+	"image/png",
+	"image/gif",
+	"image/webp",
+	"video/mp4",
+	"audio/mpeg",
+	"text/plain",
+	"application/zip",
+	"application/msword",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	"application/vnd.ms-excel",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	"application/vnd.ms-powerpoint",
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation",
+}
+
+var unsafeFileNameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// UploadPolicy membatasi ukuran dan tipe MIME file yang boleh diupload
+type UploadPolicy struct {
+	MaxBytes     int64
+	AllowedTypes []string
+}
+
+// UploadPolicyFromEnv membaca MATERIAL_MAX_UPLOAD_MB dan MATERIAL_ALLOWED_MIME
+func UploadPolicyFromEnv() UploadPolicy {
+	return LoadUploadPolicy("MATERIAL_MAX_UPLOAD_MB", "MATERIAL_ALLOWED_MIME", 20, defaultAllowedTypes)
+}
+
+// LoadUploadPolicy membaca batas ukuran (MB) dan daftar MIME (dipisah koma) dari env,
+// memakai nilai default bila env kosong atau tidak valid
+func LoadUploadPolicy(maxMBEnv, allowedEnv string, defaultMB int64, defaultTypes []string) UploadPolicy {
+	maxMB := defaultMB
+	if v, err := strconv.ParseInt(os.Getenv(maxMBEnv), 10, 64); err == nil && v > 0 {
+		maxMB = v
+	}
+
+	allowed := defaultTypes
+	if v := os.Getenv(allowedEnv); v != "" {
+		allowed = nil
+		for _, t := range strings.Split(v, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				allowed = append(allowed, strings.ToLower(t))
+			}
+		}
+	}
+
+	return UploadPolicy{MaxBytes: maxMB << 20, AllowedTypes: allowed}
+}
+
+func (p UploadPolicy) allows(contentType string) bool {
+	for _, t := range p.AllowedTypes {
+		if strings.EqualFold(t, contentType) {
+			return true
+		}
+	}
+	return false
+}
+
+// DetectContentType menentukan tipe MIME dari isi file (bukan dari header client).
+// Format kontainer seperti docx/xlsx terdeteksi sebagai zip, sehingga ekstensi
+// dipakai sebagai petunjuk hanya bila cocok dengan hasil sniffing.
+func (p UploadPolicy) DetectContentType(fileName string, head []byte) (string, error) {
+	sniffed := strings.Split(http.DetectContentType(head), ";")[0]
+	byExt := strings.Split(mime.TypeByExtension(strings.ToLower(filepath.Ext(fileName))), ";")[0]
+
+	contentType := sniffed
+	switch {
+	case byExt == "":
+	case sniffed == "application/octet-stream":
+		contentType = byExt
+	case sniffed == "application/zip" && strings.Contains(byExt, "openxmlformats"):
+		contentType = byExt
+	case sniffed == "text/plain" && strings.HasPrefix(byExt, "text/"):
+		contentType = byExt
+	}
+
+	if !p.allows(contentType) {
+		return "", ErrFileTypeNotAllowed
+	}
+	return contentType, nil
+}
+
+// FileUpload adalah file yang diterima dari request multipart
+type FileUpload struct {
+	FileName string
+	Size     int64
+	Reader   io.Reader
+}
+
+type MaterialService interface {
+	GetMaterials(ctx context.Context, userID uuid.UUID, courseRole string, courseID, moduleID uuid.UUID) ([]entities.Material, error)
+	UploadFile(ctx context.Context, userID uuid.UUID, courseRole string, courseID, moduleID uuid.UUID, title string, upload FileUpload) (*entities.Material, error)
+	DeleteMaterial(ctx context.Context, userID uuid.UUID, courseRole string, courseID, materialID uuid.UUID) error
+	GetDownloadURL(ctx context.Context, userID uuid.UUID, courseRole string, courseID, materialID uuid.UUID) (string, time.Time, error)
+}
+
+type materialService struct {
+	repo       MaterialRepository
+	courseRepo course.CourseRepository
+	store      storage.Storage
+	policy     UploadPolicy
+}
+
+func NewMaterialService(repo MaterialRepository, courseRepo course.CourseRepository, store storage.Storage, policy UploadPolicy) MaterialService {
+	return &materialService{
+		repo:       repo,
+		courseRepo: courseRepo,
+		store:      store,
+		policy:     policy,
+	}
+}
+
+func (s *materialService) findCourse(ctx context.Context, courseID uuid.UUID) (*entities.Course, error) {
+	found, err := s.courseRepo.GetCourseByID(ctx, courseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, course.ErrCourseNotFound
+		}
+		return nil, err
+	}
+	return found, nil
+}
+
+func (s *materialService) findModule(ctx context.Context, courseID, moduleID uuid.UUID) (*entities.CourseModule, error) {
+	module, err := s.courseRepo.GetModuleByID(ctx, moduleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, course.ErrModuleNotFound
+		}
+		return nil, err
+	}
+	if module.CourseID != courseID {
+		return nil, course.ErrModuleNotFound
+	}
+	return module, nil
+}
+
+func (s *materialService) findMaterial(ctx context.Context, courseID, materialID uuid.UUID) (*entities.Material, error) {
+	material, err := s.repo.GetMaterialByID(ctx, materialID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMaterialNotFound
+		}
+		return nil, err
+	}
+	if material.Module.CourseID != courseID {
+		return nil, ErrMaterialNotFound
+	}
+	return material, nil
+}
+
+// ensureCanRead: teacher course selalu boleh, selain itu harus terdaftar di course yang sudah dipublish
+func (s *materialService) ensureCanRead(ctx context.Context, found *entities.Course, userID uuid.UUID, courseRole string) error {
+	if course.CanTeach(courseRole) {
+		return nil
+	}
+
+	enrolled, err := s.repo.IsEnrolled(ctx, found.ID, userID)
+	if err != nil {
+		return err
+	}
+	if !enrolled || !found.IsPublished {
+		return ErrNotEnrolled
+	}
+	return nil
+}
+
+func (s *materialService) GetMaterials(ctx context.Context, userID uuid.UUID, courseRole string, courseID, moduleID uuid.UUID) ([]entities.Material, error) {
+	found, err := s.findCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureCanRead(ctx, found, userID, courseRole); err != nil {
+		return nil, err
+	}
+	if _, err := s.findModule(ctx, courseID, moduleID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetMaterialsByModule(ctx, moduleID)
+}
+
+func (s *materialService) UploadFile(ctx context.Context, userID uuid.UUID, courseRole string, courseID, moduleID uuid.UUID, title string, upload FileUpload) (*entities.Material, error) {
+	if !course.CanTeach(courseRole) {
+		return nil, course.ErrForbidden
+	}
+	if _, err := s.findCourse(ctx, courseID); err != nil {
+		return nil, err
+	}
+	if _, err := s.findModule(ctx, courseID, moduleID); err != nil {
+		return nil, err
+	}
+
+	if upload.Reader == nil || upload.Size <= 0 {
+		return nil, ErrFileRequired
+	}
+	if upload.Size > s.policy.MaxBytes {
+		return nil, ErrFileTooLarge
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(upload.Reader, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	head = head[:n]
+
+	contentType, err := s.policy.DetectContentType(upload.FileName, head)
+	if err != nil {
+		return nil, err
+	}
+
+	fileName := SanitizeFileName(upload.FileName)
+	materialID := uuid.New()
+	key := fmt.Sprintf("materials/%s/%s/%s", courseID, materialID, fileName)
+
+	body := io.MultiReader(bytes.NewReader(head), upload.Reader)
+	if err := s.store.Put(ctx, key, body, upload.Size, contentType); err != nil {
+		return nil, fmt.Errorf("failed to store file: %v", err)
+	}
+
+	position, err := s.repo.GetMaxPosition(ctx, moduleID)
+	if err != nil {
+		_ = s.store.Delete(ctx, key)
+		return nil, err
+	}
+
+	title = strings.TrimSpace(title)
+	if title == "" {
+		title = fileName
+	}
+
+	material := &entities.Material{
+		ID:       materialID,
+		ModuleID: moduleID,
+		Type:     entities.MaterialTypeFile,
+		Title:    title,
+		FileKey:  key,
+		Position: position + 1,
+	}
+	if err := s.repo.CreateMaterial(ctx, material); err != nil {
+		_ = s.store.Delete(ctx, key)
+		return nil, err
+	}
+
+	return material, nil
+}
+
+func (s *materialService) DeleteMaterial(ctx context.Context, userID uuid.UUID, courseRole string, courseID, materialID uuid.UUID) error {
+	if !course.CanTeach(courseRole) {
+		return course.ErrForbidden
+	}
+	if _, err := s.findCourse(ctx, courseID); err != nil {
+		return err
+	}
+
+	material, err := s.findMaterial(ctx, courseID, materialID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteMaterial(ctx, materialID); err != nil {
+		return err
+	}
+
+	if material.Type == entities.MaterialTypeFile && material.FileKey != "" {
+		if err := s.store.Delete(ctx, material.FileKey); err != nil {
+			return fmt.Errorf("material deleted but failed to remove file: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *materialService) GetDownloadURL(ctx context.Context, userID uuid.UUID, courseRole string, courseID, materialID uuid.UUID) (string, time.Time, error) {
+	found, err := s.findCourse(ctx, courseID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if err := s.ensureCanRead(ctx, found, userID, courseRole); err != nil {
+		return "", time.Time{}, err
+	}
+
+	material, err := s.findMaterial(ctx, courseID, materialID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if material.Type != entities.MaterialTypeFile || material.FileKey == "" {
+		return "", time.Time{}, ErrNotAFile
+	}
+
+	expiresAt := time.Now().Add(downloadURLTTL)
+	url, err := s.store.SignedURL(ctx, material.FileKey, downloadURLTTL, filepath.Base(material.FileKey))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return url, expiresAt, nil
+}
+
+// SanitizeFileName membuang karakter yang tidak aman dari nama file
+func SanitizeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = unsafeFileNameChars.ReplaceAllString(name, "_")
+	name = strings.Trim(name, "._")
+	if name == "" {
+		name = "file"
+	}
+	if len(name) > 120 {
+		ext := filepath.Ext(name)
+		if len(ext) > 20 {
+			ext = ""
+		}
+		name = name[:120-len(ext)] + ext
+	}
+	return name
+}
@@ -57,6 +57,8 @@ func AuthMiddleware(c *fiber.Ctx) error {
 
 	// Simpan user_id ke context untuk digunakan di handler
 	c.Locals("user_id", userID)
+	c.Locals("email", claims["email"])
+	c.Locals("roles", claims["roles"])
 
 	return c.Next()
 }
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"api-shiners/pkg/course"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/utils"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// CourseRoleResolver mencari role user di sebuah course (lihat enrollment.EnrollmentService)
+type CourseRoleResolver interface {
+	GetCourseRole(ctx context.Context, courseID, userID uuid.UUID) (entities.CourseRole, error)
+}
+
+// CourseAccess membatasi akses route yang memiliki parameter :course_id berdasarkan
+// Enrollment.RoleInCourse, bukan hanya role global di JWT.
+type CourseAccess struct {
+	resolver CourseRoleResolver
+}
+
+func NewCourseAccess(resolver CourseRoleResolver) *CourseAccess {
+	return &CourseAccess{resolver: resolver}
+}
+
+// Require mengizinkan request bila role user di course termasuk allowed.
+// ADMIN global selalu diizinkan. Harus dipasang setelah AuthMiddleware /
+// TeacherOrAdminMiddleware karena membaca user_id dan roles dari context.
+// Role di course disimpan ke context sebagai "course_role".
+func (a *CourseAccess) Require(allowed ...entities.CourseRole) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userIDStr, ok := c.Locals("user_id").(string)
+		if !ok || userIDStr == "" {
+			return utils.Error(c, http.StatusUnauthorized, "Unauthorized", "UnauthorizedException", nil)
+		}
+
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return utils.Error(c, http.StatusUnauthorized, "Invalid user ID format", "UnauthorizedException", nil)
+		}
+
+		courseID, err := uuid.Parse(c.Params("course_id"))
+		if err != nil {
+			return utils.Error(c, http.StatusBadRequest, "Invalid course ID format", "InvalidUUID", nil)
+		}
+
+		if hasAnyRole(c.Locals("roles"), string(entities.ADMIN)) {
+			c.Locals("course_role", string(entities.ADMIN))
+			return c.Next()
+		}
+
+		role, err := a.resolver.GetCourseRole(c.Context(), courseID, userID)
+		if err != nil {
+			if errors.Is(err, course.ErrCourseNotFound) {
+				return utils.Error(c, http.StatusNotFound, err.Error(), "NotFoundException", nil)
+			}
+			log.Printf("⚠️ Failed to resolve course role of user %s in course %s: %v", userID, courseID, err)
+			return utils.Error(c, http.StatusInternalServerError, "Internal server error", "InternalServerError", nil)
+		}
+
+		for _, r := range allowed {
+			if role == r {
+				c.Locals("course_role", string(role))
+				return c.Next()
+			}
+		}
+
+		if role == "" {
+			return utils.Error(c, http.StatusForbidden, "You are not enrolled in this course", "ForbiddenException", nil)
+		}
+		return utils.Error(c, http.StatusForbidden, "Access restricted for your role in this course", "ForbiddenException", nil)
+	}
+}
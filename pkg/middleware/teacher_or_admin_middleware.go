@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"api-shiners/pkg/utils"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TeacherOrAdminMiddleware memastikan hanya TEACHER atau ADMIN yang bisa mengakses route
+func TeacherOrAdminMiddleware(c *fiber.Ctx) error {
+	authHeader := c.Get("Authorization")
+	if authHeader == "" {
+		return utils.Error(c, http.StatusUnauthorized, "Missing Authorization header", "UnauthorizedException", nil)
+	}
+
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+	if tokenString == authHeader {
+		return utils.Error(c, http.StatusUnauthorized, "Invalid token format", "UnauthorizedException", nil)
+	}
+
+	secret := os.Getenv("JWT_SECRET")
+	token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fiber.NewError(http.StatusUnauthorized, "Invalid token signing method")
+		}
+		return []byte(secret), nil
+	})
+	if err != nil || !token.Valid {
+		return utils.Error(c, http.StatusUnauthorized, "Invalid or expired token", "UnauthorizedException", nil)
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return utils.Error(c, http.StatusUnauthorized, "Invalid token claims", "UnauthorizedException", nil)
+	}
+
+	rawRoles, exists := claims["roles"]
+	if !exists {
+		return utils.Error(c, http.StatusForbidden, "No roles found in token", "ForbiddenException", nil)
+	}
+
+	if !hasAnyRole(rawRoles, "TEACHER", "ADMIN") {
+		return utils.Error(c, http.StatusForbidden, "Access restricted to TEACHER or ADMIN only", "ForbiddenException", nil)
+	}
+
+	c.Locals("user_id", claims["user_id"])
+	c.Locals("email", claims["email"])
+	c.Locals("roles", rawRoles)
+
+	return c.Next()
+}
+
+// hasAnyRole mengecek apakah claim roles (string atau array) berisi salah satu role
+func hasAnyRole(rawRoles interface{}, names ...string) bool {
+	var roles []string
+
+	switch r := rawRoles.(type) {
+	case string:
+		roles = []string{r}
+	case []interface{}:
+		for _, item := range r {
+			if rs, ok := item.(string); ok {
+				roles = append(roles, rs)
+			}
+		}
+	default:
+		roles = []string{strings.TrimSpace(fmt.Sprint(rawRoles))}
+	}
+
+	for _, role := range roles {
+		for _, name := range names {
+			if strings.EqualFold(role, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package quiz
+
+import (
+	"api-shiners/pkg/entities"
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BankFilter: CourseID diisi untuk bank milik course, selain itu bank pribadi OwnerID
+type BankFilter struct {
+	OwnerID  uuid.UUID
+	CourseID *uuid.UUID
+}
+
+type BankRepository interface {
+	CreateBank(ctx context.Context, bank *entities.QuestionBank) error
+	GetBankByID(ctx context.Context, id uuid.UUID) (*entities.QuestionBank, error)
+	GetBanks(ctx context.Context, filter BankFilter) ([]entities.QuestionBank, error)
+	UpdateBank(ctx context.Context, bank *entities.QuestionBank) error
+	DeleteBank(ctx context.Context, id uuid.UUID) error
+	GetMaxBankQuestionPosition(ctx context.Context, bankID uuid.UUID) (int, error)
+}
+
+type bankRepository struct {
+	db *gorm.DB
+}
+
+func NewBankRepository(db *gorm.DB) BankRepository {
+	return &bankRepository{db}
+}
+
+func (r *bankRepository) CreateBank(ctx context.Context, bank *entities.QuestionBank) error {
+	return r.db.WithContext(ctx).Omit("Owner", "Course").Create(bank).Error
+}
+
+func (r *bankRepository) GetBankByID(ctx context.Context, id uuid.UUID) (*entities.QuestionBank, error) {
+	var bank entities.QuestionBank
+	if err := r.db.WithContext(ctx).First(&bank, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &bank, nil
+}
+
+func (r *bankRepository) GetBanks(ctx context.Context, filter BankFilter) ([]entities.QuestionBank, error) {
+	query := r.db.WithContext(ctx)
+	if filter.CourseID != nil {
+		query = query.Where("course_id = ?", *filter.CourseID)
+	} else {
+		query = query.Where("owner_id = ? AND course_id IS NULL", filter.OwnerID)
+	}
+
+	var banks []entities.QuestionBank
+	if err := query.Order("created_at ASC").Find(&banks).Error; err != nil {
+		return nil, err
+	}
+	return banks, nil
+}
+
+func (r *bankRepository) UpdateBank(ctx context.Context, bank *entities.QuestionBank) error {
+	return r.db.WithContext(ctx).Model(&entities.QuestionBank{}).
+		Where("id = ?", bank.ID).
+		Updates(map[string]interface{}{
+			"title":       bank.Title,
+			"description": bank.Description,
+			"updated_at":  gorm.Expr("now()"),
+		}).Error
+}
+
+func (r *bankRepository) DeleteBank(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&entities.QuestionBank{}, "id = ?", id).Error
+}
+
+func (r *bankRepository) GetMaxBankQuestionPosition(ctx context.Context, bankID uuid.UUID) (int, error) {
+	var maxPosition int
+	err := r.db.WithContext(ctx).Model(&entities.Question{}).
+		Where("bank_id = ?", bankID).
+		Select("COALESCE(MAX(position), 0)").
+		Scan(&maxPosition).Error
+	return maxPosition, err
+}
@@ -0,0 +1,280 @@
+package quiz
+
+import (
+	"api-shiners/pkg/entities"
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BankScope menentukan bank yang bisa dikelola: bank course (CourseID diisi, CourseRole
+// dari CourseAccess) atau bank pribadi milik UserID
+type BankScope struct {
+	UserID     uuid.UUID
+	CourseID   *uuid.UUID
+	CourseRole string
+}
+
+type BankInput struct {
+	Title       string
+	Description string
+}
+
+// BankService mengelola bank soal. Soal bank boleh belum lengkap; yang diundi ke paper
+// student hanya soal yang lolos validasi (lihat UsableQuestions).
+type BankService interface {
+	GetBanks(ctx context.Context, scope BankScope) ([]entities.QuestionBank, error)
+	// GetBank memuat bank beserta soalnya, difilter tags bila diisi
+	GetBank(ctx context.Context, scope BankScope, bankID uuid.UUID, tags []string) (*entities.QuestionBank, error)
+	CreateBank(ctx context.Context, scope BankScope, input BankInput) (*entities.QuestionBank, error)
+	UpdateBank(ctx context.Context, scope BankScope, bankID uuid.UUID, input BankInput) (*entities.QuestionBank, error)
+	DeleteBank(ctx context.Context, scope BankScope, bankID uuid.UUID) error
+
+	CreateQuestion(ctx context.Context, scope BankScope, bankID uuid.UUID, input QuestionInput) (*entities.Question, error)
+	UpdateQuestion(ctx context.Context, scope BankScope, bankID, questionID uuid.UUID, input QuestionInput) (*entities.Question, error)
+	DeleteQuestion(ctx context.Context, scope BankScope, bankID, questionID uuid.UUID) error
+
+	CreateChoice(ctx context.Context, scope BankScope, bankID, questionID uuid.UUID, input ChoiceInput) (*entities.Choice, error)
+	UpdateChoice(ctx context.Context, scope BankScope, bankID, questionID, choiceID uuid.UUID, input ChoiceInput) (*entities.Choice, error)
+	DeleteChoice(ctx context.Context, scope BankScope, bankID, questionID, choiceID uuid.UUID) error
+}
+
+type bankService struct {
+	repo     BankRepository
+	quizRepo QuizRepository
+}
+
+func NewBankService(repo BankRepository, quizRepo QuizRepository) BankService {
+	return &bankService{
+		repo:     repo,
+		quizRepo: quizRepo,
+	}
+}
+
+func (s *bankService) checkScope(scope BankScope) error {
+	if scope.CourseID != nil && !CanAuthor(scope.CourseRole) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func (s *bankService) findBank(ctx context.Context, scope BankScope, bankID uuid.UUID) (*entities.QuestionBank, error) {
+	if err := s.checkScope(scope); err != nil {
+		return nil, err
+	}
+
+	bank, err := s.repo.GetBankByID(ctx, bankID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBankNotFound
+		}
+		return nil, err
+	}
+
+	if scope.CourseID != nil {
+		if bank.CourseID == nil || *bank.CourseID != *scope.CourseID {
+			return nil, ErrBankNotFound
+		}
+		return bank, nil
+	}
+	if bank.CourseID != nil || bank.OwnerID != scope.UserID {
+		return nil, ErrBankNotFound
+	}
+	return bank, nil
+}
+
+func (s *bankService) findQuestion(ctx context.Context, scope BankScope, bankID, questionID uuid.UUID) (*entities.Question, error) {
+	if _, err := s.findBank(ctx, scope, bankID); err != nil {
+		return nil, err
+	}
+
+	question, err := s.quizRepo.GetQuestionByID(ctx, questionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrQuestionNotFound
+		}
+		return nil, err
+	}
+	if question.BankID == nil || *question.BankID != bankID {
+		return nil, ErrQuestionNotFound
+	}
+	return question, nil
+}
+
+func (s *bankService) GetBanks(ctx context.Context, scope BankScope) ([]entities.QuestionBank, error) {
+	if err := s.checkScope(scope); err != nil {
+		return nil, err
+	}
+	return s.repo.GetBanks(ctx, BankFilter{OwnerID: scope.UserID, CourseID: scope.CourseID})
+}
+
+func (s *bankService) GetBank(ctx context.Context, scope BankScope, bankID uuid.UUID, tags []string) (*entities.QuestionBank, error) {
+	bank, err := s.findBank(ctx, scope, bankID)
+	if err != nil {
+		return nil, err
+	}
+
+	questions, err := s.quizRepo.GetPoolQuestions(ctx, bankID, normalizeTags(tags))
+	if err != nil {
+		return nil, err
+	}
+	bank.Questions = questions
+	return bank, nil
+}
+
+func (s *bankService) CreateBank(ctx context.Context, scope BankScope, input BankInput) (*entities.QuestionBank, error) {
+	if err := s.checkScope(scope); err != nil {
+		return nil, err
+	}
+
+	bank := &entities.QuestionBank{OwnerID: scope.UserID, CourseID: scope.CourseID}
+	if err := applyBankInput(bank, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateBank(ctx, bank); err != nil {
+		return nil, err
+	}
+	return bank, nil
+}
+
+func (s *bankService) UpdateBank(ctx context.Context, scope BankScope, bankID uuid.UUID, input BankInput) (*entities.QuestionBank, error) {
+	bank, err := s.findBank(ctx, scope, bankID)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyBankInput(bank, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateBank(ctx, bank); err != nil {
+		return nil, err
+	}
+	return s.repo.GetBankByID(ctx, bankID)
+}
+
+func (s *bankService) DeleteBank(ctx context.Context, scope BankScope, bankID uuid.UUID) error {
+	if _, err := s.findBank(ctx, scope, bankID); err != nil {
+		return err
+	}
+	return s.repo.DeleteBank(ctx, bankID)
+}
+
+func (s *bankService) CreateQuestion(ctx context.Context, scope BankScope, bankID uuid.UUID, input QuestionInput) (*entities.Question, error) {
+	if _, err := s.findBank(ctx, scope, bankID); err != nil {
+		return nil, err
+	}
+
+	question, err := newQuestion(input)
+	if err != nil {
+		return nil, err
+	}
+	question.BankID = &bankID
+
+	if question.Position == nil {
+		maxPosition, err := s.repo.GetMaxBankQuestionPosition(ctx, bankID)
+		if err != nil {
+			return nil, err
+		}
+		position := maxPosition + 1
+		question.Position = &position
+	}
+
+	if err := s.quizRepo.CreateQuestion(ctx, question); err != nil {
+		return nil, err
+	}
+	return question, nil
+}
+
+func (s *bankService) UpdateQuestion(ctx context.Context, scope BankScope, bankID, questionID uuid.UUID, input QuestionInput) (*entities.Question, error) {
+	question, err := s.findQuestion(ctx, scope, bankID, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Position == nil {
+		input.Position = question.Position
+	}
+	if err := applyQuestionInput(question, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.quizRepo.UpdateQuestion(ctx, question); err != nil {
+		return nil, err
+	}
+	return s.quizRepo.GetQuestionByID(ctx, questionID)
+}
+
+func (s *bankService) DeleteQuestion(ctx context.Context, scope BankScope, bankID, questionID uuid.UUID) error {
+	if _, err := s.findQuestion(ctx, scope, bankID, questionID); err != nil {
+		return err
+	}
+	return s.quizRepo.DeleteQuestion(ctx, questionID)
+}
+
+func (s *bankService) CreateChoice(ctx context.Context, scope BankScope, bankID, questionID uuid.UUID, input ChoiceInput) (*entities.Choice, error) {
+	question, err := s.findQuestion(ctx, scope, bankID, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	choice, err := appendChoice(question, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.quizRepo.CreateChoice(ctx, choice); err != nil {
+		return nil, err
+	}
+	return choice, nil
+}
+
+func (s *bankService) UpdateChoice(ctx context.Context, scope BankScope, bankID, questionID, choiceID uuid.UUID, input ChoiceInput) (*entities.Choice, error) {
+	question, err := s.findQuestion(ctx, scope, bankID, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	index := choiceIndex(question, choiceID)
+	if index < 0 {
+		return nil, ErrChoiceNotFound
+	}
+
+	choice := question.Choices[index]
+	if input.Position == nil {
+		input.Position = choice.Position
+	}
+	if err := applyChoiceInput(&choice, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.quizRepo.UpdateChoice(ctx, &choice); err != nil {
+		return nil, err
+	}
+	return s.quizRepo.GetChoiceByID(ctx, choiceID)
+}
+
+func (s *bankService) DeleteChoice(ctx context.Context, scope BankScope, bankID, questionID, choiceID uuid.UUID) error {
+	question, err := s.findQuestion(ctx, scope, bankID, questionID)
+	if err != nil {
+		return err
+	}
+	if choiceIndex(question, choiceID) < 0 {
+		return ErrChoiceNotFound
+	}
+	return s.quizRepo.DeleteChoice(ctx, choiceID)
+}
+
+func applyBankInput(bank *entities.QuestionBank, input BankInput) error {
+	title := strings.TrimSpace(input.Title)
+	if title == "" {
+		return InvalidInput("title is required")
+	}
+
+	bank.Title = title
+	bank.Description = strings.TrimSpace(input.Description)
+	return nil
+}
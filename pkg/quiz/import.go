@@ -0,0 +1,645 @@
+package quiz
+
+import (
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/utils"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type ImportFormat string
+
+const (
+	ImportFormatGIFT  ImportFormat = "gift"
+	ImportFormatAiken ImportFormat = "aiken"
+	ImportFormatCSV   ImportFormat = "csv"
+)
+
+// MaxImportSize membatasi ukuran file import (2 MB)
+const MaxImportSize = 2 << 20
+
+var (
+	ErrUnknownImportFormat = errors.New("import format must be gift, aiken, csv or qti")
+	ErrImportTooLarge      = errors.New("import file exceeds the maximum size of 2 MB")
+)
+
+// ParsedQuestion adalah soal hasil parse beserta nomor baris awalnya di file,
+// atau nama file item untuk paket QTI
+type ParsedQuestion struct {
+	Line  int
+	File  string
+	Input QuestionInput
+}
+
+// fieldError menunjuk lokasi soal di file import
+func (p ParsedQuestion) fieldError(messages ...string) utils.FieldError {
+	if p.File != "" {
+		return fileError(p.File, messages...)
+	}
+	return lineError(p.Line, messages...)
+}
+
+// ParseQuestions mengubah isi file menjadi QuestionInput. Kesalahan dikumpulkan per baris
+// sebagai utils.FieldError dengan Field "line N" (nama file item untuk QTI) sehingga
+// semua bisa diperbaiki sekaligus.
+func ParseQuestions(format ImportFormat, data []byte) ([]ParsedQuestion, []utils.FieldError, error) {
+	// BOM dari Excel/Notepad tidak boleh ikut menjadi teks soal
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	switch format {
+	case ImportFormatGIFT:
+		parsed, errs := parseGIFT(data)
+		return parsed, errs, nil
+	case ImportFormatAiken:
+		parsed, errs := parseAiken(data)
+		return parsed, errs, nil
+	case ImportFormatCSV:
+		parsed, errs := parseCSV(data)
+		return parsed, errs, nil
+	case ImportFormatQTI:
+		parsed, errs := parseQTI(data)
+		return parsed, errs, nil
+	default:
+		return nil, nil, ErrUnknownImportFormat
+	}
+}
+
+func lineError(line int, messages ...string) utils.FieldError {
+	return utils.FieldError{
+		Field:    fmt.Sprintf("line %d", line),
+		Message:  messages[0],
+		Messages: messages,
+	}
+}
+
+func trueFalseChoices(answer bool) []ChoiceInput {
+	return []ChoiceInput{
+		{Text: "True", IsCorrect: answer},
+		{Text: "False", IsCorrect: !answer},
+	}
+}
+
+//
+// GIFT (Moodle)
+//
+
+var giftFormatPrefix = regexp.MustCompile(`^\[(html|moodle|plain|markdown)\]`)
+
+// parseGIFT mendukung pilihan ganda (= benar, ~ salah, ~%50% bobot), benar/salah ({T} {F}),
+// jawaban singkat ({=a =b}), numerik ({#3.14:0.01} atau {#1..5}) dan essay ({}).
+// Soal dipisah baris kosong; $CATEGORY menjadi tag soal-soal sesudahnya.
+func parseGIFT(data []byte) ([]ParsedQuestion, []utils.FieldError) {
+	var (
+		parsed   []ParsedQuestion
+		errs     []utils.FieldError
+		category []string
+		block    []string
+		start    int
+	)
+
+	flush := func() {
+		if len(block) == 0 {
+			return
+		}
+		input, err := parseGIFTQuestion(strings.Join(block, "\n"))
+		if err != nil {
+			errs = append(errs, lineError(start, err.Error()))
+		} else {
+			input.Tags = category
+			parsed = append(parsed, ParsedQuestion{Line: start, Input: input})
+		}
+		block = nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		trimmed := strings.TrimSpace(text)
+
+		switch {
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "//"):
+			// komentar
+		case strings.HasPrefix(trimmed, "$CATEGORY:"):
+			flush()
+			category = giftCategoryTags(strings.TrimPrefix(trimmed, "$CATEGORY:"))
+		default:
+			if len(block) == 0 {
+				start = line
+			}
+			block = append(block, text)
+		}
+	}
+	flush()
+
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, utils.FieldError{Field: "file", Message: err.Error(), Messages: []string{err.Error()}})
+	}
+	return parsed, errs
+}
+
+// giftCategoryTags memakai segmen terakhir category ("$course$/Aljabar/Linear" menjadi "linear")
+func giftCategoryTags(category string) []string {
+	segments := strings.Split(strings.TrimSpace(category), "/")
+	last := strings.TrimSpace(segments[len(segments)-1])
+	if last == "" || strings.HasPrefix(last, "$") {
+		return nil
+	}
+	return normalizeTags([]string{last})
+}
+
+func parseGIFTQuestion(block string) (QuestionInput, error) {
+	body := strings.TrimSpace(block)
+
+	// judul opsional ::judul::
+	if strings.HasPrefix(body, "::") {
+		end := indexUnescaped(body[2:], "::")
+		if end < 0 {
+			return QuestionInput{}, errors.New("question title is not closed with ::")
+		}
+		body = strings.TrimSpace(body[2+end+2:])
+	}
+	body = giftFormatPrefix.ReplaceAllString(body, "")
+
+	open := indexUnescaped(body, "{")
+	if open < 0 {
+		return QuestionInput{}, errors.New("missing answer block {...}")
+	}
+	closing := indexUnescaped(body[open+1:], "}")
+	if closing < 0 {
+		return QuestionInput{}, errors.New("answer block is not closed with }")
+	}
+	closing += open + 1
+
+	before := strings.TrimSpace(body[:open])
+	after := strings.TrimSpace(body[closing+1:])
+	text := giftUnescape(before)
+	if after != "" {
+		// format missing word: jawaban berada di tengah kalimat
+		text = strings.TrimSpace(text + " _____ " + giftUnescape(after))
+	}
+	if text == "" {
+		return QuestionInput{}, errors.New("question text is required")
+	}
+
+	input, err := parseGIFTAnswers(strings.TrimSpace(body[open+1 : closing]))
+	if err != nil {
+		return QuestionInput{}, err
+	}
+	input.Text = text
+	return input, nil
+}
+
+func parseGIFTAnswers(block string) (QuestionInput, error) {
+	if block == "" {
+		return QuestionInput{Type: string(entities.QuestionTypeEssay)}, nil
+	}
+
+	if strings.HasPrefix(block, "#") {
+		return parseGIFTNumeric(block[1:])
+	}
+
+	items := splitGIFTAnswers(block)
+	if len(items) == 0 {
+		// {T}, {TRUE}, {F}, {FALSE} dengan feedback opsional
+		switch strings.ToUpper(strings.TrimSpace(giftStripFeedback(block))) {
+		case "T", "TRUE":
+			return QuestionInput{Type: string(entities.QuestionTypeTrueFalse), Choices: trueFalseChoices(true)}, nil
+		case "F", "FALSE":
+			return QuestionInput{Type: string(entities.QuestionTypeTrueFalse), Choices: trueFalseChoices(false)}, nil
+		}
+		return QuestionInput{}, fmt.Errorf("unrecognized answer block {%s}", block)
+	}
+
+	hasWrong, weighted := false, false
+	for _, item := range items {
+		if item.marker == '~' {
+			hasWrong = true
+		}
+		if item.weight != nil {
+			weighted = true
+		}
+		if strings.Contains(item.text, "->") {
+			return QuestionInput{}, errors.New("matching questions are not supported")
+		}
+	}
+
+	// hanya jawaban "=" tanpa pengecoh: jawaban singkat
+	if !hasWrong && !weighted {
+		input := QuestionInput{Type: string(entities.QuestionTypeShortAnswer)}
+		for _, item := range items {
+			input.AcceptedAnswers = append(input.AcceptedAnswers, item.text)
+		}
+		return input, nil
+	}
+
+	input := QuestionInput{Type: string(entities.QuestionTypeSingleChoice)}
+	correct := 0
+	for _, item := range items {
+		isCorrect := item.marker == '='
+		if item.weight != nil {
+			isCorrect = *item.weight > 0
+		}
+		if isCorrect {
+			correct++
+		}
+		input.Choices = append(input.Choices, ChoiceInput{Text: item.text, IsCorrect: isCorrect})
+	}
+	if correct > 1 {
+		input.Type = string(entities.QuestionTypeMultipleChoice)
+	}
+	return input, nil
+}
+
+// parseGIFTNumeric: "3.14:0.01", "1..5" atau beberapa "=..." (yang pertama dipakai)
+func parseGIFTNumeric(block string) (QuestionInput, error) {
+	value := giftStripFeedback(block)
+	if items := splitGIFTAnswers(block); len(items) > 0 {
+		value = items[0].text
+	}
+	value = strings.TrimSpace(value)
+
+	var answer, tolerance float64
+	var err error
+	switch {
+	case strings.Contains(value, ".."):
+		parts := strings.SplitN(value, "..", 2)
+		min, errMin := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+		max, errMax := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if errMin != nil || errMax != nil || min > max {
+			return QuestionInput{}, fmt.Errorf("invalid numeric range %q", value)
+		}
+		answer, tolerance = (min+max)/2, (max-min)/2
+	case strings.Contains(value, ":"):
+		parts := strings.SplitN(value, ":", 2)
+		answer, err = strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+		if err == nil {
+			tolerance, err = strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		}
+		if err != nil {
+			return QuestionInput{}, fmt.Errorf("invalid numeric answer %q", value)
+		}
+	default:
+		answer, err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			return QuestionInput{}, fmt.Errorf("invalid numeric answer %q", value)
+		}
+	}
+
+	return QuestionInput{
+		Type:             string(entities.QuestionTypeNumeric),
+		NumericAnswer:    &answer,
+		NumericTolerance: &tolerance,
+	}, nil
+}
+
+type giftAnswer struct {
+	marker byte
+	weight *float64
+	text   string
+}
+
+// splitGIFTAnswers memecah isi blok jawaban pada "=" dan "~" yang tidak di-escape
+func splitGIFTAnswers(block string) []giftAnswer {
+	var (
+		items   []giftAnswer
+		current *giftAnswer
+		buf     strings.Builder
+	)
+
+	finish := func() {
+		if current == nil {
+			return
+		}
+		text := strings.TrimSpace(giftStripFeedback(buf.String()))
+		if strings.HasPrefix(text, "%") {
+			if end := strings.Index(text[1:], "%"); end >= 0 {
+				if weight, err := strconv.ParseFloat(text[1:end+1], 64); err == nil {
+					current.weight = &weight
+				}
+				text = strings.TrimSpace(text[end+2:])
+			}
+		}
+		current.text = giftUnescape(text)
+		items = append(items, *current)
+		buf.Reset()
+	}
+
+	for i := 0; i < len(block); i++ {
+		ch := block[i]
+		if ch == '\\' && i+1 < len(block) {
+			buf.WriteByte(ch)
+			buf.WriteByte(block[i+1])
+			i++
+			continue
+		}
+		if ch == '=' || ch == '~' {
+			finish()
+			current = &giftAnswer{marker: ch}
+			continue
+		}
+		if current != nil {
+			buf.WriteByte(ch)
+		}
+	}
+	finish()
+	return items
+}
+
+// giftStripFeedback membuang feedback (#...) yang tidak di-escape
+func giftStripFeedback(s string) string {
+	if i := indexUnescaped(s, "#"); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+func indexUnescaped(s, substr string) int {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(s[i:], substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+var giftEscapes = strings.NewReplacer(`\:`, ":", `\~`, "~", `\=`, "=", `\#`, "#", `\{`, "{", `\}`, "}", `\n`, "\n", `\\`, `\`)
+
+func giftUnescape(s string) string {
+	return strings.TrimSpace(giftEscapes.Replace(s))
+}
+
+//
+// Aiken
+//
+
+var (
+	aikenOption = regexp.MustCompile(`^([A-Z])[.)]\s+(.+)$`)
+	aikenAnswer = regexp.MustCompile(`^ANSWER:\s*([A-Z])\s*$`)
+)
+
+// parseAiken: teks soal, pilihan "A." atau "A)", lalu "ANSWER: X"
+func parseAiken(data []byte) ([]ParsedQuestion, []utils.FieldError) {
+	var (
+		parsed  []ParsedQuestion
+		errs    []utils.FieldError
+		text    []string
+		letters []string
+		current QuestionInput
+		start   int
+	)
+
+	reset := func() {
+		text, letters, current, start = nil, nil, QuestionInput{}, 0
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		trimmed := strings.TrimSpace(strings.TrimRight(scanner.Text(), "\r"))
+		if trimmed == "" {
+			continue
+		}
+
+		if match := aikenAnswer.FindStringSubmatch(trimmed); match != nil {
+			switch {
+			case len(text) == 0:
+				errs = append(errs, lineError(line, "ANSWER without a question"))
+			case len(letters) < 2:
+				errs = append(errs, lineError(start, "question must have at least two options"))
+			default:
+				index := indexOf(letters, match[1])
+				if index < 0 {
+					errs = append(errs, lineError(line, fmt.Sprintf("ANSWER %s does not match any option", match[1])))
+					break
+				}
+				current.Choices[index].IsCorrect = true
+				current.Type = string(entities.QuestionTypeSingleChoice)
+				current.Text = strings.Join(text, "\n")
+				parsed = append(parsed, ParsedQuestion{Line: start, Input: current})
+			}
+			reset()
+			continue
+		}
+
+		if match := aikenOption.FindStringSubmatch(trimmed); match != nil && len(text) > 0 {
+			if indexOf(letters, match[1]) >= 0 {
+				errs = append(errs, lineError(line, fmt.Sprintf("option %s is duplicated", match[1])))
+				continue
+			}
+			letters = append(letters, match[1])
+			current.Choices = append(current.Choices, ChoiceInput{Text: match[2]})
+			continue
+		}
+
+		if len(letters) > 0 {
+			errs = append(errs, lineError(line, "expected an option or ANSWER line"))
+			continue
+		}
+		if len(text) == 0 {
+			start = line
+		}
+		text = append(text, trimmed)
+	}
+
+	if len(text) > 0 {
+		errs = append(errs, lineError(start, "question has no ANSWER line"))
+	}
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, utils.FieldError{Field: "file", Message: err.Error(), Messages: []string{err.Error()}})
+	}
+	return parsed, errs
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+//
+// CSV
+//
+
+// parseCSV membaca spreadsheet dengan header. Kolom: question (wajib), type, answer,
+// tolerance, points, tags, serta satu kolom per pilihan yang namanya diawali "option" atau "choice".
+// answer berisi huruf/nomor pilihan benar ("B" atau "A;C"), true/false, jawaban singkat
+// dipisah "|", atau angka untuk soal numerik.
+func parseCSV(data []byte) ([]ParsedQuestion, []utils.FieldError) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, []utils.FieldError{lineError(1, "file is empty")}
+		}
+		return nil, []utils.FieldError{csvError(err)}
+	}
+
+	columns := map[string]int{}
+	var optionColumns []int
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if strings.HasPrefix(name, "option") || strings.HasPrefix(name, "choice") {
+			optionColumns = append(optionColumns, i)
+			continue
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["question"]; !ok {
+		return nil, []utils.FieldError{lineError(1, "header must have a question column")}
+	}
+
+	cell := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var (
+		parsed []ParsedQuestion
+		errs   []utils.FieldError
+	)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			errs = append(errs, csvError(err))
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+
+		var options []string
+		for _, i := range optionColumns {
+			if i < len(record) && strings.TrimSpace(record[i]) != "" {
+				options = append(options, strings.TrimSpace(record[i]))
+			}
+		}
+		if cell(record, "question") == "" && len(options) == 0 {
+			continue // baris kosong
+		}
+
+		input, err := csvQuestion(cell(record, "type"), cell(record, "answer"), cell(record, "tolerance"), options)
+		if err != nil {
+			errs = append(errs, lineError(line, err.Error()))
+			continue
+		}
+		input.Text = cell(record, "question")
+		if points := cell(record, "points"); points != "" {
+			value, err := strconv.ParseFloat(points, 64)
+			if err != nil {
+				errs = append(errs, lineError(line, fmt.Sprintf("points must be a number, got %q", points)))
+				continue
+			}
+			input.Points = &value
+		}
+		if tags := cell(record, "tags"); tags != "" {
+			input.Tags = strings.Split(tags, ";")
+		}
+		parsed = append(parsed, ParsedQuestion{Line: line, Input: input})
+	}
+	return parsed, errs
+}
+
+func csvError(err error) utils.FieldError {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return lineError(parseErr.Line, parseErr.Err.Error())
+	}
+	return utils.FieldError{Field: "file", Message: err.Error(), Messages: []string{err.Error()}}
+}
+
+func csvQuestion(typeName, answer, tolerance string, options []string) (QuestionInput, error) {
+	questionType := entities.QuestionType(strings.ToUpper(typeName))
+	if questionType == "" {
+		questionType = entities.QuestionTypeSingleChoice
+	}
+	if !questionType.IsValid() {
+		return QuestionInput{}, fmt.Errorf("unknown question type %s", typeName)
+	}
+	input := QuestionInput{Type: string(questionType)}
+
+	switch questionType {
+	case entities.QuestionTypeTrueFalse:
+		value, err := strconv.ParseBool(strings.ToLower(answer))
+		if err != nil {
+			return QuestionInput{}, fmt.Errorf("true/false answer must be true or false, got %q", answer)
+		}
+		input.Choices = trueFalseChoices(value)
+	case entities.QuestionTypeSingleChoice, entities.QuestionTypeMultipleChoice:
+		for _, option := range options {
+			input.Choices = append(input.Choices, ChoiceInput{Text: option})
+		}
+		for _, key := range strings.FieldsFunc(answer, func(r rune) bool { return r == ';' || r == ',' }) {
+			index, err := optionIndex(strings.TrimSpace(key), len(options))
+			if err != nil {
+				return QuestionInput{}, err
+			}
+			input.Choices[index].IsCorrect = true
+		}
+	case entities.QuestionTypeShortAnswer:
+		input.AcceptedAnswers = strings.Split(answer, "|")
+	case entities.QuestionTypeNumeric:
+		value, err := strconv.ParseFloat(answer, 64)
+		if err != nil {
+			return QuestionInput{}, fmt.Errorf("numeric answer must be a number, got %q", answer)
+		}
+		input.NumericAnswer = &value
+		if tolerance != "" {
+			tol, err := strconv.ParseFloat(tolerance, 64)
+			if err != nil {
+				return QuestionInput{}, fmt.Errorf("tolerance must be a number, got %q", tolerance)
+			}
+			input.NumericTolerance = &tol
+		}
+	}
+	return input, nil
+}
+
+// optionIndex menerima huruf (A, B, ...) atau nomor (1, 2, ...) pilihan
+func optionIndex(key string, count int) (int, error) {
+	index := -1
+	if n, err := strconv.Atoi(key); err == nil {
+		index = n - 1
+	} else if len(key) == 1 {
+		index = int(strings.ToUpper(key)[0] - 'A')
+	}
+	if index < 0 || index >= count {
+		return 0, fmt.Errorf("answer %q does not match any option", key)
+	}
+	return index, nil
+}
+
+// sortLineErrors mengurutkan kesalahan parse dan validasi berdasarkan nomor baris
+func sortLineErrors(errs []utils.FieldError) {
+	line := func(e utils.FieldError) int {
+		var n int
+		fmt.Sscanf(e.Field, "line %d", &n)
+		return n
+	}
+	sort.SliceStable(errs, func(i, j int) bool { return line(errs[i]) < line(errs[j]) })
+}
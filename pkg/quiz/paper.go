@@ -0,0 +1,124 @@
+package quiz
+
+import (
+	"api-shiners/pkg/entities"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"math/rand"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// UsableQuestions menyaring soal bank yang lengkap sehingga aman diundi ke paper student
+func UsableQuestions(questions []entities.Question) []entities.Question {
+	usable := make([]entities.Question, 0, len(questions))
+	for i := range questions {
+		if len(validateQuestion(0, &questions[i])) == 0 {
+			usable = append(usable, questions[i])
+		}
+	}
+	return usable
+}
+
+// LoadPoolCandidates memuat soal bank yang bisa diundi untuk setiap pool quiz, per PoolID
+func LoadPoolCandidates(ctx context.Context, repo QuizRepository, q *entities.Quiz) (map[uuid.UUID][]entities.Question, error) {
+	candidates := make(map[uuid.UUID][]entities.Question, len(q.Pools))
+	for _, pool := range q.Pools {
+		questions, err := repo.GetPoolQuestions(ctx, pool.BankID, pool.Tags)
+		if err != nil {
+			return nil, err
+		}
+		candidates[pool.ID] = UsableQuestions(questions)
+	}
+	return candidates, nil
+}
+
+// DrawPaper menyusun paper satu attempt: soal tetap quiz lalu hasil undian tiap pool,
+// diacak bila ShuffleQuestions. Hasilnya selalu sama untuk seed dan kandidat yang sama.
+func DrawPaper(q *entities.Quiz, candidates map[uuid.UUID][]entities.Question, seed int64) []uuid.UUID {
+	rng := rand.New(rand.NewSource(seed))
+
+	picked := make(map[uuid.UUID]bool)
+	ids := make([]uuid.UUID, 0, len(q.Questions))
+	for _, question := range q.Questions {
+		picked[question.ID] = true
+		ids = append(ids, question.ID)
+	}
+
+	for _, pool := range q.Pools {
+		// urutkan dulu agar hasil undian tidak bergantung urutan dari database
+		poolCandidates := sortedIDs(candidates[pool.ID])
+		rng.Shuffle(len(poolCandidates), func(i, j int) {
+			poolCandidates[i], poolCandidates[j] = poolCandidates[j], poolCandidates[i]
+		})
+
+		drawn := 0
+		for _, id := range poolCandidates {
+			if drawn == pool.DrawCount {
+				break
+			}
+			// soal yang sama bisa cocok dengan lebih dari satu pool
+			if picked[id] {
+				continue
+			}
+			picked[id] = true
+			ids = append(ids, id)
+			drawn++
+		}
+	}
+
+	if q.ShuffleQuestions {
+		rng.Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+	}
+	return ids
+}
+
+// BuildPaper mengembalikan soal sesuai urutan paper attempt. bankQuestions berisi soal
+// hasil undian yang bukan milik quiz. Paper kosong (attempt lama) memakai semua soal quiz.
+func BuildPaper(q *entities.Quiz, questionIDs []uuid.UUID, bankQuestions []entities.Question, seed int64) []entities.Question {
+	if len(questionIDs) == 0 {
+		return q.Questions
+	}
+
+	byID := make(map[uuid.UUID]entities.Question, len(q.Questions)+len(bankQuestions))
+	for _, question := range bankQuestions {
+		byID[question.ID] = question
+	}
+	for _, question := range q.Questions {
+		byID[question.ID] = question
+	}
+
+	paper := make([]entities.Question, 0, len(questionIDs))
+	for _, id := range questionIDs {
+		// soal yang sudah dihapus dilewati
+		question, ok := byID[id]
+		if !ok {
+			continue
+		}
+		if q.ShuffleChoices && len(question.Choices) > 1 {
+			question.Choices = shuffledChoices(question, seed)
+		}
+		paper = append(paper, question)
+	}
+	return paper
+}
+
+// shuffledChoices mengacak pilihan dengan seed attempt yang dicampur ID soal,
+// sehingga urutannya bisa dibentuk ulang saat review
+func shuffledChoices(question entities.Question, seed int64) []entities.Choice {
+	choices := append([]entities.Choice(nil), question.Choices...)
+	rng := rand.New(rand.NewSource(seed ^ int64(binary.BigEndian.Uint64(question.ID[:8]))))
+	rng.Shuffle(len(choices), func(i, j int) { choices[i], choices[j] = choices[j], choices[i] })
+	return choices
+}
+
+func sortedIDs(questions []entities.Question) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(questions))
+	for _, question := range questions {
+		ids = append(ids, question.ID)
+	}
+	sort.Slice(ids, func(i, j int) bool { return bytes.Compare(ids[i][:], ids[j][:]) < 0 })
+	return ids
+}
@@ -0,0 +1,750 @@
+package quiz
+
+import (
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/grading"
+	"api-shiners/pkg/utils"
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Paket QTI 2.1: imsmanifest.xml, assessmentTest.xml dan satu file per soal di items/.
+// Tipe soal disimpan di atribut label assessmentItem agar TRUE_FALSE tidak berubah
+// menjadi SINGLE_CHOICE saat diimpor kembali.
+
+const (
+	ImportFormatQTI ImportFormat = "qti"
+
+	qtiNamespace      = "http://www.imsglobal.org/xsd/imsqti_v2p1"
+	qtiSchemaLocation = "http://www.imsglobal.org/xsd/imsqti_v2p1 http://www.imsglobal.org/xsd/qti/qtiv2p1/imsqti_v2p1.xsd"
+	qtiCPNamespace    = "http://www.imsglobal.org/xsd/imscp_v1p1"
+	qtiTemplateMatch  = "http://www.imsglobal.org/question/qti_v2p1/rptemplates/match_correct"
+	qtiTemplateMap    = "http://www.imsglobal.org/question/qti_v2p1/rptemplates/map_response"
+	qtiResponseID     = "RESPONSE"
+	qtiTestFile       = "assessmentTest.xml"
+	qtiManifestFile   = "imsmanifest.xml"
+	qtiResourceTest   = "imsqti_test_xmlv2p1"
+	qtiResourceItem   = "imsqti_item_xmlv2p1"
+
+	// qtiMaxEntrySize membatasi ukuran file di dalam zip (mencegah zip bomb)
+	qtiMaxEntrySize = 4 << 20
+	// qtiMaxTotalSize membatasi total ukuran seluruh isi zip setelah diekstrak
+	qtiMaxTotalSize = 32 << 20
+	// qtiMaxItems membatasi jumlah soal dalam satu paket
+	qtiMaxItems = 500
+)
+
+var (
+	ErrInvalidQTIPackage  = errors.New("file is not a valid QTI 2.1 package")
+	ErrQTIPackageTooLarge = fmt.Errorf("package content exceeds %d MB when extracted", qtiMaxTotalSize>>20)
+	ErrQTITooManyItems    = fmt.Errorf("package can contain at most %d items", qtiMaxItems)
+)
+
+//
+// Export
+//
+
+type qtiValue struct {
+	Value string `xml:",chardata"`
+}
+
+type qtiMapEntry struct {
+	MapKey        string  `xml:"mapKey,attr"`
+	MappedValue   float64 `xml:"mappedValue,attr"`
+	CaseSensitive bool    `xml:"caseSensitive,attr"`
+}
+
+type qtiMapping struct {
+	DefaultValue float64       `xml:"defaultValue,attr"`
+	Entries      []qtiMapEntry `xml:"mapEntry"`
+}
+
+type qtiResponseDeclaration struct {
+	Identifier      string      `xml:"identifier,attr"`
+	Cardinality     string      `xml:"cardinality,attr"`
+	BaseType        string      `xml:"baseType,attr"`
+	CorrectResponse []qtiValue  `xml:"correctResponse>value,omitempty"`
+	Mapping         *qtiMapping `xml:"mapping,omitempty"`
+}
+
+type qtiOutcomeDeclaration struct {
+	Identifier   string     `xml:"identifier,attr"`
+	Cardinality  string     `xml:"cardinality,attr"`
+	BaseType     string     `xml:"baseType,attr"`
+	DefaultValue []qtiValue `xml:"defaultValue>value,omitempty"`
+}
+
+type qtiSimpleChoice struct {
+	Identifier string `xml:"identifier,attr"`
+	Text       string `xml:",chardata"`
+}
+
+type qtiChoiceInteraction struct {
+	ResponseIdentifier string            `xml:"responseIdentifier,attr"`
+	Shuffle            bool              `xml:"shuffle,attr"`
+	MaxChoices         int               `xml:"maxChoices,attr"`
+	Choices            []qtiSimpleChoice `xml:"simpleChoice"`
+}
+
+type qtiTextEntryInteraction struct {
+	ResponseIdentifier string `xml:"responseIdentifier,attr"`
+	ExpectedLength     int    `xml:"expectedLength,attr"`
+}
+
+type qtiExtendedTextInteraction struct {
+	ResponseIdentifier string `xml:"responseIdentifier,attr"`
+}
+
+// textEntryInteraction adalah interaksi inline sehingga harus berada di dalam blok
+type qtiInlineBlock struct {
+	TextEntry *qtiTextEntryInteraction `xml:"textEntryInteraction"`
+}
+
+type qtiItemBody struct {
+	Paragraphs   []string                    `xml:"p"`
+	Choice       *qtiChoiceInteraction       `xml:"choiceInteraction,omitempty"`
+	Inline       *qtiInlineBlock             `xml:"div,omitempty"`
+	ExtendedText *qtiExtendedTextInteraction `xml:"extendedTextInteraction,omitempty"`
+}
+
+type qtiResponseProcessing struct {
+	Template string `xml:"template,attr,omitempty"`
+	Inner    string `xml:",innerxml"`
+}
+
+type qtiAssessmentItem struct {
+	XMLName             xml.Name                `xml:"assessmentItem"`
+	Xmlns               string                  `xml:"xmlns,attr"`
+	XmlnsXsi            string                  `xml:"xmlns:xsi,attr"`
+	SchemaLocation      string                  `xml:"xsi:schemaLocation,attr"`
+	Identifier          string                  `xml:"identifier,attr"`
+	Title               string                  `xml:"title,attr"`
+	Label               string                  `xml:"label,attr"`
+	Adaptive            bool                    `xml:"adaptive,attr"`
+	TimeDependent       bool                    `xml:"timeDependent,attr"`
+	ResponseDeclaration qtiResponseDeclaration  `xml:"responseDeclaration"`
+	OutcomeDeclarations []qtiOutcomeDeclaration `xml:"outcomeDeclaration"`
+	ItemBody            qtiItemBody             `xml:"itemBody"`
+	ResponseProcessing  *qtiResponseProcessing  `xml:"responseProcessing,omitempty"`
+}
+
+type qtiItemRef struct {
+	Identifier string `xml:"identifier,attr"`
+	Href       string `xml:"href,attr"`
+}
+
+type qtiAssessmentTest struct {
+	XMLName        xml.Name `xml:"assessmentTest"`
+	Xmlns          string   `xml:"xmlns,attr"`
+	XmlnsXsi       string   `xml:"xmlns:xsi,attr"`
+	SchemaLocation string   `xml:"xsi:schemaLocation,attr"`
+	Identifier     string   `xml:"identifier,attr"`
+	Title          string   `xml:"title,attr"`
+	TestPart       struct {
+		Identifier     string `xml:"identifier,attr"`
+		NavigationMode string `xml:"navigationMode,attr"`
+		SubmissionMode string `xml:"submissionMode,attr"`
+		Section        struct {
+			Identifier string       `xml:"identifier,attr"`
+			Title      string       `xml:"title,attr"`
+			Visible    bool         `xml:"visible,attr"`
+			Ordering   *qtiOrdering `xml:"ordering,omitempty"`
+			ItemRefs   []qtiItemRef `xml:"assessmentItemRef"`
+		} `xml:"assessmentSection"`
+	} `xml:"testPart"`
+}
+
+type qtiOrdering struct {
+	Shuffle bool `xml:"shuffle,attr"`
+}
+
+type qtiFileRef struct {
+	Href string `xml:"href,attr"`
+}
+
+type qtiDependency struct {
+	IdentifierRef string `xml:"identifierref,attr"`
+}
+
+type qtiResource struct {
+	Identifier   string          `xml:"identifier,attr"`
+	Type         string          `xml:"type,attr"`
+	Href         string          `xml:"href,attr"`
+	File         qtiFileRef      `xml:"file"`
+	Dependencies []qtiDependency `xml:"dependency,omitempty"`
+}
+
+type qtiManifest struct {
+	XMLName       xml.Name      `xml:"manifest"`
+	Xmlns         string        `xml:"xmlns,attr"`
+	Identifier    string        `xml:"identifier,attr"`
+	Organizations struct{}      `xml:"organizations"`
+	Resources     []qtiResource `xml:"resources>resource"`
+}
+
+// WriteQTI menulis soal tetap quiz (tanpa soal pool dari bank) sebagai paket zip QTI 2.1
+func WriteQTI(w io.Writer, q *entities.Quiz) error {
+	archive := zip.NewWriter(w)
+
+	test := qtiAssessmentTest{
+		Xmlns:          qtiNamespace,
+		XmlnsXsi:       "http://www.w3.org/2001/XMLSchema-instance",
+		SchemaLocation: qtiSchemaLocation,
+		Identifier:     "test-" + q.ID.String(),
+		Title:          q.Title,
+	}
+	test.TestPart.Identifier = "part-1"
+	test.TestPart.NavigationMode = "nonlinear"
+	test.TestPart.SubmissionMode = "simultaneous"
+	test.TestPart.Section.Identifier = "section-1"
+	test.TestPart.Section.Title = q.Title
+	test.TestPart.Section.Visible = true
+	if q.ShuffleQuestions {
+		test.TestPart.Section.Ordering = &qtiOrdering{Shuffle: true}
+	}
+
+	manifest := qtiManifest{Xmlns: qtiCPNamespace, Identifier: "manifest-" + q.ID.String()}
+	testResource := qtiResource{Identifier: "resource-test", Type: qtiResourceTest, Href: qtiTestFile, File: qtiFileRef{Href: qtiTestFile}}
+
+	for i, question := range q.Questions {
+		identifier := "item-" + question.ID.String()
+		href := fmt.Sprintf("items/item-%03d.xml", i+1)
+
+		item, err := toQTIItem(identifier, question, q.ShuffleChoices)
+		if err != nil {
+			return err
+		}
+		if err := writeXML(archive, href, item); err != nil {
+			return err
+		}
+
+		test.TestPart.Section.ItemRefs = append(test.TestPart.Section.ItemRefs, qtiItemRef{Identifier: identifier, Href: href})
+		manifest.Resources = append(manifest.Resources, qtiResource{Identifier: "resource-" + identifier, Type: qtiResourceItem, Href: href, File: qtiFileRef{Href: href}})
+		testResource.Dependencies = append(testResource.Dependencies, qtiDependency{IdentifierRef: "resource-" + identifier})
+	}
+	manifest.Resources = append([]qtiResource{testResource}, manifest.Resources...)
+
+	if err := writeXML(archive, qtiTestFile, test); err != nil {
+		return err
+	}
+	if err := writeXML(archive, qtiManifestFile, manifest); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+func writeXML(archive *zip.Writer, name string, v interface{}) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(file, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(file)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+func toQTIItem(identifier string, question entities.Question, shuffleChoices bool) (*qtiAssessmentItem, error) {
+	item := &qtiAssessmentItem{
+		Xmlns:          qtiNamespace,
+		XmlnsXsi:       "http://www.w3.org/2001/XMLSchema-instance",
+		SchemaLocation: qtiSchemaLocation,
+		Identifier:     identifier,
+		Title:          qtiTitle(question.Text),
+		Label:          string(question.Type),
+		OutcomeDeclarations: []qtiOutcomeDeclaration{
+			{Identifier: "SCORE", Cardinality: "single", BaseType: "float", DefaultValue: []qtiValue{{Value: "0"}}},
+			{Identifier: "MAXSCORE", Cardinality: "single", BaseType: "float", DefaultValue: []qtiValue{{Value: formatQTIFloat(grading.MaxPoints(question))}}},
+		},
+		ItemBody: qtiItemBody{Paragraphs: []string{question.Text}},
+	}
+	response := qtiResponseDeclaration{Identifier: qtiResponseID, Cardinality: "single", BaseType: "identifier"}
+
+	switch question.Type {
+	case entities.QuestionTypeSingleChoice, entities.QuestionTypeTrueFalse, entities.QuestionTypeMultipleChoice:
+		interaction := &qtiChoiceInteraction{ResponseIdentifier: qtiResponseID, Shuffle: shuffleChoices, MaxChoices: 1}
+		if question.Type == entities.QuestionTypeMultipleChoice {
+			response.Cardinality = "multiple"
+			interaction.MaxChoices = 0
+		}
+		for i, c := range question.Choices {
+			choiceID := fmt.Sprintf("choice-%d", i+1)
+			interaction.Choices = append(interaction.Choices, qtiSimpleChoice{Identifier: choiceID, Text: c.Text})
+			if c.IsCorrect {
+				response.CorrectResponse = append(response.CorrectResponse, qtiValue{Value: choiceID})
+			}
+		}
+		item.ItemBody.Choice = interaction
+		item.ResponseProcessing = &qtiResponseProcessing{Template: qtiTemplateMatch}
+
+	case entities.QuestionTypeShortAnswer:
+		response.BaseType = "string"
+		if len(question.AcceptedAnswers) > 0 {
+			response.CorrectResponse = []qtiValue{{Value: question.AcceptedAnswers[0]}}
+			response.Mapping = &qtiMapping{}
+			for _, accepted := range question.AcceptedAnswers {
+				response.Mapping.Entries = append(response.Mapping.Entries, qtiMapEntry{MapKey: accepted, MappedValue: 1})
+			}
+		}
+		item.ItemBody.Inline = &qtiInlineBlock{TextEntry: &qtiTextEntryInteraction{ResponseIdentifier: qtiResponseID, ExpectedLength: 30}}
+		if question.AnswerPattern == "" {
+			item.ResponseProcessing = &qtiResponseProcessing{Template: qtiTemplateMap}
+		} else {
+			item.ResponseProcessing = &qtiResponseProcessing{Inner: shortAnswerProcessing(question)}
+		}
+
+	case entities.QuestionTypeNumeric:
+		if question.NumericAnswer == nil {
+			return nil, fmt.Errorf("question %s has no numeric answer", question.ID)
+		}
+		response.BaseType = "float"
+		response.CorrectResponse = []qtiValue{{Value: formatQTIFloat(*question.NumericAnswer)}}
+		item.ItemBody.Inline = &qtiInlineBlock{TextEntry: &qtiTextEntryInteraction{ResponseIdentifier: qtiResponseID, ExpectedLength: 15}}
+		item.ResponseProcessing = &qtiResponseProcessing{Inner: numericProcessing(question.NumericTolerance)}
+
+	case entities.QuestionTypeEssay:
+		// dinilai manual, tanpa kunci jawaban dan responseProcessing
+		response.BaseType = "string"
+		item.ItemBody.ExtendedText = &qtiExtendedTextInteraction{ResponseIdentifier: qtiResponseID}
+
+	default:
+		return nil, fmt.Errorf("question %s has unknown type %s", question.ID, question.Type)
+	}
+
+	item.ResponseDeclaration = response
+	return item, nil
+}
+
+// shortAnswerProcessing: benar bila cocok dengan pola regex atau salah satu jawaban di mapping
+func shortAnswerProcessing(question entities.Question) string {
+	var condition strings.Builder
+	condition.WriteString(`<patternMatch pattern="` + escapeXML(question.AnswerPattern) + `"><variable identifier="RESPONSE"/></patternMatch>`)
+	if len(question.AcceptedAnswers) > 0 {
+		condition.WriteString(`<gt><mapResponse identifier="RESPONSE"/><baseValue baseType="float">0</baseValue></gt>`)
+	}
+	return `<responseCondition><responseIf><or>` + condition.String() + `</or>` +
+		`<setOutcomeValue identifier="SCORE"><baseValue baseType="float">1</baseValue></setOutcomeValue>` +
+		`</responseIf></responseCondition>`
+}
+
+func numericProcessing(tolerance float64) string {
+	value := formatQTIFloat(tolerance)
+	return `<responseCondition><responseIf>` +
+		`<equal toleranceMode="absolute" tolerance="` + value + ` ` + value + `"><variable identifier="RESPONSE"/><correct identifier="RESPONSE"/></equal>` +
+		`<setOutcomeValue identifier="SCORE"><baseValue baseType="float">1</baseValue></setOutcomeValue>` +
+		`</responseIf></responseCondition>`
+}
+
+func escapeXML(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+func formatQTIFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// qtiTitle: atribut title wajib diisi, dipakai potongan teks soal
+func qtiTitle(text string) string {
+	title := strings.Join(strings.Fields(text), " ")
+	if runes := []rune(title); len(runes) > 60 {
+		title = string(runes[:60]) + "..."
+	}
+	return title
+}
+
+//
+// Import
+//
+
+type qtiResponseDoc struct {
+	Identifier  string   `xml:"identifier,attr"`
+	Cardinality string   `xml:"cardinality,attr"`
+	BaseType    string   `xml:"baseType,attr"`
+	Correct     []string `xml:"correctResponse>value"`
+	Mapping     []struct {
+		Key   string  `xml:"mapKey,attr"`
+		Value float64 `xml:"mappedValue,attr"`
+	} `xml:"mapping>mapEntry"`
+}
+
+type qtiItemDoc struct {
+	Label      string                  `xml:"label,attr"`
+	Responses  []qtiResponseDoc        `xml:"responseDeclaration"`
+	Outcomes   []qtiOutcomeDeclaration `xml:"outcomeDeclaration"`
+	Body       qtiInnerXML             `xml:"itemBody"`
+	Processing qtiInnerXML             `xml:"responseProcessing"`
+}
+
+type qtiInnerXML struct {
+	Inner string `xml:",innerxml"`
+}
+
+// qtiInteraction adalah interaksi pertama di itemBody; interaksi lain diabaikan
+type qtiInteraction struct {
+	Kind               string
+	ResponseIdentifier string
+	MaxChoices         int
+	ChoiceIDs          []string
+	ChoiceTexts        []string
+}
+
+// parseQTI membaca paket zip QTI 2.1. Urutan soal mengikuti assessmentTest,
+// atau urutan resource di manifest bila paket tidak berisi test.
+func parseQTI(data []byte) ([]ParsedQuestion, []utils.FieldError) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, []utils.FieldError{fileError("file", ErrInvalidQTIPackage.Error())}
+	}
+
+	// ukuran di header zip dicek ulang saat dibaca, sehingga total ini adalah batas
+	// byte yang bisa didekompresi dari paket
+	var total uint64
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		total += f.UncompressedSize64
+		files[path.Clean(f.Name)] = f
+	}
+	if total > qtiMaxTotalSize {
+		return nil, []utils.FieldError{fileError("file", ErrQTIPackageTooLarge.Error())}
+	}
+
+	hrefs, err := qtiItemHrefs(files)
+	if err != nil {
+		return nil, []utils.FieldError{fileError(qtiManifestFile, err.Error())}
+	}
+	// item yang dirujuk berulang kali hanya dibaca dan diimpor sekali
+	hrefs = uniqueHrefs(hrefs)
+	if len(hrefs) > qtiMaxItems {
+		return nil, []utils.FieldError{fileError("file", ErrQTITooManyItems.Error())}
+	}
+
+	var (
+		parsed []ParsedQuestion
+		errs   []utils.FieldError
+	)
+	for _, href := range hrefs {
+		content, err := readZipFile(files, href)
+		if err != nil {
+			errs = append(errs, fileError(href, err.Error()))
+			continue
+		}
+		input, err := parseQTIItem(content)
+		if err != nil {
+			errs = append(errs, fileError(href, err.Error()))
+			continue
+		}
+		parsed = append(parsed, ParsedQuestion{File: href, Input: input})
+	}
+	return parsed, errs
+}
+
+func uniqueHrefs(hrefs []string) []string {
+	seen := make(map[string]bool, len(hrefs))
+	unique := make([]string, 0, len(hrefs))
+	for _, href := range hrefs {
+		href = path.Clean(href)
+		if !seen[href] {
+			seen[href] = true
+			unique = append(unique, href)
+		}
+	}
+	return unique
+}
+
+func fileError(name string, messages ...string) utils.FieldError {
+	return utils.FieldError{Field: name, Message: messages[0], Messages: messages}
+}
+
+func readZipFile(files map[string]*zip.File, name string) ([]byte, error) {
+	f, ok := files[path.Clean(name)]
+	if !ok {
+		return nil, fmt.Errorf("%s is missing from the package", name)
+	}
+	if f.UncompressedSize64 > qtiMaxEntrySize {
+		return nil, fmt.Errorf("%s is too large", name)
+	}
+	reader, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(io.LimitReader(reader, qtiMaxEntrySize))
+}
+
+func qtiItemHrefs(files map[string]*zip.File) ([]string, error) {
+	content, err := readZipFile(files, qtiManifestFile)
+	if err != nil {
+		return nil, err
+	}
+	var manifest struct {
+		Resources []struct {
+			Type string `xml:"type,attr"`
+			Href string `xml:"href,attr"`
+		} `xml:"resources>resource"`
+	}
+	if err := xml.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %v", err)
+	}
+
+	var itemHrefs []string
+	for _, resource := range manifest.Resources {
+		switch {
+		case strings.HasPrefix(resource.Type, qtiResourceTest):
+			return qtiTestItemHrefs(files, resource.Href)
+		case strings.HasPrefix(resource.Type, qtiResourceItem):
+			itemHrefs = append(itemHrefs, resource.Href)
+		}
+	}
+	if len(itemHrefs) == 0 {
+		return nil, errors.New("manifest does not list any QTI item")
+	}
+	return itemHrefs, nil
+}
+
+// qtiTestItemHrefs mengambil assessmentItemRef sesuai urutan, termasuk di section bertingkat
+func qtiTestItemHrefs(files map[string]*zip.File, testHref string) ([]string, error) {
+	content, err := readZipFile(files, testHref)
+	if err != nil {
+		return nil, err
+	}
+
+	var hrefs []string
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid assessment test: %v", err)
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == "assessmentItemRef" {
+			if href := xmlAttr(start, "href"); href != "" {
+				hrefs = append(hrefs, path.Join(path.Dir(testHref), href))
+			}
+		}
+	}
+	if len(hrefs) == 0 {
+		return nil, errors.New("assessment test does not reference any item")
+	}
+	return hrefs, nil
+}
+
+func parseQTIItem(content []byte) (QuestionInput, error) {
+	var doc qtiItemDoc
+	if err := xml.Unmarshal(content, &doc); err != nil {
+		return QuestionInput{}, fmt.Errorf("invalid assessment item: %v", err)
+	}
+
+	text, interaction, err := scanQTIBody(doc.Body.Inner)
+	if err != nil {
+		return QuestionInput{}, err
+	}
+	if interaction == nil {
+		return QuestionInput{}, errors.New("item has no supported interaction")
+	}
+
+	var response qtiResponseDoc
+	for _, r := range doc.Responses {
+		if r.Identifier == interaction.ResponseIdentifier {
+			response = r
+		}
+	}
+
+	input := QuestionInput{Text: text}
+	// nilai maksimal soal dari MAXSCORE, bila ada
+	for _, outcome := range doc.Outcomes {
+		if outcome.Identifier == "MAXSCORE" && len(outcome.DefaultValue) > 0 {
+			if points, err := strconv.ParseFloat(strings.TrimSpace(outcome.DefaultValue[0].Value), 64); err == nil && points > 0 {
+				input.Points = &points
+			}
+		}
+	}
+	switch interaction.Kind {
+	case "choiceInteraction":
+		input.Type = string(entities.QuestionTypeSingleChoice)
+		if interaction.MaxChoices != 1 || response.Cardinality == "multiple" {
+			input.Type = string(entities.QuestionTypeMultipleChoice)
+		} else if doc.Label == string(entities.QuestionTypeTrueFalse) {
+			input.Type = string(entities.QuestionTypeTrueFalse)
+		}
+		for i, id := range interaction.ChoiceIDs {
+			input.Choices = append(input.Choices, ChoiceInput{
+				Text:      interaction.ChoiceTexts[i],
+				IsCorrect: indexOf(response.Correct, id) >= 0,
+			})
+		}
+
+	case "textEntryInteraction":
+		if response.BaseType == "float" || response.BaseType == "integer" {
+			if len(response.Correct) == 0 {
+				return QuestionInput{}, errors.New("numeric item has no correct response")
+			}
+			answer, err := strconv.ParseFloat(strings.TrimSpace(response.Correct[0]), 64)
+			if err != nil {
+				return QuestionInput{}, fmt.Errorf("invalid numeric correct response %q", response.Correct[0])
+			}
+			tolerance := qtiTolerance(doc.Processing.Inner)
+			input.Type = string(entities.QuestionTypeNumeric)
+			input.NumericAnswer = &answer
+			input.NumericTolerance = &tolerance
+			break
+		}
+
+		input.Type = string(entities.QuestionTypeShortAnswer)
+		for _, entry := range response.Mapping {
+			if entry.Value > 0 {
+				input.AcceptedAnswers = append(input.AcceptedAnswers, entry.Key)
+			}
+		}
+		if len(input.AcceptedAnswers) == 0 {
+			input.AcceptedAnswers = response.Correct
+		}
+		input.AnswerPattern = qtiAttrOf(doc.Processing.Inner, "patternMatch", "pattern")
+
+	case "extendedTextInteraction":
+		input.Type = string(entities.QuestionTypeEssay)
+	}
+	return input, nil
+}
+
+var qtiInteractions = map[string]bool{
+	"choiceInteraction":       true,
+	"textEntryInteraction":    true,
+	"extendedTextInteraction": true,
+}
+
+// scanQTIBody mengambil teks soal (di luar interaksi, termasuk prompt) dan interaksi pertama
+func scanQTIBody(body string) (string, *qtiInteraction, error) {
+	var (
+		text        strings.Builder
+		interaction *qtiInteraction
+		choiceText  *strings.Builder
+		depth       int  // kedalaman di dalam interaksi
+		capturing   bool // sedang berada di interaksi pertama
+		inPrompt    bool
+	)
+
+	decoder := xml.NewDecoder(strings.NewReader("<body>" + body + "</body>"))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid item body: %v", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			name := t.Name.Local
+			if depth > 0 {
+				depth++
+				switch {
+				case name == "prompt":
+					inPrompt = true
+				case name == "simpleChoice" && capturing:
+					interaction.ChoiceIDs = append(interaction.ChoiceIDs, xmlAttr(t, "identifier"))
+					choiceText = &strings.Builder{}
+				}
+				continue
+			}
+			if qtiInteractions[name] {
+				depth = 1
+				capturing = interaction == nil
+				if capturing {
+					interaction = &qtiInteraction{Kind: name, ResponseIdentifier: xmlAttr(t, "responseIdentifier")}
+					interaction.MaxChoices, _ = strconv.Atoi(xmlAttr(t, "maxChoices"))
+				}
+				continue
+			}
+			if name == "br" || name == "p" || name == "div" {
+				text.WriteString("\n")
+			}
+		case xml.EndElement:
+			if depth == 0 {
+				continue
+			}
+			depth--
+			switch t.Name.Local {
+			case "prompt":
+				inPrompt = false
+				text.WriteString("\n")
+			case "simpleChoice":
+				if choiceText != nil {
+					interaction.ChoiceTexts = append(interaction.ChoiceTexts, normalizeQTIText(choiceText.String()))
+					choiceText = nil
+				}
+			}
+		case xml.CharData:
+			switch {
+			case choiceText != nil:
+				choiceText.Write(t)
+			case depth == 0 || inPrompt:
+				text.Write(t)
+			}
+		}
+	}
+	return normalizeQTIText(text.String()), interaction, nil
+}
+
+// normalizeQTIText membuang indentasi XML dan baris kosong
+func normalizeQTIText(s string) string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// qtiTolerance membaca toleransi absolut dari <equal toleranceMode="absolute" tolerance="t">
+func qtiTolerance(processing string) float64 {
+	fields := strings.Fields(qtiAttrOf(processing, "equal", "tolerance"))
+	if len(fields) == 0 {
+		return 0
+	}
+	tolerance, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil || tolerance < 0 {
+		return 0
+	}
+	return tolerance
+}
+
+// qtiAttrOf mencari atribut elemen pertama bernama element
+func qtiAttrOf(fragment, element, attr string) string {
+	decoder := xml.NewDecoder(strings.NewReader("<root>" + fragment + "</root>"))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == element {
+			return xmlAttr(start, attr)
+		}
+	}
+}
+
+func xmlAttr(start xml.StartElement, name string) string {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,278 @@
+package quiz
+
+import (
+	"api-shiners/pkg/entities"
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type QuizRepository interface {
+	CreateQuiz(ctx context.Context, quiz *entities.Quiz) error
+	GetQuizByID(ctx context.Context, id uuid.UUID) (*entities.Quiz, error)
+	GetQuizWithQuestions(ctx context.Context, id uuid.UUID) (*entities.Quiz, error)
+	GetQuizzesByModule(ctx context.Context, moduleID uuid.UUID, publishedOnly bool) ([]entities.Quiz, error)
+	UpdateQuiz(ctx context.Context, quiz *entities.Quiz) error
+	DeleteQuiz(ctx context.Context, id uuid.UUID) error
+
+	CreateQuestion(ctx context.Context, question *entities.Question) error
+	// CreateQuestions menyimpan banyak soal (beserta pilihan) dalam satu transaksi
+	CreateQuestions(ctx context.Context, questions []entities.Question) error
+	GetQuestionByID(ctx context.Context, id uuid.UUID) (*entities.Question, error)
+	UpdateQuestion(ctx context.Context, question *entities.Question) error
+	DeleteQuestion(ctx context.Context, id uuid.UUID) error
+	GetMaxQuestionPosition(ctx context.Context, quizID uuid.UUID) (int, error)
+	// GetQuestionsByIDs memuat soal (beserta pilihan) dari quiz maupun bank
+	GetQuestionsByIDs(ctx context.Context, ids []uuid.UUID) ([]entities.Question, error)
+
+	CreateChoice(ctx context.Context, choice *entities.Choice) error
+	GetChoiceByID(ctx context.Context, id uuid.UUID) (*entities.Choice, error)
+	UpdateChoice(ctx context.Context, choice *entities.Choice) error
+	DeleteChoice(ctx context.Context, id uuid.UUID) error
+
+	CreatePool(ctx context.Context, pool *entities.QuizPool) error
+	GetPoolByID(ctx context.Context, id uuid.UUID) (*entities.QuizPool, error)
+	UpdatePool(ctx context.Context, pool *entities.QuizPool) error
+	DeletePool(ctx context.Context, id uuid.UUID) error
+	// GetPoolQuestions mencari soal bank yang punya salah satu tag (semua soal bila tags kosong)
+	GetPoolQuestions(ctx context.Context, bankID uuid.UUID, tags []string) ([]entities.Question, error)
+}
+
+type quizRepository struct {
+	db *gorm.DB
+}
+
+func NewQuizRepository(db *gorm.DB) QuizRepository {
+	return &quizRepository{db}
+}
+
+// orderByPosition mengurutkan berdasarkan position, baris tanpa position di akhir
+func orderByPosition(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC NULLS LAST, created_at ASC")
+}
+
+func (r *quizRepository) CreateQuiz(ctx context.Context, quiz *entities.Quiz) error {
+	return r.db.WithContext(ctx).Omit("Module").Create(quiz).Error
+}
+
+func (r *quizRepository) GetQuizByID(ctx context.Context, id uuid.UUID) (*entities.Quiz, error) {
+	var quiz entities.Quiz
+	if err := r.db.WithContext(ctx).Preload("Module").First(&quiz, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &quiz, nil
+}
+
+func (r *quizRepository) GetQuizWithQuestions(ctx context.Context, id uuid.UUID) (*entities.Quiz, error) {
+	var quiz entities.Quiz
+	err := r.db.WithContext(ctx).
+		Preload("Module").
+		Preload("Questions", orderByPosition).
+		Preload("Questions.Choices", orderByPosition).
+		Preload("Pools", orderByPosition).
+		First(&quiz, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &quiz, nil
+}
+
+func (r *quizRepository) GetQuizzesByModule(ctx context.Context, moduleID uuid.UUID, publishedOnly bool) ([]entities.Quiz, error) {
+	var quizzes []entities.Quiz
+	query := r.db.WithContext(ctx).Where("module_id = ?", moduleID)
+	if publishedOnly {
+		query = query.Where("is_published = ?", true)
+	}
+	if err := query.Order("created_at ASC").Find(&quizzes).Error; err != nil {
+		return nil, err
+	}
+	return quizzes, nil
+}
+
+func (r *quizRepository) UpdateQuiz(ctx context.Context, quiz *entities.Quiz) error {
+	return r.db.WithContext(ctx).Model(&entities.Quiz{}).
+		Where("id = ?", quiz.ID).
+		Updates(map[string]interface{}{
+			"title":             quiz.Title,
+			"instructions":      quiz.Instructions,
+			"open_at":           quiz.OpenAt,
+			"close_at":          quiz.CloseAt,
+			"time_limit_sec":    quiz.TimeLimitSec,
+			"attempt_allowed":   quiz.AttemptAllowed,
+			"is_published":      quiz.IsPublished,
+			"shuffle_questions": quiz.ShuffleQuestions,
+			"shuffle_choices":   quiz.ShuffleChoices,
+
+			"negative_marking":     quiz.NegativeMarking,
+			"allow_negative_score": quiz.AllowNegativeScore,
+			"multi_select_scoring": quiz.MultiSelectScoring,
+			"score_mode":           quiz.ScoreMode,
+			"release_policy":       quiz.ReleasePolicy,
+			"release_content":      quiz.ReleaseContent,
+			"results_released_at":  quiz.ResultsReleasedAt,
+			"max_focus_lost":       quiz.MaxFocusLost,
+			"max_fullscreen_exit":  quiz.MaxFullscreenExit,
+			"max_paste":            quiz.MaxPaste,
+			"updated_at":           gorm.Expr("now()"),
+		}).Error
+}
+
+func (r *quizRepository) DeleteQuiz(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&entities.Quiz{}, "id = ?", id).Error
+}
+
+func (r *quizRepository) CreateQuestion(ctx context.Context, question *entities.Question) error {
+	// Choices ikut dibuat dalam satu transaksi oleh GORM
+	return r.db.WithContext(ctx).Omit("Quiz", "Bank").Create(question).Error
+}
+
+func (r *quizRepository) CreateQuestions(ctx context.Context, questions []entities.Question) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range questions {
+			if err := tx.Omit("Quiz", "Bank").Create(&questions[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *quizRepository) GetQuestionByID(ctx context.Context, id uuid.UUID) (*entities.Question, error) {
+	var question entities.Question
+	err := r.db.WithContext(ctx).
+		Preload("Choices", orderByPosition).
+		First(&question, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &question, nil
+}
+
+func (r *quizRepository) UpdateQuestion(ctx context.Context, question *entities.Question) error {
+	// pakai struct + Select agar serializer jsonb dijalankan dan nilai kosong tetap tersimpan
+	return r.db.WithContext(ctx).Model(question).
+		Select("type", "text", "position", "points", "tags", "accepted_answers", "answer_pattern", "numeric_answer", "numeric_tolerance", "updated_at").
+		Updates(&entities.Question{
+			Type:             question.Type,
+			Tags:             question.Tags,
+			Text:             question.Text,
+			Position:         question.Position,
+			Points:           question.Points,
+			AcceptedAnswers:  question.AcceptedAnswers,
+			AnswerPattern:    question.AnswerPattern,
+			NumericAnswer:    question.NumericAnswer,
+			NumericTolerance: question.NumericTolerance,
+			UpdatedAt:        time.Now(),
+		}).Error
+}
+
+func (r *quizRepository) DeleteQuestion(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&entities.Question{}, "id = ?", id).Error
+}
+
+func (r *quizRepository) GetMaxQuestionPosition(ctx context.Context, quizID uuid.UUID) (int, error) {
+	var maxPosition int
+	err := r.db.WithContext(ctx).Model(&entities.Question{}).
+		Where("quiz_id = ?", quizID).
+		Select("COALESCE(MAX(position), 0)").
+		Scan(&maxPosition).Error
+	return maxPosition, err
+}
+
+func (r *quizRepository) GetQuestionsByIDs(ctx context.Context, ids []uuid.UUID) ([]entities.Question, error) {
+	var questions []entities.Question
+	if len(ids) == 0 {
+		return questions, nil
+	}
+	err := r.db.WithContext(ctx).
+		Preload("Choices", orderByPosition).
+		Where("id IN ?", ids).
+		Find(&questions).Error
+	return questions, err
+}
+
+func (r *quizRepository) CreateChoice(ctx context.Context, choice *entities.Choice) error {
+	return r.db.WithContext(ctx).Omit("Question").Create(choice).Error
+}
+
+func (r *quizRepository) GetChoiceByID(ctx context.Context, id uuid.UUID) (*entities.Choice, error) {
+	var choice entities.Choice
+	if err := r.db.WithContext(ctx).First(&choice, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &choice, nil
+}
+
+func (r *quizRepository) UpdateChoice(ctx context.Context, choice *entities.Choice) error {
+	return r.db.WithContext(ctx).Model(&entities.Choice{}).
+		Where("id = ?", choice.ID).
+		Updates(map[string]interface{}{
+			"text":       choice.Text,
+			"is_correct": choice.IsCorrect,
+			"position":   choice.Position,
+			"updated_at": gorm.Expr("now()"),
+		}).Error
+}
+
+func (r *quizRepository) DeleteChoice(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&entities.Choice{}, "id = ?", id).Error
+}
+
+func (r *quizRepository) CreatePool(ctx context.Context, pool *entities.QuizPool) error {
+	return r.db.WithContext(ctx).Omit("Quiz", "Bank").Create(pool).Error
+}
+
+func (r *quizRepository) GetPoolByID(ctx context.Context, id uuid.UUID) (*entities.QuizPool, error) {
+	var pool entities.QuizPool
+	if err := r.db.WithContext(ctx).First(&pool, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &pool, nil
+}
+
+func (r *quizRepository) UpdatePool(ctx context.Context, pool *entities.QuizPool) error {
+	return r.db.WithContext(ctx).Model(pool).
+		Select("bank_id", "tags", "draw_count", "position", "updated_at").
+		Updates(&entities.QuizPool{
+			BankID:    pool.BankID,
+			Tags:      pool.Tags,
+			DrawCount: pool.DrawCount,
+			Position:  pool.Position,
+			UpdatedAt: time.Now(),
+		}).Error
+}
+
+func (r *quizRepository) DeletePool(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&entities.QuizPool{}, "id = ?", id).Error
+}
+
+func (r *quizRepository) GetPoolQuestions(ctx context.Context, bankID uuid.UUID, tags []string) ([]entities.Question, error) {
+	query := r.db.WithContext(ctx).
+		Preload("Choices", orderByPosition).
+		Where("bank_id = ?", bankID)
+
+	if len(tags) > 0 {
+		// tags @> '["x"]' per tag, digabung OR: soal cukup punya salah satu tag
+		conditions := make([]string, 0, len(tags))
+		args := make([]interface{}, 0, len(tags))
+		for _, tag := range tags {
+			encoded, err := json.Marshal([]string{tag})
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, "tags @> ?::jsonb")
+			args = append(args, string(encoded))
+		}
+		query = query.Where(strings.Join(conditions, " OR "), args...)
+	}
+
+	var questions []entities.Question
+	if err := query.Order("created_at ASC, id ASC").Find(&questions).Error; err != nil {
+		return nil, err
+	}
+	return questions, nil
+}
@@ -0,0 +1,980 @@
+package quiz
+
+import (
+	"api-shiners/pkg/course"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/grading"
+	"api-shiners/pkg/utils"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxQuestionPoints adalah nilai maksimal yang boleh diberikan untuk satu soal
+const maxQuestionPoints = 1000
+
+var (
+	ErrQuizNotFound     = errors.New("quiz not found")
+	ErrQuestionNotFound = errors.New("question not found")
+	ErrChoiceNotFound   = errors.New("choice not found")
+	ErrForbidden        = errors.New("you are not allowed to manage quizzes in this course")
+	ErrChoicesNotUsed   = errors.New("this question type does not use choices")
+	ErrBankNotFound     = errors.New("question bank not found")
+	ErrPoolNotFound     = errors.New("question pool not found")
+)
+
+// QuizInput dipakai untuk create dan update quiz. AttemptAllowed nil berarti tidak diubah (default 1).
+type QuizInput struct {
+	Title          string
+	Instructions   string
+	OpenAt         *time.Time
+	CloseAt        *time.Time
+	TimeLimitSec   *int
+	AttemptAllowed *int
+
+	ShuffleQuestions *bool
+	ShuffleChoices   *bool
+
+	// kebijakan penilaian, nil berarti tidak diubah
+	NegativeMarking    *float64
+	AllowNegativeScore *bool
+	MultiSelectScoring *string
+	ScoreMode          *string
+
+	// rilis hasil ke student, nil berarti tidak diubah
+	ReleasePolicy  *string
+	ReleaseContent *string
+
+	// batas kejadian integritas per attempt, nil berarti tidak ditandai
+	MaxFocusLost      *int
+	MaxFullscreenExit *int
+	MaxPaste          *int
+}
+
+type ChoiceInput struct {
+	Text      string
+	IsCorrect bool
+	Position  *int
+}
+
+// QuestionInput: field kunci jawaban dipakai sesuai tipe soal
+// (AcceptedAnswers/AnswerPattern untuk SHORT_ANSWER, Numeric* untuk NUMERIC)
+type QuestionInput struct {
+	Type             string
+	Text             string
+	Position         *int
+	Points           *float64 // nil: 1 untuk soal baru, tidak diubah saat update
+	Tags             []string
+	Choices          []ChoiceInput
+	AcceptedAnswers  []string
+	AnswerPattern    string
+	NumericAnswer    *float64
+	NumericTolerance *float64
+}
+
+// PoolInput: undi DrawCount soal dari bank yang punya salah satu Tags
+type PoolInput struct {
+	BankID    uuid.UUID
+	Tags      []string
+	DrawCount int
+	Position  *int
+}
+
+// ImportInput: DryRun hanya mem-parse dan memvalidasi file tanpa menyimpan
+type ImportInput struct {
+	Format ImportFormat
+	Data   []byte
+	DryRun bool
+}
+
+// ImportResult berisi soal yang (akan) ditambahkan beserta position-nya
+type ImportResult struct {
+	DryRun    bool
+	Questions []entities.Question
+}
+
+type QuizService interface {
+	GetQuizzes(ctx context.Context, courseRole string, courseID, moduleID uuid.UUID) ([]entities.Quiz, error)
+	GetQuiz(ctx context.Context, courseRole string, courseID, quizID uuid.UUID) (*entities.Quiz, error)
+	CreateQuiz(ctx context.Context, courseRole string, courseID, moduleID uuid.UUID, input QuizInput) (*entities.Quiz, error)
+	UpdateQuiz(ctx context.Context, courseRole string, courseID, quizID uuid.UUID, input QuizInput) (*entities.Quiz, error)
+	DeleteQuiz(ctx context.Context, courseRole string, courseID, quizID uuid.UUID) error
+	SetPublished(ctx context.Context, courseRole string, courseID, quizID uuid.UUID, published bool) (*entities.Quiz, error)
+	// SetResultsReleased merilis atau menahan kembali hasil quiz untuk student
+	SetResultsReleased(ctx context.Context, courseRole string, courseID, quizID uuid.UUID, released bool) (*entities.Quiz, error)
+
+	CreateQuestion(ctx context.Context, courseRole string, courseID, quizID uuid.UUID, input QuestionInput) (*entities.Question, error)
+	UpdateQuestion(ctx context.Context, courseRole string, courseID, quizID, questionID uuid.UUID, input QuestionInput) (*entities.Question, error)
+	DeleteQuestion(ctx context.Context, courseRole string, courseID, quizID, questionID uuid.UUID) error
+	// ImportQuestions menambahkan soal dari file GIFT/Aiken/CSV setelah soal yang sudah ada
+	ImportQuestions(ctx context.Context, courseRole string, courseID, quizID uuid.UUID, input ImportInput) (*ImportResult, error)
+	// ExportQTI menulis soal quiz sebagai paket QTI 2.1 (zip) ke w
+	ExportQTI(ctx context.Context, courseRole string, courseID, quizID uuid.UUID, w io.Writer) (*entities.Quiz, error)
+
+	CreateChoice(ctx context.Context, courseRole string, courseID, quizID, questionID uuid.UUID, input ChoiceInput) (*entities.Choice, error)
+	UpdateChoice(ctx context.Context, courseRole string, courseID, quizID, questionID, choiceID uuid.UUID, input ChoiceInput) (*entities.Choice, error)
+	DeleteChoice(ctx context.Context, courseRole string, courseID, quizID, questionID, choiceID uuid.UUID) error
+
+	// Pool memakai bank milik course ini atau bank pribadi milik userID
+	CreatePool(ctx context.Context, userID uuid.UUID, courseRole string, courseID, quizID uuid.UUID, input PoolInput) (*entities.QuizPool, error)
+	UpdatePool(ctx context.Context, userID uuid.UUID, courseRole string, courseID, quizID, poolID uuid.UUID, input PoolInput) (*entities.QuizPool, error)
+	DeletePool(ctx context.Context, courseRole string, courseID, quizID, poolID uuid.UUID) error
+}
+
+type quizService struct {
+	repo       QuizRepository
+	bankRepo   BankRepository
+	courseRepo course.CourseRepository
+}
+
+func NewQuizService(repo QuizRepository, bankRepo BankRepository, courseRepo course.CourseRepository) QuizService {
+	return &quizService{
+		repo:       repo,
+		bankRepo:   bankRepo,
+		courseRepo: courseRepo,
+	}
+}
+
+// CanAuthor: role di course (dari CourseAccess) yang boleh mengelola quiz dan melihat kunci jawaban
+func CanAuthor(courseRole string) bool {
+	return courseRole == string(entities.ADMIN) || courseRole == string(entities.CourseRoleTeacher)
+}
+
+func (s *quizService) findModule(ctx context.Context, courseID, moduleID uuid.UUID) (*entities.CourseModule, error) {
+	module, err := s.courseRepo.GetModuleByID(ctx, moduleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, course.ErrModuleNotFound
+		}
+		return nil, err
+	}
+	if module.CourseID != courseID {
+		return nil, course.ErrModuleNotFound
+	}
+	return module, nil
+}
+
+// ensureStudentCanView: student hanya melihat course yang sudah dipublish
+func (s *quizService) ensureStudentCanView(ctx context.Context, courseRole string, courseID uuid.UUID) error {
+	if CanAuthor(courseRole) {
+		return nil
+	}
+	found, err := s.courseRepo.GetCourseByID(ctx, courseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return course.ErrCourseNotFound
+		}
+		return err
+	}
+	if !found.IsPublished {
+		return course.ErrCourseNotFound
+	}
+	return nil
+}
+
+func (s *quizService) findQuiz(ctx context.Context, courseID, quizID uuid.UUID, withQuestions bool) (*entities.Quiz, error) {
+	var (
+		quiz *entities.Quiz
+		err  error
+	)
+	if withQuestions {
+		quiz, err = s.repo.GetQuizWithQuestions(ctx, quizID)
+	} else {
+		quiz, err = s.repo.GetQuizByID(ctx, quizID)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrQuizNotFound
+		}
+		return nil, err
+	}
+	if quiz.Module.CourseID != courseID {
+		return nil, ErrQuizNotFound
+	}
+	return quiz, nil
+}
+
+func (s *quizService) findAuthoredQuiz(ctx context.Context, courseRole string, courseID, quizID uuid.UUID) (*entities.Quiz, error) {
+	if !CanAuthor(courseRole) {
+		return nil, ErrForbidden
+	}
+	return s.findQuiz(ctx, courseID, quizID, false)
+}
+
+func (s *quizService) findQuestion(ctx context.Context, quizID, questionID uuid.UUID) (*entities.Question, error) {
+	question, err := s.repo.GetQuestionByID(ctx, questionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrQuestionNotFound
+		}
+		return nil, err
+	}
+	if question.QuizID == nil || *question.QuizID != quizID {
+		return nil, ErrQuestionNotFound
+	}
+	return question, nil
+}
+
+// ensureStillValid: perubahan soal pada quiz yang sudah dipublish tidak boleh membuatnya tidak valid
+func ensureStillValid(quiz *entities.Quiz, question *entities.Question) error {
+	if !quiz.IsPublished {
+		return nil
+	}
+	if errs := validateQuestion(0, question); len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// validate menjalankan ValidateForPublish dengan kandidat soal dari bank
+func (s *quizService) validate(ctx context.Context, quiz *entities.Quiz) error {
+	candidates, err := LoadPoolCandidates(ctx, s.repo, quiz)
+	if err != nil {
+		return err
+	}
+	return ValidateForPublish(quiz, candidates)
+}
+
+func (s *quizService) GetQuizzes(ctx context.Context, courseRole string, courseID, moduleID uuid.UUID) ([]entities.Quiz, error) {
+	if err := s.ensureStudentCanView(ctx, courseRole, courseID); err != nil {
+		return nil, err
+	}
+	if _, err := s.findModule(ctx, courseID, moduleID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetQuizzesByModule(ctx, moduleID, !CanAuthor(courseRole))
+}
+
+func (s *quizService) GetQuiz(ctx context.Context, courseRole string, courseID, quizID uuid.UUID) (*entities.Quiz, error) {
+	if err := s.ensureStudentCanView(ctx, courseRole, courseID); err != nil {
+		return nil, err
+	}
+	// soal hanya dimuat untuk author; student menerima soal lewat lembar attempt
+	// yang sudah memperhitungkan jadwal, pengacakan dan pengambilan soal bank
+	quiz, err := s.findQuiz(ctx, courseID, quizID, CanAuthor(courseRole))
+	if err != nil {
+		return nil, err
+	}
+	if !CanAuthor(courseRole) && !quiz.IsPublished {
+		return nil, ErrQuizNotFound
+	}
+	return quiz, nil
+}
+
+func (s *quizService) CreateQuiz(ctx context.Context, courseRole string, courseID, moduleID uuid.UUID, input QuizInput) (*entities.Quiz, error) {
+	if !CanAuthor(courseRole) {
+		return nil, ErrForbidden
+	}
+	if _, err := s.findModule(ctx, courseID, moduleID); err != nil {
+		return nil, err
+	}
+
+	quiz := &entities.Quiz{
+		ModuleID:           moduleID,
+		AttemptAllowed:     1,
+		MultiSelectScoring: entities.MultiSelectPartial,
+		ScoreMode:          entities.ScoreModePercentage,
+		ReleasePolicy:      entities.ReleaseImmediate,
+		ReleaseContent:     entities.ReleaseScore,
+	}
+	if err := applyQuizInput(quiz, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateQuiz(ctx, quiz); err != nil {
+		return nil, err
+	}
+	return quiz, nil
+}
+
+func (s *quizService) UpdateQuiz(ctx context.Context, courseRole string, courseID, quizID uuid.UUID, input QuizInput) (*entities.Quiz, error) {
+	quiz, err := s.findAuthoredQuiz(ctx, courseRole, courseID, quizID)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyQuizInput(quiz, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateQuiz(ctx, quiz); err != nil {
+		return nil, err
+	}
+	return s.findQuiz(ctx, courseID, quizID, false)
+}
+
+func (s *quizService) DeleteQuiz(ctx context.Context, courseRole string, courseID, quizID uuid.UUID) error {
+	if _, err := s.findAuthoredQuiz(ctx, courseRole, courseID, quizID); err != nil {
+		return err
+	}
+	return s.repo.DeleteQuiz(ctx, quizID)
+}
+
+func (s *quizService) SetPublished(ctx context.Context, courseRole string, courseID, quizID uuid.UUID, published bool) (*entities.Quiz, error) {
+	if !CanAuthor(courseRole) {
+		return nil, ErrForbidden
+	}
+	quiz, err := s.findQuiz(ctx, courseID, quizID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if published {
+		if err := s.validate(ctx, quiz); err != nil {
+			return nil, err
+		}
+	}
+
+	quiz.IsPublished = published
+	if err := s.repo.UpdateQuiz(ctx, quiz); err != nil {
+		return nil, err
+	}
+	return quiz, nil
+}
+
+func (s *quizService) SetResultsReleased(ctx context.Context, courseRole string, courseID, quizID uuid.UUID, released bool) (*entities.Quiz, error) {
+	quiz, err := s.findAuthoredQuiz(ctx, courseRole, courseID, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	quiz.ResultsReleasedAt = nil
+	if released {
+		now := time.Now()
+		quiz.ResultsReleasedAt = &now
+	}
+	if err := s.repo.UpdateQuiz(ctx, quiz); err != nil {
+		return nil, err
+	}
+	return quiz, nil
+}
+
+func (s *quizService) CreateQuestion(ctx context.Context, courseRole string, courseID, quizID uuid.UUID, input QuestionInput) (*entities.Question, error) {
+	quiz, err := s.findAuthoredQuiz(ctx, courseRole, courseID, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	question, err := newQuestion(input)
+	if err != nil {
+		return nil, err
+	}
+	question.QuizID = &quizID
+
+	if question.Position == nil {
+		maxPosition, err := s.repo.GetMaxQuestionPosition(ctx, quizID)
+		if err != nil {
+			return nil, err
+		}
+		position := maxPosition + 1
+		question.Position = &position
+	}
+
+	if err := ensureStillValid(quiz, question); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateQuestion(ctx, question); err != nil {
+		return nil, err
+	}
+	return question, nil
+}
+
+func (s *quizService) UpdateQuestion(ctx context.Context, courseRole string, courseID, quizID, questionID uuid.UUID, input QuestionInput) (*entities.Question, error) {
+	quiz, err := s.findAuthoredQuiz(ctx, courseRole, courseID, quizID)
+	if err != nil {
+		return nil, err
+	}
+	question, err := s.findQuestion(ctx, quizID, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Position == nil {
+		input.Position = question.Position
+	}
+	if err := applyQuestionInput(question, input); err != nil {
+		return nil, err
+	}
+	if err := ensureStillValid(quiz, question); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateQuestion(ctx, question); err != nil {
+		return nil, err
+	}
+	return s.findQuestion(ctx, quizID, questionID)
+}
+
+func (s *quizService) DeleteQuestion(ctx context.Context, courseRole string, courseID, quizID, questionID uuid.UUID) error {
+	quiz, err := s.findAuthoredQuiz(ctx, courseRole, courseID, quizID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.findQuestion(ctx, quizID, questionID); err != nil {
+		return err
+	}
+
+	if quiz.IsPublished {
+		withQuestions, err := s.findQuiz(ctx, courseID, quizID, true)
+		if err != nil {
+			return err
+		}
+		if len(withQuestions.Questions) <= 1 && len(withQuestions.Pools) == 0 {
+			return &ValidationError{Errors: []utils.FieldError{{Field: "questions", Message: "a published quiz must have at least one question"}}}
+		}
+	}
+
+	return s.repo.DeleteQuestion(ctx, questionID)
+}
+
+func (s *quizService) ImportQuestions(ctx context.Context, courseRole string, courseID, quizID uuid.UUID, input ImportInput) (*ImportResult, error) {
+	if _, err := s.findAuthoredQuiz(ctx, courseRole, courseID, quizID); err != nil {
+		return nil, err
+	}
+
+	if len(input.Data) > MaxImportSize {
+		return nil, ErrImportTooLarge
+	}
+
+	parsed, errs, err := ParseQuestions(input.Format, input.Data)
+	if err != nil {
+		return nil, err
+	}
+	if len(parsed) == 0 && len(errs) == 0 {
+		return nil, &ValidationError{Errors: []utils.FieldError{{
+			Field:   "file",
+			Message: "file does not contain any question",
+		}}}
+	}
+
+	maxPosition, err := s.repo.GetMaxQuestionPosition(ctx, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	// soal import harus lengkap agar aman ditambahkan ke quiz yang sudah dipublish
+	questions := make([]entities.Question, 0, len(parsed))
+	for _, p := range parsed {
+		question, err := newQuestion(p.Input)
+		if err != nil {
+			errs = append(errs, p.fieldError(err.Error()))
+			continue
+		}
+		if problems := validateQuestion(0, question); len(problems) > 0 {
+			errs = append(errs, p.fieldError(problems[0].Messages...))
+			continue
+		}
+
+		position := maxPosition + len(questions) + 1
+		question.QuizID = &quizID
+		question.Position = &position
+		questions = append(questions, *question)
+	}
+	if len(errs) > 0 {
+		sortLineErrors(errs)
+		return nil, &ValidationError{Errors: errs}
+	}
+
+	result := &ImportResult{DryRun: input.DryRun, Questions: questions}
+	if input.DryRun {
+		return result, nil
+	}
+	if err := s.repo.CreateQuestions(ctx, result.Questions); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *quizService) ExportQTI(ctx context.Context, courseRole string, courseID, quizID uuid.UUID, w io.Writer) (*entities.Quiz, error) {
+	if !CanAuthor(courseRole) {
+		return nil, ErrForbidden
+	}
+	quiz, err := s.findQuiz(ctx, courseID, quizID, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := WriteQTI(w, quiz); err != nil {
+		return nil, err
+	}
+	return quiz, nil
+}
+
+func (s *quizService) CreateChoice(ctx context.Context, courseRole string, courseID, quizID, questionID uuid.UUID, input ChoiceInput) (*entities.Choice, error) {
+	quiz, err := s.findAuthoredQuiz(ctx, courseRole, courseID, quizID)
+	if err != nil {
+		return nil, err
+	}
+	question, err := s.findQuestion(ctx, quizID, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	choice, err := appendChoice(question, input)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureStillValid(quiz, question); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateChoice(ctx, choice); err != nil {
+		return nil, err
+	}
+	return choice, nil
+}
+
+func (s *quizService) UpdateChoice(ctx context.Context, courseRole string, courseID, quizID, questionID, choiceID uuid.UUID, input ChoiceInput) (*entities.Choice, error) {
+	quiz, err := s.findAuthoredQuiz(ctx, courseRole, courseID, quizID)
+	if err != nil {
+		return nil, err
+	}
+	question, err := s.findQuestion(ctx, quizID, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	index := choiceIndex(question, choiceID)
+	if index < 0 {
+		return nil, ErrChoiceNotFound
+	}
+
+	choice := question.Choices[index]
+	if input.Position == nil {
+		input.Position = choice.Position
+	}
+	if err := applyChoiceInput(&choice, input); err != nil {
+		return nil, err
+	}
+
+	question.Choices[index] = choice
+	if err := ensureStillValid(quiz, question); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateChoice(ctx, &choice); err != nil {
+		return nil, err
+	}
+	return s.repo.GetChoiceByID(ctx, choiceID)
+}
+
+func (s *quizService) DeleteChoice(ctx context.Context, courseRole string, courseID, quizID, questionID, choiceID uuid.UUID) error {
+	quiz, err := s.findAuthoredQuiz(ctx, courseRole, courseID, quizID)
+	if err != nil {
+		return err
+	}
+	question, err := s.findQuestion(ctx, quizID, questionID)
+	if err != nil {
+		return err
+	}
+
+	index := choiceIndex(question, choiceID)
+	if index < 0 {
+		return ErrChoiceNotFound
+	}
+
+	question.Choices = append(question.Choices[:index], question.Choices[index+1:]...)
+	if err := ensureStillValid(quiz, question); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteChoice(ctx, choiceID)
+}
+
+// findUsableBank: bank milik course ini, atau bank pribadi milik userID (admin boleh semua)
+func (s *quizService) findUsableBank(ctx context.Context, userID uuid.UUID, courseRole string, courseID, bankID uuid.UUID) (*entities.QuestionBank, error) {
+	bank, err := s.bankRepo.GetBankByID(ctx, bankID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBankNotFound
+		}
+		return nil, err
+	}
+
+	if bank.CourseID != nil {
+		if *bank.CourseID != courseID {
+			return nil, ErrBankNotFound
+		}
+		return bank, nil
+	}
+	if bank.OwnerID != userID && courseRole != string(entities.ADMIN) {
+		return nil, ErrBankNotFound
+	}
+	return bank, nil
+}
+
+func (s *quizService) findPool(ctx context.Context, quizID, poolID uuid.UUID) (*entities.QuizPool, error) {
+	pool, err := s.repo.GetPoolByID(ctx, poolID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPoolNotFound
+		}
+		return nil, err
+	}
+	if pool.QuizID != quizID {
+		return nil, ErrPoolNotFound
+	}
+	return pool, nil
+}
+
+// ensurePoolsValid: perubahan pool pada quiz yang sudah dipublish harus tetap bisa diundi.
+// change dijalankan pada daftar pool quiz sebelum divalidasi.
+func (s *quizService) ensurePoolsValid(ctx context.Context, courseID, quizID uuid.UUID, change func(pools []entities.QuizPool) []entities.QuizPool) error {
+	quiz, err := s.findQuiz(ctx, courseID, quizID, true)
+	if err != nil {
+		return err
+	}
+	if !quiz.IsPublished {
+		return nil
+	}
+	quiz.Pools = change(quiz.Pools)
+	return s.validate(ctx, quiz)
+}
+
+func (s *quizService) CreatePool(ctx context.Context, userID uuid.UUID, courseRole string, courseID, quizID uuid.UUID, input PoolInput) (*entities.QuizPool, error) {
+	if _, err := s.findAuthoredQuiz(ctx, courseRole, courseID, quizID); err != nil {
+		return nil, err
+	}
+	if _, err := s.findUsableBank(ctx, userID, courseRole, courseID, input.BankID); err != nil {
+		return nil, err
+	}
+
+	pool := &entities.QuizPool{QuizID: quizID}
+	if err := applyPoolInput(pool, input); err != nil {
+		return nil, err
+	}
+
+	err := s.ensurePoolsValid(ctx, courseID, quizID, func(pools []entities.QuizPool) []entities.QuizPool {
+		return append(pools, *pool)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreatePool(ctx, pool); err != nil {
+		return nil, err
+	}
+	return pool, nil
+}
+
+func (s *quizService) UpdatePool(ctx context.Context, userID uuid.UUID, courseRole string, courseID, quizID, poolID uuid.UUID, input PoolInput) (*entities.QuizPool, error) {
+	if _, err := s.findAuthoredQuiz(ctx, courseRole, courseID, quizID); err != nil {
+		return nil, err
+	}
+	pool, err := s.findPool(ctx, quizID, poolID)
+	if err != nil {
+		return nil, err
+	}
+	if input.BankID != pool.BankID {
+		if _, err := s.findUsableBank(ctx, userID, courseRole, courseID, input.BankID); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.Position == nil {
+		input.Position = pool.Position
+	}
+	if err := applyPoolInput(pool, input); err != nil {
+		return nil, err
+	}
+
+	err = s.ensurePoolsValid(ctx, courseID, quizID, func(pools []entities.QuizPool) []entities.QuizPool {
+		for i := range pools {
+			if pools[i].ID == poolID {
+				pools[i] = *pool
+			}
+		}
+		return pools
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdatePool(ctx, pool); err != nil {
+		return nil, err
+	}
+	return s.findPool(ctx, quizID, poolID)
+}
+
+func (s *quizService) DeletePool(ctx context.Context, courseRole string, courseID, quizID, poolID uuid.UUID) error {
+	if _, err := s.findAuthoredQuiz(ctx, courseRole, courseID, quizID); err != nil {
+		return err
+	}
+	if _, err := s.findPool(ctx, quizID, poolID); err != nil {
+		return err
+	}
+
+	err := s.ensurePoolsValid(ctx, courseID, quizID, func(pools []entities.QuizPool) []entities.QuizPool {
+		kept := pools[:0]
+		for _, p := range pools {
+			if p.ID != poolID {
+				kept = append(kept, p)
+			}
+		}
+		return kept
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.repo.DeletePool(ctx, poolID)
+}
+
+// newQuestion membuat soal baru beserta pilihannya dari input
+func newQuestion(input QuestionInput) (*entities.Question, error) {
+	question := &entities.Question{}
+	if err := applyQuestionInput(question, input); err != nil {
+		return nil, err
+	}
+	for i, c := range input.Choices {
+		choice := entities.Choice{}
+		if err := applyChoiceInput(&choice, c); err != nil {
+			return nil, err
+		}
+		if choice.Position == nil {
+			position := i + 1
+			choice.Position = &position
+		}
+		question.Choices = append(question.Choices, choice)
+	}
+	return question, nil
+}
+
+// appendChoice menambahkan pilihan baru ke question (position default di akhir)
+func appendChoice(question *entities.Question, input ChoiceInput) (*entities.Choice, error) {
+	if !question.Type.UsesChoices() {
+		return nil, ErrChoicesNotUsed
+	}
+
+	choice := &entities.Choice{QuestionID: question.ID}
+	if err := applyChoiceInput(choice, input); err != nil {
+		return nil, err
+	}
+	if choice.Position == nil {
+		position := 0
+		for _, c := range question.Choices {
+			if c.Position != nil && *c.Position > position {
+				position = *c.Position
+			}
+		}
+		position++
+		choice.Position = &position
+	}
+
+	question.Choices = append(question.Choices, *choice)
+	return choice, nil
+}
+
+func choiceIndex(question *entities.Question, choiceID uuid.UUID) int {
+	for i, c := range question.Choices {
+		if c.ID == choiceID {
+			return i
+		}
+	}
+	return -1
+}
+
+func applyQuizInput(quiz *entities.Quiz, input QuizInput) error {
+	title := strings.TrimSpace(input.Title)
+	if title == "" {
+		return InvalidInput("title is required")
+	}
+	if input.OpenAt != nil && input.CloseAt != nil && !input.CloseAt.After(*input.OpenAt) {
+		return InvalidInput("close_at must be after open_at")
+	}
+	if input.TimeLimitSec != nil && *input.TimeLimitSec <= 0 {
+		return InvalidInput("time_limit_sec must be greater than 0")
+	}
+	if input.AttemptAllowed != nil && *input.AttemptAllowed < 1 {
+		return InvalidInput("attempt_allowed must be at least 1")
+	}
+	if input.NegativeMarking != nil && (*input.NegativeMarking < 0 || *input.NegativeMarking > 1) {
+		return InvalidInput("negative_marking must be between 0 and 1")
+	}
+	var multiSelect entities.MultiSelectScoring
+	if input.MultiSelectScoring != nil {
+		multiSelect = entities.MultiSelectScoring(strings.ToUpper(strings.TrimSpace(*input.MultiSelectScoring)))
+		if multiSelect != entities.MultiSelectPartial && multiSelect != entities.MultiSelectAllOrNothing {
+			return InvalidInput("multi_select_scoring must be PARTIAL or ALL_OR_NOTHING")
+		}
+	}
+	var scoreMode entities.ScoreMode
+	if input.ScoreMode != nil {
+		scoreMode = entities.ScoreMode(strings.ToUpper(strings.TrimSpace(*input.ScoreMode)))
+		if scoreMode != entities.ScoreModePercentage && scoreMode != entities.ScoreModePoints {
+			return InvalidInput("score_mode must be PERCENTAGE or POINTS")
+		}
+	}
+	for _, limit := range []*int{input.MaxFocusLost, input.MaxFullscreenExit, input.MaxPaste} {
+		if limit != nil && *limit < 0 {
+			return InvalidInput("integrity thresholds must not be negative")
+		}
+	}
+	releasePolicy := quiz.ReleasePolicy
+	if input.ReleasePolicy != nil {
+		releasePolicy = entities.ReleasePolicy(strings.ToUpper(strings.TrimSpace(*input.ReleasePolicy)))
+		switch releasePolicy {
+		case entities.ReleaseImmediate, entities.ReleaseAfterClose, entities.ReleaseManual:
+		default:
+			return InvalidInput("release_policy must be IMMEDIATE, AFTER_CLOSE or MANUAL")
+		}
+	}
+	if releasePolicy == entities.ReleaseAfterClose && input.CloseAt == nil {
+		return InvalidInput("close_at is required when release_policy is AFTER_CLOSE")
+	}
+	var releaseContent entities.ReleaseContent
+	if input.ReleaseContent != nil {
+		releaseContent = entities.ReleaseContent(strings.ToUpper(strings.TrimSpace(*input.ReleaseContent)))
+		switch releaseContent {
+		case entities.ReleaseScore, entities.ReleaseCorrectness, entities.ReleaseFull:
+		default:
+			return InvalidInput("release_content must be SCORE, CORRECTNESS or FULL")
+		}
+	}
+
+	quiz.Title = title
+	quiz.Instructions = strings.TrimSpace(input.Instructions)
+	quiz.OpenAt = input.OpenAt
+	quiz.CloseAt = input.CloseAt
+	quiz.TimeLimitSec = input.TimeLimitSec
+	quiz.MaxFocusLost = input.MaxFocusLost
+	quiz.MaxFullscreenExit = input.MaxFullscreenExit
+	quiz.MaxPaste = input.MaxPaste
+	if input.AttemptAllowed != nil {
+		quiz.AttemptAllowed = *input.AttemptAllowed
+	}
+	if input.ShuffleQuestions != nil {
+		quiz.ShuffleQuestions = *input.ShuffleQuestions
+	}
+	if input.ShuffleChoices != nil {
+		quiz.ShuffleChoices = *input.ShuffleChoices
+	}
+	if input.NegativeMarking != nil {
+		quiz.NegativeMarking = grading.Round2(*input.NegativeMarking)
+	}
+	if input.AllowNegativeScore != nil {
+		quiz.AllowNegativeScore = *input.AllowNegativeScore
+	}
+	if multiSelect != "" {
+		quiz.MultiSelectScoring = multiSelect
+	}
+	if scoreMode != "" {
+		quiz.ScoreMode = scoreMode
+	}
+	quiz.ReleasePolicy = releasePolicy
+	if releaseContent != "" {
+		quiz.ReleaseContent = releaseContent
+	}
+	return nil
+}
+
+func applyQuestionInput(question *entities.Question, input QuestionInput) error {
+	text := strings.TrimSpace(input.Text)
+	if text == "" {
+		return InvalidInput("question text is required")
+	}
+
+	questionType := entities.QuestionType(strings.ToUpper(strings.TrimSpace(input.Type)))
+	if questionType == "" {
+		questionType = entities.QuestionTypeSingleChoice
+	}
+	if !questionType.IsValid() {
+		return InvalidInput("unknown question type %s", questionType)
+	}
+	if !questionType.UsesChoices() && len(input.Choices) > 0 {
+		return ErrChoicesNotUsed
+	}
+
+	var accepted []string
+	for _, a := range input.AcceptedAnswers {
+		if a = strings.TrimSpace(a); a != "" {
+			accepted = append(accepted, a)
+		}
+	}
+	pattern := strings.TrimSpace(input.AnswerPattern)
+	if pattern != "" {
+		if _, err := grading.CompileAnswerPattern(pattern); err != nil {
+			return InvalidInput("invalid answer_pattern: %v", err)
+		}
+	}
+	if input.Points != nil && (*input.Points <= 0 || *input.Points > maxQuestionPoints) {
+		return InvalidInput("points must be greater than 0 and at most %d", maxQuestionPoints)
+	}
+	tolerance := 0.0
+	if input.NumericTolerance != nil {
+		if *input.NumericTolerance < 0 {
+			return InvalidInput("numeric_tolerance must not be negative")
+		}
+		tolerance = *input.NumericTolerance
+	}
+
+	question.Type = questionType
+	question.Text = text
+	question.Position = input.Position
+	if input.Points != nil {
+		question.Points = grading.Round2(*input.Points)
+	} else if question.Points <= 0 {
+		question.Points = 1
+	}
+	question.Tags = normalizeTags(input.Tags)
+	question.AcceptedAnswers = nil
+	question.AnswerPattern = ""
+	question.NumericAnswer = nil
+	question.NumericTolerance = 0
+
+	switch questionType {
+	case entities.QuestionTypeShortAnswer:
+		question.AcceptedAnswers = accepted
+		question.AnswerPattern = pattern
+	case entities.QuestionTypeNumeric:
+		question.NumericAnswer = input.NumericAnswer
+		question.NumericTolerance = tolerance
+	}
+	return nil
+}
+
+func applyPoolInput(pool *entities.QuizPool, input PoolInput) error {
+	if input.BankID == uuid.Nil {
+		return InvalidInput("bank_id is required")
+	}
+	if input.DrawCount < 1 {
+		return InvalidInput("draw_count must be at least 1")
+	}
+
+	pool.BankID = input.BankID
+	pool.Tags = normalizeTags(input.Tags)
+	pool.DrawCount = input.DrawCount
+	pool.Position = input.Position
+	return nil
+}
+
+func applyChoiceInput(choice *entities.Choice, input ChoiceInput) error {
+	text := strings.TrimSpace(input.Text)
+	if text == "" {
+		return InvalidInput("choice text is required")
+	}
+
+	choice.Text = text
+	choice.IsCorrect = input.IsCorrect
+	choice.Position = input.Position
+	return nil
+}
+
+// normalizeTags: huruf kecil, tanpa spasi berlebih dan tanpa duplikat
+func normalizeTags(tags []string) []string {
+	var normalized []string
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.Join(strings.Fields(tag), " "))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
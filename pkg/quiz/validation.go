@@ -0,0 +1,132 @@
+package quiz
+
+import (
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/utils"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidQuiz = errors.New("quiz is not valid")
+
+// ValidationError berisi daftar kesalahan per soal, dikirim ke client sebagai utils.FieldError
+type ValidationError struct {
+	Errors []utils.FieldError
+}
+
+func (e *ValidationError) Error() string {
+	return ErrInvalidQuiz.Error()
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidQuiz
+}
+
+// InputError adalah kesalahan isian request yang pesannya aman dikirim ke client
+type InputError struct {
+	Message string
+}
+
+func (e *InputError) Error() string {
+	return e.Message
+}
+
+// InvalidInput membuat InputError, format seperti fmt.Sprintf
+func InvalidInput(format string, args ...interface{}) error {
+	return &InputError{Message: fmt.Sprintf(format, args...)}
+}
+
+// ValidateForPublish memastikan quiz siap dikerjakan student. candidates adalah
+// soal bank yang bisa diundi per PoolID (lihat LoadPoolCandidates).
+func ValidateForPublish(quiz *entities.Quiz, candidates map[uuid.UUID][]entities.Question) error {
+	total := len(quiz.Questions)
+	for _, pool := range quiz.Pools {
+		total += pool.DrawCount
+	}
+	if total == 0 {
+		return &ValidationError{Errors: []utils.FieldError{{
+			Field:   "questions",
+			Message: "a published quiz must have at least one question",
+		}}}
+	}
+
+	var errs []utils.FieldError
+	for i := range quiz.Questions {
+		errs = append(errs, validateQuestion(i+1, &quiz.Questions[i])...)
+	}
+	for i, pool := range quiz.Pools {
+		if available := len(candidates[pool.ID]); available < pool.DrawCount {
+			errs = append(errs, utils.FieldError{
+				Field:   fmt.Sprintf("pools[%d]", i+1),
+				Message: fmt.Sprintf("pool draws %d questions but the bank only has %d complete questions matching its tags", pool.DrawCount, available),
+			})
+		}
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// validateQuestion memeriksa satu soal; number adalah nomor urut soal (0 bila tidak relevan)
+func validateQuestion(number int, question *entities.Question) []utils.FieldError {
+	field := "question"
+	if number > 0 {
+		field = fmt.Sprintf("questions[%d]", number)
+	}
+
+	correct := 0
+	for _, c := range question.Choices {
+		if c.IsCorrect {
+			correct++
+		}
+	}
+
+	var messages []string
+	switch question.Type {
+	case entities.QuestionTypeSingleChoice:
+		if len(question.Choices) < 2 {
+			messages = append(messages, "single-choice question must have at least two choices")
+		}
+		if correct != 1 {
+			messages = append(messages, fmt.Sprintf("single-choice question must have exactly one correct choice, found %d", correct))
+		}
+	case entities.QuestionTypeTrueFalse:
+		if len(question.Choices) != 2 {
+			messages = append(messages, "true/false question must have exactly two choices")
+		}
+		if correct != 1 {
+			messages = append(messages, fmt.Sprintf("true/false question must have exactly one correct choice, found %d", correct))
+		}
+	case entities.QuestionTypeMultipleChoice:
+		if len(question.Choices) < 2 {
+			messages = append(messages, "multiple-choice question must have at least two choices")
+		}
+		if correct == 0 {
+			messages = append(messages, "multiple-choice question must have at least one correct choice")
+		}
+	case entities.QuestionTypeShortAnswer:
+		if len(question.AcceptedAnswers) == 0 && question.AnswerPattern == "" {
+			messages = append(messages, "short-answer question needs accepted_answers or answer_pattern")
+		}
+	case entities.QuestionTypeNumeric:
+		if question.NumericAnswer == nil {
+			messages = append(messages, "numeric question needs numeric_answer")
+		}
+	case entities.QuestionTypeEssay:
+		// dinilai manual, tidak butuh kunci jawaban
+	default:
+		messages = append(messages, fmt.Sprintf("unknown question type %s", question.Type))
+	}
+
+	if len(messages) == 0 {
+		return nil
+	}
+	return []utils.FieldError{{
+		Field:    field,
+		Message:  fmt.Sprintf("%s: %s", question.ID, messages[0]),
+		Messages: messages,
+	}}
+}
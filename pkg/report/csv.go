@@ -0,0 +1,72 @@
+package report
+
+import (
+	"api-shiners/pkg/utils"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteItemAnalysisCSV menulis satu baris per soal. Sebaran jawaban ditulis dalam satu
+// kolom dengan format "A=0.500*; B=0.250" (tanda * untuk kunci jawaban).
+func WriteItemAnalysisCSV(w io.Writer, analysis *ItemAnalysis) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{"number", "question_id", "type", "question", "max_points", "responses", "omitted_rate", "p_value", "point_biserial", "choice_rates"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, item := range analysis.Items {
+		record := []string{
+			strconv.Itoa(item.Number),
+			item.QuestionID.String(),
+			string(item.Type),
+			utils.SafeCSVCell(item.Text),
+			formatFloat(item.MaxPoints),
+			strconv.Itoa(item.Responses),
+			formatFloat(item.OmittedRate),
+			formatOptional(item.PValue),
+			formatOptional(item.PointBiserial),
+			choiceRates(item.Choices),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func choiceRates(choices []ChoiceStat) string {
+	parts := make([]string, 0, len(choices))
+	for i, c := range choices {
+		part := choiceLabel(i) + "=" + formatFloat(c.Rate)
+		if c.IsCorrect {
+			part += "*"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// choiceLabel: A, B, ..., Z, lalu 27, 28, ... untuk soal dengan pilihan sangat banyak
+func choiceLabel(i int) string {
+	if i < 26 {
+		return string(rune('A' + i))
+	}
+	return strconv.Itoa(i + 1)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func formatOptional(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return formatFloat(*v)
+}
@@ -0,0 +1,338 @@
+package report
+
+import (
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/grading"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// histogramBins membagi score 0-100 menjadi 10 kelompok selebar 10 poin
+const histogramBins = 10
+
+type ChoiceStat struct {
+	ChoiceID  uuid.UUID `json:"choice_id"`
+	Text      string    `json:"text"`
+	IsCorrect bool      `json:"is_correct"`
+	Count     int       `json:"count"`
+	Rate      float64   `json:"rate"` // proporsi attempt yang memilih pilihan ini
+}
+
+// ItemStat adalah statistik satu soal. PValue (indeks kesukaran) adalah rata-rata
+// nilai relatif soal; PointBiserial adalah korelasi nilai soal dengan nilai sisa
+// (total tanpa soal ini). Nil bila datanya belum cukup.
+type ItemStat struct {
+	Number        int                   `json:"number"`
+	QuestionID    uuid.UUID             `json:"question_id"`
+	Type          entities.QuestionType `json:"type"`
+	Text          string                `json:"text"`
+	MaxPoints     float64               `json:"max_points"`
+	Responses     int                   `json:"responses"` // attempt yang paper-nya memuat soal ini
+	Omitted       int                   `json:"omitted"`
+	OmittedRate   float64               `json:"omitted_rate"`
+	PValue        *float64              `json:"p_value"`
+	PointBiserial *float64              `json:"point_biserial"`
+	Choices       []ChoiceStat          `json:"choices,omitempty"`
+}
+
+type HistogramBin struct {
+	From  float64 `json:"from"`
+	To    float64 `json:"to"`
+	Count int     `json:"count"`
+}
+
+// ItemAnalysis dihitung dari attempt yang sudah disubmit dan selesai dinilai.
+// Attempt yang masih menunggu penilaian essay hanya dihitung di PendingAttempts.
+type ItemAnalysis struct {
+	QuizID          uuid.UUID `json:"quiz_id"`
+	QuizTitle       string    `json:"quiz_title"`
+	GeneratedAt     time.Time `json:"generated_at"`
+	Attempts        int       `json:"attempts"`
+	PendingAttempts int       `json:"pending_attempts"`
+	// MeanScore dan StdDevScore mengikuti ScoreMode quiz; Histogram selalu dalam persentase
+	ScoreMode   entities.ScoreMode `json:"score_mode"`
+	MeanScore   *float64           `json:"mean_score"`
+	StdDevScore *float64           `json:"std_dev_score"`
+	Histogram   []HistogramBin     `json:"histogram"`
+	// CronbachAlpha dihitung dari soal yang muncul di semua paper (AlphaItems soal)
+	CronbachAlpha *float64   `json:"cronbach_alpha"`
+	AlphaItems    int        `json:"alpha_items"`
+	Items         []ItemStat `json:"items"`
+}
+
+// AnalyzeItems menghitung item analysis. questions berisi semua soal yang mungkin
+// muncul di paper (soal quiz dan soal bank hasil undian), sesuai urutan laporan.
+func AnalyzeItems(q *entities.Quiz, questions []entities.Question, attempts []entities.QuizAttempt) *ItemAnalysis {
+	analysis := &ItemAnalysis{
+		QuizID:      q.ID,
+		QuizTitle:   q.Title,
+		GeneratedAt: time.Now(),
+		ScoreMode:   entities.ScoreModePercentage,
+		Histogram:   newHistogram(),
+	}
+	if q.ScoreMode == entities.ScoreModePoints {
+		analysis.ScoreMode = entities.ScoreModePoints
+	}
+
+	var graded []entities.QuizAttempt
+	for _, a := range attempts {
+		if a.SubmittedAt == nil {
+			continue
+		}
+		if a.PendingReview {
+			analysis.PendingAttempts++
+			continue
+		}
+		graded = append(graded, a)
+	}
+	analysis.Attempts = len(graded)
+
+	quizQuestionIDs := make([]uuid.UUID, 0, len(q.Questions))
+	for _, question := range q.Questions {
+		quizQuestionIDs = append(quizQuestionIDs, question.ID)
+	}
+
+	// skor mentah per attempt per soal; soal yang tidak ada di paper tidak punya entri
+	scores := make([]map[uuid.UUID]float64, len(graded))
+	answers := make([]map[uuid.UUID]*entities.Answer, len(graded))
+	totals := make([]float64, len(graded))
+	maxPoints := make(map[uuid.UUID]float64, len(questions))
+	for _, question := range questions {
+		maxPoints[question.ID] = grading.MaxPoints(question)
+	}
+
+	displayScores := make([]float64, 0, len(graded))
+	for i, a := range graded {
+		paper := a.QuestionIDs
+		if len(paper) == 0 {
+			paper = quizQuestionIDs
+		}
+
+		answers[i] = make(map[uuid.UUID]*entities.Answer, len(a.Answers))
+		for j := range a.Answers {
+			answers[i][a.Answers[j].QuestionID] = &a.Answers[j]
+		}
+
+		scores[i] = make(map[uuid.UUID]float64, len(paper))
+		for _, id := range paper {
+			max, ok := maxPoints[id]
+			if !ok {
+				continue // soal sudah dihapus
+			}
+			points := answerPoints(answers[i][id], max)
+			scores[i][id] = points
+			totals[i] += points
+		}
+
+		score, _ := grading.DisplayScore(q, &a)
+		displayScores = append(displayScores, score)
+		analysis.Histogram[histogramBin(a.Score)].Count++
+	}
+
+	if len(displayScores) > 0 {
+		mean := round(meanOf(displayScores), 2)
+		analysis.MeanScore = &mean
+	}
+	if len(displayScores) > 1 {
+		stdDev := round(math.Sqrt(sampleVariance(displayScores)), 2)
+		analysis.StdDevScore = &stdDev
+	}
+
+	for n, question := range questions {
+		analysis.Items = append(analysis.Items, analyzeItem(n+1, question, maxPoints[question.ID], scores, answers, totals))
+	}
+
+	analysis.CronbachAlpha, analysis.AlphaItems = cronbachAlpha(questions, scores)
+	return analysis
+}
+
+func analyzeItem(number int, question entities.Question, max float64, scores []map[uuid.UUID]float64, answers []map[uuid.UUID]*entities.Answer, totals []float64) ItemStat {
+	stat := ItemStat{
+		Number:     number,
+		QuestionID: question.ID,
+		Type:       question.Type,
+		Text:       question.Text,
+		MaxPoints:  max,
+	}
+
+	choiceCounts := make(map[uuid.UUID]int, len(question.Choices))
+	var itemScores, restScores []float64
+	for i := range scores {
+		points, onPaper := scores[i][question.ID]
+		if !onPaper {
+			continue
+		}
+		stat.Responses++
+
+		answer := answers[i][question.ID]
+		if isOmitted(answer) {
+			stat.Omitted++
+		}
+		if answer != nil {
+			if answer.ChoiceID != nil {
+				choiceCounts[*answer.ChoiceID]++
+			}
+			for _, id := range answer.ChoiceIDs {
+				choiceCounts[id]++
+			}
+		}
+
+		itemScores = append(itemScores, points/max)
+		restScores = append(restScores, totals[i]-points)
+	}
+
+	if stat.Responses == 0 {
+		return stat
+	}
+
+	stat.OmittedRate = round(float64(stat.Omitted)/float64(stat.Responses), 3)
+	pValue := round(meanOf(itemScores), 3)
+	stat.PValue = &pValue
+	if r, ok := pearson(itemScores, restScores); ok {
+		r = round(r, 3)
+		stat.PointBiserial = &r
+	}
+
+	if question.Type.UsesChoices() {
+		for _, c := range question.Choices {
+			stat.Choices = append(stat.Choices, ChoiceStat{
+				ChoiceID:  c.ID,
+				Text:      c.Text,
+				IsCorrect: c.IsCorrect,
+				Count:     choiceCounts[c.ID],
+				Rate:      round(float64(choiceCounts[c.ID])/float64(stat.Responses), 3),
+			})
+		}
+	}
+	return stat
+}
+
+// cronbachAlpha = k/(k-1) * (1 - Σ var(item) / var(total)) atas soal yang ada di semua paper
+func cronbachAlpha(questions []entities.Question, scores []map[uuid.UUID]float64) (*float64, int) {
+	if len(scores) < 2 {
+		return nil, 0
+	}
+
+	var common []uuid.UUID
+	for _, question := range questions {
+		inAll := true
+		for i := range scores {
+			if _, ok := scores[i][question.ID]; !ok {
+				inAll = false
+				break
+			}
+		}
+		if inAll {
+			common = append(common, question.ID)
+		}
+	}
+
+	k := len(common)
+	if k < 2 {
+		return nil, k
+	}
+
+	totals := make([]float64, len(scores))
+	var itemVariance float64
+	for _, id := range common {
+		column := make([]float64, len(scores))
+		for i := range scores {
+			column[i] = scores[i][id]
+			totals[i] += scores[i][id]
+		}
+		itemVariance += sampleVariance(column)
+	}
+
+	totalVariance := sampleVariance(totals)
+	if totalVariance == 0 {
+		return nil, k
+	}
+	alpha := round(float64(k)/float64(k-1)*(1-itemVariance/totalVariance), 3)
+	return &alpha, k
+}
+
+// answerPoints: nilai mentah jawaban; attempt lama yang belum menyimpan Points memakai IsCorrect
+func answerPoints(answer *entities.Answer, max float64) float64 {
+	if answer == nil {
+		return 0
+	}
+	if answer.Points != nil {
+		return *answer.Points
+	}
+	if answer.IsCorrect {
+		return max
+	}
+	return 0
+}
+
+func isOmitted(answer *entities.Answer) bool {
+	return answer == nil ||
+		(answer.ChoiceID == nil && len(answer.ChoiceIDs) == 0 && answer.TextAnswer == "" && answer.NumericAnswer == nil)
+}
+
+func newHistogram() []HistogramBin {
+	bins := make([]HistogramBin, histogramBins)
+	width := 100.0 / histogramBins
+	for i := range bins {
+		bins[i] = HistogramBin{From: float64(i) * width, To: float64(i+1) * width}
+	}
+	return bins
+}
+
+// histogramBin: batas bawah inklusif, score 100 masuk kelompok terakhir
+func histogramBin(score float64) int {
+	bin := int(score / (100.0 / histogramBins))
+	if bin < 0 {
+		return 0
+	}
+	if bin >= histogramBins {
+		return histogramBins - 1
+	}
+	return bin
+}
+
+func meanOf(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+func sampleVariance(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	mean := meanOf(values)
+	var sum float64
+	for _, v := range values {
+		sum += (v - mean) * (v - mean)
+	}
+	return sum / float64(len(values)-1)
+}
+
+// pearson menghitung korelasi; false bila salah satu variabel tidak bervariasi
+func pearson(x, y []float64) (float64, bool) {
+	if len(x) < 2 || len(x) != len(y) {
+		return 0, false
+	}
+	meanX, meanY := meanOf(x), meanOf(y)
+	var cov, varX, varY float64
+	for i := range x {
+		dx, dy := x[i]-meanX, y[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return 0, false
+	}
+	return cov / math.Sqrt(varX*varY), true
+}
+
+func round(v float64, places int) float64 {
+	factor := math.Pow(10, float64(places))
+	return math.Round(v*factor) / factor
+}
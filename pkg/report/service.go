@@ -0,0 +1,104 @@
+package report
+
+import (
+	"api-shiners/pkg/attempt"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/quiz"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ReportService menyusun laporan hasil quiz untuk teacher/admin course
+type ReportService interface {
+	GetItemAnalysis(ctx context.Context, courseRole string, courseID, quizID uuid.UUID) (*ItemAnalysis, error)
+}
+
+type reportService struct {
+	attemptRepo attempt.AttemptRepository
+	quizRepo    quiz.QuizRepository
+}
+
+func NewReportService(attemptRepo attempt.AttemptRepository, quizRepo quiz.QuizRepository) ReportService {
+	return &reportService{
+		attemptRepo: attemptRepo,
+		quizRepo:    quizRepo,
+	}
+}
+
+func (s *reportService) findQuiz(ctx context.Context, courseID, quizID uuid.UUID) (*entities.Quiz, error) {
+	found, err := s.quizRepo.GetQuizWithQuestions(ctx, quizID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, quiz.ErrQuizNotFound
+		}
+		return nil, err
+	}
+	if found.Module.CourseID != courseID {
+		return nil, quiz.ErrQuizNotFound
+	}
+	return found, nil
+}
+
+func (s *reportService) GetItemAnalysis(ctx context.Context, courseRole string, courseID, quizID uuid.UUID) (*ItemAnalysis, error) {
+	if !quiz.CanAuthor(courseRole) {
+		return nil, quiz.ErrForbidden
+	}
+	q, err := s.findQuiz(ctx, courseID, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := s.attemptRepo.GetSubmittedAttemptsWithAnswers(ctx, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	questions, err := s.paperQuestions(ctx, q, attempts)
+	if err != nil {
+		return nil, err
+	}
+	return AnalyzeItems(q, questions, attempts), nil
+}
+
+// paperQuestions: soal quiz sesuai urutan, lalu soal bank yang pernah diundi
+// sesuai urutan kemunculan pertamanya di paper
+func (s *reportService) paperQuestions(ctx context.Context, q *entities.Quiz, attempts []entities.QuizAttempt) ([]entities.Question, error) {
+	seen := make(map[uuid.UUID]bool, len(q.Questions))
+	for _, question := range q.Questions {
+		seen[question.ID] = true
+	}
+
+	var bankIDs []uuid.UUID
+	for _, a := range attempts {
+		for _, id := range a.QuestionIDs {
+			if !seen[id] {
+				seen[id] = true
+				bankIDs = append(bankIDs, id)
+			}
+		}
+	}
+
+	questions := append([]entities.Question(nil), q.Questions...)
+	if len(bankIDs) == 0 {
+		return questions, nil
+	}
+
+	bankQuestions, err := s.quizRepo.GetQuestionsByIDs(ctx, bankIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]entities.Question, len(bankQuestions))
+	for _, question := range bankQuestions {
+		byID[question.ID] = question
+	}
+	for _, id := range bankIDs {
+		// soal bank yang sudah dihapus tidak dilaporkan
+		if question, ok := byID[id]; ok {
+			questions = append(questions, question)
+		}
+	}
+	return questions, nil
+}
@@ -0,0 +1,93 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// Locker memastikan sebuah job hanya berjalan di satu replica pada satu waktu
+type Locker interface {
+	// TryLock tidak menunggu: ok false berarti lock sedang dipegang proses lain
+	TryLock(ctx context.Context, name string, ttl time.Duration) (unlock func(), ok bool, err error)
+}
+
+// NewLocker memakai Redis bila tersedia, selain itu advisory lock Postgres
+func NewLocker(redisClient *redis.Client, db *gorm.DB) Locker {
+	if redisClient != nil {
+		return &RedisLocker{client: redisClient}
+	}
+	return &PostgresLocker{db: db}
+}
+
+// hanya menghapus key bila token masih milik pemegang lock
+var redisUnlockScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("del", KEYS[1])
+end
+return 0
+`)
+
+type RedisLocker struct {
+	client *redis.Client
+}
+
+func NewRedisLocker(client *redis.Client) *RedisLocker {
+	return &RedisLocker{client: client}
+}
+
+func (l *RedisLocker) TryLock(ctx context.Context, name string, ttl time.Duration) (func(), bool, error) {
+	key := "lock:" + name
+	token := uuid.NewString()
+
+	ok, err := l.client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	unlock := func() {
+		redisUnlockScript.Run(context.Background(), l.client, []string{key}, token)
+	}
+	return unlock, true, nil
+}
+
+// PostgresLocker memakai pg_try_advisory_lock pada satu koneksi khusus.
+// Lock otomatis lepas bila koneksi atau proses mati, sehingga ttl tidak dipakai.
+type PostgresLocker struct {
+	db *gorm.DB
+}
+
+func NewPostgresLocker(db *gorm.DB) *PostgresLocker {
+	return &PostgresLocker{db: db}
+}
+
+func (l *PostgresLocker) TryLock(ctx context.Context, name string, _ time.Duration) (func(), bool, error) {
+	sqlDB, err := l.db.DB()
+	if err != nil {
+		return nil, false, err
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var ok bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock(hashtext($1))", name).Scan(&ok); err != nil {
+		conn.Close()
+		return nil, false, err
+	}
+	if !ok {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock(hashtext($1))", name)
+		conn.Close()
+	}
+	return unlock, true, nil
+}
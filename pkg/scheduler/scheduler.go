@@ -0,0 +1,56 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Job adalah pekerjaan terjadwal; harus idempotent karena bisa diulang setelah gagal
+type Job func(ctx context.Context) error
+
+// RunLocked menjalankan job bila lock berhasil diambil. ran false berarti
+// replica lain sedang menjalankan job yang sama.
+func RunLocked(ctx context.Context, locker Locker, name string, ttl time.Duration, job Job) (bool, error) {
+	unlock, ok, err := locker.TryLock(ctx, name, ttl)
+	if err != nil || !ok {
+		return false, err
+	}
+	defer unlock()
+
+	return true, job(ctx)
+}
+
+// RunEvery menjalankan job secara berkala di goroutine terpisah sampai ctx dibatalkan.
+// Lock ditahan maksimal satu interval agar lock milik replica yang mati tidak menggantung.
+func RunEvery(ctx context.Context, name string, interval time.Duration, locker Locker, job Job) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				jobCtx, cancel := context.WithTimeout(ctx, interval)
+				if _, err := RunLocked(jobCtx, locker, name, interval, job); err != nil {
+					log.Printf("⚠️ Job %s failed: %v", name, err)
+				}
+				cancel()
+			}
+		}
+	}()
+
+	log.Printf("⏱️ Job %s scheduled every %s", name, interval)
+}
+
+// IntervalFromEnv membaca interval dalam detik dari env, fallback ke def
+func IntervalFromEnv(key string, def time.Duration) time.Duration {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil && v > 0 {
+		return time.Duration(v) * time.Second
+	}
+	return def
+}
@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LocalStorage menyimpan file di disk lokal. Download dilayani oleh API sendiri
+// melalui URL bertanda tangan HMAC (lihat FileController).
+type LocalStorage struct {
+	baseDir string
+	baseURL string
+	secret  string
+}
+
+// NewLocalStorage membuat storage disk. baseURL adalah prefix publik endpoint
+// download, misalnya "http://localhost:3000/api/files".
+func NewLocalStorage(baseDir, baseURL, secret string) (*LocalStorage, error) {
+	if err := os.MkdirAll(baseDir, 0o755); err != nil {
+		return nil, err
+	}
+	return &LocalStorage{
+		baseDir: baseDir,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		secret:  secret,
+	}, nil
+}
+
+func (s *LocalStorage) path(key string) (string, error) {
+	clean, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.baseDir, filepath.FromSlash(clean)), nil
+}
+
+func (s *LocalStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	fullPath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+		return err
+	}
+
+	// tulis ke file sementara dulu supaya file tidak pernah terbaca setengah jadi
+	tmp, err := os.CreateTemp(filepath.Dir(fullPath), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), fullPath)
+}
+
+func (s *LocalStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	fullPath, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(fullPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	fullPath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(fullPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalStorage) SignedURL(ctx context.Context, key string, ttl time.Duration, fileName string) (string, error) {
+	clean, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	expires := time.Now().Add(ttl).Unix()
+	query := url.Values{}
+	query.Set("expires", fmt.Sprint(expires))
+	query.Set("signature", Sign(s.secret, clean, fileName, expires))
+	if fileName != "" {
+		query.Set("name", fileName)
+	}
+
+	return fmt.Sprintf("%s/%s?%s", s.baseURL, clean, query.Encode()), nil
+}
+
+// Verify memvalidasi parameter URL yang dibuat oleh SignedURL; fileName ikut ditandatangani
+// agar nama download tidak bisa diganti tanpa membuat URL baru
+func (s *LocalStorage) Verify(key, fileName string, expires int64, signature string) error {
+	clean, err := CleanKey(key)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	return VerifySignature(s.secret, clean, fileName, expires, signature, time.Now())
+}
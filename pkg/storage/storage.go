@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound         = errors.New("file not found")
+	ErrInvalidSignature = errors.New("invalid or expired download signature")
+)
+
+// Storage adalah abstraksi penyimpanan file (material, lampiran, dll).
+// Key selalu berupa path relatif dengan pemisah "/".
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// SignedURL menghasilkan URL download yang hanya berlaku selama ttl
+	SignedURL(ctx context.Context, key string, ttl time.Duration, fileName string) (string, error)
+}
+
+// CleanKey menormalkan key dan menolak path traversal
+func CleanKey(key string) (string, error) {
+	key = strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(key, "\\", "/")), "/")
+	if key == "" || key == "." || strings.HasPrefix(key, "..") {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return key, nil
+}
+
+// Sign menghitung signature HMAC-SHA256 untuk key, nama file download dan waktu kedaluwarsa (unix)
+func Sign(secret, key, fileName string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%s\n%s\n%d", key, fileName, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature memvalidasi signature dan memastikan URL belum kedaluwarsa
+func VerifySignature(secret, key, fileName string, expires int64, signature string, now time.Time) error {
+	if now.Unix() > expires {
+		return ErrInvalidSignature
+	}
+	expected := Sign(secret, key, fileName, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
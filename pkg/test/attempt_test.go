@@ -0,0 +1,410 @@
+package test
+
+import (
+	"api-shiners/pkg/attempt"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/grading"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+//
+// ===== MOCK REPOSITORY =====
+//
+type MockAttemptRepo struct {
+	mock.Mock
+}
+
+func (m *MockAttemptRepo) GetAttemptByID(ctx context.Context, id uuid.UUID) (*entities.QuizAttempt, error) {
+	args := m.Called(ctx, id)
+	a, _ := args.Get(0).(*entities.QuizAttempt)
+	return a, args.Error(1)
+}
+
+func (m *MockAttemptRepo) GetAttemptWithAnswers(ctx context.Context, id uuid.UUID) (*entities.QuizAttempt, error) {
+	args := m.Called(ctx, id)
+	a, _ := args.Get(0).(*entities.QuizAttempt)
+	return a, args.Error(1)
+}
+
+func (m *MockAttemptRepo) GetAttemptsByStudent(ctx context.Context, quizID, studentID uuid.UUID) ([]entities.QuizAttempt, error) {
+	args := m.Called(ctx, quizID, studentID)
+	attempts, _ := args.Get(0).([]entities.QuizAttempt)
+	return attempts, args.Error(1)
+}
+
+func (m *MockAttemptRepo) GetAttemptsByQuiz(ctx context.Context, quizID uuid.UUID) ([]entities.QuizAttempt, error) {
+	args := m.Called(ctx, quizID)
+	attempts, _ := args.Get(0).([]entities.QuizAttempt)
+	return attempts, args.Error(1)
+}
+
+func (m *MockAttemptRepo) GetSubmittedAttemptsWithAnswers(ctx context.Context, quizID uuid.UUID) ([]entities.QuizAttempt, error) {
+	args := m.Called(ctx, quizID)
+	attempts, _ := args.Get(0).([]entities.QuizAttempt)
+	return attempts, args.Error(1)
+}
+
+func (m *MockAttemptRepo) GetOpenAttempt(ctx context.Context, quizID, studentID uuid.UUID) (*entities.QuizAttempt, error) {
+	args := m.Called(ctx, quizID, studentID)
+	a, _ := args.Get(0).(*entities.QuizAttempt)
+	return a, args.Error(1)
+}
+
+func (m *MockAttemptRepo) GetExpiredOpenAttempts(ctx context.Context, before time.Time, limit int) ([]entities.QuizAttempt, error) {
+	args := m.Called(ctx, before, limit)
+	attempts, _ := args.Get(0).([]entities.QuizAttempt)
+	return attempts, args.Error(1)
+}
+
+func (m *MockAttemptRepo) CreateAttempt(ctx context.Context, a *entities.QuizAttempt, maxAttempts int) error {
+	args := m.Called(ctx, a, maxAttempts)
+	return args.Error(0)
+}
+
+func (m *MockAttemptRepo) SaveAnswers(ctx context.Context, attemptID uuid.UUID, answers []entities.Answer) error {
+	args := m.Called(ctx, attemptID, answers)
+	return args.Error(0)
+}
+
+// Finalize menjalankan fn terhadap attempt dan answers yang disiapkan test
+func (m *MockAttemptRepo) Finalize(ctx context.Context, attemptID uuid.UUID, fn attempt.FinalizeFunc) (*entities.QuizAttempt, error) {
+	args := m.Called(ctx, attemptID)
+	a, _ := args.Get(0).(*entities.QuizAttempt)
+	answers, _ := args.Get(1).([]entities.Answer)
+	if err := args.Error(2); err != nil {
+		return nil, err
+	}
+	if err := fn(a, answers); err != nil {
+		return nil, err
+	}
+	a.Answers = answers
+	return a, nil
+}
+
+// Rescore menjalankan fn seperti Finalize
+func (m *MockAttemptRepo) Rescore(ctx context.Context, attemptID uuid.UUID, fn attempt.FinalizeFunc) (*entities.QuizAttempt, error) {
+	args := m.Called(ctx, attemptID)
+	a, _ := args.Get(0).(*entities.QuizAttempt)
+	answers, _ := args.Get(1).([]entities.Answer)
+	if err := args.Error(2); err != nil {
+		return nil, err
+	}
+	if err := fn(a, answers); err != nil {
+		return nil, err
+	}
+	a.Answers = answers
+	return a, nil
+}
+
+// RegradeAttempts menjalankan fn terhadap attempt (beserta Answers) yang disiapkan test
+func (m *MockAttemptRepo) RegradeAttempts(ctx context.Context, attemptIDs []uuid.UUID, fn attempt.FinalizeFunc) ([]entities.QuizAttempt, error) {
+	args := m.Called(ctx, attemptIDs)
+	attempts, _ := args.Get(0).([]entities.QuizAttempt)
+	if err := args.Error(1); err != nil {
+		return nil, err
+	}
+	for i := range attempts {
+		if err := fn(&attempts[i], attempts[i].Answers); err != nil {
+			return nil, err
+		}
+	}
+	return attempts, nil
+}
+
+func (m *MockAttemptRepo) CreateRegrade(ctx context.Context, regrade *entities.QuizRegrade) error {
+	args := m.Called(ctx, regrade)
+	return args.Error(0)
+}
+
+func (m *MockAttemptRepo) GetRegradesByQuiz(ctx context.Context, quizID uuid.UUID) ([]entities.QuizRegrade, error) {
+	args := m.Called(ctx, quizID)
+	regrades, _ := args.Get(0).([]entities.QuizRegrade)
+	return regrades, args.Error(1)
+}
+
+func (m *MockAttemptRepo) GetAnswerByID(ctx context.Context, id uuid.UUID) (*entities.Answer, error) {
+	args := m.Called(ctx, id)
+	a, _ := args.Get(0).(*entities.Answer)
+	return a, args.Error(1)
+}
+
+func (m *MockAttemptRepo) GetPendingManualAnswers(ctx context.Context, quizID uuid.UUID) ([]entities.Answer, error) {
+	args := m.Called(ctx, quizID)
+	answers, _ := args.Get(0).([]entities.Answer)
+	return answers, args.Error(1)
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+//
+// ===== TEST ATTEMPT =====
+//
+func TestDeadline_UsesEarliestOfTimeLimitAndCloseAt(t *testing.T) {
+	started := time.Date(2025, 1, 6, 8, 0, 0, 0, time.UTC)
+	a := &entities.QuizAttempt{StartedAt: started}
+
+	q := &entities.Quiz{TimeLimitSec: intPtr(1800)}
+	assert.Equal(t, started.Add(30*time.Minute), *attempt.Deadline(q, a))
+
+	closeAt := started.Add(10 * time.Minute)
+	q.CloseAt = &closeAt
+	assert.Equal(t, closeAt, *attempt.Deadline(q, a))
+
+	assert.Nil(t, attempt.Deadline(&entities.Quiz{}, a))
+}
+
+func TestGrade_SingleChoice(t *testing.T) {
+	q1 := singleChoiceQuestion(true, false)
+	q2 := singleChoiceQuestion(false, true)
+	q3 := singleChoiceQuestion(true, false)
+
+	result := grading.Grade([]entities.Question{q1, q2, q3}, []entities.Answer{
+		{QuestionID: q1.ID, ChoiceID: &q1.Choices[0].ID},
+		{QuestionID: q2.ID, ChoiceID: &q2.Choices[0].ID},
+	})
+
+	assert.Equal(t, 1, result.Correct)
+	assert.Equal(t, float64(3), result.Total)
+	assert.Equal(t, 33.33, result.Score)
+	assert.True(t, result.Outcomes[q1.ID].IsCorrect)
+	assert.False(t, result.Outcomes[q2.ID].IsCorrect)
+}
+
+func TestGrade_MultipleChoicePartialCredit(t *testing.T) {
+	q := singleChoiceQuestion(true, true, false, false)
+	q.Type = entities.QuestionTypeMultipleChoice
+
+	// satu dari dua pilihan benar: 0.5
+	half := grading.GradeAnswer(q, &entities.Answer{ChoiceIDs: []uuid.UUID{q.Choices[0].ID}})
+	assert.Equal(t, 0.5, half.Points)
+	assert.False(t, half.IsCorrect)
+
+	// satu benar, satu salah: 0.5 - 0.5
+	mixed := grading.GradeAnswer(q, &entities.Answer{ChoiceIDs: []uuid.UUID{q.Choices[0].ID, q.Choices[2].ID}})
+	assert.Equal(t, float64(0), mixed.Points)
+
+	// semua pilihan dipilih tidak boleh mendapat nilai penuh, minimal 0
+	all := grading.GradeAnswer(q, &entities.Answer{ChoiceIDs: []uuid.UUID{q.Choices[0].ID, q.Choices[1].ID, q.Choices[2].ID, q.Choices[3].ID}})
+	assert.Equal(t, float64(0), all.Points)
+
+	exact := grading.GradeAnswer(q, &entities.Answer{ChoiceIDs: []uuid.UUID{q.Choices[1].ID, q.Choices[0].ID}})
+	assert.Equal(t, float64(1), exact.Points)
+	assert.True(t, exact.IsCorrect)
+}
+
+func TestGrade_ShortAnswerAndNumeric(t *testing.T) {
+	short := entities.Question{
+		ID:              uuid.New(),
+		Type:            entities.QuestionTypeShortAnswer,
+		AcceptedAnswers: []string{"Jakarta"},
+		AnswerPattern:   `(dki\s+)?jakarta raya`,
+	}
+	assert.True(t, grading.GradeAnswer(short, &entities.Answer{TextAnswer: "  jakarta "}).IsCorrect)
+	assert.True(t, grading.GradeAnswer(short, &entities.Answer{TextAnswer: "DKI Jakarta Raya"}).IsCorrect)
+	assert.False(t, grading.GradeAnswer(short, &entities.Answer{TextAnswer: "Bandung"}).IsCorrect)
+	assert.False(t, grading.GradeAnswer(short, &entities.Answer{TextAnswer: "bukan jakarta raya"}).IsCorrect)
+
+	answer := 3.14
+	numeric := entities.Question{ID: uuid.New(), Type: entities.QuestionTypeNumeric, NumericAnswer: &answer, NumericTolerance: 0.01}
+	inside, outside := 3.15, 3.16
+	assert.True(t, grading.GradeAnswer(numeric, &entities.Answer{NumericAnswer: &inside}).IsCorrect)
+	assert.False(t, grading.GradeAnswer(numeric, &entities.Answer{NumericAnswer: &outside}).IsCorrect)
+	assert.False(t, grading.GradeAnswer(numeric, &entities.Answer{}).IsCorrect)
+}
+
+func TestGrade_PolicyWeightsNegativeMarkingAndFloor(t *testing.T) {
+	heavy := singleChoiceQuestion(true, false)
+	heavy.Points = 3
+	light := singleChoiceQuestion(true, false)
+	multi := singleChoiceQuestion(true, true, false, false)
+	multi.Type = entities.QuestionTypeMultipleChoice
+	multi.Points = 2
+	questions := []entities.Question{heavy, light, multi}
+
+	policy := grading.Policy{NegativeMarking: 0.5, PartialCredit: true}
+	result := policy.Grade(questions, []entities.Answer{
+		{QuestionID: heavy.ID, ChoiceID: &heavy.Choices[0].ID},
+		{QuestionID: light.ID, ChoiceID: &light.Choices[1].ID},
+		{QuestionID: multi.ID, ChoiceIDs: []uuid.UUID{multi.Choices[0].ID}},
+	})
+	// 3 - 0.5 + 0.5 * 2 dari total 6
+	assert.Equal(t, float64(6), result.Total)
+	assert.Equal(t, 3.5, result.Earned)
+	assert.Equal(t, 58.33, result.Score)
+	assert.Equal(t, -0.5, result.Outcomes[light.ID].Points)
+
+	// tanpa nilai parsial, multi-select yang tidak tepat dikurangi
+	policy.PartialCredit = false
+	assert.Equal(t, float64(-1), policy.GradeAnswer(multi, &entities.Answer{ChoiceIDs: []uuid.UUID{multi.Choices[0].ID}}).Points)
+	assert.Equal(t, float64(0), policy.GradeAnswer(multi, &entities.Answer{}).Points)
+
+	// semua salah: total dibatasi 0 kecuali nilai negatif diizinkan
+	allWrong := []entities.Answer{
+		{QuestionID: heavy.ID, ChoiceID: &heavy.Choices[1].ID},
+		{QuestionID: light.ID, ChoiceID: &light.Choices[1].ID},
+	}
+	assert.Equal(t, float64(0), policy.Grade(questions, allWrong).Score)
+	policy.AllowNegative = true
+	assert.Equal(t, -2.0, policy.Grade(questions, allWrong).Earned)
+	assert.Equal(t, -33.33, policy.Grade(questions, allWrong).Score)
+
+	q := &entities.Quiz{ScoreMode: entities.ScoreModePoints}
+	score, max := grading.DisplayScore(q, &entities.QuizAttempt{Score: 58.33, Points: 3.5, MaxPoints: 6})
+	assert.Equal(t, 3.5, score)
+	assert.Equal(t, float64(6), max)
+}
+
+func TestGradeAnswer_EssayRescoresAttempt(t *testing.T) {
+	repo := new(MockAttemptRepo)
+	quizRepo := new(MockQuizRepo)
+	service := attempt.NewAttemptService(repo, noOverrides(), quizRepo, new(MockCourseRepo))
+
+	courseID := uuid.New()
+	choice := singleChoiceQuestion(true, false)
+	essay := entities.Question{ID: uuid.New(), Type: entities.QuestionTypeEssay, Text: "Jelaskan fotosintesis."}
+	q := &entities.Quiz{ID: uuid.New(), Module: entities.CourseModule{CourseID: courseID}, Questions: []entities.Question{choice, essay}}
+
+	submittedAt := time.Now()
+	a := &entities.QuizAttempt{ID: uuid.New(), QuizID: q.ID, SubmittedAt: &submittedAt, Score: 50, PendingReview: true}
+	essayAnswer := entities.Answer{ID: uuid.New(), AttemptID: a.ID, QuestionID: essay.ID, TextAnswer: "Tumbuhan membuat makanan.", Attempt: *a}
+	answers := []entities.Answer{
+		{ID: uuid.New(), AttemptID: a.ID, QuestionID: choice.ID, ChoiceID: &choice.Choices[0].ID},
+		essayAnswer,
+	}
+
+	repo.On("GetAnswerByID", mock.Anything, essayAnswer.ID).Return(&essayAnswer, nil)
+	quizRepo.On("GetQuizWithQuestions", mock.Anything, q.ID).Return(q, nil)
+	repo.On("Rescore", mock.Anything, a.ID).Return(a, answers, nil)
+
+	grader := uuid.New()
+	_, _, err := service.GradeAnswer(context.Background(), grader, "TEACHER", courseID, essayAnswer.ID, attempt.ManualGradeInput{Points: 1.5})
+	assert.ErrorIs(t, err, attempt.ErrInvalidPoints)
+
+	graded, _, err := service.GradeAnswer(context.Background(), grader, "TEACHER", courseID, essayAnswer.ID, attempt.ManualGradeInput{Points: 0.5, Comment: " Kurang lengkap "})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 75.0, graded.Score)
+	assert.False(t, graded.PendingReview)
+	assert.Equal(t, 0.5, *graded.Answers[1].Points)
+	assert.Equal(t, "Kurang lengkap", graded.Answers[1].Comment)
+	assert.Equal(t, grader, *graded.Answers[1].GradedByID)
+}
+
+func TestSubmitAttempt_LateIsMarkedAndIgnoresNewAnswers(t *testing.T) {
+	repo := new(MockAttemptRepo)
+	quizRepo := new(MockQuizRepo)
+	service := attempt.NewAttemptService(repo, noOverrides(), quizRepo, new(MockCourseRepo))
+
+	courseID := uuid.New()
+	student := uuid.New()
+	question := singleChoiceQuestion(true, false)
+	q := &entities.Quiz{
+		ID:           uuid.New(),
+		TimeLimitSec: intPtr(600),
+		Module:       entities.CourseModule{CourseID: courseID},
+		Questions:    []entities.Question{question},
+	}
+	a := &entities.QuizAttempt{ID: uuid.New(), QuizID: q.ID, StudentID: student, StartedAt: time.Now().Add(-time.Hour)}
+	saved := []entities.Answer{{ID: uuid.New(), AttemptID: a.ID, QuestionID: question.ID, ChoiceID: &question.Choices[1].ID}}
+
+	repo.On("GetAttemptByID", mock.Anything, a.ID).Return(a, nil)
+	quizRepo.On("GetQuizWithQuestions", mock.Anything, q.ID).Return(q, nil)
+	repo.On("Finalize", mock.Anything, a.ID).Return(a, saved, nil)
+
+	submitted, _, err := service.SubmitAttempt(context.Background(), student, courseID, a.ID, []attempt.AnswerInput{
+		{QuestionID: question.ID, ChoiceID: &question.Choices[0].ID},
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, submitted.IsLate)
+	assert.Equal(t, float64(0), submitted.Score)
+	assert.NotNil(t, submitted.SubmittedAt)
+	assert.GreaterOrEqual(t, *submitted.DurationSec, 3600)
+	repo.AssertNotCalled(t, "SaveAnswers", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSaveAnswers_RejectedAfterDeadline(t *testing.T) {
+	repo := new(MockAttemptRepo)
+	quizRepo := new(MockQuizRepo)
+	service := attempt.NewAttemptService(repo, noOverrides(), quizRepo, new(MockCourseRepo))
+
+	courseID := uuid.New()
+	student := uuid.New()
+	q := &entities.Quiz{ID: uuid.New(), TimeLimitSec: intPtr(60), Module: entities.CourseModule{CourseID: courseID}}
+	a := &entities.QuizAttempt{ID: uuid.New(), QuizID: q.ID, StudentID: student, StartedAt: time.Now().Add(-5 * time.Minute)}
+
+	repo.On("GetAttemptByID", mock.Anything, a.ID).Return(a, nil)
+	quizRepo.On("GetQuizWithQuestions", mock.Anything, q.ID).Return(q, nil)
+
+	_, _, err := service.SaveAnswers(context.Background(), student, courseID, a.ID, nil)
+
+	assert.ErrorIs(t, err, attempt.ErrAttemptExpired)
+}
+
+func TestStartAttempt_ClosedQuizRejected(t *testing.T) {
+	repo := new(MockAttemptRepo)
+	quizRepo := new(MockQuizRepo)
+	courseRepo := new(MockCourseRepo)
+	service := attempt.NewAttemptService(repo, noOverrides(), quizRepo, courseRepo)
+
+	c := &entities.Course{ID: uuid.New(), IsPublished: true}
+	closeAt := time.Now().Add(-time.Minute)
+	q := &entities.Quiz{ID: uuid.New(), IsPublished: true, CloseAt: &closeAt, Module: entities.CourseModule{CourseID: c.ID}}
+	student := uuid.New()
+
+	courseRepo.On("GetCourseByID", mock.Anything, c.ID).Return(c, nil)
+	quizRepo.On("GetQuizWithQuestions", mock.Anything, q.ID).Return(q, nil)
+	repo.On("GetOpenAttempt", mock.Anything, q.ID, student).Return(nil, gorm.ErrRecordNotFound)
+
+	_, _, err := service.StartAttempt(context.Background(), student, "STUDENT", c.ID, q.ID)
+
+	assert.ErrorIs(t, err, attempt.ErrQuizClosed)
+	repo.AssertNotCalled(t, "CreateAttempt", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestStartAttempt_OnlyStudents(t *testing.T) {
+	service := attempt.NewAttemptService(new(MockAttemptRepo), noOverrides(), new(MockQuizRepo), new(MockCourseRepo))
+
+	_, _, err := service.StartAttempt(context.Background(), uuid.New(), "TEACHER", uuid.New(), uuid.New())
+
+	assert.ErrorIs(t, err, attempt.ErrForbidden)
+}
+
+func TestAutoSubmitExpired_GradesSavedAnswersAtDeadline(t *testing.T) {
+	repo := new(MockAttemptRepo)
+	quizRepo := new(MockQuizRepo)
+	service := attempt.NewAttemptService(repo, noOverrides(), quizRepo, new(MockCourseRepo))
+
+	question := singleChoiceQuestion(true, false)
+	q := &entities.Quiz{ID: uuid.New(), TimeLimitSec: intPtr(600), Questions: []entities.Question{question}}
+	started := time.Now().Add(-time.Hour)
+	expired := entities.QuizAttempt{ID: uuid.New(), QuizID: q.ID, StartedAt: started}
+	raced := entities.QuizAttempt{ID: uuid.New(), QuizID: q.ID, StartedAt: started}
+	saved := []entities.Answer{{ID: uuid.New(), QuestionID: question.ID, ChoiceID: &question.Choices[0].ID}}
+
+	repo.On("GetExpiredOpenAttempts", mock.Anything, mock.Anything, mock.Anything).
+		Return([]entities.QuizAttempt{expired, raced}, nil)
+	quizRepo.On("GetQuizWithQuestions", mock.Anything, q.ID).Return(q, nil).Once()
+	repo.On("Finalize", mock.Anything, expired.ID).Return(&expired, saved, nil)
+	// replica lain atau student sudah menutup attempt ini lebih dulu
+	repo.On("Finalize", mock.Anything, raced.ID).Return(nil, nil, attempt.ErrAlreadySubmitted)
+
+	count, err := service.AutoSubmitExpired(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, started.Add(10*time.Minute), *expired.SubmittedAt)
+	assert.Equal(t, 600, *expired.DurationSec)
+	assert.Equal(t, float64(100), expired.Score)
+	assert.False(t, expired.IsLate)
+	assert.True(t, saved[0].IsCorrect)
+}
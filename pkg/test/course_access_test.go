@@ -0,0 +1,92 @@
+package test
+
+import (
+	"api-shiners/pkg/course"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/middleware"
+	"api-shiners/pkg/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeCourseRoleResolver map[uuid.UUID]entities.CourseRole
+
+func (f fakeCourseRoleResolver) GetCourseRole(ctx context.Context, courseID, userID uuid.UUID) (entities.CourseRole, error) {
+	if courseID == uuid.Nil {
+		return "", course.ErrCourseNotFound
+	}
+	return f[userID], nil
+}
+
+type failingCourseRoleResolver struct{}
+
+func (failingCourseRoleResolver) GetCourseRole(ctx context.Context, courseID, userID uuid.UUID) (entities.CourseRole, error) {
+	return "", errors.New("redis: connection pool timeout")
+}
+
+func newCourseAccessApp(userID uuid.UUID, roles []interface{}, resolver middleware.CourseRoleResolver) *fiber.App {
+	app := fiber.New()
+	access := middleware.NewCourseAccess(resolver)
+
+	app.Get("/courses/:course_id", func(c *fiber.Ctx) error {
+		c.Locals("user_id", userID.String())
+		c.Locals("roles", roles)
+		return c.Next()
+	}, access.Require(entities.CourseRoleTeacher), func(c *fiber.Ctx) error {
+		return c.SendString(c.Locals("course_role").(string))
+	})
+	return app
+}
+
+func TestCourseAccess_RequireCourseRole(t *testing.T) {
+	teacher := uuid.New()
+	student := uuid.New()
+	resolver := fakeCourseRoleResolver{
+		teacher: entities.CourseRoleTeacher,
+		student: entities.CourseRoleStudent,
+	}
+	path := "/courses/" + uuid.NewString()
+
+	cases := []struct {
+		name   string
+		userID uuid.UUID
+		roles  []interface{}
+		path   string
+		status int
+	}{
+		{"teacher in course", teacher, []interface{}{"TEACHER"}, path, fiber.StatusOK},
+		{"student in course", student, []interface{}{"STUDENT"}, path, fiber.StatusForbidden},
+		{"teacher not enrolled", uuid.New(), []interface{}{"TEACHER"}, path, fiber.StatusForbidden},
+		{"admin override", uuid.New(), []interface{}{"ADMIN"}, path, fiber.StatusOK},
+		{"unknown course", teacher, []interface{}{"TEACHER"}, "/courses/" + uuid.Nil.String(), fiber.StatusNotFound},
+		{"invalid course id", teacher, []interface{}{"TEACHER"}, "/courses/abc", fiber.StatusBadRequest},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := newCourseAccessApp(tc.userID, tc.roles, resolver)
+			resp, err := app.Test(httptest.NewRequest("GET", tc.path, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.status, resp.StatusCode)
+		})
+	}
+}
+
+func TestCourseAccess_LookupFailureReturnsGeneric500(t *testing.T) {
+	app := newCourseAccessApp(uuid.New(), []interface{}{"TEACHER"}, failingCourseRoleResolver{})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/courses/"+uuid.NewString(), nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+
+	var body utils.ErrorResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "Internal server error", body.Message)
+}
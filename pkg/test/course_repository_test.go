@@ -0,0 +1,112 @@
+package test
+
+import (
+	"api-shiners/pkg/course"
+	"api-shiners/pkg/entities"
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// expectLockCourse mengharapkan baris course dikunci dengan SELECT ... FOR UPDATE
+func expectLockCourse(mock sqlmock.Sqlmock, courseID uuid.UUID) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "courses" WHERE id = $1`) + `.*FOR UPDATE`).
+		WithArgs(courseID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(courseID))
+}
+
+func expectCurrentModules(mock sqlmock.Sqlmock, courseID uuid.UUID, ids ...uuid.UUID) {
+	rows := sqlmock.NewRows([]string{"id"})
+	for _, id := range ids {
+		rows.AddRow(id)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "course_modules" WHERE course_id = $1`)).
+		WithArgs(courseID).WillReturnRows(rows)
+}
+
+//
+// ===== TEST COURSE REPOSITORY =====
+//
+func TestReorderModules_LocksCourseBeforeRewritingOrder(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := course.NewCourseRepository(db)
+	courseID, first, second := uuid.New(), uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	expectLockCourse(mock, courseID)
+	expectCurrentModules(mock, courseID, first, second)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "course_modules" SET "order_no"=$1,"updated_at"=now() WHERE id = $2`)).
+		WithArgs(1, second).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "course_modules" SET "order_no"=$1,"updated_at"=now() WHERE id = $2`)).
+		WithArgs(2, first).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.ReorderModules(context.Background(), courseID, []uuid.UUID{second, first})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReorderModules_RejectsMismatchedList(t *testing.T) {
+	first, second, foreign := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name     string
+		ordered  []uuid.UUID
+		expected course.OrderMismatchError
+	}{
+		{"missing", []uuid.UUID{first}, course.OrderMismatchError{Missing: []uuid.UUID{second}}},
+		{"foreign", []uuid.UUID{first, second, foreign}, course.OrderMismatchError{Foreign: []uuid.UUID{foreign}}},
+		{"duplicate", []uuid.UUID{first, second, first}, course.OrderMismatchError{Duplicate: []uuid.UUID{first}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			repo := course.NewCourseRepository(db)
+			courseID := uuid.New()
+
+			mock.ExpectBegin()
+			expectLockCourse(mock, courseID)
+			expectCurrentModules(mock, courseID, first, second)
+			// tidak ada UPDATE: transaksi dibatalkan sebelum order_no disentuh
+			mock.ExpectRollback()
+
+			err := repo.ReorderModules(context.Background(), courseID, tt.ordered)
+
+			assert.ErrorIs(t, err, course.ErrInvalidOrder)
+			var mismatch *course.OrderMismatchError
+			if assert.True(t, errors.As(err, &mismatch)) {
+				assert.Equal(t, tt.expected, *mismatch)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCreateModule_AppendsAfterLastOrderUnderCourseLock(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := course.NewCourseRepository(db)
+	courseID := uuid.New()
+	module := &entities.CourseModule{CourseID: courseID, Title: "Bab 4"}
+
+	mock.ExpectBegin()
+	expectLockCourse(mock, courseID)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(MAX(order_no), 0) FROM "course_modules" WHERE course_id = $1`)).
+		WithArgs(courseID).WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "course_modules"`)).
+		WithArgs(courseID, "Bab 4", 4).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(uuid.New(), nil, nil))
+	mock.ExpectCommit()
+
+	err := repo.CreateModule(context.Background(), module)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, module.OrderNo)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
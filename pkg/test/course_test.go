@@ -0,0 +1,325 @@
+package test
+
+import (
+	"api-shiners/api/handlers"
+	"api-shiners/pkg/config"
+	"api-shiners/pkg/course"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// ===== MOCK REPOSITORY =====
+type MockCourseRepo struct {
+	mock.Mock
+}
+
+func (m *MockCourseRepo) CreateCourse(ctx context.Context, c *entities.Course) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepo) GetCourseByID(ctx context.Context, id uuid.UUID) (*entities.Course, error) {
+	args := m.Called(ctx, id)
+	c, _ := args.Get(0).(*entities.Course)
+	return c, args.Error(1)
+}
+
+func (m *MockCourseRepo) GetCourseByCode(ctx context.Context, code string) (*entities.Course, error) {
+	args := m.Called(ctx, code)
+	c, _ := args.Get(0).(*entities.Course)
+	return c, args.Error(1)
+}
+
+func (m *MockCourseRepo) GetAllCourses(ctx context.Context, filter course.CourseFilter, page, perPage int) ([]entities.Course, int64, error) {
+	args := m.Called(ctx, filter, page, perPage)
+	courses, _ := args.Get(0).([]entities.Course)
+	return courses, int64(args.Int(1)), args.Error(2)
+}
+
+func (m *MockCourseRepo) UpdateCourse(ctx context.Context, c *entities.Course) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepo) DeleteCourse(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepo) CreateModule(ctx context.Context, module *entities.CourseModule) error {
+	args := m.Called(ctx, module)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepo) GetModuleByID(ctx context.Context, id uuid.UUID) (*entities.CourseModule, error) {
+	args := m.Called(ctx, id)
+	module, _ := args.Get(0).(*entities.CourseModule)
+	return module, args.Error(1)
+}
+
+func (m *MockCourseRepo) GetModulesByCourse(ctx context.Context, courseID uuid.UUID) ([]entities.CourseModule, error) {
+	args := m.Called(ctx, courseID)
+	modules, _ := args.Get(0).([]entities.CourseModule)
+	return modules, args.Error(1)
+}
+
+func (m *MockCourseRepo) UpdateModule(ctx context.Context, module *entities.CourseModule) error {
+	args := m.Called(ctx, module)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepo) DeleteModule(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepo) ReorderModules(ctx context.Context, courseID uuid.UUID, ids []uuid.UUID) error {
+	args := m.Called(ctx, courseID, ids)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepo) GetMaterialsByModule(ctx context.Context, moduleID uuid.UUID) ([]entities.Material, error) {
+	args := m.Called(ctx, moduleID)
+	materials, _ := args.Get(0).([]entities.Material)
+	return materials, args.Error(1)
+}
+
+func (m *MockCourseRepo) ReorderMaterials(ctx context.Context, moduleID uuid.UUID, ids []uuid.UUID) error {
+	args := m.Called(ctx, moduleID, ids)
+	return args.Error(0)
+}
+
+// ===== TEST COURSE =====
+func TestCreateCourse_TeacherBecomesOwner(t *testing.T) {
+	mockRepo := new(MockCourseRepo)
+	service := course.NewCourseService(mockRepo)
+	teacherID := uuid.New()
+
+	mockRepo.On("GetCourseByCode", mock.Anything, "MTK-10A").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("CreateCourse", mock.Anything, mock.AnythingOfType("*entities.Course")).Return(nil)
+
+	otherTeacher := uuid.New()
+	created, err := service.CreateCourse(context.Background(), teacherID, "TEACHER", course.CourseInput{
+		Code:           "MTK-10A",
+		Title:          "Matematika",
+		OwnerTeacherID: &otherTeacher,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, teacherID, created.OwnerTeacherID)
+	assert.False(t, created.IsPublished)
+}
+
+func TestUpdateCourse_TeacherNotOwnerForbidden(t *testing.T) {
+	mockRepo := new(MockCourseRepo)
+	service := course.NewCourseService(mockRepo)
+	existing := &entities.Course{ID: uuid.New(), Code: "MTK-10A", Title: "Matematika", OwnerTeacherID: uuid.New()}
+
+	mockRepo.On("GetCourseByID", mock.Anything, existing.ID).Return(existing, nil)
+
+	_, err := service.UpdateCourse(context.Background(), uuid.New(), "TEACHER", existing.ID, course.CourseInput{Title: "Baru"})
+
+	assert.ErrorIs(t, err, course.ErrForbidden)
+	mockRepo.AssertNotCalled(t, "UpdateCourse", mock.Anything, mock.Anything)
+}
+
+func TestUpdateCourse_OmittedDescriptionIsKept(t *testing.T) {
+	mockRepo := new(MockCourseRepo)
+	service := course.NewCourseService(mockRepo)
+	teacherID := uuid.New()
+	existing := &entities.Course{ID: uuid.New(), Code: "MTK-10A", Title: "Matematika", Description: "Semester ganjil", OwnerTeacherID: teacherID}
+
+	mockRepo.On("GetCourseByID", mock.Anything, existing.ID).Return(existing, nil)
+	mockRepo.On("UpdateCourse", mock.Anything, existing).Return(nil)
+
+	updated, err := service.UpdateCourse(context.Background(), teacherID, "TEACHER", existing.ID, course.CourseInput{Title: "Matematika Lanjut"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Matematika Lanjut", updated.Title)
+	assert.Equal(t, "Semester ganjil", updated.Description)
+
+	cleared := ""
+	updated, err = service.UpdateCourse(context.Background(), teacherID, "TEACHER", existing.ID, course.CourseInput{Description: &cleared})
+
+	assert.NoError(t, err)
+	assert.Empty(t, updated.Description)
+}
+
+func TestPublishCourse_AdminCanManageAnyCourse(t *testing.T) {
+	mockRepo := new(MockCourseRepo)
+	service := course.NewCourseService(mockRepo)
+	existing := &entities.Course{ID: uuid.New(), Code: "MTK-10A", Title: "Matematika", OwnerTeacherID: uuid.New()}
+
+	mockRepo.On("GetCourseByID", mock.Anything, existing.ID).Return(existing, nil)
+	mockRepo.On("UpdateCourse", mock.Anything, existing).Return(nil)
+
+	updated, err := service.SetPublished(context.Background(), uuid.New(), "ADMIN", existing.ID, true)
+
+	assert.NoError(t, err)
+	assert.True(t, updated.IsPublished)
+}
+
+func TestGetCourse_StudentCannotSeeUnpublished(t *testing.T) {
+	mockRepo := new(MockCourseRepo)
+	service := course.NewCourseService(mockRepo)
+	existing := &entities.Course{ID: uuid.New(), OwnerTeacherID: uuid.New()}
+
+	mockRepo.On("GetCourseByID", mock.Anything, existing.ID).Return(existing, nil)
+
+	_, err := service.GetCourseByID(context.Background(), uuid.New(), "STUDENT", existing.ID)
+
+	assert.ErrorIs(t, err, course.ErrCourseNotFound)
+}
+
+func TestReorderModules_StudentCourseRoleForbidden(t *testing.T) {
+	mockRepo := new(MockCourseRepo)
+	service := course.NewCourseService(mockRepo)
+
+	_, err := service.ReorderModules(context.Background(), "STUDENT", uuid.New(), []uuid.UUID{uuid.New()})
+
+	assert.ErrorIs(t, err, course.ErrForbidden)
+	mockRepo.AssertNotCalled(t, "ReorderModules", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReorderModules_MismatchIsReturnedWithDetails(t *testing.T) {
+	mockRepo := new(MockCourseRepo)
+	service := course.NewCourseService(mockRepo)
+	existing := &entities.Course{ID: uuid.New(), OwnerTeacherID: uuid.New()}
+	missing, foreign, duplicate := uuid.New(), uuid.New(), uuid.New()
+	ordered := []uuid.UUID{duplicate, duplicate, foreign}
+
+	mockRepo.On("GetCourseByID", mock.Anything, existing.ID).Return(existing, nil)
+	mockRepo.On("ReorderModules", mock.Anything, existing.ID, ordered).Return(&course.OrderMismatchError{
+		Missing:   []uuid.UUID{missing},
+		Foreign:   []uuid.UUID{foreign},
+		Duplicate: []uuid.UUID{duplicate},
+	})
+
+	_, err := service.ReorderModules(context.Background(), "TEACHER", existing.ID, ordered)
+
+	assert.ErrorIs(t, err, course.ErrInvalidOrder)
+	var mismatch *course.OrderMismatchError
+	if assert.True(t, errors.As(err, &mismatch)) {
+		assert.Equal(t, []uuid.UUID{missing}, mismatch.Missing)
+		assert.Equal(t, []uuid.UUID{foreign}, mismatch.Foreign)
+		assert.Equal(t, []uuid.UUID{duplicate}, mismatch.Duplicate)
+	}
+	mockRepo.AssertNotCalled(t, "GetModulesByCourse", mock.Anything, mock.Anything)
+}
+
+func TestCourseHandler_UnexpectedErrorReturnsGeneric500(t *testing.T) {
+	mockRepo := new(MockCourseRepo)
+	controller := handlers.NewCourseController(course.NewCourseService(mockRepo))
+	courseID := uuid.New()
+
+	mockRepo.On("GetCourseByID", mock.Anything, courseID).Return(nil, errors.New("pq: connection refused"))
+
+	app := fiber.New()
+	app.Get("/courses/:course_id", func(c *fiber.Ctx) error {
+		c.Locals("user_id", uuid.NewString())
+		c.Locals("roles", "TEACHER")
+		return c.Next()
+	}, controller.GetCourseByID)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/courses/"+courseID.String(), nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+
+	var body utils.ErrorResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "Internal server error", body.Message)
+	assert.NotContains(t, body.Message, "pq:")
+}
+
+func TestCourseHandler_GetAllCoursesClampsPerPageAndHidesDBError(t *testing.T) {
+	mockRepo := new(MockCourseRepo)
+	controller := handlers.NewCourseController(course.NewCourseService(mockRepo))
+
+	mockRepo.On("GetAllCourses", mock.Anything, course.CourseFilter{}, 1, 100).Return(nil, 0, errors.New("pq: connection refused"))
+
+	app := fiber.New()
+	app.Get("/courses", func(c *fiber.Ctx) error {
+		c.Locals("user_id", uuid.NewString())
+		c.Locals("roles", "ADMIN")
+		return c.Next()
+	}, controller.GetAllCourses)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/courses?per_page=1000000", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+
+	var body utils.ErrorResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "Internal server error", body.Message)
+	mockRepo.AssertExpectations(t)
+}
+
+// useTestRedis mengarahkan config.RedisClient ke miniredis selama test berjalan
+func useTestRedis(t *testing.T) *miniredis.Miniredis {
+	srv := miniredis.RunT(t)
+	previous := config.RedisClient
+	config.RedisClient = redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	t.Cleanup(func() {
+		config.RedisClient.Close()
+		config.RedisClient = previous
+	})
+	return srv
+}
+
+func TestUpdateCourse_OwnerTransferInvalidatesCachedRoles(t *testing.T) {
+	srv := useTestRedis(t)
+	mockRepo := new(MockCourseRepo)
+	service := course.NewCourseService(mockRepo)
+	oldOwner, newOwner, student := uuid.New(), uuid.New(), uuid.New()
+	existing := &entities.Course{ID: uuid.New(), Code: "MTK-10A", Title: "Matematika", OwnerTeacherID: oldOwner}
+
+	srv.Set(course.RoleCacheKey(existing.ID, oldOwner), "TEACHER")
+	srv.Set(course.RoleCacheKey(existing.ID, newOwner), "NONE")
+	srv.Set(course.RoleCacheKey(existing.ID, student), "STUDENT")
+
+	mockRepo.On("GetCourseByID", mock.Anything, existing.ID).Return(existing, nil)
+	mockRepo.On("UpdateCourse", mock.Anything, existing).Return(nil)
+
+	_, err := service.UpdateCourse(context.Background(), uuid.New(), "ADMIN", existing.ID, course.CourseInput{OwnerTeacherID: &newOwner})
+
+	assert.NoError(t, err)
+	assert.False(t, srv.Exists(course.RoleCacheKey(existing.ID, oldOwner)))
+	assert.False(t, srv.Exists(course.RoleCacheKey(existing.ID, newOwner)))
+	assert.True(t, srv.Exists(course.RoleCacheKey(existing.ID, student)))
+}
+
+func TestDeleteCourse_ClearsCachedRolesOfCourse(t *testing.T) {
+	srv := useTestRedis(t)
+	mockRepo := new(MockCourseRepo)
+	service := course.NewCourseService(mockRepo)
+	teacherID, student := uuid.New(), uuid.New()
+	existing := &entities.Course{ID: uuid.New(), OwnerTeacherID: teacherID}
+	otherCourse := uuid.New()
+
+	srv.Set(course.RoleCacheKey(existing.ID, teacherID), "TEACHER")
+	srv.Set(course.RoleCacheKey(existing.ID, student), "STUDENT")
+	srv.Set(course.RoleCacheKey(otherCourse, student), "STUDENT")
+
+	mockRepo.On("GetCourseByID", mock.Anything, existing.ID).Return(existing, nil)
+	mockRepo.On("DeleteCourse", mock.Anything, existing.ID).Return(nil)
+
+	err := service.DeleteCourse(context.Background(), teacherID, "TEACHER", existing.ID)
+
+	assert.NoError(t, err)
+	assert.False(t, srv.Exists(course.RoleCacheKey(existing.ID, teacherID)))
+	assert.False(t, srv.Exists(course.RoleCacheKey(existing.ID, student)))
+	assert.True(t, srv.Exists(course.RoleCacheKey(otherCourse, student)))
+}
@@ -0,0 +1,197 @@
+package test
+
+import (
+	"api-shiners/api/handlers"
+	"api-shiners/pkg/enrollment"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+//
+// ===== MOCK REPOSITORY =====
+//
+type MockEnrollmentRepo struct {
+	mock.Mock
+}
+
+func (m *MockEnrollmentRepo) GetEnrollmentsByCourse(ctx context.Context, courseID uuid.UUID) ([]entities.Enrollment, error) {
+	args := m.Called(ctx, courseID)
+	enrollments, _ := args.Get(0).([]entities.Enrollment)
+	return enrollments, args.Error(1)
+}
+
+func (m *MockEnrollmentRepo) GetEnrollmentsByUser(ctx context.Context, userID uuid.UUID) ([]entities.Enrollment, error) {
+	args := m.Called(ctx, userID)
+	enrollments, _ := args.Get(0).([]entities.Enrollment)
+	return enrollments, args.Error(1)
+}
+
+func (m *MockEnrollmentRepo) GetEnrollment(ctx context.Context, courseID, userID uuid.UUID) (*entities.Enrollment, error) {
+	args := m.Called(ctx, courseID, userID)
+	e, _ := args.Get(0).(*entities.Enrollment)
+	return e, args.Error(1)
+}
+
+func (m *MockEnrollmentRepo) CreateEnrollment(ctx context.Context, e *entities.Enrollment) (bool, error) {
+	args := m.Called(ctx, e)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEnrollmentRepo) DeleteEnrollment(ctx context.Context, courseID, userID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, courseID, userID)
+	return int64(args.Int(0)), args.Error(1)
+}
+
+func (m *MockEnrollmentRepo) GetUserByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
+	args := m.Called(ctx, id)
+	u, _ := args.Get(0).(*entities.User)
+	return u, args.Error(1)
+}
+
+func (m *MockEnrollmentRepo) GetUserByEmail(ctx context.Context, email string) (*entities.User, error) {
+	args := m.Called(ctx, email)
+	u, _ := args.Get(0).(*entities.User)
+	return u, args.Error(1)
+}
+
+func (m *MockEnrollmentRepo) GetCourseByJoinCode(ctx context.Context, code string) (*entities.Course, error) {
+	args := m.Called(ctx, code)
+	c, _ := args.Get(0).(*entities.Course)
+	return c, args.Error(1)
+}
+
+func (m *MockEnrollmentRepo) SetJoinCode(ctx context.Context, courseID uuid.UUID, code string) error {
+	args := m.Called(ctx, courseID, code)
+	return args.Error(0)
+}
+
+//
+// ===== TEST ENROLLMENT =====
+//
+func TestBulkEnroll_ReportsResultPerRow(t *testing.T) {
+	repo := new(MockEnrollmentRepo)
+	courseRepo := new(MockCourseRepo)
+	service := enrollment.NewEnrollmentService(repo, courseRepo)
+
+	c := &entities.Course{ID: uuid.New(), OwnerTeacherID: uuid.New()}
+	siti := &entities.User{ID: uuid.New(), Email: "siti@example.com"}
+	budi := &entities.User{ID: uuid.New(), Email: "budi@example.com"}
+
+	courseRepo.On("GetCourseByID", mock.Anything, c.ID).Return(c, nil)
+	repo.On("GetUserByEmail", mock.Anything, "siti@example.com").Return(siti, nil)
+	repo.On("GetUserByEmail", mock.Anything, "budi@example.com").Return(budi, nil)
+	repo.On("GetUserByEmail", mock.Anything, "hilang@example.com").Return(nil, gorm.ErrRecordNotFound)
+	repo.On("CreateEnrollment", mock.Anything, mock.MatchedBy(func(e *entities.Enrollment) bool { return e.UserID == siti.ID })).Return(true, nil)
+	repo.On("CreateEnrollment", mock.Anything, mock.MatchedBy(func(e *entities.Enrollment) bool { return e.UserID == budi.ID })).Return(false, nil)
+
+	csv := "email,role_in_course\nSiti@example.com,student\nbudi@example.com,\nhilang@example.com,\nbukan-email,\nsiti@example.com,\n,\n"
+
+	results, err := service.BulkEnroll(context.Background(), "TEACHER", c.ID, strings.NewReader(csv))
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 5)
+	assert.Equal(t, enrollment.BulkEnrollResult{Row: 2, Email: "siti@example.com", Status: enrollment.BulkStatusEnrolled}, results[0])
+	assert.Equal(t, enrollment.BulkStatusAlreadyEnrolled, results[1].Status)
+	assert.Equal(t, enrollment.BulkStatusFailed, results[2].Status)
+	assert.Equal(t, "user not found", results[2].Message)
+	assert.Equal(t, "invalid email format", results[3].Message)
+	assert.Equal(t, "duplicate email in file", results[4].Message)
+}
+
+func TestEnroll_DuplicateRejected(t *testing.T) {
+	repo := new(MockEnrollmentRepo)
+	courseRepo := new(MockCourseRepo)
+	service := enrollment.NewEnrollmentService(repo, courseRepo)
+
+	c := &entities.Course{ID: uuid.New(), OwnerTeacherID: uuid.New()}
+	student := &entities.User{ID: uuid.New()}
+
+	courseRepo.On("GetCourseByID", mock.Anything, c.ID).Return(c, nil)
+	repo.On("GetUserByID", mock.Anything, student.ID).Return(student, nil)
+	repo.On("CreateEnrollment", mock.Anything, mock.AnythingOfType("*entities.Enrollment")).Return(false, nil)
+
+	_, err := service.Enroll(context.Background(), "ADMIN", c.ID, student.ID, entities.CourseRoleStudent)
+
+	assert.ErrorIs(t, err, enrollment.ErrAlreadyEnrolled)
+}
+
+func TestJoinByCode_UnpublishedCourseRejected(t *testing.T) {
+	repo := new(MockEnrollmentRepo)
+	service := enrollment.NewEnrollmentService(repo, new(MockCourseRepo))
+
+	repo.On("GetCourseByJoinCode", mock.Anything, "K7PX2QMA").Return(&entities.Course{ID: uuid.New()}, nil)
+
+	_, err := service.JoinByCode(context.Background(), uuid.New(), " k7px2qma ")
+
+	assert.ErrorIs(t, err, enrollment.ErrInvalidJoinCode)
+}
+
+func TestGenerateJoinCode(t *testing.T) {
+	code, err := enrollment.GenerateJoinCode()
+	assert.NoError(t, err)
+	assert.Len(t, code, 8)
+	assert.NotContains(t, code, "0")
+	assert.NotContains(t, code, "O")
+}
+
+func TestGetCourseRole(t *testing.T) {
+	repo := new(MockEnrollmentRepo)
+	courseRepo := new(MockCourseRepo)
+	service := enrollment.NewEnrollmentService(repo, courseRepo)
+
+	owner := uuid.New()
+	student := uuid.New()
+	stranger := uuid.New()
+	c := &entities.Course{ID: uuid.New(), OwnerTeacherID: owner}
+
+	courseRepo.On("GetCourseByID", mock.Anything, c.ID).Return(c, nil)
+	repo.On("GetEnrollment", mock.Anything, c.ID, student).
+		Return(&entities.Enrollment{CourseID: c.ID, UserID: student, RoleInCourse: entities.CourseRoleStudent}, nil)
+	repo.On("GetEnrollment", mock.Anything, c.ID, stranger).Return(nil, gorm.ErrRecordNotFound)
+
+	role, err := service.GetCourseRole(context.Background(), c.ID, owner)
+	assert.NoError(t, err)
+	assert.Equal(t, entities.CourseRoleTeacher, role)
+
+	role, err = service.GetCourseRole(context.Background(), c.ID, student)
+	assert.NoError(t, err)
+	assert.Equal(t, entities.CourseRoleStudent, role)
+
+	role, err = service.GetCourseRole(context.Background(), c.ID, stranger)
+	assert.NoError(t, err)
+	assert.Equal(t, entities.CourseRole(""), role)
+}
+
+func TestEnrollmentHandler_UnexpectedErrorReturnsGeneric500(t *testing.T) {
+	repo := new(MockEnrollmentRepo)
+	controller := handlers.NewEnrollmentController(enrollment.NewEnrollmentService(repo, new(MockCourseRepo)))
+	userID := uuid.New()
+
+	repo.On("GetEnrollmentsByUser", mock.Anything, userID).Return(nil, errors.New("pq: relation \"enrollments\" does not exist"))
+
+	app := fiber.New()
+	app.Get("/enrollments/me", func(c *fiber.Ctx) error {
+		c.Locals("user_id", userID.String())
+		return c.Next()
+	}, controller.GetMyEnrollments)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/enrollments/me", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+
+	var body utils.ErrorResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "Internal server error", body.Message)
+}
@@ -0,0 +1,141 @@
+package test
+
+import (
+	"api-shiners/pkg/attempt"
+	"api-shiners/pkg/entities"
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockEventRepo struct {
+	mock.Mock
+}
+
+func (m *MockEventRepo) CreateEvents(ctx context.Context, events []entities.AttemptEvent) error {
+	args := m.Called(ctx, events)
+	return args.Error(0)
+}
+
+func (m *MockEventRepo) GetEventsByAttempt(ctx context.Context, attemptID uuid.UUID) ([]entities.AttemptEvent, error) {
+	args := m.Called(ctx, attemptID)
+	events, _ := args.Get(0).([]entities.AttemptEvent)
+	return events, args.Error(1)
+}
+
+func (m *MockEventRepo) GetEventsByQuiz(ctx context.Context, quizID uuid.UUID) ([]entities.AttemptEvent, error) {
+	args := m.Called(ctx, quizID)
+	events, _ := args.Get(0).([]entities.AttemptEvent)
+	return events, args.Error(1)
+}
+
+//
+// ===== TEST INTEGRITY =====
+//
+func TestAnalyze_FlagsIPChangeAndThresholds(t *testing.T) {
+	q := &entities.Quiz{MaxFocusLost: intPtr(2), MaxPaste: intPtr(0)}
+	start := time.Now()
+	event := func(sec int, eventType entities.AttemptEventType, ip string) entities.AttemptEvent {
+		return entities.AttemptEvent{Type: eventType, IPAddress: ip, OccurredAt: start.Add(time.Duration(sec) * time.Second)}
+	}
+	events := []entities.AttemptEvent{
+		event(1, entities.AttemptEventFocusLost, "10.0.0.5"),
+		event(2, entities.AttemptEventFocusLost, "10.0.0.5"),
+		event(3, entities.AttemptEventFullscreenExit, "10.0.0.5"),
+		event(4, entities.AttemptEventFocusLost, "10.0.0.9"),
+		event(5, entities.AttemptEventFocusLost, "10.0.0.9"),
+	}
+
+	counts, flags := attempt.Analyze(q, events)
+
+	assert.Equal(t, 4, counts[entities.AttemptEventFocusLost])
+	// fullscreen tanpa batas dan paste belum terjadi: tidak ditandai
+	assert.Len(t, flags, 2)
+	assert.Equal(t, attempt.FlagIPChanged, flags[0].Type)
+	assert.Equal(t, "10.0.0.5 -> 10.0.0.9", flags[0].Detail)
+	assert.Equal(t, attempt.FlagThresholdExceeded, flags[1].Type)
+	assert.Equal(t, entities.AttemptEventFocusLost, flags[1].EventType)
+	assert.Equal(t, 4, flags[1].Count)
+	assert.Equal(t, events[3].OccurredAt, flags[1].At)
+}
+
+func TestRecordEvents_UsesServerIPAndRejectsSubmitted(t *testing.T) {
+	eventRepo := new(MockEventRepo)
+	attemptRepo := new(MockAttemptRepo)
+	quizRepo := new(MockQuizRepo)
+	service := attempt.NewIntegrityService(eventRepo, attemptRepo, quizRepo)
+
+	courseID := uuid.New()
+	student := uuid.New()
+	q := &entities.Quiz{ID: uuid.New(), Module: entities.CourseModule{CourseID: courseID}}
+	a := &entities.QuizAttempt{ID: uuid.New(), QuizID: q.ID, StudentID: student, StartedAt: time.Now().Add(-time.Minute)}
+
+	attemptRepo.On("GetAttemptByID", mock.Anything, a.ID).Return(a, nil)
+	quizRepo.On("GetQuizByID", mock.Anything, q.ID).Return(q, nil)
+	eventRepo.On("CreateEvents", mock.Anything, mock.Anything).Return(nil)
+
+	// jam client yang jauh di masa depan diganti waktu server
+	future := time.Now().Add(time.Hour)
+	recorded, err := service.RecordEvents(context.Background(), student, courseID, a.ID, "10.0.0.5", "Chrome", []attempt.EventInput{
+		{Type: "focus_lost", OccurredAt: &future},
+		{Type: "PASTE", Detail: "42 characters"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, recorded)
+	saved := eventRepo.Calls[0].Arguments.Get(1).([]entities.AttemptEvent)
+	assert.Equal(t, entities.AttemptEventFocusLost, saved[0].Type)
+	assert.Equal(t, "10.0.0.5", saved[0].IPAddress)
+	assert.True(t, saved[0].OccurredAt.Before(future))
+
+	_, err = service.RecordEvents(context.Background(), student, courseID, a.ID, "10.0.0.5", "Chrome", []attempt.EventInput{{Type: "SCREENSHOT"}})
+	assert.ErrorIs(t, err, attempt.ErrInvalidEvent)
+
+	submittedAt := time.Now()
+	a.SubmittedAt = &submittedAt
+	_, err = service.RecordEvents(context.Background(), student, courseID, a.ID, "10.0.0.5", "Chrome", []attempt.EventInput{{Type: "PASTE"}})
+	assert.ErrorIs(t, err, attempt.ErrAlreadySubmitted)
+}
+
+func TestRecordRequest_ServerEventsFlagIPChange(t *testing.T) {
+	eventRepo := new(MockEventRepo)
+	service := attempt.NewIntegrityService(eventRepo, new(MockAttemptRepo), new(MockQuizRepo))
+	attemptID := uuid.New()
+
+	eventRepo.On("CreateEvents", mock.Anything, mock.Anything).Return(nil)
+
+	assert.NoError(t, service.RecordRequest(context.Background(), attemptID, entities.AttemptEventStarted, "10.0.0.5", "Chrome"))
+	assert.NoError(t, service.RecordRequest(context.Background(), attemptID, entities.AttemptEventSubmitted, "10.0.0.9", "Chrome"))
+
+	var events []entities.AttemptEvent
+	for _, call := range eventRepo.Calls {
+		events = append(events, call.Arguments.Get(1).([]entities.AttemptEvent)...)
+	}
+	assert.Equal(t, attemptID, events[0].AttemptID)
+	assert.Equal(t, entities.AttemptEventStarted, events[0].Type)
+
+	// client tidak mengirim kejadian apa pun, perubahan IP tetap terdeteksi dari request attempt
+	_, flags := attempt.Analyze(&entities.Quiz{}, events)
+	assert.Len(t, flags, 1)
+	assert.Equal(t, attempt.FlagIPChanged, flags[0].Type)
+	assert.Equal(t, "10.0.0.5 -> 10.0.0.9", flags[0].Detail)
+}
+
+func TestGetEventsByAttempt_OrdersByServerTime(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := attempt.NewEventRepository(db)
+	attemptID := uuid.New()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "attempt_events" WHERE attempt_id = $1 ORDER BY created_at ASC, occurred_at ASC`)).
+		WithArgs(attemptID).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := repo.GetEventsByAttempt(context.Background(), attemptID)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
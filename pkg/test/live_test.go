@@ -0,0 +1,176 @@
+package test
+
+import (
+	"api-shiners/pkg/config"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/live"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+//
+// ===== TEST LIVE QUIZ =====
+//
+func TestScore_FasterCorrectAnswersEarnMore(t *testing.T) {
+	limit := 20 * time.Second
+
+	assert.Equal(t, 1000, live.Score(true, 0, limit, 1))
+	assert.Equal(t, 750, live.Score(true, 10*time.Second, limit, 1))
+	assert.Equal(t, 500, live.Score(true, time.Minute, limit, 1))
+	assert.Equal(t, 1000, live.Score(true, 20*time.Second, limit, 2))
+	assert.Equal(t, 0, live.Score(false, 0, limit, 1))
+}
+
+func TestLiveSession_JoinAnswerAndLeaderboard(t *testing.T) {
+	store := live.NewMemoryStore()
+	quizRepo := new(MockQuizRepo)
+	enrollmentRepo := new(MockEnrollmentRepo)
+	service := live.NewLiveService(store, quizRepo, enrollmentRepo)
+	ctx := context.Background()
+
+	courseID := uuid.New()
+	question := singleChoiceQuestion(false, true)
+	essay := entities.Question{ID: uuid.New(), Type: entities.QuestionTypeEssay, Text: "Jelaskan"}
+	q := &entities.Quiz{ID: uuid.New(), Module: entities.CourseModule{CourseID: courseID}, Questions: []entities.Question{essay, question}}
+	quizRepo.On("GetQuizWithQuestions", mock.Anything, q.ID).Return(q, nil)
+
+	fast, slow := uuid.New(), uuid.New()
+	for _, id := range []uuid.UUID{fast, slow} {
+		enrollmentRepo.On("GetEnrollment", mock.Anything, courseID, id).
+			Return(&entities.Enrollment{UserID: id, CourseID: courseID, RoleInCourse: entities.CourseRoleStudent}, nil)
+		enrollmentRepo.On("GetUserByID", mock.Anything, id).Return(&entities.User{ID: id, Name: "Santri"}, nil)
+	}
+
+	session, err := service.OpenSession(ctx, uuid.New(), "TEACHER", courseID, q.ID, 0)
+	assert.NoError(t, err)
+	assert.Len(t, session.PIN, 6)
+	// essay tidak bisa dimainkan secara live
+	assert.Len(t, session.Questions, 1)
+
+	events, unsubscribe, err := service.Subscribe(ctx, session.ID)
+	assert.NoError(t, err)
+	defer unsubscribe()
+
+	_, ticket, err := service.Join(ctx, fast, session.PIN)
+	assert.NoError(t, err)
+	_, _, err = service.Join(ctx, slow, session.PIN)
+	assert.NoError(t, err)
+	connected, err := service.Connect(ctx, ticket)
+	assert.NoError(t, err)
+	assert.Equal(t, fast, connected.UserID)
+	_, err = service.Connect(ctx, ticket)
+	assert.ErrorIs(t, err, live.ErrTicketInvalid)
+
+	_, err = service.NextQuestion(ctx, "TEACHER", courseID, session.ID)
+	assert.NoError(t, err)
+
+	answer, err := service.SubmitAnswer(ctx, session.ID, fast, []uuid.UUID{question.Choices[1].ID})
+	assert.NoError(t, err)
+	assert.True(t, answer.IsCorrect)
+	_, err = service.SubmitAnswer(ctx, session.ID, fast, []uuid.UUID{question.Choices[0].ID})
+	assert.ErrorIs(t, err, live.ErrAlreadyAnswered)
+
+	// jawaban terakhir langsung menutup soal
+	_, err = service.SubmitAnswer(ctx, session.ID, slow, []uuid.UUID{question.Choices[0].ID})
+	assert.NoError(t, err)
+
+	current, leaderboard, err := service.GetSession(ctx, "TEACHER", courseID, session.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, live.StatusReveal, current.Status)
+	assert.Equal(t, fast, leaderboard[0].UserID)
+	assert.Greater(t, leaderboard[0].Score, 0)
+	assert.Equal(t, 0, leaderboard[1].Score)
+	assert.Len(t, events, 6) // 2 player_joined, question, 2 answer_count, reveal
+
+	finished, _, err := service.EndSession(ctx, "TEACHER", courseID, session.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, live.StatusFinished, finished.Status)
+	_, _, err = service.Join(ctx, fast, session.PIN)
+	assert.ErrorIs(t, err, live.ErrSessionNotFound)
+}
+
+func TestRedisStore_RejectsAnswerAfterReveal(t *testing.T) {
+	useTestRedis(t)
+	store := live.NewRedisStore(config.RedisClient)
+	ctx := context.Background()
+	sessionID := uuid.NewString()
+	first, late := live.Answer{UserID: uuid.New()}, live.Answer{UserID: uuid.New()}
+
+	added, err := store.RecordAnswer(ctx, sessionID, 0, first)
+	assert.NoError(t, err)
+	assert.True(t, added)
+	added, err = store.RecordAnswer(ctx, sessionID, 0, first)
+	assert.NoError(t, err)
+	assert.False(t, added)
+
+	revealed, err := store.MarkRevealed(ctx, sessionID, 0)
+	assert.NoError(t, err)
+	assert.True(t, revealed)
+
+	_, err = store.RecordAnswer(ctx, sessionID, 0, late)
+	assert.ErrorIs(t, err, live.ErrNoActiveQuestion)
+	answers, err := store.GetAnswers(ctx, sessionID, 0)
+	assert.NoError(t, err)
+	assert.Len(t, answers, 1)
+}
+
+func TestLiveSession_AnotherInstanceRevealsAfterDeadline(t *testing.T) {
+	useTestRedis(t)
+	quizRepo := new(MockQuizRepo)
+	enrollmentRepo := new(MockEnrollmentRepo)
+	// dua instance API yang berbagi Redis
+	host := live.NewLiveService(live.NewRedisStore(config.RedisClient), quizRepo, enrollmentRepo)
+	other := live.NewLiveService(live.NewRedisStore(config.RedisClient), quizRepo, enrollmentRepo)
+	ctx := context.Background()
+
+	courseID := uuid.New()
+	question := singleChoiceQuestion(false, true)
+	q := &entities.Quiz{ID: uuid.New(), Module: entities.CourseModule{CourseID: courseID}, Questions: []entities.Question{question}}
+	quizRepo.On("GetQuizWithQuestions", mock.Anything, q.ID).Return(q, nil)
+
+	answered, silent := uuid.New(), uuid.New()
+	for _, id := range []uuid.UUID{answered, silent} {
+		enrollmentRepo.On("GetEnrollment", mock.Anything, courseID, id).
+			Return(&entities.Enrollment{UserID: id, CourseID: courseID, RoleInCourse: entities.CourseRoleStudent}, nil)
+		enrollmentRepo.On("GetUserByID", mock.Anything, id).Return(&entities.User{ID: id, Name: "Santri"}, nil)
+	}
+
+	session, err := host.OpenSession(ctx, uuid.New(), "TEACHER", courseID, q.ID, 0)
+	assert.NoError(t, err)
+	for _, id := range []uuid.UUID{answered, silent} {
+		_, _, err = other.Join(ctx, id, session.PIN)
+		assert.NoError(t, err)
+	}
+	_, err = host.NextQuestion(ctx, "TEACHER", courseID, session.ID)
+	assert.NoError(t, err)
+	_, err = other.SubmitAnswer(ctx, session.ID, answered, []uuid.UUID{question.Choices[1].ID})
+	assert.NoError(t, err)
+
+	// countdown belum habis
+	count, err := other.RevealExpired(ctx, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	deadline := time.Now().Add(time.Duration(live.DefaultDurationSec)*time.Second + 2*time.Second)
+	count, err = other.RevealExpired(ctx, deadline)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	current, leaderboard, err := host.GetSession(ctx, "TEACHER", courseID, session.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, live.StatusReveal, current.Status)
+	assert.Equal(t, answered, leaderboard[0].UserID)
+	assert.Greater(t, leaderboard[0].Score, 0)
+
+	// jawaban yang terlambat ditolak dan jadwal tidak diproses dua kali
+	_, err = other.SubmitAnswer(ctx, session.ID, silent, []uuid.UUID{question.Choices[1].ID})
+	assert.ErrorIs(t, err, live.ErrNoActiveQuestion)
+	count, err = host.RevealExpired(ctx, deadline)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
@@ -0,0 +1,132 @@
+package test
+
+import (
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/logbook"
+	"api-shiners/pkg/material"
+	"api-shiners/pkg/storage"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func pngUpload(name string) material.FileUpload {
+	data := "\x89PNG\r\n\x1a\n" + strings.Repeat("x", 100)
+	return material.FileUpload{FileName: name, Size: int64(len(data)), Reader: strings.NewReader(data)}
+}
+
+//
+// ===== TEST LOGBOOK ATTACHMENT =====
+//
+func TestAddAttachment_StoresFileAndEnforcesLimits(t *testing.T) {
+	store, err := storage.NewLocalStorage(t.TempDir(), "http://localhost:3000/api/files", "secret")
+	require.NoError(t, err)
+	repo := new(MockLogBookRepo)
+	policy := material.UploadPolicy{MaxBytes: 1 << 10, AllowedTypes: []string{"image/png", "application/pdf"}}
+	service := logbook.NewLogBookService(repo, new(MockEnrollmentRepo), store, policy)
+
+	courseID, studentID := uuid.New(), uuid.New()
+	draft := &entities.LogBook{ID: uuid.New(), CourseID: courseID, StudentID: studentID, Status: entities.StatusDraft}
+	locked := &entities.LogBook{ID: uuid.New(), CourseID: courseID, StudentID: studentID, Status: entities.StatusLocked}
+	entry := &entities.LogBookEntry{ID: uuid.New(), LogBookID: draft.ID}
+	full := &entities.LogBookEntry{ID: uuid.New(), LogBookID: draft.ID}
+
+	repo.On("GetLogBookByID", mock.Anything, draft.ID).Return(draft, nil)
+	repo.On("GetLogBookByID", mock.Anything, locked.ID).Return(locked, nil)
+	repo.On("GetEntryByID", mock.Anything, entry.ID).Return(entry, nil)
+	repo.On("GetEntryByID", mock.Anything, full.ID).Return(full, nil)
+	repo.On("CreateAttachment", mock.Anything, draft.ID, mock.MatchedBy(func(a *entities.LogBookAttachment) bool {
+		return a.EntryID == full.ID
+	})).Return(logbook.ErrTooManyAttachments)
+	repo.On("CreateAttachment", mock.Anything, draft.ID, mock.Anything).Return(nil)
+
+	ctx := context.Background()
+	attachment, err := service.AddAttachment(ctx, studentID, courseID, draft.ID, entry.ID, pngUpload("foto lapangan.png"))
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", attachment.ContentType)
+	assert.Equal(t, "foto_lapangan.png", attachment.FileName)
+	file, err := store.Open(ctx, attachment.FileKey)
+	require.NoError(t, err)
+	file.Close()
+
+	big := pngUpload("besar.png")
+	big.Size = 2 << 10
+	_, err = service.AddAttachment(ctx, studentID, courseID, draft.ID, entry.ID, big)
+	assert.ErrorIs(t, err, material.ErrFileTooLarge)
+
+	script := material.FileUpload{FileName: "tugas.png", Size: 20, Reader: strings.NewReader("#!/bin/sh\necho hello")}
+	_, err = service.AddAttachment(ctx, studentID, courseID, draft.ID, entry.ID, script)
+	assert.ErrorIs(t, err, material.ErrFileTypeNotAllowed)
+
+	_, err = service.AddAttachment(ctx, studentID, courseID, draft.ID, full.ID, pngUpload("lagi.png"))
+	assert.ErrorIs(t, err, logbook.ErrTooManyAttachments)
+	rejected := repo.Calls[len(repo.Calls)-1].Arguments.Get(2).(*entities.LogBookAttachment)
+	_, err = store.Open(ctx, rejected.FileKey)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	_, err = service.AddAttachment(ctx, studentID, courseID, locked.ID, entry.ID, pngUpload("telat.png"))
+	assert.ErrorIs(t, err, logbook.ErrNotEditable)
+	repo.AssertNumberOfCalls(t, "CreateAttachment", 2)
+}
+
+func TestDeleteEntry_RemovesAttachmentFiles(t *testing.T) {
+	store, err := storage.NewLocalStorage(t.TempDir(), "http://localhost:3000/api/files", "secret")
+	require.NoError(t, err)
+	repo := new(MockLogBookRepo)
+	service := logbook.NewLogBookService(repo, new(MockEnrollmentRepo), store, material.UploadPolicy{})
+
+	courseID, studentID := uuid.New(), uuid.New()
+	lb := &entities.LogBook{ID: uuid.New(), CourseID: courseID, StudentID: studentID, Status: entities.StatusDraft}
+	entry := &entities.LogBookEntry{ID: uuid.New(), LogBookID: lb.ID}
+	ctx := context.Background()
+
+	attachments := []entities.LogBookAttachment{
+		{ID: uuid.New(), EntryID: entry.ID, FileKey: "logbooks/x/a/foto.png"},
+		{ID: uuid.New(), EntryID: entry.ID, FileKey: "logbooks/x/b/laporan.pdf"},
+	}
+	for _, a := range attachments {
+		require.NoError(t, store.Put(ctx, a.FileKey, strings.NewReader("data"), 4, "application/octet-stream"))
+	}
+
+	repo.On("GetLogBookByID", mock.Anything, lb.ID).Return(lb, nil)
+	repo.On("GetEntryByID", mock.Anything, entry.ID).Return(entry, nil)
+	repo.On("GetAttachmentsByEntry", mock.Anything, entry.ID).Return(attachments, nil)
+	repo.On("DeleteEntry", mock.Anything, lb.ID, entry.ID).Return(nil)
+
+	require.NoError(t, service.DeleteEntry(ctx, studentID, courseID, lb.ID, entry.ID))
+
+	for _, a := range attachments {
+		_, err := store.Open(ctx, a.FileKey)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	}
+	repo.AssertCalled(t, "DeleteEntry", mock.Anything, lb.ID, entry.ID)
+}
+
+func TestAddAttachment_SubmittedMeanwhileRemovesStoredFile(t *testing.T) {
+	store, err := storage.NewLocalStorage(t.TempDir(), "http://localhost:3000/api/files", "secret")
+	require.NoError(t, err)
+	repo := new(MockLogBookRepo)
+	policy := material.UploadPolicy{MaxBytes: 1 << 10, AllowedTypes: []string{"image/png"}}
+	service := logbook.NewLogBookService(repo, new(MockEnrollmentRepo), store, policy)
+
+	courseID, studentID := uuid.New(), uuid.New()
+	lb := &entities.LogBook{ID: uuid.New(), CourseID: courseID, StudentID: studentID, Status: entities.StatusDraft}
+	entry := &entities.LogBookEntry{ID: uuid.New(), LogBookID: lb.ID}
+
+	repo.On("GetLogBookByID", mock.Anything, lb.ID).Return(lb, nil)
+	repo.On("GetEntryByID", mock.Anything, entry.ID).Return(entry, nil)
+	// logbook disubmit request lain setelah pengecekan status di service
+	repo.On("CreateAttachment", mock.Anything, lb.ID, mock.Anything).Return(logbook.ErrNotEditable)
+
+	_, err = service.AddAttachment(context.Background(), studentID, courseID, lb.ID, entry.ID, pngUpload("foto.png"))
+
+	assert.ErrorIs(t, err, logbook.ErrNotEditable)
+	key := repo.Calls[len(repo.Calls)-1].Arguments.Get(2).(*entities.LogBookAttachment).FileKey
+	_, err = store.Open(context.Background(), key)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
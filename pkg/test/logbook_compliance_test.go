@@ -0,0 +1,98 @@
+package test
+
+import (
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/logbook"
+	"api-shiners/pkg/material"
+	"api-shiners/pkg/utils"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+//
+// ===== TEST LOGBOOK COMPLIANCE =====
+//
+func TestBuildComplianceReport_ListsMissingDatesWithTotals(t *testing.T) {
+	courseID := uuid.New()
+	budi := entities.User{ID: uuid.New(), Name: "Budi", Email: "budi@example.com"}
+	ani := entities.User{ID: uuid.New(), Name: "Ani", Email: "ani@example.com"}
+	citra := entities.User{ID: uuid.New(), Name: "Citra", Email: "citra@example.com"}
+
+	entry := func(date string) entities.LogBookEntry { return entities.LogBookEntry{EntryDate: day(date)} }
+	logBooks := []entities.LogBook{
+		// Senin 6 - Minggu 12 Januari 2025
+		{ID: uuid.New(), StudentID: budi.ID, PeriodStart: day("2025-01-06"), PeriodEnd: day("2025-01-12"), Status: entities.StatusSubmitted,
+			Entries: []entities.LogBookEntry{entry("2025-01-06"), entry("2025-01-07"), entry("2025-01-09"), entry("2025-01-10")}},
+		{ID: uuid.New(), StudentID: ani.ID, PeriodStart: day("2025-01-06"), PeriodEnd: day("2025-01-12"), Status: entities.StatusDraft,
+			Entries: []entities.LogBookEntry{entry("2025-01-06"), entry("2025-01-07"), entry("2025-01-08"), entry("2025-01-09")}},
+	}
+	opts := logbook.ComplianceOptions{From: day("2025-01-01"), To: day("2025-01-31"), ExcludeWeekends: true}
+
+	// laporan dibuat Jumat 10 Januari: hari setelahnya belum dihitung
+	report := logbook.BuildComplianceReport(courseID, []entities.User{budi, ani, citra}, logBooks, opts, day("2025-01-10").Add(8*time.Hour))
+
+	assert.Equal(t, 3, report.Totals.Students)
+	assert.Equal(t, 1, report.Totals.StudentsWithoutLogBook)
+	assert.Equal(t, 10, report.Totals.ExpectedDays)
+	assert.Equal(t, 2, report.Totals.MissingDays)
+	assert.Equal(t, 80.0, *report.Totals.CompliancePercent)
+
+	// urut nama: Ani, Budi, Citra
+	assert.Equal(t, []string{"2025-01-10"}, report.Students[0].MissingDates)
+	assert.Equal(t, []string{"2025-01-08"}, report.Students[1].MissingDates)
+	assert.Equal(t, 80.0, *report.Students[1].CompliancePercent)
+	assert.Equal(t, 0, report.Students[2].LogBooks)
+	assert.Nil(t, report.Students[2].CompliancePercent)
+
+	var buf bytes.Buffer
+	assert.NoError(t, logbook.WriteComplianceCSV(&buf, report))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 4)
+	assert.Equal(t, budi.ID.String()+",Budi,budi@example.com,1,5,4,1,80,2025-01-08", lines[2])
+	assert.True(t, strings.HasSuffix(lines[3], ",0,0,0,0,,"))
+}
+
+func TestGetComplianceReport_RejectsStudentAndInvalidRange(t *testing.T) {
+	service := logbook.NewLogBookService(new(MockLogBookRepo), new(MockEnrollmentRepo), nil, material.UploadPolicy{})
+	courseID := uuid.New()
+
+	_, err := service.GetComplianceReport(context.Background(), "STUDENT", courseID,
+		logbook.ComplianceOptions{From: day("2025-01-01"), To: day("2025-01-31")})
+	assert.ErrorIs(t, err, logbook.ErrForbidden)
+
+	_, err = service.GetComplianceReport(context.Background(), "TEACHER", courseID,
+		logbook.ComplianceOptions{From: day("2025-02-01"), To: day("2025-01-31")})
+	assert.ErrorIs(t, err, logbook.ErrInvalidRange)
+
+	_, err = service.GetComplianceReport(context.Background(), "TEACHER", courseID,
+		logbook.ComplianceOptions{From: day("2025-01-01"), To: day("2026-01-02")})
+	assert.ErrorIs(t, err, logbook.ErrRangeTooLong)
+}
+
+func TestWriteComplianceCSV_EscapesFormulaCells(t *testing.T) {
+	for input, expected := range map[string]string{
+		`=HYPERLINK("http://evil","klik")`: `'=HYPERLINK("http://evil","klik")`,
+		"+62 812":                          "'+62 812",
+		"-1":                               "'-1",
+		"@SUM(A1)":                         "'@SUM(A1)",
+		"\tBudi":                           "'\tBudi",
+		"\rBudi":                           "'\rBudi",
+		"Budi = Ani":                       "Budi = Ani",
+		"":                                 "",
+	} {
+		assert.Equal(t, expected, utils.SafeCSVCell(input))
+	}
+
+	report := &logbook.ComplianceReport{Students: []logbook.StudentCompliance{
+		{StudentID: uuid.New(), Name: "=1+1", Email: "@evil.example.com"},
+	}}
+	var buf bytes.Buffer
+	assert.NoError(t, logbook.WriteComplianceCSV(&buf, report))
+	assert.Contains(t, buf.String(), ",'=1+1,'@evil.example.com,")
+}
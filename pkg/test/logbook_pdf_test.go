@@ -0,0 +1,87 @@
+package test
+
+import (
+	"api-shiners/pkg/document"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/logbook"
+	"api-shiners/pkg/material"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+//
+// ===== TEST LOGBOOK PDF =====
+//
+func TestRenderLogBookPDF_SpansPagesWithLongEntries(t *testing.T) {
+	submittedAt := day("2025-02-01").Add(9 * time.Hour)
+	lb := &entities.LogBook{ID: uuid.New(), PeriodStart: day("2025-01-01"), PeriodEnd: day("2025-01-31"),
+		Status: entities.StatusSubmitted, SubmittedAt: &submittedAt,
+		Course:  entities.Course{Code: "IPA-7", Title: "Ilmu Pengetahuan Alam"},
+		Student: entities.User{Name: "Siti Nurhaliza", Email: "siti@example.com"}}
+	for d := 0; d < 31; d++ {
+		lb.Entries = append(lb.Entries, entities.LogBookEntry{ID: uuid.New(), EntryDate: day("2025-01-01").AddDate(0, 0, d),
+			Content: "Praktikum pengamatan café — " + strings.Repeat("mencatat hasil pengamatan tumbuhan ", 8)})
+	}
+	comments := []entities.LogBookComment{
+		{EntryID: &lb.Entries[2].ID, Body: "Tambahkan foto hasil pengamatan.", Author: entities.User{Name: "Bu Ani"}, CreatedAt: day("2025-02-02")},
+		{Body: "Secara umum sudah baik.", Author: entities.User{Name: "Bu Ani"}, CreatedAt: day("2025-02-02")},
+	}
+	header := document.Header{SchoolName: "SMP Negeri 1 Bandung", Address: "Jl. Pendidikan No. 1", Contact: "(022) 123456"}
+
+	doc := logbook.RenderLogBookPDF(header, lb, comments, day("2025-02-03"))
+
+	assert.NoError(t, doc.Err())
+	var buf bytes.Buffer
+	assert.NoError(t, doc.Output(&buf))
+	assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")))
+	// entry panjang tidak muat dalam satu halaman
+	assert.Greater(t, bytes.Count(buf.Bytes(), []byte("/Type /Page\n")), 1)
+	assert.Equal(t, "logbook-siti-nurhaliza-2025-01-01.pdf", logbook.PDFFileName(lb))
+}
+
+func TestExportPDF_FollowsLogBookAccessRules(t *testing.T) {
+	repo := new(MockLogBookRepo)
+	service := logbook.NewLogBookService(repo, new(MockEnrollmentRepo), nil, material.UploadPolicy{})
+
+	courseID, ownerID := uuid.New(), uuid.New()
+	lb := &entities.LogBook{ID: uuid.New(), CourseID: courseID, StudentID: ownerID,
+		PeriodStart: day("2025-01-06"), PeriodEnd: day("2025-01-10"), Status: entities.StatusDraft}
+	repo.On("GetLogBookByID", mock.Anything, lb.ID).Return(lb, nil)
+	repo.On("GetComments", mock.Anything, lb.ID).Return([]entities.LogBookComment{}, nil)
+
+	_, _, err := service.ExportPDF(context.Background(), uuid.New(), "STUDENT", courseID, lb.ID, document.Header{SchoolName: "Shiners"})
+	assert.ErrorIs(t, err, logbook.ErrLogBookNotFound)
+
+	doc, _, err := service.ExportPDF(context.Background(), uuid.New(), "TEACHER", courseID, lb.ID, document.Header{SchoolName: "Shiners"})
+	assert.NoError(t, err)
+	assert.NotNil(t, doc)
+
+	_, _, err = service.ExportPDF(context.Background(), ownerID, "STUDENT", uuid.New(), lb.ID, document.Header{SchoolName: "Shiners"})
+	assert.ErrorIs(t, err, logbook.ErrLogBookNotFound)
+}
+
+func TestRenderLogBookPDF_EmbedsUnicodeFontForNonLatinText(t *testing.T) {
+	lb := &entities.LogBook{ID: uuid.New(), PeriodStart: day("2025-01-06"), PeriodEnd: day("2025-01-10"), Status: entities.StatusDraft,
+		Course:  entities.Course{Title: "Bahasa Ελληνικά"},
+		Student: entities.User{Name: "Дмитрий Güneş", Email: "dmitri@example.com"},
+		Entries: []entities.LogBookEntry{{ID: uuid.New(), EntryDate: day("2025-01-06"),
+			Content: "Belajar ğ, ş, ł dan Кириллица 🎉 " + strings.Repeat("Ünite çalışması ", 20)}}}
+	comments := []entities.LogBookComment{{Body: "Отлично 👍", Author: entities.User{Name: "Łukasz"}, CreatedAt: day("2025-01-11")}}
+
+	doc := logbook.RenderLogBookPDF(document.Header{SchoolName: "Sekolah Ünggul"}, lb, comments, day("2025-01-11"))
+
+	assert.NoError(t, doc.Err())
+	var buf bytes.Buffer
+	assert.NoError(t, doc.Output(&buf))
+	// teks dicetak dengan font TrueType yang di-embed, bukan Helvetica cp1252
+	assert.Contains(t, buf.String(), "/FontFile2")
+	assert.Contains(t, buf.String(), "/BaseFont /utf8dejavu")
+	assert.NotContains(t, buf.String(), "Helvetica")
+}
@@ -0,0 +1,119 @@
+package test
+
+import (
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/logbook"
+	"context"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+// newMockDB membuka gorm di atas sqlmock untuk menguji query repository tanpa database
+func newMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	return db, mock
+}
+
+// onDeleteRules memetakan nama foreign key ke aturan ON DELETE yang akan dibuat AutoMigrate
+func onDeleteRules(t *testing.T, models ...interface{}) map[string]string {
+	rules := map[string]string{}
+	for _, model := range models {
+		s, err := schema.Parse(model, &sync.Map{}, schema.NamingStrategy{})
+		require.NoError(t, err)
+		for _, rel := range s.Relationships.Relations {
+			if c := rel.ParseConstraint(); c != nil {
+				rules[c.Name] = c.OnDelete
+			}
+		}
+	}
+	return rules
+}
+
+//
+// ===== TEST LOGBOOK REPOSITORY =====
+//
+func TestLogBookSchema_ForeignKeyDeleteRules(t *testing.T) {
+	rules := onDeleteRules(t, &entities.LogBook{}, &entities.LogBookEntry{}, &entities.LogBookAttachment{},
+		&entities.LogBookReview{}, &entities.LogBookComment{})
+
+	assert.Equal(t, "CASCADE", rules["fk_log_books_entries"])
+	assert.Equal(t, "CASCADE", rules["fk_log_book_entries_attachments"])
+	// riwayat review dan komentar tetap ada walau entry atau akun teacher dihapus
+	assert.Equal(t, "SET NULL", rules["fk_log_book_comments_entry"])
+	assert.Equal(t, "SET NULL", rules["fk_log_book_reviews_actor"])
+}
+
+// expectLockDraft mengharapkan baris logbook dikunci dengan SELECT ... FOR UPDATE
+func expectLockDraft(mock sqlmock.Sqlmock, logBookID uuid.UUID, status entities.StatusType) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id","status" FROM "log_books" WHERE id = $1`) + `.*FOR UPDATE`).
+		WithArgs(logBookID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(logBookID, status))
+}
+
+func TestDeleteEntry_DeletesAttachmentRowsInTransaction(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := logbook.NewLogBookRepository(db)
+	logBookID, entryID := uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	expectLockDraft(mock, logBookID, entities.StatusDraft)
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "log_book_attachments" WHERE entry_id = $1`)).
+		WithArgs(entryID).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "log_book_entries" WHERE id = $1`)).
+		WithArgs(entryID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.DeleteEntry(context.Background(), logBookID, entryID))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateEntry_RejectedWhenLogBookSubmittedMeanwhile(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := logbook.NewLogBookRepository(db)
+	entry := &entities.LogBookEntry{LogBookID: uuid.New(), EntryDate: day("2025-01-06"), Content: "Praktikum"}
+
+	// logbook sudah disubmit setelah service mengecek status: tidak ada INSERT
+	mock.ExpectBegin()
+	expectLockDraft(mock, entry.LogBookID, entities.StatusSubmitted)
+	mock.ExpectRollback()
+
+	err := repo.CreateEntry(context.Background(), entry)
+
+	assert.ErrorIs(t, err, logbook.ErrNotEditable)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateAttachment_CountsUnderDraftLock(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := logbook.NewLogBookRepository(db)
+	logBookID := uuid.New()
+	attachment := &entities.LogBookAttachment{ID: uuid.New(), EntryID: uuid.New(), FileName: "foto.png"}
+
+	// entry sudah berisi 5 lampiran saat kunci didapat: tidak ada INSERT
+	mock.ExpectBegin()
+	expectLockDraft(mock, logBookID, entities.StatusDraft)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "log_book_attachments" WHERE entry_id = $1`)).
+		WithArgs(attachment.EntryID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+	mock.ExpectRollback()
+
+	err := repo.CreateAttachment(context.Background(), logBookID, attachment)
+
+	assert.ErrorIs(t, err, logbook.ErrTooManyAttachments)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,111 @@
+package test
+
+import (
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/logbook"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockScheduleRepo struct {
+	mock.Mock
+}
+
+func (m *MockScheduleRepo) GetScheduleByCourse(ctx context.Context, courseID uuid.UUID) (*entities.LogBookSchedule, error) {
+	args := m.Called(ctx, courseID)
+	schedule, _ := args.Get(0).(*entities.LogBookSchedule)
+	return schedule, args.Error(1)
+}
+
+func (m *MockScheduleRepo) SaveSchedule(ctx context.Context, schedule *entities.LogBookSchedule) error {
+	args := m.Called(ctx, schedule)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepo) DeleteSchedule(ctx context.Context, courseID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockScheduleRepo) GetActiveSchedules(ctx context.Context) ([]entities.LogBookSchedule, error) {
+	args := m.Called(ctx)
+	schedules, _ := args.Get(0).([]entities.LogBookSchedule)
+	return schedules, args.Error(1)
+}
+
+func (m *MockScheduleRepo) CreatePeriodLogBooks(ctx context.Context, courseID uuid.UUID, start, end time.Time) (int64, error) {
+	args := m.Called(ctx, courseID, start, end)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockScheduleRepo) GetLogBooksToClose(ctx context.Context, courseID uuid.UUID, endBefore time.Time, includeSubmitted bool, limit int) ([]entities.LogBook, error) {
+	args := m.Called(ctx, courseID, endBefore, includeSubmitted, limit)
+	logBooks, _ := args.Get(0).([]entities.LogBook)
+	return logBooks, args.Error(1)
+}
+
+//
+// ===== TEST LOGBOOK SCHEDULE =====
+//
+func TestPeriodFor_WeeklyAndMonthlyClippedToSchedule(t *testing.T) {
+	end := day("2025-03-12")
+	weekly := &entities.LogBookSchedule{Frequency: entities.LogBookWeekly, WeekStart: time.Monday,
+		StartDate: day("2025-01-08"), EndDate: &end}
+
+	// Rabu 8 Jan: minggu Senin 6 - Minggu 12, dipotong ke tanggal mulai jadwal
+	start, finish, ok := logbook.PeriodFor(weekly, day("2025-01-08"))
+	assert.True(t, ok)
+	assert.Equal(t, day("2025-01-08"), start)
+	assert.Equal(t, day("2025-01-12"), finish)
+
+	start, finish, ok = logbook.PeriodFor(weekly, day("2025-01-19").Add(15*time.Hour))
+	assert.True(t, ok)
+	assert.Equal(t, day("2025-01-13"), start)
+	assert.Equal(t, day("2025-01-19"), finish)
+
+	_, _, ok = logbook.PeriodFor(weekly, day("2025-03-13"))
+	assert.False(t, ok)
+
+	monthly := &entities.LogBookSchedule{Frequency: entities.LogBookMonthly, StartDate: day("2025-01-01"), EndDate: &end}
+	start, finish, ok = logbook.PeriodFor(monthly, day("2025-03-05"))
+	assert.True(t, ok)
+	assert.Equal(t, day("2025-03-01"), start)
+	assert.Equal(t, day("2025-03-12"), finish)
+}
+
+func TestAutoClose_LocksAfterGraceAndSkipsChangedLogBooks(t *testing.T) {
+	repo := new(MockScheduleRepo)
+	logBookRepo := new(MockLogBookRepo)
+	service := logbook.NewScheduleService(repo, logBookRepo)
+
+	courseID := uuid.New()
+	schedule := entities.LogBookSchedule{CourseID: courseID, CloseAction: entities.LogBookAutoLock, GraceDays: 2, IsActive: true}
+	draft := entities.LogBook{ID: uuid.New(), CourseID: courseID, Status: entities.StatusDraft}
+	submitted := entities.LogBook{ID: uuid.New(), CourseID: courseID, Status: entities.StatusSubmitted}
+
+	now := day("2025-01-15").Add(10 * time.Hour)
+	var reviews []*entities.LogBookReview
+	repo.On("GetActiveSchedules", mock.Anything).Return([]entities.LogBookSchedule{schedule}, nil)
+	repo.On("GetLogBooksToClose", mock.Anything, courseID, day("2025-01-13"), true, mock.Anything).
+		Return([]entities.LogBook{draft, submitted}, nil)
+	logBookRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(lb *entities.LogBook) bool { return lb.ID == draft.ID }), entities.StatusDraft, mock.Anything).
+		Return(true, nil).Run(func(args mock.Arguments) { reviews = append(reviews, args.Get(3).(*entities.LogBookReview)) })
+	// sudah diubah teacher di antara query dan update
+	logBookRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(lb *entities.LogBook) bool { return lb.ID == submitted.ID }), entities.StatusSubmitted, mock.Anything).
+		Return(false, nil)
+
+	closed, err := service.AutoClose(context.Background(), now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, closed)
+	assert.Len(t, reviews, 1)
+	assert.Nil(t, reviews[0].ActorID)
+	assert.Equal(t, entities.LogBookActionAutoLocked, reviews[0].Action)
+	assert.Equal(t, entities.StatusDraft, reviews[0].FromStatus)
+	assert.Equal(t, entities.StatusLocked, reviews[0].ToStatus)
+}
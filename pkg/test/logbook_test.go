@@ -0,0 +1,273 @@
+package test
+
+import (
+	"api-shiners/api/handlers"
+	"api-shiners/pkg/document"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/logbook"
+	"api-shiners/pkg/material"
+	"api-shiners/pkg/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLogBookRepo struct {
+	mock.Mock
+}
+
+func (m *MockLogBookRepo) CreateLogBook(ctx context.Context, logBook *entities.LogBook) error {
+	args := m.Called(ctx, logBook)
+	return args.Error(0)
+}
+
+func (m *MockLogBookRepo) GetLogBookByID(ctx context.Context, id uuid.UUID) (*entities.LogBook, error) {
+	args := m.Called(ctx, id)
+	logBook, _ := args.Get(0).(*entities.LogBook)
+	return logBook, args.Error(1)
+}
+
+func (m *MockLogBookRepo) GetLogBooksByCourse(ctx context.Context, courseID uuid.UUID, filter logbook.ListFilter) ([]entities.LogBook, error) {
+	args := m.Called(ctx, courseID, filter)
+	logBooks, _ := args.Get(0).([]entities.LogBook)
+	return logBooks, args.Error(1)
+}
+
+func (m *MockLogBookRepo) GetLogBooksInRange(ctx context.Context, courseID uuid.UUID, from, to time.Time) ([]entities.LogBook, error) {
+	args := m.Called(ctx, courseID, from, to)
+	logBooks, _ := args.Get(0).([]entities.LogBook)
+	return logBooks, args.Error(1)
+}
+
+func (m *MockLogBookRepo) HasOverlappingPeriod(ctx context.Context, courseID, studentID uuid.UUID, start, end time.Time) (bool, error) {
+	args := m.Called(ctx, courseID, studentID, start, end)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockLogBookRepo) UpdateStatus(ctx context.Context, logBook *entities.LogBook, from entities.StatusType, review *entities.LogBookReview) (bool, error) {
+	args := m.Called(ctx, logBook, from, review)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockLogBookRepo) GetReviews(ctx context.Context, logBookID uuid.UUID) ([]entities.LogBookReview, error) {
+	args := m.Called(ctx, logBookID)
+	reviews, _ := args.Get(0).([]entities.LogBookReview)
+	return reviews, args.Error(1)
+}
+
+func (m *MockLogBookRepo) CreateComment(ctx context.Context, comment *entities.LogBookComment) error {
+	args := m.Called(ctx, comment)
+	return args.Error(0)
+}
+
+func (m *MockLogBookRepo) GetComments(ctx context.Context, logBookID uuid.UUID) ([]entities.LogBookComment, error) {
+	args := m.Called(ctx, logBookID)
+	comments, _ := args.Get(0).([]entities.LogBookComment)
+	return comments, args.Error(1)
+}
+
+func (m *MockLogBookRepo) CreateEntry(ctx context.Context, entry *entities.LogBookEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockLogBookRepo) GetEntryByID(ctx context.Context, id uuid.UUID) (*entities.LogBookEntry, error) {
+	args := m.Called(ctx, id)
+	entry, _ := args.Get(0).(*entities.LogBookEntry)
+	return entry, args.Error(1)
+}
+
+func (m *MockLogBookRepo) UpdateEntry(ctx context.Context, entry *entities.LogBookEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockLogBookRepo) DeleteEntry(ctx context.Context, logBookID, id uuid.UUID) error {
+	args := m.Called(ctx, logBookID, id)
+	return args.Error(0)
+}
+
+func (m *MockLogBookRepo) CountEntries(ctx context.Context, logBookID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, logBookID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockLogBookRepo) EntryDateTaken(ctx context.Context, logBookID uuid.UUID, date time.Time, excludeID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, logBookID, date, excludeID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockLogBookRepo) CreateAttachment(ctx context.Context, logBookID uuid.UUID, attachment *entities.LogBookAttachment) error {
+	args := m.Called(ctx, logBookID, attachment)
+	return args.Error(0)
+}
+
+func (m *MockLogBookRepo) GetAttachmentByID(ctx context.Context, id uuid.UUID) (*entities.LogBookAttachment, error) {
+	args := m.Called(ctx, id)
+	attachment, _ := args.Get(0).(*entities.LogBookAttachment)
+	return attachment, args.Error(1)
+}
+
+func (m *MockLogBookRepo) GetAttachmentsByEntry(ctx context.Context, entryID uuid.UUID) ([]entities.LogBookAttachment, error) {
+	args := m.Called(ctx, entryID)
+	attachments, _ := args.Get(0).([]entities.LogBookAttachment)
+	return attachments, args.Error(1)
+}
+
+func (m *MockLogBookRepo) DeleteAttachment(ctx context.Context, logBookID, id uuid.UUID) error {
+	args := m.Called(ctx, logBookID, id)
+	return args.Error(0)
+}
+
+func day(s string) time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return t
+}
+
+//
+// ===== TEST LOGBOOK =====
+//
+func TestAddEntry_RejectsOutOfPeriodAndNonDraft(t *testing.T) {
+	repo := new(MockLogBookRepo)
+	service := logbook.NewLogBookService(repo, new(MockEnrollmentRepo), nil, material.UploadPolicy{})
+
+	courseID, studentID := uuid.New(), uuid.New()
+	draft := &entities.LogBook{ID: uuid.New(), CourseID: courseID, StudentID: studentID,
+		PeriodStart: day("2025-01-06"), PeriodEnd: day("2025-01-10"), Status: entities.StatusDraft}
+	submitted := &entities.LogBook{ID: uuid.New(), CourseID: courseID, StudentID: studentID,
+		PeriodStart: day("2025-01-13"), PeriodEnd: day("2025-01-17"), Status: entities.StatusSubmitted}
+
+	repo.On("GetLogBookByID", mock.Anything, draft.ID).Return(draft, nil)
+	repo.On("GetLogBookByID", mock.Anything, submitted.ID).Return(submitted, nil)
+	repo.On("EntryDateTaken", mock.Anything, draft.ID, day("2025-01-07"), uuid.Nil).Return(false, nil)
+	repo.On("CreateEntry", mock.Anything, mock.Anything).Return(nil)
+
+	_, err := service.AddEntry(context.Background(), studentID, courseID, draft.ID,
+		logbook.EntryInput{EntryDate: day("2025-01-11"), Content: "Praktik"})
+	assert.ErrorIs(t, err, logbook.ErrEntryOutOfPeriod)
+
+	_, err = service.AddEntry(context.Background(), studentID, courseID, submitted.ID,
+		logbook.EntryInput{EntryDate: day("2025-01-14"), Content: "Praktik"})
+	assert.ErrorIs(t, err, logbook.ErrNotEditable)
+
+	// logbook student lain tidak terlihat
+	_, err = service.AddEntry(context.Background(), uuid.New(), courseID, draft.ID,
+		logbook.EntryInput{EntryDate: day("2025-01-07"), Content: "Praktik"})
+	assert.ErrorIs(t, err, logbook.ErrLogBookNotFound)
+
+	entry, err := service.AddEntry(context.Background(), studentID, courseID, draft.ID,
+		logbook.EntryInput{EntryDate: day("2025-01-07").Add(9 * time.Hour), Content: "  Praktik jaringan  "})
+	assert.NoError(t, err)
+	assert.Equal(t, day("2025-01-07"), entry.EntryDate)
+	assert.Equal(t, "Praktik jaringan", entry.Content)
+}
+
+func TestSubmitAndLock_EnforceStateMachine(t *testing.T) {
+	repo := new(MockLogBookRepo)
+	service := logbook.NewLogBookService(repo, new(MockEnrollmentRepo), nil, material.UploadPolicy{})
+
+	courseID, studentID := uuid.New(), uuid.New()
+	lb := &entities.LogBook{ID: uuid.New(), CourseID: courseID, StudentID: studentID,
+		PeriodStart: day("2025-01-06"), PeriodEnd: day("2025-01-10"), Status: entities.StatusDraft}
+
+	repo.On("GetLogBookByID", mock.Anything, lb.ID).Return(lb, nil)
+	repo.On("CountEntries", mock.Anything, lb.ID).Return(int64(3), nil)
+	repo.On("UpdateStatus", mock.Anything, lb, mock.Anything, mock.Anything).Return(true, nil)
+
+	teacherID := uuid.New()
+	// DRAFT tidak bisa langsung dikunci
+	_, err := service.Lock(context.Background(), teacherID, string(entities.CourseRoleTeacher), courseID, lb.ID)
+	assert.ErrorIs(t, err, logbook.ErrInvalidTransition)
+	assert.EqualError(t, err, "cannot change logbook status from DRAFT to LOCKED")
+
+	submitted, err := service.Submit(context.Background(), studentID, courseID, lb.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, entities.StatusSubmitted, submitted.Status)
+	assert.NotNil(t, submitted.SubmittedAt)
+	repo.AssertCalled(t, "UpdateStatus", mock.Anything, lb, entities.StatusDraft, mock.Anything)
+
+	_, err = service.Submit(context.Background(), studentID, courseID, lb.ID)
+	assert.ErrorIs(t, err, logbook.ErrInvalidTransition)
+
+	_, err = service.Lock(context.Background(), studentID, string(entities.CourseRoleStudent), courseID, lb.ID)
+	assert.ErrorIs(t, err, logbook.ErrForbidden)
+
+	locked, err := service.Lock(context.Background(), teacherID, string(entities.CourseRoleTeacher), courseID, lb.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, entities.StatusLocked, locked.Status)
+	assert.NotNil(t, locked.LockedAt)
+}
+
+func TestRequestRevisionAndApprove_RecordHistory(t *testing.T) {
+	repo := new(MockLogBookRepo)
+	service := logbook.NewLogBookService(repo, new(MockEnrollmentRepo), nil, material.UploadPolicy{})
+
+	courseID, teacherID := uuid.New(), uuid.New()
+	submittedAt := time.Now().Add(-time.Hour)
+	lb := &entities.LogBook{ID: uuid.New(), CourseID: courseID, StudentID: uuid.New(),
+		Status: entities.StatusSubmitted, SubmittedAt: &submittedAt}
+	teacher := string(entities.CourseRoleTeacher)
+
+	var reviews []*entities.LogBookReview
+	repo.On("GetLogBookByID", mock.Anything, lb.ID).Return(lb, nil)
+	repo.On("UpdateStatus", mock.Anything, lb, mock.Anything, mock.Anything).Return(true, nil).
+		Run(func(args mock.Arguments) { reviews = append(reviews, args.Get(3).(*entities.LogBookReview)) })
+
+	_, err := service.RequestRevision(context.Background(), teacherID, teacher, courseID, lb.ID, "   ")
+	assert.ErrorIs(t, err, logbook.ErrReasonRequired)
+
+	revised, err := service.RequestRevision(context.Background(), teacherID, teacher, courseID, lb.ID, "Lengkapi entry tanggal 8")
+	assert.NoError(t, err)
+	assert.Equal(t, entities.StatusDraft, revised.Status)
+	assert.Nil(t, revised.SubmittedAt)
+
+	// DRAFT hasil revisi belum bisa disetujui
+	_, err = service.Approve(context.Background(), teacherID, teacher, courseID, lb.ID, "")
+	assert.ErrorIs(t, err, logbook.ErrInvalidTransition)
+
+	lb.Status = entities.StatusSubmitted
+	approved, err := service.Approve(context.Background(), teacherID, teacher, courseID, lb.ID, "Bagus")
+	assert.NoError(t, err)
+	assert.Equal(t, entities.StatusLocked, approved.Status)
+	assert.NotNil(t, approved.LockedAt)
+
+	assert.Len(t, reviews, 2)
+	assert.Equal(t, entities.LogBookActionRevisionRequested, reviews[0].Action)
+	assert.Equal(t, "Lengkapi entry tanggal 8", reviews[0].Reason)
+	assert.Equal(t, &teacherID, reviews[0].ActorID)
+	assert.Equal(t, entities.LogBookActionApproved, reviews[1].Action)
+	assert.Equal(t, entities.StatusSubmitted, reviews[1].FromStatus)
+	assert.Equal(t, entities.StatusLocked, reviews[1].ToStatus)
+}
+
+func TestLogBookHandler_UnexpectedErrorReturnsGeneric500(t *testing.T) {
+	repo := new(MockLogBookRepo)
+	service := logbook.NewLogBookService(repo, new(MockEnrollmentRepo), nil, material.UploadPolicy{})
+	controller := handlers.NewLogBookController(service, document.Header{})
+	courseID, logBookID := uuid.New(), uuid.New()
+
+	repo.On("GetLogBookByID", mock.Anything, logBookID).Return(nil, errors.New("pq: canceling statement due to statement timeout"))
+
+	app := fiber.New()
+	app.Get("/courses/:course_id/logbooks/:logbook_id", func(c *fiber.Ctx) error {
+		c.Locals("user_id", uuid.NewString())
+		c.Locals("course_role", "TEACHER")
+		return c.Next()
+	}, controller.GetLogBook)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/courses/"+courseID.String()+"/logbooks/"+logBookID.String(), nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+
+	var body utils.ErrorResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "Internal server error", body.Message)
+}
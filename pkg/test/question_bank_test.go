@@ -0,0 +1,177 @@
+package test
+
+import (
+	"api-shiners/pkg/attempt"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/quiz"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+//
+// ===== MOCK REPOSITORY =====
+//
+type MockBankRepo struct {
+	mock.Mock
+}
+
+func (m *MockBankRepo) CreateBank(ctx context.Context, bank *entities.QuestionBank) error {
+	args := m.Called(ctx, bank)
+	return args.Error(0)
+}
+
+func (m *MockBankRepo) GetBankByID(ctx context.Context, id uuid.UUID) (*entities.QuestionBank, error) {
+	args := m.Called(ctx, id)
+	bank, _ := args.Get(0).(*entities.QuestionBank)
+	return bank, args.Error(1)
+}
+
+func (m *MockBankRepo) GetBanks(ctx context.Context, filter quiz.BankFilter) ([]entities.QuestionBank, error) {
+	args := m.Called(ctx, filter)
+	banks, _ := args.Get(0).([]entities.QuestionBank)
+	return banks, args.Error(1)
+}
+
+func (m *MockBankRepo) UpdateBank(ctx context.Context, bank *entities.QuestionBank) error {
+	args := m.Called(ctx, bank)
+	return args.Error(0)
+}
+
+func (m *MockBankRepo) DeleteBank(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockBankRepo) GetMaxBankQuestionPosition(ctx context.Context, bankID uuid.UUID) (int, error) {
+	args := m.Called(ctx, bankID)
+	return args.Int(0), args.Error(1)
+}
+
+// bankQuestions membuat n soal single choice lengkap di bank
+func bankQuestions(bankID uuid.UUID, n int) []entities.Question {
+	questions := make([]entities.Question, 0, n)
+	for i := 0; i < n; i++ {
+		q := singleChoiceQuestion(true, false, false)
+		q.BankID = &bankID
+		questions = append(questions, q)
+	}
+	return questions
+}
+
+//
+// ===== TEST QUESTION BANK =====
+//
+func TestDrawPaper_SameSeedSamePaper(t *testing.T) {
+	bankID := uuid.New()
+	pool := entities.QuizPool{ID: uuid.New(), BankID: bankID, DrawCount: 3}
+	fixed := singleChoiceQuestion(true, false)
+	q := &entities.Quiz{ID: uuid.New(), ShuffleQuestions: true, Questions: []entities.Question{fixed}, Pools: []entities.QuizPool{pool}}
+	candidates := map[uuid.UUID][]entities.Question{pool.ID: bankQuestions(bankID, 10)}
+
+	first := quiz.DrawPaper(q, candidates, 42)
+	assert.Len(t, first, 4)
+	assert.Contains(t, first, fixed.ID)
+	assert.Equal(t, first, quiz.DrawPaper(q, candidates, 42))
+
+	// urutan kandidat dari database tidak mempengaruhi hasil undian
+	reversed := make([]entities.Question, 0, 10)
+	for i := len(candidates[pool.ID]) - 1; i >= 0; i-- {
+		reversed = append(reversed, candidates[pool.ID][i])
+	}
+	assert.Equal(t, first, quiz.DrawPaper(q, map[uuid.UUID][]entities.Question{pool.ID: reversed}, 42))
+}
+
+func TestBuildPaper_ChoiceOrderRebuiltFromSeed(t *testing.T) {
+	question := singleChoiceQuestion(true, false, false, false, false)
+	q := &entities.Quiz{ShuffleChoices: true, Questions: []entities.Question{question}}
+
+	paper := quiz.BuildPaper(q, []uuid.UUID{question.ID}, nil, 7)
+	again := quiz.BuildPaper(q, []uuid.UUID{question.ID}, nil, 7)
+
+	assert.Equal(t, paper[0].Choices, again[0].Choices)
+	assert.ElementsMatch(t, question.Choices, paper[0].Choices)
+	// urutan asli quiz tidak ikut berubah
+	assert.True(t, q.Questions[0].Choices[0].IsCorrect)
+}
+
+func TestStartAttempt_StoresSeedAndDrawnQuestions(t *testing.T) {
+	repo := new(MockAttemptRepo)
+	quizRepo := new(MockQuizRepo)
+	courseRepo := new(MockCourseRepo)
+	service := attempt.NewAttemptService(repo, noOverrides(), quizRepo, courseRepo)
+
+	c := &entities.Course{ID: uuid.New(), IsPublished: true}
+	bankID := uuid.New()
+	pool := entities.QuizPool{ID: uuid.New(), BankID: bankID, Tags: []string{"aljabar"}, DrawCount: 2}
+	fixed := singleChoiceQuestion(true, false)
+	q := &entities.Quiz{
+		ID:             uuid.New(),
+		IsPublished:    true,
+		AttemptAllowed: 1,
+		Module:         entities.CourseModule{CourseID: c.ID},
+		Questions:      []entities.Question{fixed},
+		Pools:          []entities.QuizPool{pool},
+	}
+	candidates := bankQuestions(bankID, 5)
+	// soal bank yang belum lengkap tidak boleh terundi
+	incomplete := entities.Question{ID: uuid.New(), BankID: &bankID, Type: entities.QuestionTypeSingleChoice}
+	student := uuid.New()
+
+	courseRepo.On("GetCourseByID", mock.Anything, c.ID).Return(c, nil)
+	quizRepo.On("GetQuizWithQuestions", mock.Anything, q.ID).Return(q, nil)
+	quizRepo.On("GetPoolQuestions", mock.Anything, bankID, []string{"aljabar"}).Return(append(candidates, incomplete), nil)
+	quizRepo.On("GetQuestionsByIDs", mock.Anything, mock.Anything).Return(candidates, nil)
+	repo.On("GetOpenAttempt", mock.Anything, q.ID, student).Return(nil, gorm.ErrRecordNotFound)
+	repo.On("CreateAttempt", mock.Anything, mock.Anything, 1).Return(nil)
+
+	started, paper, err := service.StartAttempt(context.Background(), student, "STUDENT", c.ID, q.ID)
+
+	assert.NoError(t, err)
+	assert.Len(t, started.QuestionIDs, 3)
+	assert.NotContains(t, started.QuestionIDs, incomplete.ID)
+	assert.Equal(t, started.QuestionIDs, quiz.DrawPaper(q, map[uuid.UUID][]entities.Question{pool.ID: candidates}, started.Seed))
+	assert.Len(t, paper.Questions, 3)
+	// paper dikembalikan sebagai salinan, quiz asli tidak berubah
+	assert.Len(t, q.Questions, 1)
+}
+
+func TestPublishQuiz_PoolNeedsEnoughBankQuestions(t *testing.T) {
+	repo := new(MockQuizRepo)
+	service := quiz.NewQuizService(repo, new(MockBankRepo), new(MockCourseRepo))
+
+	courseID := uuid.New()
+	bankID := uuid.New()
+	pool := entities.QuizPool{ID: uuid.New(), BankID: bankID, DrawCount: 4}
+	q := &entities.Quiz{ID: uuid.New(), Module: entities.CourseModule{CourseID: courseID}, Pools: []entities.QuizPool{pool}}
+	repo.On("GetQuizWithQuestions", mock.Anything, q.ID).Return(q, nil)
+	repo.On("GetPoolQuestions", mock.Anything, bankID, []string(nil)).Return(bankQuestions(bankID, 3), nil)
+
+	_, err := service.SetPublished(context.Background(), "TEACHER", courseID, q.ID, true)
+
+	var invalid *quiz.ValidationError
+	assert.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "pools[1]", invalid.Errors[0].Field)
+	repo.AssertNotCalled(t, "UpdateQuiz", mock.Anything, mock.Anything)
+}
+
+func TestCreatePool_RejectsOtherTeachersPersonalBank(t *testing.T) {
+	repo := new(MockQuizRepo)
+	bankRepo := new(MockBankRepo)
+	service := quiz.NewQuizService(repo, bankRepo, new(MockCourseRepo))
+
+	courseID := uuid.New()
+	q := &entities.Quiz{ID: uuid.New(), Module: entities.CourseModule{CourseID: courseID}}
+	bank := &entities.QuestionBank{ID: uuid.New(), OwnerID: uuid.New()}
+	repo.On("GetQuizByID", mock.Anything, q.ID).Return(q, nil)
+	bankRepo.On("GetBankByID", mock.Anything, bank.ID).Return(bank, nil)
+
+	_, err := service.CreatePool(context.Background(), uuid.New(), "TEACHER", courseID, q.ID, quiz.PoolInput{BankID: bank.ID, DrawCount: 1})
+
+	assert.ErrorIs(t, err, quiz.ErrBankNotFound)
+	repo.AssertNotCalled(t, "CreatePool", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,109 @@
+package test
+
+import (
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/quiz"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const giftSample = `// soal latihan
+$CATEGORY: $course$/Aljabar
+
+::Q1:: Hasil 2 + 2 adalah {=4 ~3 ~5}
+
+Bumi itu bulat. {T}
+
+Ibu kota Indonesia {=Jakarta =DKI Jakarta}
+
+Nilai pi dua desimal {#3.14:0.005}
+
+Pilih bilangan prima {~%50%2 ~%50%3 ~%-100%4}
+
+Jelaskan teorema Pythagoras. {}
+`
+
+//
+// ===== TEST IMPORT =====
+//
+func TestParseQuestions_GIFT(t *testing.T) {
+	parsed, errs, err := quiz.ParseQuestions(quiz.ImportFormatGIFT, []byte(giftSample))
+
+	assert.NoError(t, err)
+	assert.Empty(t, errs)
+	assert.Len(t, parsed, 6)
+
+	assert.Equal(t, 4, parsed[0].Line)
+	assert.Equal(t, "Hasil 2 + 2 adalah", parsed[0].Input.Text)
+	assert.Equal(t, string(entities.QuestionTypeSingleChoice), parsed[0].Input.Type)
+	assert.True(t, parsed[0].Input.Choices[0].IsCorrect)
+	assert.Equal(t, []string{"aljabar"}, parsed[0].Input.Tags)
+
+	assert.Equal(t, string(entities.QuestionTypeTrueFalse), parsed[1].Input.Type)
+	assert.Equal(t, []string{"Jakarta", "DKI Jakarta"}, parsed[2].Input.AcceptedAnswers)
+	assert.Equal(t, 3.14, *parsed[3].Input.NumericAnswer)
+	assert.Equal(t, string(entities.QuestionTypeMultipleChoice), parsed[4].Input.Type)
+	assert.Equal(t, string(entities.QuestionTypeEssay), parsed[5].Input.Type)
+}
+
+func TestImportQuestions_ReportsLineNumbers(t *testing.T) {
+	repo := new(MockQuizRepo)
+	service := quiz.NewQuizService(repo, new(MockBankRepo), new(MockCourseRepo))
+
+	courseID := uuid.New()
+	q := &entities.Quiz{ID: uuid.New(), Module: entities.CourseModule{CourseID: courseID}}
+	repo.On("GetQuizByID", mock.Anything, q.ID).Return(q, nil)
+	repo.On("GetMaxQuestionPosition", mock.Anything, q.ID).Return(3, nil)
+
+	aiken := "Ibu kota Jawa Barat?\nA. Bandung\nB. Bogor\nANSWER: C\n\nIbu kota Jawa Tengah?\nA. Semarang\nB. Solo\nANSWER: A\n"
+	_, err := service.ImportQuestions(context.Background(), "TEACHER", courseID, q.ID, quiz.ImportInput{
+		Format: quiz.ImportFormatAiken,
+		Data:   []byte(aiken),
+	})
+
+	var invalid *quiz.ValidationError
+	assert.True(t, errors.As(err, &invalid))
+	assert.Len(t, invalid.Errors, 1)
+	assert.Equal(t, "line 4", invalid.Errors[0].Field)
+	repo.AssertNotCalled(t, "CreateQuestions", mock.Anything, mock.Anything)
+}
+
+func TestImportQuestions_AppendsAfterExistingPositions(t *testing.T) {
+	repo := new(MockQuizRepo)
+	service := quiz.NewQuizService(repo, new(MockBankRepo), new(MockCourseRepo))
+
+	courseID := uuid.New()
+	q := &entities.Quiz{ID: uuid.New(), Module: entities.CourseModule{CourseID: courseID}}
+	repo.On("GetQuizByID", mock.Anything, q.ID).Return(q, nil)
+	repo.On("GetMaxQuestionPosition", mock.Anything, q.ID).Return(3, nil)
+	repo.On("CreateQuestions", mock.Anything, mock.Anything).Return(nil)
+
+	csvData := "type,question,answer,option_a,option_b,option_c\n" +
+		"SINGLE_CHOICE,2 + 3 = ?,B,4,5,6\n" +
+		"TRUE_FALSE,Air mendidih pada 100 derajat Celsius,true,,,\n"
+
+	preview, err := service.ImportQuestions(context.Background(), "TEACHER", courseID, q.ID, quiz.ImportInput{
+		Format: quiz.ImportFormatCSV,
+		Data:   []byte(csvData),
+		DryRun: true,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, preview.Questions, 2)
+	repo.AssertNotCalled(t, "CreateQuestions", mock.Anything, mock.Anything)
+
+	result, err := service.ImportQuestions(context.Background(), "TEACHER", courseID, q.ID, quiz.ImportInput{
+		Format: quiz.ImportFormatCSV,
+		Data:   []byte(csvData),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, *result.Questions[0].Position)
+	assert.Equal(t, 5, *result.Questions[1].Position)
+	assert.True(t, result.Questions[0].Choices[1].IsCorrect)
+	assert.Equal(t, q.ID, *result.Questions[1].QuizID)
+	repo.AssertCalled(t, "CreateQuestions", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,164 @@
+package test
+
+import (
+	"api-shiners/pkg/attempt"
+	"api-shiners/pkg/entities"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+//
+// ===== MOCK REPOSITORY =====
+//
+type MockOverrideRepo struct {
+	mock.Mock
+}
+
+func (m *MockOverrideRepo) GetOverride(ctx context.Context, quizID, studentID uuid.UUID) (*entities.QuizOverride, error) {
+	args := m.Called(ctx, quizID, studentID)
+	o, _ := args.Get(0).(*entities.QuizOverride)
+	return o, args.Error(1)
+}
+
+func (m *MockOverrideRepo) GetOverridesByQuiz(ctx context.Context, quizID uuid.UUID) ([]entities.QuizOverride, error) {
+	args := m.Called(ctx, quizID)
+	overrides, _ := args.Get(0).([]entities.QuizOverride)
+	return overrides, args.Error(1)
+}
+
+func (m *MockOverrideRepo) SaveOverride(ctx context.Context, o *entities.QuizOverride) error {
+	args := m.Called(ctx, o)
+	return args.Error(0)
+}
+
+func (m *MockOverrideRepo) DeleteOverride(ctx context.Context, quizID, studentID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, quizID, studentID)
+	return int64(args.Int(0)), args.Error(1)
+}
+
+func (m *MockOverrideRepo) GetLatestCloseAt(ctx context.Context, quizID uuid.UUID) (*time.Time, error) {
+	args := m.Called(ctx, quizID)
+	closeAt, _ := args.Get(0).(*time.Time)
+	return closeAt, args.Error(1)
+}
+
+// noOverrides: tidak ada student yang memiliki override
+func noOverrides() *MockOverrideRepo {
+	repo := new(MockOverrideRepo)
+	repo.On("GetOverride", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Maybe()
+	return repo
+}
+
+//
+// ===== TEST OVERRIDE =====
+//
+func TestStartAttempt_OverrideExtendsWindowAttemptsAndTime(t *testing.T) {
+	repo := new(MockAttemptRepo)
+	overrideRepo := new(MockOverrideRepo)
+	quizRepo := new(MockQuizRepo)
+	courseRepo := new(MockCourseRepo)
+	service := attempt.NewAttemptService(repo, overrideRepo, quizRepo, courseRepo)
+
+	c := &entities.Course{ID: uuid.New(), IsPublished: true}
+	closeAt := time.Now().Add(-time.Minute)
+	q := &entities.Quiz{
+		ID:             uuid.New(),
+		IsPublished:    true,
+		CloseAt:        &closeAt,
+		TimeLimitSec:   intPtr(600),
+		AttemptAllowed: 1,
+		Module:         entities.CourseModule{CourseID: c.ID},
+		Questions:      []entities.Question{singleChoiceQuestion(true, false)},
+	}
+	student := uuid.New()
+	extended := time.Now().Add(24 * time.Hour)
+	override := &entities.QuizOverride{QuizID: q.ID, StudentID: student, CloseAt: &extended, TimeLimitSec: intPtr(900), AttemptAllowed: intPtr(3)}
+
+	courseRepo.On("GetCourseByID", mock.Anything, c.ID).Return(c, nil)
+	quizRepo.On("GetQuizWithQuestions", mock.Anything, q.ID).Return(q, nil)
+	overrideRepo.On("GetOverride", mock.Anything, q.ID, student).Return(override, nil)
+	repo.On("GetOpenAttempt", mock.Anything, q.ID, student).Return(nil, gorm.ErrRecordNotFound)
+	repo.On("CreateAttempt", mock.Anything, mock.Anything, 3).Return(nil)
+
+	started, settings, err := service.StartAttempt(context.Background(), student, "STUDENT", c.ID, q.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, started.StartedAt.Add(15*time.Minute), *attempt.Deadline(settings, started))
+	// pengaturan quiz untuk student lain tidak berubah
+	assert.Equal(t, 600, *q.TimeLimitSec)
+	assert.Equal(t, closeAt, *q.CloseAt)
+}
+
+func TestSetOverride_RejectsStudentOutsideCourse(t *testing.T) {
+	overrideRepo := new(MockOverrideRepo)
+	quizRepo := new(MockQuizRepo)
+	enrollmentRepo := new(MockEnrollmentRepo)
+	service := attempt.NewOverrideService(overrideRepo, new(MockAttemptRepo), quizRepo, enrollmentRepo)
+
+	courseID := uuid.New()
+	q := &entities.Quiz{ID: uuid.New(), Module: entities.CourseModule{CourseID: courseID}}
+	outsider := uuid.New()
+	teacher := uuid.New()
+
+	quizRepo.On("GetQuizByID", mock.Anything, q.ID).Return(q, nil)
+	enrollmentRepo.On("GetEnrollment", mock.Anything, courseID, outsider).Return(nil, gorm.ErrRecordNotFound)
+	enrollmentRepo.On("GetEnrollment", mock.Anything, courseID, teacher).
+		Return(&entities.Enrollment{UserID: teacher, RoleInCourse: entities.CourseRoleTeacher}, nil)
+
+	_, err := service.SetOverride(context.Background(), teacher, "TEACHER", courseID, q.ID, outsider, attempt.OverrideInput{TimeLimitSec: intPtr(900)})
+	assert.ErrorIs(t, err, attempt.ErrStudentNotEnrolled)
+
+	_, err = service.SetOverride(context.Background(), teacher, "TEACHER", courseID, q.ID, teacher, attempt.OverrideInput{TimeLimitSec: intPtr(900)})
+	assert.ErrorIs(t, err, attempt.ErrStudentNotEnrolled)
+
+	overrideRepo.AssertNotCalled(t, "SaveOverride", mock.Anything, mock.Anything)
+}
+
+func TestGetRoster_ShowsEffectiveSettingsPerStudent(t *testing.T) {
+	overrideRepo := new(MockOverrideRepo)
+	attemptRepo := new(MockAttemptRepo)
+	quizRepo := new(MockQuizRepo)
+	enrollmentRepo := new(MockEnrollmentRepo)
+	service := attempt.NewOverrideService(overrideRepo, attemptRepo, quizRepo, enrollmentRepo)
+
+	courseID := uuid.New()
+	q := &entities.Quiz{ID: uuid.New(), TimeLimitSec: intPtr(600), AttemptAllowed: 1, Module: entities.CourseModule{CourseID: courseID}}
+	accommodated := entities.User{ID: uuid.New(), Name: "Budi"}
+	regular := entities.User{ID: uuid.New(), Name: "Sari"}
+	teacher := entities.User{ID: uuid.New(), Name: "Guru"}
+	submittedAt := time.Now()
+
+	quizRepo.On("GetQuizByID", mock.Anything, q.ID).Return(q, nil)
+	enrollmentRepo.On("GetEnrollmentsByCourse", mock.Anything, courseID).Return([]entities.Enrollment{
+		{UserID: teacher.ID, User: teacher, RoleInCourse: entities.CourseRoleTeacher},
+		{UserID: accommodated.ID, User: accommodated, RoleInCourse: entities.CourseRoleStudent},
+		{UserID: regular.ID, User: regular, RoleInCourse: entities.CourseRoleStudent},
+	}, nil)
+	overrideRepo.On("GetOverridesByQuiz", mock.Anything, q.ID).Return([]entities.QuizOverride{
+		{QuizID: q.ID, StudentID: accommodated.ID, TimeLimitSec: intPtr(900), AttemptAllowed: intPtr(2)},
+	}, nil)
+	attemptRepo.On("GetAttemptsByQuiz", mock.Anything, q.ID).Return([]entities.QuizAttempt{
+		{StudentID: accommodated.ID, SubmittedAt: &submittedAt, Score: 60},
+		{StudentID: accommodated.ID, SubmittedAt: &submittedAt, Score: 80},
+		{StudentID: regular.ID},
+	}, nil)
+
+	roster, _, err := service.GetRoster(context.Background(), "TEACHER", courseID, q.ID)
+
+	assert.NoError(t, err)
+	assert.Len(t, roster, 2)
+	assert.Equal(t, 900, *roster[0].Settings.TimeLimitSec)
+	assert.Equal(t, 2, roster[0].Settings.AttemptAllowed)
+	assert.Equal(t, 2, roster[0].AttemptsUsed)
+	assert.Equal(t, 80.0, *roster[0].BestScore)
+	assert.Nil(t, roster[1].Override)
+	assert.Equal(t, 600, *roster[1].Settings.TimeLimitSec)
+	assert.True(t, roster[1].InProgress)
+	assert.Nil(t, roster[1].BestScore)
+}
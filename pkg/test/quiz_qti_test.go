@@ -0,0 +1,164 @@
+package test
+
+import (
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/quiz"
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func qtiSampleQuiz() *entities.Quiz {
+	answer, tolerance := 9.81, 0.05
+	trueFalse := entities.Question{ID: uuid.New(), Type: entities.QuestionTypeTrueFalse, Text: "Matahari terbit dari timur."}
+	trueFalse.Choices = []entities.Choice{{Text: "True", IsCorrect: true}, {Text: "False"}}
+	multiple := entities.Question{ID: uuid.New(), Type: entities.QuestionTypeMultipleChoice, Text: "Pilih bilangan prima"}
+	multiple.Choices = []entities.Choice{{Text: "2", IsCorrect: true}, {Text: "4"}, {Text: "5", IsCorrect: true}}
+
+	return &entities.Quiz{
+		ID:    uuid.New(),
+		Title: "Ulangan <Harian> & Remedial",
+		Questions: []entities.Question{
+			singleChoiceQuestion(false, true, false),
+			trueFalse,
+			multiple,
+			{ID: uuid.New(), Type: entities.QuestionTypeShortAnswer, Text: "Ibu kota Indonesia?", AcceptedAnswers: []string{"Jakarta", "DKI Jakarta"}, AnswerPattern: `^jak(arta)?$`},
+			{ID: uuid.New(), Type: entities.QuestionTypeNumeric, Text: "Percepatan gravitasi (m/s²)?", NumericAnswer: &answer, NumericTolerance: tolerance},
+			{ID: uuid.New(), Type: entities.QuestionTypeEssay, Text: "Jelaskan hukum Newton\nsecara singkat."},
+		},
+	}
+}
+
+//
+// ===== TEST QTI =====
+//
+func TestQTI_RoundTripPreservesQuestions(t *testing.T) {
+	original := qtiSampleQuiz()
+
+	var buf bytes.Buffer
+	assert.NoError(t, quiz.WriteQTI(&buf, original))
+
+	parsed, errs, err := quiz.ParseQuestions(quiz.ImportFormatQTI, buf.Bytes())
+	assert.NoError(t, err)
+	assert.Empty(t, errs)
+	assert.Len(t, parsed, len(original.Questions))
+
+	for i, question := range original.Questions {
+		input := parsed[i].Input
+		assert.Equal(t, question.Text, input.Text)
+		assert.Equal(t, string(question.Type), input.Type)
+		assert.Len(t, input.Choices, len(question.Choices))
+		for j, c := range question.Choices {
+			assert.Equal(t, c.Text, input.Choices[j].Text)
+			assert.Equal(t, c.IsCorrect, input.Choices[j].IsCorrect)
+		}
+	}
+
+	shortAnswer := parsed[3].Input
+	assert.Equal(t, []string{"Jakarta", "DKI Jakarta"}, shortAnswer.AcceptedAnswers)
+	assert.Equal(t, `^jak(arta)?$`, shortAnswer.AnswerPattern)
+
+	numeric := parsed[4].Input
+	assert.Equal(t, 9.81, *numeric.NumericAnswer)
+	assert.Equal(t, 0.05, *numeric.NumericTolerance)
+}
+
+func TestQTI_ReportsBrokenItemByFileName(t *testing.T) {
+	_, errs, err := quiz.ParseQuestions(quiz.ImportFormatQTI, []byte("bukan zip"))
+
+	assert.NoError(t, err)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "file", errs[0].Field)
+}
+
+// qtiPackage menyusun zip QTI dari daftar file
+func qtiPackage(t *testing.T, files map[string][]byte) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		assert.NoError(t, err)
+		_, err = f.Write(content)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+// qtiSampleItem mengambil satu file item hasil export
+func qtiSampleItem(t *testing.T) []byte {
+	var buf bytes.Buffer
+	assert.NoError(t, quiz.WriteQTI(&buf, &entities.Quiz{ID: uuid.New(), Title: "Item", Questions: []entities.Question{singleChoiceQuestion(false, true)}}))
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	for _, f := range archive.File {
+		if strings.HasPrefix(f.Name, "items/") {
+			r, err := f.Open()
+			assert.NoError(t, err)
+			content, err := io.ReadAll(r)
+			assert.NoError(t, err)
+			return content
+		}
+	}
+	t.Fatal("export has no item file")
+	return nil
+}
+
+func TestQTI_RepeatedItemRefIsImportedOnce(t *testing.T) {
+	refs := strings.Repeat(`<assessmentItemRef identifier="i" href="items/q.xml"/>`, 2000)
+	data := qtiPackage(t, map[string][]byte{
+		"imsmanifest.xml":    []byte(`<manifest><resources><resource type="imsqti_test_xmlv2p1" href="assessmentTest.xml"/></resources></manifest>`),
+		"assessmentTest.xml": []byte(`<assessmentTest><testPart><assessmentSection>` + refs + `</assessmentSection></testPart></assessmentTest>`),
+		"items/q.xml":        qtiSampleItem(t),
+	})
+
+	parsed, errs, err := quiz.ParseQuestions(quiz.ImportFormatQTI, data)
+
+	assert.NoError(t, err)
+	assert.Empty(t, errs)
+	assert.Len(t, parsed, 1)
+}
+
+func TestQTI_RejectsTooManyItems(t *testing.T) {
+	var resources strings.Builder
+	for i := 0; i <= 500; i++ {
+		fmt.Fprintf(&resources, `<resource type="imsqti_item_xmlv2p1" href="items/q%d.xml"/>`, i)
+	}
+	data := qtiPackage(t, map[string][]byte{
+		"imsmanifest.xml": []byte(`<manifest><resources>` + resources.String() + `</resources></manifest>`),
+	})
+
+	parsed, errs, err := quiz.ParseQuestions(quiz.ImportFormatQTI, data)
+
+	assert.NoError(t, err)
+	assert.Empty(t, parsed)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, quiz.ErrQTITooManyItems.Error(), errs[0].Message)
+}
+
+func TestQTI_RejectsLargeExtractedContent(t *testing.T) {
+	// isi yang sangat mudah dikompres: kecil di zip, besar setelah diekstrak
+	filler := bytes.Repeat([]byte(" "), 3<<20)
+	files := map[string][]byte{
+		"imsmanifest.xml": []byte(`<manifest><resources><resource type="imsqti_item_xmlv2p1" href="items/q.xml"/></resources></manifest>`),
+		"items/q.xml":     qtiSampleItem(t),
+	}
+	for i := 0; i < 11; i++ {
+		files[fmt.Sprintf("padding/%d.txt", i)] = filler
+	}
+	data := qtiPackage(t, files)
+	assert.Less(t, len(data), quiz.MaxImportSize)
+
+	parsed, errs, err := quiz.ParseQuestions(quiz.ImportFormatQTI, data)
+
+	assert.NoError(t, err)
+	assert.Empty(t, parsed)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, quiz.ErrQTIPackageTooLarge.Error(), errs[0].Message)
+}
@@ -0,0 +1,319 @@
+package test
+
+import (
+	"api-shiners/api/handlers"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/quiz"
+	"api-shiners/pkg/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+//
+// ===== MOCK REPOSITORY =====
+//
+type MockQuizRepo struct {
+	mock.Mock
+}
+
+func (m *MockQuizRepo) CreateQuiz(ctx context.Context, q *entities.Quiz) error {
+	args := m.Called(ctx, q)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepo) GetQuizByID(ctx context.Context, id uuid.UUID) (*entities.Quiz, error) {
+	args := m.Called(ctx, id)
+	q, _ := args.Get(0).(*entities.Quiz)
+	return q, args.Error(1)
+}
+
+func (m *MockQuizRepo) GetQuizWithQuestions(ctx context.Context, id uuid.UUID) (*entities.Quiz, error) {
+	args := m.Called(ctx, id)
+	q, _ := args.Get(0).(*entities.Quiz)
+	return q, args.Error(1)
+}
+
+func (m *MockQuizRepo) GetQuizzesByModule(ctx context.Context, moduleID uuid.UUID, publishedOnly bool) ([]entities.Quiz, error) {
+	args := m.Called(ctx, moduleID, publishedOnly)
+	quizzes, _ := args.Get(0).([]entities.Quiz)
+	return quizzes, args.Error(1)
+}
+
+func (m *MockQuizRepo) UpdateQuiz(ctx context.Context, q *entities.Quiz) error {
+	args := m.Called(ctx, q)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepo) DeleteQuiz(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepo) CreateQuestion(ctx context.Context, q *entities.Question) error {
+	args := m.Called(ctx, q)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepo) CreateQuestions(ctx context.Context, questions []entities.Question) error {
+	args := m.Called(ctx, questions)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepo) GetQuestionByID(ctx context.Context, id uuid.UUID) (*entities.Question, error) {
+	args := m.Called(ctx, id)
+	q, _ := args.Get(0).(*entities.Question)
+	return q, args.Error(1)
+}
+
+func (m *MockQuizRepo) UpdateQuestion(ctx context.Context, q *entities.Question) error {
+	args := m.Called(ctx, q)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepo) DeleteQuestion(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepo) GetMaxQuestionPosition(ctx context.Context, quizID uuid.UUID) (int, error) {
+	args := m.Called(ctx, quizID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockQuizRepo) CreateChoice(ctx context.Context, ch *entities.Choice) error {
+	args := m.Called(ctx, ch)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepo) GetChoiceByID(ctx context.Context, id uuid.UUID) (*entities.Choice, error) {
+	args := m.Called(ctx, id)
+	ch, _ := args.Get(0).(*entities.Choice)
+	return ch, args.Error(1)
+}
+
+func (m *MockQuizRepo) UpdateChoice(ctx context.Context, ch *entities.Choice) error {
+	args := m.Called(ctx, ch)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepo) DeleteChoice(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepo) GetQuestionsByIDs(ctx context.Context, ids []uuid.UUID) ([]entities.Question, error) {
+	args := m.Called(ctx, ids)
+	questions, _ := args.Get(0).([]entities.Question)
+	return questions, args.Error(1)
+}
+
+func (m *MockQuizRepo) CreatePool(ctx context.Context, pool *entities.QuizPool) error {
+	args := m.Called(ctx, pool)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepo) GetPoolByID(ctx context.Context, id uuid.UUID) (*entities.QuizPool, error) {
+	args := m.Called(ctx, id)
+	pool, _ := args.Get(0).(*entities.QuizPool)
+	return pool, args.Error(1)
+}
+
+func (m *MockQuizRepo) UpdatePool(ctx context.Context, pool *entities.QuizPool) error {
+	args := m.Called(ctx, pool)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepo) DeletePool(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepo) GetPoolQuestions(ctx context.Context, bankID uuid.UUID, tags []string) ([]entities.Question, error) {
+	args := m.Called(ctx, bankID, tags)
+	questions, _ := args.Get(0).([]entities.Question)
+	return questions, args.Error(1)
+}
+
+func singleChoiceQuestion(correct ...bool) entities.Question {
+	q := entities.Question{ID: uuid.New(), Type: entities.QuestionTypeSingleChoice, Text: "2 + 2 = ?"}
+	for _, c := range correct {
+		q.Choices = append(q.Choices, entities.Choice{ID: uuid.New(), QuestionID: q.ID, Text: "opsi", IsCorrect: c})
+	}
+	return q
+}
+
+//
+// ===== TEST QUIZ =====
+//
+func TestPublishQuiz_RejectsInvalidSingleChoice(t *testing.T) {
+	repo := new(MockQuizRepo)
+	service := quiz.NewQuizService(repo, new(MockBankRepo), new(MockCourseRepo))
+
+	courseID := uuid.New()
+	q := &entities.Quiz{
+		ID:     uuid.New(),
+		Module: entities.CourseModule{CourseID: courseID},
+		Questions: []entities.Question{
+			singleChoiceQuestion(true, false),
+			singleChoiceQuestion(false, false),
+			singleChoiceQuestion(true, true),
+		},
+	}
+	repo.On("GetQuizWithQuestions", mock.Anything, q.ID).Return(q, nil)
+
+	_, err := service.SetPublished(context.Background(), "TEACHER", courseID, q.ID, true)
+
+	var invalid *quiz.ValidationError
+	assert.ErrorAs(t, err, &invalid)
+	assert.Len(t, invalid.Errors, 2)
+	assert.Equal(t, "questions[2]", invalid.Errors[0].Field)
+	assert.Equal(t, "questions[3]", invalid.Errors[1].Field)
+	repo.AssertNotCalled(t, "UpdateQuiz", mock.Anything, mock.Anything)
+}
+
+func TestPublishQuiz_Success(t *testing.T) {
+	repo := new(MockQuizRepo)
+	service := quiz.NewQuizService(repo, new(MockBankRepo), new(MockCourseRepo))
+
+	courseID := uuid.New()
+	q := &entities.Quiz{
+		ID:        uuid.New(),
+		Module:    entities.CourseModule{CourseID: courseID},
+		Questions: []entities.Question{singleChoiceQuestion(false, true, false)},
+	}
+	repo.On("GetQuizWithQuestions", mock.Anything, q.ID).Return(q, nil)
+	repo.On("UpdateQuiz", mock.Anything, q).Return(nil)
+
+	published, err := service.SetPublished(context.Background(), "TEACHER", courseID, q.ID, true)
+
+	assert.NoError(t, err)
+	assert.True(t, published.IsPublished)
+}
+
+func TestGetQuiz_StudentCannotSeeDraft(t *testing.T) {
+	repo := new(MockQuizRepo)
+	courseRepo := new(MockCourseRepo)
+	service := quiz.NewQuizService(repo, new(MockBankRepo), courseRepo)
+
+	c := &entities.Course{ID: uuid.New(), IsPublished: true}
+	q := &entities.Quiz{ID: uuid.New(), Module: entities.CourseModule{CourseID: c.ID}}
+	courseRepo.On("GetCourseByID", mock.Anything, c.ID).Return(c, nil)
+	repo.On("GetQuizByID", mock.Anything, q.ID).Return(q, nil)
+
+	_, err := service.GetQuiz(context.Background(), "STUDENT", c.ID, q.ID)
+
+	assert.ErrorIs(t, err, quiz.ErrQuizNotFound)
+}
+
+func TestGetQuiz_StudentGetsMetadataWithoutQuestions(t *testing.T) {
+	repo := new(MockQuizRepo)
+	courseRepo := new(MockCourseRepo)
+	service := quiz.NewQuizService(repo, new(MockBankRepo), courseRepo)
+
+	c := &entities.Course{ID: uuid.New(), IsPublished: true}
+	q := &entities.Quiz{ID: uuid.New(), Title: "Kuis 1", IsPublished: true, Module: entities.CourseModule{CourseID: c.ID}}
+	courseRepo.On("GetCourseByID", mock.Anything, c.ID).Return(c, nil)
+	repo.On("GetQuizByID", mock.Anything, q.ID).Return(q, nil)
+
+	found, err := service.GetQuiz(context.Background(), "STUDENT", c.ID, q.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Kuis 1", found.Title)
+	assert.Empty(t, found.Questions)
+	repo.AssertNotCalled(t, "GetQuizWithQuestions", mock.Anything, mock.Anything)
+}
+
+func TestCreateChoice_CannotBreakPublishedQuiz(t *testing.T) {
+	repo := new(MockQuizRepo)
+	service := quiz.NewQuizService(repo, new(MockBankRepo), new(MockCourseRepo))
+
+	courseID := uuid.New()
+	question := singleChoiceQuestion(true, false)
+	q := &entities.Quiz{ID: uuid.New(), IsPublished: true, Module: entities.CourseModule{CourseID: courseID}}
+	question.QuizID = &q.ID
+	repo.On("GetQuizByID", mock.Anything, q.ID).Return(q, nil)
+	repo.On("GetQuestionByID", mock.Anything, question.ID).Return(&question, nil)
+
+	_, err := service.CreateChoice(context.Background(), "TEACHER", courseID, q.ID, question.ID, quiz.ChoiceInput{Text: "5", IsCorrect: true})
+
+	assert.ErrorIs(t, err, quiz.ErrInvalidQuiz)
+	repo.AssertNotCalled(t, "CreateChoice", mock.Anything, mock.Anything)
+}
+
+func TestCreateQuiz_StudentForbidden(t *testing.T) {
+	service := quiz.NewQuizService(new(MockQuizRepo), new(MockBankRepo), new(MockCourseRepo))
+
+	_, err := service.CreateQuiz(context.Background(), "STUDENT", uuid.New(), uuid.New(), quiz.QuizInput{Title: "Kuis"})
+
+	assert.ErrorIs(t, err, quiz.ErrForbidden)
+}
+
+func TestPublishQuiz_ValidatesQuestionTypes(t *testing.T) {
+	repo := new(MockQuizRepo)
+	service := quiz.NewQuizService(repo, new(MockBankRepo), new(MockCourseRepo))
+
+	courseID := uuid.New()
+	multi := singleChoiceQuestion(true, true, false)
+	multi.Type = entities.QuestionTypeMultipleChoice
+	q := &entities.Quiz{
+		ID:     uuid.New(),
+		Module: entities.CourseModule{CourseID: courseID},
+		Questions: []entities.Question{
+			multi,
+			{ID: uuid.New(), Type: entities.QuestionTypeShortAnswer, Text: "Ibu kota Indonesia?"},
+			{ID: uuid.New(), Type: entities.QuestionTypeNumeric, Text: "Nilai pi?"},
+			{ID: uuid.New(), Type: entities.QuestionTypeEssay, Text: "Jelaskan fotosintesis."},
+		},
+	}
+	repo.On("GetQuizWithQuestions", mock.Anything, q.ID).Return(q, nil)
+
+	_, err := service.SetPublished(context.Background(), "TEACHER", courseID, q.ID, true)
+
+	var invalid *quiz.ValidationError
+	assert.ErrorAs(t, err, &invalid)
+	assert.Len(t, invalid.Errors, 2)
+	assert.Equal(t, "questions[2]", invalid.Errors[0].Field)
+	assert.Equal(t, "questions[3]", invalid.Errors[1].Field)
+}
+
+func TestQuizHandler_InputErrorIs400AndUnexpectedErrorIsGeneric500(t *testing.T) {
+	repo := new(MockQuizRepo)
+	courseRepo := new(MockCourseRepo)
+	controller := handlers.NewQuizController(quiz.NewQuizService(repo, new(MockBankRepo), courseRepo))
+	courseID, moduleID := uuid.New(), uuid.New()
+
+	courseRepo.On("GetModuleByID", mock.Anything, moduleID).Return(&entities.CourseModule{ID: moduleID, CourseID: courseID}, nil)
+	repo.On("CreateQuiz", mock.Anything, mock.Anything).Return(errors.New("pq: connection refused"))
+
+	app := fiber.New()
+	app.Post("/courses/:course_id/modules/:module_id/quizzes", func(c *fiber.Ctx) error {
+		c.Locals("course_role", "TEACHER")
+		return c.Next()
+	}, controller.CreateQuiz)
+	post := func(body string) (int, utils.ErrorResponse) {
+		req := httptest.NewRequest("POST", "/courses/"+courseID.String()+"/modules/"+moduleID.String()+"/quizzes", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		var errResp utils.ErrorResponse
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&errResp))
+		return resp.StatusCode, errResp
+	}
+
+	status, body := post(`{"title": " "}`)
+	assert.Equal(t, fiber.StatusBadRequest, status)
+	assert.Equal(t, "title is required", body.Message)
+
+	status, body = post(`{"title": "Ulangan Harian"}`)
+	assert.Equal(t, fiber.StatusInternalServerError, status)
+	assert.Equal(t, "Internal server error", body.Message)
+}
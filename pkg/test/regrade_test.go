@@ -0,0 +1,97 @@
+package test
+
+import (
+	"api-shiners/pkg/attempt"
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/quiz"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func pointsPtr(v float64) *float64 {
+	return &v
+}
+
+//
+// ===== TEST REGRADE =====
+//
+func TestRegrade_SingleQuestionPreviewThenApply(t *testing.T) {
+	repo := new(MockAttemptRepo)
+	quizRepo := new(MockQuizRepo)
+	service := attempt.NewAttemptService(repo, noOverrides(), quizRepo, new(MockCourseRepo))
+
+	courseID := uuid.New()
+	// kunci q1 diperbaiki: pilihan kedua yang benar
+	fixed := singleChoiceQuestion(false, true)
+	// kunci q2 juga berubah, tetapi tidak ikut dinilai ulang
+	other := singleChoiceQuestion(false, true)
+	q := &entities.Quiz{ID: uuid.New(), Module: entities.CourseModule{CourseID: courseID}, Questions: []entities.Question{fixed, other}}
+
+	submittedAt := time.Now()
+	newAttempt := func(fixedChoice int, fixedCorrect bool, score float64) entities.QuizAttempt {
+		a := entities.QuizAttempt{ID: uuid.New(), QuizID: q.ID, StudentID: uuid.New(), SubmittedAt: &submittedAt, Score: score}
+		fixedPoints := 0.0
+		if fixedCorrect {
+			fixedPoints = 1
+		}
+		a.Answers = []entities.Answer{
+			{ID: uuid.New(), AttemptID: a.ID, QuestionID: fixed.ID, ChoiceID: &fixed.Choices[fixedChoice].ID, IsCorrect: fixedCorrect, Points: pointsPtr(fixedPoints)},
+			{ID: uuid.New(), AttemptID: a.ID, QuestionID: other.ID, ChoiceID: &other.Choices[0].ID, IsCorrect: true, Points: pointsPtr(1)},
+		}
+		return a
+	}
+	wronglyMarked := newAttempt(1, false, 50)
+	wronglyCredited := newAttempt(0, true, 100)
+	attempts := []entities.QuizAttempt{wronglyMarked, wronglyCredited}
+
+	quizRepo.On("GetQuizWithQuestions", mock.Anything, q.ID).Return(q, nil)
+	repo.On("GetSubmittedAttemptsWithAnswers", mock.Anything, q.ID).Return(attempts, nil)
+
+	teacher := uuid.New()
+	preview, err := service.Regrade(context.Background(), teacher, "TEACHER", courseID, q.ID, attempt.RegradeInput{QuestionID: &fixed.ID, DryRun: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, preview.AttemptsChecked)
+	assert.Equal(t, 2, preview.AttemptsChanged)
+	assert.Equal(t, 50.0, preview.Changes[0].OldScore)
+	assert.Equal(t, 100.0, preview.Changes[0].NewScore)
+	assert.Equal(t, 50.0, preview.Changes[1].NewScore)
+	assert.Equal(t, 1, preview.Changes[1].AnswersChanged)
+	// pratinjau tidak mengubah data
+	assert.False(t, attempts[0].Answers[0].IsCorrect)
+	repo.AssertNotCalled(t, "RegradeAttempts", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "CreateRegrade", mock.Anything, mock.Anything)
+
+	repo.On("RegradeAttempts", mock.Anything, []uuid.UUID{wronglyMarked.ID, wronglyCredited.ID}).
+		Return([]entities.QuizAttempt{newAttempt(1, false, 50), newAttempt(0, true, 100)}, nil)
+	repo.On("CreateRegrade", mock.Anything, mock.Anything).Return(nil)
+
+	applied, err := service.Regrade(context.Background(), teacher, "TEACHER", courseID, q.ID, attempt.RegradeInput{QuestionID: &fixed.ID, Reason: " Kunci salah "})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, applied.AttemptsChanged)
+	assert.Equal(t, "Kunci salah", applied.Reason)
+	assert.Equal(t, teacher, applied.RegradedByID)
+	repo.AssertCalled(t, "CreateRegrade", mock.Anything, applied)
+}
+
+func TestRegrade_UnknownQuestionRejected(t *testing.T) {
+	repo := new(MockAttemptRepo)
+	quizRepo := new(MockQuizRepo)
+	service := attempt.NewAttemptService(repo, noOverrides(), quizRepo, new(MockCourseRepo))
+
+	courseID := uuid.New()
+	q := &entities.Quiz{ID: uuid.New(), Module: entities.CourseModule{CourseID: courseID}, Questions: []entities.Question{singleChoiceQuestion(true, false)}}
+	quizRepo.On("GetQuizWithQuestions", mock.Anything, q.ID).Return(q, nil)
+	repo.On("GetSubmittedAttemptsWithAnswers", mock.Anything, q.ID).Return([]entities.QuizAttempt{}, nil)
+
+	unknown := uuid.New()
+	_, err := service.Regrade(context.Background(), uuid.New(), "TEACHER", courseID, q.ID, attempt.RegradeInput{QuestionID: &unknown})
+
+	assert.ErrorIs(t, err, quiz.ErrQuestionNotFound)
+}
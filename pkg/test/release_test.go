@@ -0,0 +1,79 @@
+package test
+
+import (
+	"api-shiners/pkg/attempt"
+	"api-shiners/pkg/entities"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+//
+// ===== TEST RELEASE POLICY =====
+//
+func TestReviewOf_ReleasePolicies(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	immediate := &entities.Quiz{ReleasePolicy: entities.ReleaseImmediate, ReleaseContent: entities.ReleaseScore}
+	review := attempt.ReviewOf(immediate, nil, now)
+	assert.True(t, review.ShowScore)
+	assert.False(t, review.ShowCorrectness)
+	assert.False(t, review.ShowAnswerKey)
+
+	afterClose := &entities.Quiz{ReleasePolicy: entities.ReleaseAfterClose, ReleaseContent: entities.ReleaseCorrectness}
+	review = attempt.ReviewOf(afterClose, &future, now)
+	assert.False(t, review.Released)
+	assert.False(t, review.ShowScore)
+	assert.Equal(t, &future, review.ReleaseAt)
+	review = attempt.ReviewOf(afterClose, &past, now)
+	assert.True(t, review.ShowCorrectness)
+	assert.False(t, review.ShowAnswerKey)
+
+	manual := &entities.Quiz{ReleasePolicy: entities.ReleaseManual, ReleaseContent: entities.ReleaseFull}
+	assert.False(t, attempt.ReviewOf(manual, nil, now).Released)
+	manual.ResultsReleasedAt = &past
+	assert.True(t, attempt.ReviewOf(manual, nil, now).ShowAnswerKey)
+}
+
+func TestReviewAttempt_WaitsForLatestOverrideClose(t *testing.T) {
+	repo := new(MockAttemptRepo)
+	overrideRepo := noOverrides()
+	quizRepo := new(MockQuizRepo)
+	service := attempt.NewAttemptService(repo, overrideRepo, quizRepo, new(MockCourseRepo))
+
+	courseID := uuid.New()
+	student := uuid.New()
+	closeAt := time.Now().Add(-time.Hour)
+	extended := time.Now().Add(time.Hour)
+	q := &entities.Quiz{
+		ID:             uuid.New(),
+		CloseAt:        &closeAt,
+		ReleasePolicy:  entities.ReleaseAfterClose,
+		ReleaseContent: entities.ReleaseFull,
+		Module:         entities.CourseModule{CourseID: courseID},
+		Questions:      []entities.Question{singleChoiceQuestion(true, false)},
+	}
+	submittedAt := closeAt.Add(-time.Minute)
+	a := &entities.QuizAttempt{ID: uuid.New(), QuizID: q.ID, StudentID: student, SubmittedAt: &submittedAt}
+
+	repo.On("GetAttemptWithAnswers", mock.Anything, a.ID).Return(a, nil)
+	quizRepo.On("GetQuizWithQuestions", mock.Anything, q.ID).Return(q, nil)
+	// student lain masih mengerjakan dengan close_at yang diperpanjang
+	overrideRepo.On("GetLatestCloseAt", mock.Anything, q.ID).Return(&extended, nil)
+
+	_, _, review, err := service.ReviewAttempt(context.Background(), student, courseID, a.ID)
+
+	assert.NoError(t, err)
+	assert.False(t, review.Released)
+	assert.False(t, review.ShowAnswerKey)
+	assert.Equal(t, &extended, review.ReleaseAt)
+
+	_, _, _, err = service.ReviewAttempt(context.Background(), uuid.New(), courseID, a.ID)
+	assert.ErrorIs(t, err, attempt.ErrAttemptNotFound)
+}
@@ -0,0 +1,104 @@
+package test
+
+import (
+	"api-shiners/pkg/entities"
+	"api-shiners/pkg/quiz"
+	"api-shiners/pkg/report"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// submittedAttempt membuat attempt yang sudah dinilai; picks[i] = index pilihan soal ke-i, -1 = kosong
+func submittedAttempt(questions []entities.Question, score float64, picks ...int) entities.QuizAttempt {
+	now := time.Now()
+	a := entities.QuizAttempt{ID: uuid.New(), SubmittedAt: &now, Score: score}
+	for i, pick := range picks {
+		if pick < 0 {
+			continue
+		}
+		choice := questions[i].Choices[pick]
+		points := 0.0
+		if choice.IsCorrect {
+			points = 1
+		}
+		a.Answers = append(a.Answers, entities.Answer{
+			QuestionID: questions[i].ID,
+			ChoiceID:   &choice.ID,
+			IsCorrect:  choice.IsCorrect,
+			Points:     &points,
+		})
+	}
+	return a
+}
+
+//
+// ===== TEST ITEM ANALYSIS =====
+//
+func TestAnalyzeItems_ComputesItemAndQuizStatistics(t *testing.T) {
+	questions := []entities.Question{
+		singleChoiceQuestion(true, false),
+		singleChoiceQuestion(true, false),
+		singleChoiceQuestion(true, false),
+	}
+	q := &entities.Quiz{ID: uuid.New(), Title: "Kuis", Questions: questions}
+
+	pending := submittedAttempt(questions, 0, 0, 0, 0)
+	pending.PendingReview = true
+	attempts := []entities.QuizAttempt{
+		submittedAttempt(questions, 100, 0, 0, 0),
+		submittedAttempt(questions, 66.67, 0, 0, 1),
+		submittedAttempt(questions, 33.33, 0, 1, -1),
+		submittedAttempt(questions, 0, 1, 1, 1),
+		pending,
+	}
+
+	analysis := report.AnalyzeItems(q, questions, attempts)
+
+	assert.Equal(t, 4, analysis.Attempts)
+	assert.Equal(t, 1, analysis.PendingAttempts)
+	assert.Equal(t, 0.75, *analysis.Items[0].PValue)
+	assert.Equal(t, 0.5, *analysis.Items[1].PValue)
+	assert.Equal(t, 0.25, *analysis.Items[2].PValue)
+	assert.Equal(t, 0.522, *analysis.Items[0].PointBiserial)
+
+	third := analysis.Items[2]
+	assert.Equal(t, 1, third.Omitted)
+	assert.Equal(t, 0.25, third.Choices[0].Rate)
+	assert.Equal(t, 0.5, third.Choices[1].Rate)
+
+	assert.Equal(t, 0.75, *analysis.CronbachAlpha)
+	assert.Equal(t, 3, analysis.AlphaItems)
+
+	for _, bin := range []int{0, 3, 6, 9} {
+		assert.Equal(t, 1, analysis.Histogram[bin].Count)
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, report.WriteItemAnalysisCSV(&buf, analysis))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 4)
+	assert.Contains(t, lines[3], "A=0.25*; B=0.5")
+
+	// teks soal yang diawali = tidak boleh menjadi formula saat dibuka di spreadsheet
+	analysis.Items[0].Text = "=2+3"
+	buf.Reset()
+	assert.NoError(t, report.WriteItemAnalysisCSV(&buf, analysis))
+	assert.Contains(t, buf.String(), ",'=2+3,")
+}
+
+func TestGetItemAnalysis_StudentForbidden(t *testing.T) {
+	attemptRepo := new(MockAttemptRepo)
+	quizRepo := new(MockQuizRepo)
+	service := report.NewReportService(attemptRepo, quizRepo)
+
+	_, err := service.GetItemAnalysis(context.Background(), string(entities.CourseRoleStudent), uuid.New(), uuid.New())
+
+	assert.ErrorIs(t, err, quiz.ErrForbidden)
+	quizRepo.AssertNotCalled(t, "GetQuizWithQuestions")
+}
@@ -0,0 +1,119 @@
+package test
+
+import (
+	"api-shiners/pkg/scheduler"
+	"context"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryLocker meniru lock terdistribusi di dalam satu proses
+type memoryLocker struct {
+	mu   sync.Mutex
+	held map[string]bool
+}
+
+func (l *memoryLocker) TryLock(ctx context.Context, name string, ttl time.Duration) (func(), bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held[name] {
+		return nil, false, nil
+	}
+	l.held[name] = true
+	return func() {
+		l.mu.Lock()
+		delete(l.held, name)
+		l.mu.Unlock()
+	}, true, nil
+}
+
+func TestRunLocked_SkipsWhileAnotherReplicaHoldsLock(t *testing.T) {
+	locker := &memoryLocker{held: map[string]bool{}}
+	runs := 0
+	job := func(ctx context.Context) error {
+		runs++
+		// replica kedua mencoba saat job pertama masih berjalan
+		ran, err := scheduler.RunLocked(ctx, locker, "quiz-auto-submit", time.Minute, func(context.Context) error {
+			runs++
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.False(t, ran)
+		return nil
+	}
+
+	ran, err := scheduler.RunLocked(context.Background(), locker, "quiz-auto-submit", time.Minute, job)
+
+	assert.NoError(t, err)
+	assert.True(t, ran)
+	assert.Equal(t, 1, runs)
+
+	ran, _ = scheduler.RunLocked(context.Background(), locker, "quiz-auto-submit", time.Minute, func(context.Context) error { return nil })
+	assert.True(t, ran, "lock must be released after the job finishes")
+}
+
+func TestRedisLocker_HeldLockAndStaleUnlock(t *testing.T) {
+	srv := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	t.Cleanup(func() { client.Close() })
+	replicaA, replicaB := scheduler.NewRedisLocker(client), scheduler.NewRedisLocker(client)
+	ctx := context.Background()
+
+	unlockA, ok, err := replicaA.TryLock(ctx, "logbook-deadlines", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_, ok, err = replicaB.TryLock(ctx, "logbook-deadlines", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, ok, "second locker must fail while the lock is held")
+
+	// job A melewati ttl sehingga lock kedaluwarsa dan diambil replica B
+	srv.FastForward(2 * time.Minute)
+	unlockB, ok, err := replicaB.TryLock(ctx, "logbook-deadlines", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// unlock dari A tidak boleh melepas lock milik B
+	unlockA()
+	assert.True(t, srv.Exists("lock:logbook-deadlines"))
+	_, ok, _ = replicaA.TryLock(ctx, "logbook-deadlines", time.Minute)
+	assert.False(t, ok)
+
+	unlockB()
+	assert.False(t, srv.Exists("lock:logbook-deadlines"))
+}
+
+func TestPostgresLocker_AdvisoryLockOnDedicatedConnection(t *testing.T) {
+	db, mock := newMockDB(t)
+	locker := scheduler.NewPostgresLocker(db)
+	ctx := context.Background()
+	tryLock := regexp.QuoteMeta("SELECT pg_try_advisory_lock(hashtext($1))")
+
+	mock.ExpectQuery(tryLock).WithArgs("quiz-auto-submit").
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(true))
+	mock.ExpectQuery(tryLock).WithArgs("quiz-auto-submit").
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(false))
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock(hashtext($1))")).WithArgs("quiz-auto-submit").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	unlock, ok, err := locker.TryLock(ctx, "quiz-auto-submit", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// sesi lain mendapat false dari pg_try_advisory_lock selama lock dipegang
+	second, ok, err := locker.TryLock(ctx, "quiz-auto-submit", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Nil(t, second, "a failed attempt must not return an unlock that could release the holder's lock")
+
+	unlock()
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,263 @@
+package test
+
+import (
+	"api-shiners/api/handlers"
+	"api-shiners/pkg/material"
+	"api-shiners/pkg/storage"
+	"api-shiners/pkg/utils"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+//
+// ===== FAKE S3 SERVER =====
+//
+// fakeS3 adalah stand-in minimal ala MinIO (path-style, satu bucket) untuk PUT/GET/HEAD/DELETE object
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{objects: map[string][]byte{}, types: map[string]string{}}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		if strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+			body = decodeAWSChunked(body)
+		}
+		f.objects[key] = body
+		f.types[key] = r.Header.Get("Content-Type")
+		w.Header().Set("ETag", `"fake-etag"`)
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet, http.MethodHead:
+		body, ok := f.objects[key]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			if r.Method == http.MethodGet {
+				io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>not found</Message></Error>`)
+			}
+			return
+		}
+		w.Header().Set("Content-Type", f.types[key])
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.Header().Set("ETag", `"fake-etag"`)
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			w.Write(body)
+		}
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// decodeAWSChunked membuka payload "aws-chunked" (signature v4 streaming)
+func decodeAWSChunked(body []byte) []byte {
+	var out bytes.Buffer
+	reader := bufio.NewReader(bytes.NewReader(body))
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return out.Bytes()
+		}
+		sizeHex := strings.SplitN(strings.TrimSpace(line), ";", 2)[0]
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil || size == 0 {
+			return out.Bytes()
+		}
+		io.CopyN(&out, reader, size)
+		reader.ReadString('\n')
+	}
+}
+
+//
+// ===== TEST STORAGE =====
+//
+func TestLocalStorage_PutOpenDelete(t *testing.T) {
+	store, err := storage.NewLocalStorage(t.TempDir(), "http://localhost:3000/api/files", "secret")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, store.Put(ctx, "materials/a/b/notes.txt", strings.NewReader("hello"), 5, "text/plain"))
+
+	file, err := store.Open(ctx, "materials/a/b/notes.txt")
+	require.NoError(t, err)
+	data, _ := io.ReadAll(file)
+	file.Close()
+	assert.Equal(t, "hello", string(data))
+
+	require.NoError(t, store.Delete(ctx, "materials/a/b/notes.txt"))
+	_, err = store.Open(ctx, "materials/a/b/notes.txt")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestLocalStorage_RejectsPathTraversal(t *testing.T) {
+	store, err := storage.NewLocalStorage(t.TempDir(), "http://localhost:3000/api/files", "secret")
+	require.NoError(t, err)
+
+	key, err := storage.CleanKey("../../etc/passwd")
+	require.NoError(t, err)
+	assert.Equal(t, "etc/passwd", key)
+
+	_, err = store.Open(context.Background(), "..")
+	assert.Error(t, err)
+}
+
+func TestLocalStorage_SignedURL(t *testing.T) {
+	store, err := storage.NewLocalStorage(t.TempDir(), "http://localhost:3000/api/files", "secret")
+	require.NoError(t, err)
+
+	raw, err := store.SignedURL(context.Background(), "materials/x/report.pdf", time.Minute, "report.pdf")
+	require.NoError(t, err)
+
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+	assert.Equal(t, "/api/files/materials/x/report.pdf", u.Path)
+
+	expires, _ := strconv.ParseInt(u.Query().Get("expires"), 10, 64)
+	assert.NoError(t, store.Verify("materials/x/report.pdf", "report.pdf", expires, u.Query().Get("signature")))
+	assert.ErrorIs(t, store.Verify("materials/x/other.pdf", "report.pdf", expires, u.Query().Get("signature")), storage.ErrInvalidSignature)
+	// nama download ikut ditandatangani
+	assert.ErrorIs(t, store.Verify("materials/x/report.pdf", "report.html", expires, u.Query().Get("signature")), storage.ErrInvalidSignature)
+
+	// signature kedaluwarsa tidak boleh diterima
+	past := time.Now().Add(-time.Minute).Unix()
+	sig := storage.Sign("secret", "materials/x/report.pdf", "report.pdf", past)
+	assert.ErrorIs(t, store.Verify("materials/x/report.pdf", "report.pdf", past, sig), storage.ErrInvalidSignature)
+}
+
+func TestFileDownload_NameIsSignedAndContentTypeFollowsKey(t *testing.T) {
+	store, err := storage.NewLocalStorage(t.TempDir(), "http://localhost:3000/api/files", "secret")
+	require.NoError(t, err)
+	key := "materials/x/report.pdf"
+	require.NoError(t, store.Put(context.Background(), key, strings.NewReader("%PDF-1.4"), 8, "application/pdf"))
+
+	app := fiber.New()
+	app.Get("/api/files/*", handlers.NewFileController(store).Download)
+
+	raw, err := store.SignedURL(context.Background(), key, time.Minute, "Laporan Akhir.pdf")
+	require.NoError(t, err)
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+
+	resp, err := app.Test(httptest.NewRequest("GET", u.RequestURI(), nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/pdf", resp.Header.Get("Content-Type"))
+	assert.Contains(t, resp.Header.Get("Content-Disposition"), `filename="Laporan Akhir.pdf"`)
+
+	// nama diganti agar browser merender HTML: signature tidak lagi cocok
+	query := u.Query()
+	query.Set("name", "report.html")
+	u.RawQuery = query.Encode()
+	resp, err = app.Test(httptest.NewRequest("GET", u.RequestURI(), nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestS3Storage_AgainstFakeServer(t *testing.T) {
+	fake := newFakeS3()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	store, err := storage.NewS3Storage(storage.S3Config{
+		Endpoint:  strings.TrimPrefix(server.URL, "http://"),
+		AccessKey: "minio",
+		SecretKey: "minio123",
+		Bucket:    "lms",
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	content := "%PDF-1.4 fake pdf"
+	require.NoError(t, store.Put(ctx, "materials/c/m/slides.pdf", strings.NewReader(content), int64(len(content)), "application/pdf"))
+	assert.Equal(t, content, string(fake.objects["lms/materials/c/m/slides.pdf"]))
+	assert.Equal(t, "application/pdf", fake.types["lms/materials/c/m/slides.pdf"])
+
+	file, err := store.Open(ctx, "materials/c/m/slides.pdf")
+	require.NoError(t, err)
+	data, _ := io.ReadAll(file)
+	file.Close()
+	assert.Equal(t, content, string(data))
+
+	signed, err := store.SignedURL(ctx, "materials/c/m/slides.pdf", 5*time.Minute, "slides.pdf")
+	require.NoError(t, err)
+	assert.Contains(t, signed, "X-Amz-Signature=")
+	assert.Contains(t, signed, "X-Amz-Expires=300")
+
+	require.NoError(t, store.Delete(ctx, "materials/c/m/slides.pdf"))
+	_, err = store.Open(ctx, "materials/c/m/slides.pdf")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestUploadPolicy_DetectContentType(t *testing.T) {
+	policy := material.UploadPolicy{MaxBytes: 1 << 20, AllowedTypes: []string{"application/pdf", "image/png"}}
+
+	contentType, err := policy.DetectContentType("tugas.pdf", []byte("%PDF-1.7\n..."))
+	assert.NoError(t, err)
+	assert.Equal(t, "application/pdf", contentType)
+
+	// ekstensi dipalsukan: isi file tetap dideteksi sebagai HTML
+	_, err = policy.DetectContentType("foto.png", []byte("<html><script>alert(1)</script></html>"))
+	assert.ErrorIs(t, err, material.ErrFileTypeNotAllowed)
+}
+
+func TestSanitizeFileName(t *testing.T) {
+	assert.Equal(t, "laporan_akhir.pdf", material.SanitizeFileName("../../laporan akhir.pdf"))
+	assert.Equal(t, "file", material.SanitizeFileName("..."))
+}
+
+func TestMaterialHandler_UnexpectedErrorReturnsGeneric500(t *testing.T) {
+	courseRepo := new(MockCourseRepo)
+	service := material.NewMaterialService(nil, courseRepo, nil, material.UploadPolicy{})
+	controller := handlers.NewMaterialController(service)
+	courseID, moduleID := uuid.New(), uuid.New()
+
+	courseRepo.On("GetCourseByID", mock.Anything, courseID).Return(nil, errors.New("dial tcp 10.0.0.5:5432: connection refused"))
+
+	app := fiber.New()
+	app.Get("/courses/:course_id/modules/:module_id/materials", func(c *fiber.Ctx) error {
+		c.Locals("user_id", uuid.NewString())
+		c.Locals("course_role", "STUDENT")
+		return c.Next()
+	}, controller.GetMaterials)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/courses/"+courseID.String()+"/modules/"+moduleID.String()+"/materials", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+
+	var body utils.ErrorResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "Internal server error", body.Message)
+}
@@ -0,0 +1,12 @@
+package utils
+
+import "strings"
+
+// SafeCSVCell mencegah formula injection: sel yang diawali =, +, -, @, tab atau CR
+// diberi awalan ' agar Excel/Sheets membacanya sebagai teks
+func SafeCSVCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}